@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	_ "time/tzdata" // analytics bucket by IANA timezone; the runtime image ships without zoneinfo
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -14,9 +17,11 @@ import (
 	"github.com/valenrio66/be-project/internal/api"
 	"github.com/valenrio66/be-project/internal/api/handlers"
 	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/jobs"
 	"github.com/valenrio66/be-project/internal/middleware"
 	"github.com/valenrio66/be-project/pkg/database"
 	"github.com/valenrio66/be-project/pkg/logger"
+	"github.com/valenrio66/be-project/pkg/storage"
 )
 
 // @title           Marketing Dashboard API
@@ -60,9 +65,69 @@ func main() {
 
 	queries := db.New(dbPool)
 	userService := service.NewUserService(queries, tokenMaker, cfg)
-	campaignService := service.NewCampaignService(queries)
+	campaignService := service.NewCampaignService(dbPool, queries, cfg)
+	goalService := service.NewGoalService(dbPool, queries)
+	analyticsService := service.NewAnalyticsService(dbPool, queries, goalService)
+	dashboardService := service.NewDashboardService(queries)
+	searchService := service.NewSearchService(queries)
+	templateService := service.NewTemplateService(queries, campaignService)
+	importService := service.NewImportService(dbPool, queries)
+	exportService := service.NewExportService(dbPool, queries)
+	tagService := service.NewTagService(dbPool, queries)
+	approvalService := service.NewApprovalService(dbPool, queries)
+	commentService := service.NewCommentService(dbPool, queries)
+	notificationService := service.NewNotificationService(queries)
+	assetStorage, localStorage, err := newStorage(cfg)
+	if err != nil {
+		log.Fatalf("Storage setup failed: %v", err)
+	}
+	assetService := service.NewAssetService(dbPool, queries, assetStorage, cfg.AssetURLTTL)
+	channelService := service.NewChannelService(dbPool, queries)
+	segmentService := service.NewSegmentService(dbPool, queries)
+	variantService := service.NewVariantService(dbPool, queries)
+	trackingSecret := cfg.TrackingSecret
+	if trackingSecret == "" {
+		trackingSecret = cfg.JWTSecret
+	}
+	linkService := service.NewLinkService(dbPool, queries, goalService, cfg.TrackingBaseURL, []byte(trackingSecret))
+	conversionService := service.NewConversionService(dbPool, queries, goalService, cfg.ReportingCurrency, []byte(cfg.PostbackSecret))
+	if cfg.PostbackSecret == "" {
+		zap.L().Warn("POSTBACK_SECRET is not set, conversion postbacks will be rejected")
+	}
+	attributionService := service.NewAttributionService(queries, cfg.ReportingCurrency)
+	funnelService := service.NewFunnelService(dbPool, queries)
+	cohortService := service.NewCohortService(queries)
 	userHandler := handlers.NewUserHandler(userService)
 	campaignHandler := handlers.NewCampaignHandler(campaignService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	dashboardHandler := handlers.NewDashboardHandler(dashboardService)
+	searchHandler := handlers.NewSearchHandler(searchService)
+	templateHandler := handlers.NewTemplateHandler(templateService)
+	importHandler := handlers.NewImportHandler(importService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	approvalHandler := handlers.NewApprovalHandler(approvalService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	assetHandler := handlers.NewAssetHandler(assetService)
+	channelHandler := handlers.NewChannelHandler(channelService)
+	segmentHandler := handlers.NewSegmentHandler(segmentService)
+	variantHandler := handlers.NewVariantHandler(variantService)
+	goalHandler := handlers.NewGoalHandler(goalService)
+	linkHandler := handlers.NewLinkHandler(linkService)
+	conversionHandler := handlers.NewConversionHandler(conversionService)
+	attributionHandler := handlers.NewAttributionHandler(attributionService)
+	funnelHandler := handlers.NewFunnelHandler(funnelService)
+	cohortHandler := handlers.NewCohortHandler(cohortService)
+	var fileHandler *handlers.FileHandler
+	if localStorage != nil {
+		fileHandler = handlers.NewFileHandler(localStorage)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	jobs.NewTrashPurger(campaignService, assetService, cfg.TrashPurgeInterval).Start(ctx)
+	jobs.NewGoalChecker(goalService, cfg.GoalCheckInterval).Start(ctx)
 
 	r := gin.New()
 	r.Use(gin.Recovery())
@@ -70,13 +135,67 @@ func main() {
 
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowAllOrigins = true
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "If-Match", "If-None-Match"}
+	corsConfig.ExposeHeaders = []string{"ETag"}
 	r.Use(cors.New(corsConfig))
 
-	api.SetupRoutes(r, userHandler, campaignHandler, tokenMaker)
+	api.SetupRoutes(r, api.Handlers{
+		User:         userHandler,
+		Campaign:     campaignHandler,
+		Analytics:    analyticsHandler,
+		Dashboard:    dashboardHandler,
+		Search:       searchHandler,
+		Template:     templateHandler,
+		Import:       importHandler,
+		Export:       exportHandler,
+		Tag:          tagHandler,
+		Approval:     approvalHandler,
+		Comment:      commentHandler,
+		Notification: notificationHandler,
+		Asset:        assetHandler,
+		Channel:      channelHandler,
+		Segment:      segmentHandler,
+		Variant:      variantHandler,
+		Goal:         goalHandler,
+		Link:         linkHandler,
+		Conversion:   conversionHandler,
+		Attribution:  attributionHandler,
+		Funnel:       funnelHandler,
+		Cohort:       cohortHandler,
+		File:         fileHandler,
+	}, tokenMaker)
 
 	logger.Info("Server running on port " + cfg.ServerPort)
 	if err := r.Run(":" + cfg.ServerPort); err != nil {
 		logger.Error("Failed to run server", zap.Error(err))
 	}
 }
+
+// newStorage builds the asset storage backend. The local backend is also
+// returned on its own, as the API serves its files.
+func newStorage(cfg config.Config) (storage.Storage, *storage.Local, error) {
+	switch cfg.StorageBackend {
+	case "local":
+		secret := cfg.StorageSigningSecret
+		if secret == "" {
+			secret = cfg.JWTSecret
+		}
+		local, err := storage.NewLocal(cfg.StorageLocalDir, cfg.StoragePublicURL, []byte(secret))
+		if err != nil {
+			return nil, nil, err
+		}
+		return local, local, nil
+	case "s3":
+		s3, err := storage.NewS3(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3UsePathStyle,
+		})
+		return s3, nil, err
+	default:
+		return nil, nil, fmt.Errorf("unknown STORAGE_BACKEND %q, expected local or s3", cfg.StorageBackend)
+	}
+}
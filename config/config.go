@@ -13,6 +13,30 @@ type Config struct {
 	JWTSecret     string        `mapstructure:"JWT_SECRET"`
 	TokenDuration time.Duration `mapstructure:"TOKEN_DURATION"`
 	Environment   string        `mapstructure:"ENVIRONMENT"`
+
+	TrashRetentionDays int           `mapstructure:"TRASH_RETENTION_DAYS"`
+	TrashPurgeInterval time.Duration `mapstructure:"TRASH_PURGE_INTERVAL"`
+
+	GoalCheckInterval time.Duration `mapstructure:"GOAL_CHECK_INTERVAL"`
+
+	TrackingBaseURL string `mapstructure:"TRACKING_BASE_URL"`
+	TrackingSecret  string `mapstructure:"TRACKING_SECRET"`
+
+	PostbackSecret    string `mapstructure:"POSTBACK_SECRET"`
+	ReportingCurrency string `mapstructure:"REPORTING_CURRENCY"`
+
+	StorageBackend       string        `mapstructure:"STORAGE_BACKEND"`
+	StorageLocalDir      string        `mapstructure:"STORAGE_LOCAL_DIR"`
+	StoragePublicURL     string        `mapstructure:"STORAGE_PUBLIC_URL"`
+	StorageSigningSecret string        `mapstructure:"STORAGE_SIGNING_SECRET"`
+	AssetURLTTL          time.Duration `mapstructure:"ASSET_URL_TTL"`
+
+	S3Endpoint     string `mapstructure:"S3_ENDPOINT"`
+	S3Region       string `mapstructure:"S3_REGION"`
+	S3Bucket       string `mapstructure:"S3_BUCKET"`
+	S3AccessKey    string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey    string `mapstructure:"S3_SECRET_KEY"`
+	S3UsePathStyle bool   `mapstructure:"S3_USE_PATH_STYLE"`
 }
 
 func LoadConfig() (config Config, err error) {
@@ -22,6 +46,22 @@ func LoadConfig() (config Config, err error) {
 	viper.SetDefault("SERVER_PORT", "8080")
 	viper.SetDefault("TOKEN_DURATION", "24h")
 	viper.SetDefault("ENVIRONMENT", "development")
+	viper.SetDefault("TRASH_RETENTION_DAYS", 30)
+	viper.SetDefault("TRASH_PURGE_INTERVAL", "1h")
+	viper.SetDefault("GOAL_CHECK_INTERVAL", "15m")
+	viper.SetDefault("TRACKING_BASE_URL", "http://localhost:8080")
+	viper.SetDefault("REPORTING_CURRENCY", "USD")
+	viper.SetDefault("STORAGE_BACKEND", "local")
+	viper.SetDefault("STORAGE_LOCAL_DIR", "./data/assets")
+	viper.SetDefault("STORAGE_PUBLIC_URL", "http://localhost:8080/api/v1/files")
+	viper.SetDefault("STORAGE_SIGNING_SECRET", "")
+	viper.SetDefault("ASSET_URL_TTL", "15m")
+	viper.SetDefault("S3_ENDPOINT", "")
+	viper.SetDefault("S3_REGION", "us-east-1")
+	viper.SetDefault("S3_BUCKET", "")
+	viper.SetDefault("S3_ACCESS_KEY", "")
+	viper.SetDefault("S3_SECRET_KEY", "")
+	viper.SetDefault("S3_USE_PATH_STYLE", false)
 
 	err = viper.ReadInConfig()
 	if err != nil {
@@ -34,5 +74,17 @@ func LoadConfig() (config Config, err error) {
 	}
 
 	err = viper.Unmarshal(&config)
+	if err != nil {
+		return
+	}
+
+	if config.TrashPurgeInterval <= 0 {
+		err = errors.New("TRASH_PURGE_INTERVAL must be positive")
+		return
+	}
+	if config.GoalCheckInterval <= 0 {
+		err = errors.New("GOAL_CHECK_INTERVAL must be positive")
+		return
+	}
 	return
 }
@@ -0,0 +1,19 @@
+-- migrate:up
+CREATE TABLE campaign_metrics (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL, -- start of the reporting period (e.g. the hour)
+    impressions BIGINT NOT NULL DEFAULT 0 CHECK (impressions >= 0),
+    clicks BIGINT NOT NULL DEFAULT 0 CHECK (clicks >= 0),
+    conversions BIGINT NOT NULL DEFAULT 0 CHECK (conversions >= 0),
+    spend DECIMAL(15, 2) NOT NULL DEFAULT 0 CHECK (spend >= 0),
+    revenue DECIMAL(15, 2) NOT NULL DEFAULT 0 CHECK (revenue >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (campaign_id, recorded_at)
+);
+
+CREATE INDEX idx_campaign_metrics_recorded_at ON campaign_metrics(recorded_at);
+
+-- migrate:down
+DROP TABLE campaign_metrics;
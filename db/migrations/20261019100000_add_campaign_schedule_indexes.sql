@@ -0,0 +1,7 @@
+-- migrate:up
+CREATE INDEX idx_campaigns_user_id_start_date ON campaigns(user_id, start_date);
+CREATE INDEX idx_campaigns_user_id_end_date ON campaigns(user_id, end_date);
+
+-- migrate:down
+DROP INDEX idx_campaigns_user_id_end_date;
+DROP INDEX idx_campaigns_user_id_start_date;
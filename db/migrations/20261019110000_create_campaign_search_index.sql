@@ -0,0 +1,39 @@
+-- migrate:up
+CREATE TABLE campaign_search_index (
+    campaign_id UUID PRIMARY KEY REFERENCES campaigns(id) ON DELETE CASCADE,
+    document TSVECTOR NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_campaign_search_index_document ON campaign_search_index USING GIN (document);
+
+-- Title matches outrank description matches.
+CREATE FUNCTION campaign_search_document(title TEXT, description TEXT) RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+           setweight(to_tsvector('english', COALESCE(description, '')), 'B');
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE FUNCTION refresh_campaign_search_index() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO campaign_search_index (campaign_id, document, updated_at)
+    VALUES (NEW.id, campaign_search_document(NEW.title, NEW.description), NOW())
+    ON CONFLICT (campaign_id) DO UPDATE
+    SET document = EXCLUDED.document,
+        updated_at = EXCLUDED.updated_at;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_campaigns_search_index
+    AFTER INSERT OR UPDATE OF title, description ON campaigns
+    FOR EACH ROW EXECUTE FUNCTION refresh_campaign_search_index();
+
+INSERT INTO campaign_search_index (campaign_id, document)
+SELECT id, campaign_search_document(title, description)
+FROM campaigns;
+
+-- migrate:down
+DROP TRIGGER trg_campaigns_search_index ON campaigns;
+DROP FUNCTION refresh_campaign_search_index();
+DROP FUNCTION campaign_search_document(TEXT, TEXT);
+DROP TABLE campaign_search_index;
@@ -0,0 +1,8 @@
+-- migrate:up
+ALTER TABLE campaigns ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_campaigns_deleted_at ON campaigns(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- migrate:down
+DROP INDEX idx_campaigns_deleted_at;
+ALTER TABLE campaigns DROP COLUMN deleted_at;
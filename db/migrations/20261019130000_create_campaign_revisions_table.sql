@@ -0,0 +1,34 @@
+-- migrate:up
+CREATE TABLE campaign_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL CHECK (revision > 0),
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(32) NOT NULL, -- created, updated, deleted, restored, reverted
+    changes JSONB NOT NULL DEFAULT '{}'::jsonb, -- {"field": {"before": ..., "after": ...}}
+    snapshot JSONB NOT NULL, -- editable fields after the change
+    source_revision INTEGER, -- set when action = 'reverted'
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (campaign_id, revision)
+);
+
+-- Existing campaigns start their history with their current state.
+INSERT INTO campaign_revisions (campaign_id, revision, actor_id, action, snapshot, created_at)
+SELECT
+    id,
+    1,
+    user_id,
+    'created',
+    jsonb_build_object(
+        'title', title,
+        'description', description,
+        'status', status,
+        'start_date', start_date,
+        'end_date', end_date,
+        'budget', budget
+    ),
+    created_at
+FROM campaigns;
+
+-- migrate:down
+DROP TABLE campaign_revisions;
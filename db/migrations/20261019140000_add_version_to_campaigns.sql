@@ -0,0 +1,6 @@
+-- migrate:up
+-- Incremented on every write; exposed to clients as the campaign ETag.
+ALTER TABLE campaigns ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- migrate:down
+ALTER TABLE campaigns DROP COLUMN version;
@@ -0,0 +1,19 @@
+-- migrate:up
+CREATE TABLE campaign_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scope VARCHAR(20) NOT NULL DEFAULT 'user' CHECK (scope IN ('user', 'workspace')),
+    name VARCHAR(255) NOT NULL,
+    title_template VARCHAR(255) NOT NULL,
+    description_template TEXT,
+    default_budget DECIMAL(15, 2) CHECK (default_budget >= 0),
+    default_duration_days INTEGER CHECK (default_duration_days > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_campaign_templates_owner_id ON campaign_templates(owner_id);
+CREATE INDEX idx_campaign_templates_scope ON campaign_templates(scope);
+
+-- migrate:down
+DROP TABLE campaign_templates;
@@ -0,0 +1,21 @@
+-- migrate:up
+CREATE TABLE campaign_imports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'xlsx')),
+    mode VARCHAR(20) NOT NULL CHECK (mode IN ('all_or_nothing', 'partial')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'completed', 'failed')),
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    imported_rows INTEGER NOT NULL DEFAULT 0,
+    failed_rows INTEGER NOT NULL DEFAULT 0,
+    row_errors JSONB NOT NULL DEFAULT '[]'::jsonb, -- [{"row": 2, "field": "budget", "message": "..."}]
+    error TEXT, -- why the import as a whole failed
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_campaign_imports_user_id ON campaign_imports(user_id);
+
+-- migrate:down
+DROP TABLE campaign_imports;
@@ -0,0 +1,25 @@
+-- migrate:up
+-- Tags are shared by the whole workspace; campaigns stay owner scoped.
+CREATE TABLE tags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(7) NOT NULL DEFAULT '#6B7280' CHECK (color ~ '^#[0-9A-F]{6}$'),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_tags_name ON tags(lower(name));
+
+CREATE TABLE campaign_tags (
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (campaign_id, tag_id)
+);
+
+CREATE INDEX idx_campaign_tags_tag_id ON campaign_tags(tag_id);
+
+-- migrate:down
+DROP TABLE campaign_tags;
+DROP TABLE tags;
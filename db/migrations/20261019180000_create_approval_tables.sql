@@ -0,0 +1,49 @@
+-- migrate:up
+-- A policy requires campaigns with at least min_budget to pass the approver
+-- chain before going live. The policy with the highest matching min_budget
+-- wins; applies_to_roles limits it to campaigns owned by those roles.
+CREATE TABLE approval_policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    min_budget DECIMAL(15, 2) NOT NULL CHECK (min_budget >= 0),
+    approver_roles TEXT[] NOT NULL CHECK (cardinality(approver_roles) > 0),
+    applies_to_roles TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE campaign_approvals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    policy_id UUID REFERENCES approval_policies(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled')),
+    -- The budget and chain are copied at submission so later policy edits
+    -- do not change a request in flight.
+    budget DECIMAL(15, 2) NOT NULL,
+    approver_roles TEXT[] NOT NULL,
+    current_step INTEGER NOT NULL DEFAULT 0,
+    comment TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    decided_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_campaign_approvals_campaign_id ON campaign_approvals(campaign_id);
+CREATE INDEX idx_campaign_approvals_status ON campaign_approvals(status, created_at);
+CREATE UNIQUE INDEX idx_campaign_approvals_one_pending ON campaign_approvals(campaign_id) WHERE status = 'pending';
+
+CREATE TABLE approval_decisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    approval_id UUID NOT NULL REFERENCES campaign_approvals(id) ON DELETE CASCADE,
+    step INTEGER NOT NULL,
+    approver_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    decision VARCHAR(20) NOT NULL CHECK (decision IN ('approved', 'rejected')),
+    comment TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (approval_id, step)
+);
+
+-- migrate:down
+DROP TABLE approval_decisions;
+DROP TABLE campaign_approvals;
+DROP TABLE approval_policies;
@@ -0,0 +1,45 @@
+-- migrate:up
+CREATE TABLE campaign_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    -- parent_id is the comment being replied to and root_id the top-level
+    -- comment of the thread; both are NULL for top-level comments.
+    parent_id UUID REFERENCES campaign_comments(id) ON DELETE CASCADE,
+    root_id UUID REFERENCES campaign_comments(id) ON DELETE CASCADE,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    edited_at TIMESTAMP WITH TIME ZONE,
+    -- Deleted comments keep their place in the thread with an empty body.
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_campaign_comments_campaign_id ON campaign_comments(campaign_id, created_at) WHERE parent_id IS NULL;
+CREATE INDEX idx_campaign_comments_root_id ON campaign_comments(root_id, created_at);
+
+CREATE TABLE comment_mentions (
+    comment_id UUID NOT NULL REFERENCES campaign_comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (comment_id, user_id)
+);
+
+CREATE INDEX idx_comment_mentions_user_id ON comment_mentions(user_id);
+
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL CHECK (type IN ('mention', 'reply', 'comment')),
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    campaign_id UUID REFERENCES campaigns(id) ON DELETE CASCADE,
+    comment_id UUID REFERENCES campaign_comments(id) ON DELETE CASCADE,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- migrate:down
+DROP TABLE notifications;
+DROP TABLE comment_mentions;
+DROP TABLE campaign_comments;
@@ -0,0 +1,33 @@
+-- migrate:up
+-- assets holds one row per distinct file content; campaigns attach them
+-- through campaign_assets, so re-uploading the same file stores it once.
+CREATE TABLE assets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    sha256 CHAR(64) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+    mime_type VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('image', 'video', 'document')),
+    storage_key TEXT NOT NULL,
+    thumbnail_key TEXT,
+    width INTEGER,
+    height INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_assets_sha256 ON assets(sha256);
+
+CREATE TABLE campaign_assets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE RESTRICT,
+    filename VARCHAR(255) NOT NULL,
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (campaign_id, asset_id)
+);
+
+CREATE INDEX idx_campaign_assets_asset_id ON campaign_assets(asset_id);
+
+-- migrate:down
+DROP TABLE campaign_assets;
+DROP TABLE assets;
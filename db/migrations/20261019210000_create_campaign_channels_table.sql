@@ -0,0 +1,32 @@
+-- migrate:up
+-- A campaign may split its budget across channels; when it does, the
+-- allocations add up to the campaign budget.
+CREATE TABLE campaign_channels (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('search', 'social', 'email', 'display', 'video', 'affiliate', 'other')),
+    allocated_budget NUMERIC(15, 2) NOT NULL CHECK (allocated_budget >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'planned' CHECK (status IN ('planned', 'active', 'paused', 'completed')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (campaign_id, channel)
+);
+
+-- Metrics without a channel are unattributed. A channel with metrics cannot
+-- be removed; the check runs at the end of the statement so deleting the
+-- whole campaign still cascades.
+ALTER TABLE campaign_metrics ADD COLUMN channel_id UUID REFERENCES campaign_channels(id);
+
+ALTER TABLE campaign_metrics DROP CONSTRAINT campaign_metrics_campaign_id_recorded_at_key;
+ALTER TABLE campaign_metrics ADD CONSTRAINT campaign_metrics_campaign_id_channel_id_recorded_at_key
+    UNIQUE NULLS NOT DISTINCT (campaign_id, channel_id, recorded_at);
+
+CREATE INDEX idx_campaign_metrics_channel_id ON campaign_metrics(channel_id);
+
+-- migrate:down
+DELETE FROM campaign_metrics WHERE channel_id IS NOT NULL;
+DROP INDEX idx_campaign_metrics_channel_id;
+ALTER TABLE campaign_metrics DROP CONSTRAINT campaign_metrics_campaign_id_channel_id_recorded_at_key;
+ALTER TABLE campaign_metrics ADD CONSTRAINT campaign_metrics_campaign_id_recorded_at_key UNIQUE (campaign_id, recorded_at);
+ALTER TABLE campaign_metrics DROP COLUMN channel_id;
+DROP TABLE campaign_channels;
@@ -0,0 +1,29 @@
+-- migrate:up
+-- Segments are shared by the whole workspace. rules holds the canonical rule
+-- tree (see pkg/audience) and summary its rendering, kept for listings.
+CREATE TABLE audience_segments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    rules JSONB NOT NULL,
+    summary TEXT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_audience_segments_name ON audience_segments(lower(name));
+
+-- A segment in use cannot be deleted; detach it from its campaigns first.
+CREATE TABLE campaign_segments (
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    segment_id UUID NOT NULL REFERENCES audience_segments(id) ON DELETE RESTRICT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (campaign_id, segment_id)
+);
+
+CREATE INDEX idx_campaign_segments_segment_id ON campaign_segments(segment_id);
+
+-- migrate:down
+DROP TABLE campaign_segments;
+DROP TABLE audience_segments;
@@ -0,0 +1,35 @@
+-- migrate:up
+-- Experiment variants of a campaign. When a campaign has variants their
+-- traffic splits add up to 100 and exactly one of them is the control.
+CREATE TABLE campaign_variants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    traffic_split NUMERIC(5, 2) NOT NULL CHECK (traffic_split > 0 AND traffic_split <= 100),
+    is_control BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_campaign_variants_name ON campaign_variants(campaign_id, lower(name));
+CREATE UNIQUE INDEX idx_campaign_variants_control ON campaign_variants(campaign_id) WHERE is_control;
+
+-- Like channels, a variant with metrics cannot be removed. A metric row is
+-- one slice of a campaign: unattributed, per channel, per variant or both.
+ALTER TABLE campaign_metrics ADD COLUMN variant_id UUID REFERENCES campaign_variants(id);
+
+ALTER TABLE campaign_metrics DROP CONSTRAINT campaign_metrics_campaign_id_channel_id_recorded_at_key;
+ALTER TABLE campaign_metrics ADD CONSTRAINT campaign_metrics_slice_key
+    UNIQUE NULLS NOT DISTINCT (campaign_id, channel_id, variant_id, recorded_at);
+
+CREATE INDEX idx_campaign_metrics_variant_id ON campaign_metrics(variant_id);
+
+-- migrate:down
+DELETE FROM campaign_metrics WHERE variant_id IS NOT NULL;
+DROP INDEX idx_campaign_metrics_variant_id;
+ALTER TABLE campaign_metrics DROP CONSTRAINT campaign_metrics_slice_key;
+ALTER TABLE campaign_metrics ADD CONSTRAINT campaign_metrics_campaign_id_channel_id_recorded_at_key
+    UNIQUE NULLS NOT DISTINCT (campaign_id, channel_id, recorded_at);
+ALTER TABLE campaign_metrics DROP COLUMN variant_id;
+DROP TABLE campaign_variants;
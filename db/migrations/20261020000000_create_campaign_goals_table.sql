@@ -0,0 +1,34 @@
+-- migrate:up
+-- A goal is a target for one campaign metric by a deadline. Counts and
+-- revenue aim for at least the target, costs for at most. achieved_at and
+-- missed_at are set once, when the goal is decided.
+CREATE TABLE campaign_goals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    metric VARCHAR(20) NOT NULL CHECK (metric IN ('impressions', 'clicks', 'conversions', 'spend', 'revenue', 'ctr', 'cpc', 'cpa', 'cpm', 'conversion_rate', 'roas')),
+    direction VARCHAR(10) NOT NULL CHECK (direction IN ('at_least', 'at_most')),
+    target NUMERIC(15, 4) NOT NULL CHECK (target > 0),
+    deadline TIMESTAMP WITH TIME ZONE NOT NULL,
+    achieved_at TIMESTAMP WITH TIME ZONE,
+    missed_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (campaign_id, metric)
+);
+
+CREATE INDEX idx_campaign_goals_open_deadline ON campaign_goals(deadline)
+    WHERE achieved_at IS NULL AND missed_at IS NULL;
+
+ALTER TABLE notifications ADD COLUMN goal_id UUID REFERENCES campaign_goals(id) ON DELETE CASCADE;
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('mention', 'reply', 'comment', 'goal_achieved'));
+
+-- migrate:down
+DELETE FROM notifications WHERE type = 'goal_achieved';
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('mention', 'reply', 'comment'));
+ALTER TABLE notifications DROP COLUMN goal_id;
+DROP TABLE campaign_goals;
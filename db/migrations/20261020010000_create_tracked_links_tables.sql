@@ -0,0 +1,41 @@
+-- migrate:up
+-- A tracked link redirects /r/{code} to its destination with the UTM
+-- parameters applied. channel and variant, when set, attribute its clicks.
+CREATE TABLE tracked_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    code VARCHAR(16) NOT NULL UNIQUE,
+    destination_url TEXT NOT NULL,
+    channel_id UUID REFERENCES campaign_channels(id) ON DELETE SET NULL,
+    variant_id UUID REFERENCES campaign_variants(id) ON DELETE SET NULL,
+    utm_source VARCHAR(100) NOT NULL,
+    utm_medium VARCHAR(100) NOT NULL,
+    utm_campaign VARCHAR(100) NOT NULL,
+    utm_term VARCHAR(100) NOT NULL DEFAULT '',
+    utm_content VARCHAR(100) NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_tracked_links_campaign_id ON tracked_links(campaign_id);
+
+-- One row per redirect. The IP is only kept as a keyed hash; visitor_id is
+-- the first-party cookie set by the redirect.
+CREATE TABLE link_clicks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    link_id UUID NOT NULL REFERENCES tracked_links(id) ON DELETE CASCADE,
+    clicked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    visitor_id UUID NOT NULL,
+    ip_hash VARCHAR(64) NOT NULL,
+    referrer TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    is_bot BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX idx_link_clicks_link_id ON link_clicks(link_id, clicked_at);
+CREATE INDEX idx_link_clicks_visitor_id ON link_clicks(visitor_id, clicked_at);
+
+-- migrate:down
+DROP TABLE link_clicks;
+DROP TABLE tracked_links;
@@ -0,0 +1,28 @@
+-- migrate:up
+-- Conversions reported by the tracking pixel or a server-to-server
+-- postback. A conversion is tied to the tracked link click that led to it,
+-- when known, and through it to the campaign. A transaction ID is unique
+-- per campaign, whichever source reports it, so an order seen by both the
+-- pixel and a postback is only counted once, while pixel hits cannot claim
+-- the IDs of other advertisers' campaigns.
+CREATE TABLE conversions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID REFERENCES campaigns(id) ON DELETE CASCADE,
+    link_id UUID REFERENCES tracked_links(id) ON DELETE SET NULL,
+    click_id UUID REFERENCES link_clicks(id) ON DELETE SET NULL,
+    visitor_id UUID,
+    transaction_id VARCHAR(100),
+    value NUMERIC(15, 2) NOT NULL DEFAULT 0 CHECK (value >= 0),
+    currency CHAR(3) NOT NULL,
+    source VARCHAR(10) NOT NULL CHECK (source IN ('pixel', 'postback')),
+    converted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_conversions_campaign_id ON conversions(campaign_id, converted_at);
+CREATE INDEX idx_conversions_visitor_id ON conversions(visitor_id, converted_at);
+CREATE UNIQUE INDEX idx_conversions_transaction ON conversions(campaign_id, transaction_id) NULLS NOT DISTINCT
+    WHERE transaction_id IS NOT NULL;
+
+-- migrate:down
+DROP TABLE conversions;
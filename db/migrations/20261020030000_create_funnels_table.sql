@@ -0,0 +1,39 @@
+-- migrate:up
+-- Conversions name the event they report, e.g. signup or purchase. Only the
+-- default event, conversion, counts in campaign metrics and attribution;
+-- the others are steps for funnels. click is reserved for link clicks.
+ALTER TABLE conversions ADD COLUMN event VARCHAR(50) NOT NULL DEFAULT 'conversion'
+    CHECK (event ~ '^[a-z0-9_]+$' AND event <> 'click');
+
+-- Everything a visitor did: clicks by people on tracked links and the
+-- conversion events reported for them.
+CREATE VIEW visitor_events AS
+SELECT k.visitor_id, 'click'::varchar AS event, k.clicked_at AS occurred_at, l.campaign_id
+FROM link_clicks k
+JOIN tracked_links l ON l.id = k.link_id
+WHERE NOT k.is_bot
+UNION ALL
+SELECT cv.visitor_id, cv.event, cv.converted_at AS occurred_at, cv.campaign_id
+FROM conversions cv
+WHERE cv.visitor_id IS NOT NULL;
+
+-- Funnels are shared by the whole workspace. steps are event names in the
+-- order visitors are expected to go through them, within window_days of
+-- entering the funnel.
+CREATE TABLE funnels (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    steps TEXT[] NOT NULL CHECK (cardinality(steps) BETWEEN 2 AND 10),
+    window_days INTEGER NOT NULL DEFAULT 30 CHECK (window_days BETWEEN 1 AND 90),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_funnels_name ON funnels(lower(name));
+
+-- migrate:down
+DROP TABLE funnels;
+DROP VIEW visitor_events;
+ALTER TABLE conversions DROP COLUMN event;
@@ -0,0 +1,96 @@
+-- migrate:up
+-- Tag names are searchable along with the title and description, so a
+-- campaign's document is rebuilt when its tags or their names change.
+CREATE FUNCTION campaign_search_document(title TEXT, description TEXT, tags TEXT) RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+           setweight(to_tsvector('english', COALESCE(tags, '')), 'B') ||
+           setweight(to_tsvector('english', COALESCE(description, '')), 'B');
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE FUNCTION refresh_campaign_search_document(target_id UUID) RETURNS VOID AS $$
+    INSERT INTO campaign_search_index (campaign_id, document, updated_at)
+    SELECT
+        c.id,
+        campaign_search_document(c.title, c.description, (
+            SELECT string_agg(t.name, ' ')
+            FROM campaign_tags ct
+            JOIN tags t ON t.id = ct.tag_id
+            WHERE ct.campaign_id = c.id
+        )),
+        NOW()
+    FROM campaigns c
+    WHERE c.id = target_id
+    ON CONFLICT (campaign_id) DO UPDATE
+    SET document = EXCLUDED.document,
+        updated_at = EXCLUDED.updated_at;
+$$ LANGUAGE sql;
+
+CREATE OR REPLACE FUNCTION refresh_campaign_search_index() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_campaign_search_document(NEW.id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION campaign_search_document(TEXT, TEXT);
+
+CREATE FUNCTION refresh_campaign_tag_search_index() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM refresh_campaign_search_document(OLD.campaign_id);
+        RETURN OLD;
+    END IF;
+    PERFORM refresh_campaign_search_document(NEW.campaign_id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_campaign_tags_search_index
+    AFTER INSERT OR DELETE ON campaign_tags
+    FOR EACH ROW EXECUTE FUNCTION refresh_campaign_tag_search_index();
+
+CREATE FUNCTION refresh_tag_search_index() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_campaign_search_document(ct.campaign_id)
+    FROM campaign_tags ct
+    WHERE ct.tag_id = NEW.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_tags_search_index
+    AFTER UPDATE OF name ON tags
+    FOR EACH ROW EXECUTE FUNCTION refresh_tag_search_index();
+
+SELECT refresh_campaign_search_document(id) FROM campaigns;
+
+-- migrate:down
+DROP TRIGGER trg_tags_search_index ON tags;
+DROP FUNCTION refresh_tag_search_index();
+DROP TRIGGER trg_campaign_tags_search_index ON campaign_tags;
+DROP FUNCTION refresh_campaign_tag_search_index();
+
+CREATE FUNCTION campaign_search_document(title TEXT, description TEXT) RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+           setweight(to_tsvector('english', COALESCE(description, '')), 'B');
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION refresh_campaign_search_index() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO campaign_search_index (campaign_id, document, updated_at)
+    VALUES (NEW.id, campaign_search_document(NEW.title, NEW.description), NOW())
+    ON CONFLICT (campaign_id) DO UPDATE
+    SET document = EXCLUDED.document,
+        updated_at = EXCLUDED.updated_at;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION refresh_campaign_search_document(UUID);
+DROP FUNCTION campaign_search_document(TEXT, TEXT, TEXT);
+
+UPDATE campaign_search_index s
+SET document = campaign_search_document(c.title, c.description),
+    updated_at = NOW()
+FROM campaigns c
+WHERE c.id = s.campaign_id;
@@ -0,0 +1,126 @@
+-- name: CreateApprovalPolicy :one
+INSERT INTO approval_policies (name, min_budget, approver_roles, applies_to_roles)
+VALUES (@name, @min_budget, @approver_roles::text[], @applies_to_roles::text[])
+RETURNING *;
+
+-- name: ListApprovalPolicies :many
+SELECT * FROM approval_policies
+ORDER BY min_budget, created_at;
+
+-- name: UpdateApprovalPolicy :one
+UPDATE approval_policies
+SET
+    name = @name,
+    min_budget = @min_budget,
+    approver_roles = @approver_roles::text[],
+    applies_to_roles = @applies_to_roles::text[],
+    updated_at = NOW()
+WHERE id = @id
+RETURNING *;
+
+-- name: DeleteApprovalPolicy :execrows
+DELETE FROM approval_policies
+WHERE id = $1;
+
+-- name: GetApplicableApprovalPolicy :one
+SELECT * FROM approval_policies
+WHERE min_budget <= @budget::float8
+  AND (cardinality(applies_to_roles) = 0 OR @owner_role::text = ANY(applies_to_roles))
+ORDER BY min_budget DESC, created_at
+LIMIT 1;
+
+-- name: GetUserRole :one
+SELECT role FROM users
+WHERE id = $1;
+
+-- name: CreateCampaignApproval :one
+INSERT INTO campaign_approvals (campaign_id, requested_by, policy_id, budget, approver_roles, comment)
+VALUES (@campaign_id, @requested_by, @policy_id, @budget, @approver_roles::text[], sqlc.narg('comment'))
+RETURNING *;
+
+-- name: GetCampaignApproval :one
+SELECT * FROM campaign_approvals
+WHERE id = $1;
+
+-- name: GetCampaignApprovalForUpdate :one
+SELECT a.*, c.user_id AS campaign_owner_id
+FROM campaign_approvals a
+JOIN campaigns c ON c.id = a.campaign_id
+WHERE a.id = $1
+    FOR UPDATE OF a;
+
+-- name: GetPendingCampaignApproval :one
+SELECT * FROM campaign_approvals
+WHERE campaign_id = $1 AND status = 'pending';
+
+-- name: GetLatestApprovedBudget :one
+-- The largest budget ever approved for the campaign; launching with more
+-- than that needs a new approval.
+SELECT COALESCE(MAX(budget), -1)::float8
+FROM campaign_approvals
+WHERE campaign_id = $1 AND status = 'approved';
+
+-- name: ListCampaignApprovals :many
+SELECT * FROM campaign_approvals
+WHERE campaign_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListPendingApprovals :many
+-- The approval queue: pending requests whose current step any of the given
+-- roles may decide, oldest first. Requests the user made or owns are left out
+-- because they cannot decide them.
+SELECT a.*, c.title AS campaign_title, c.user_id AS campaign_owner_id
+FROM campaign_approvals a
+JOIN campaigns c ON c.id = a.campaign_id
+WHERE a.status = 'pending'
+  AND c.deleted_at IS NULL
+  AND a.approver_roles[a.current_step + 1] = ANY(@roles::text[])
+  AND c.user_id <> @user_id
+  AND a.requested_by IS DISTINCT FROM @user_id
+  AND (sqlc.narg('cursor_id')::uuid IS NULL
+       OR (a.created_at, a.id) > (@cursor_time::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY a.created_at, a.id
+LIMIT @row_limit;
+
+-- name: CountPendingApprovals :one
+SELECT COUNT(*)::bigint
+FROM campaign_approvals a
+JOIN campaigns c ON c.id = a.campaign_id
+WHERE a.status = 'pending'
+  AND c.deleted_at IS NULL
+  AND a.approver_roles[a.current_step + 1] = ANY(@roles::text[])
+  AND c.user_id <> @user_id
+  AND a.requested_by IS DISTINCT FROM @user_id;
+
+-- name: AdvanceCampaignApproval :one
+UPDATE campaign_approvals
+SET current_step = current_step + 1,
+    status = CASE WHEN current_step + 1 >= cardinality(approver_roles) THEN 'approved' ELSE status END,
+    decided_at = CASE WHEN current_step + 1 >= cardinality(approver_roles) THEN NOW() ELSE decided_at END
+WHERE id = $1
+RETURNING *;
+
+-- name: CloseCampaignApproval :one
+UPDATE campaign_approvals
+SET status = @status,
+    decided_at = NOW()
+WHERE id = @id
+RETURNING *;
+
+-- name: CreateApprovalDecision :one
+INSERT INTO approval_decisions (approval_id, step, approver_id, decision, comment)
+VALUES (@approval_id, @step, @approver_id, @decision, sqlc.narg('comment'))
+RETURNING *;
+
+-- name: HasApprovalDecisionBy :one
+SELECT EXISTS (
+    SELECT 1 FROM approval_decisions
+    WHERE approval_id = @approval_id AND approver_id = @approver_id
+)::boolean;
+
+-- name: ListApprovalDecisions :many
+SELECT d.*, u.email AS approver_email
+FROM approval_decisions d
+LEFT JOIN users u ON u.id = d.approver_id
+WHERE d.approval_id = ANY(@approval_ids::uuid[])
+ORDER BY d.approval_id, d.step;
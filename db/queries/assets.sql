@@ -0,0 +1,64 @@
+-- name: GetAssetBySHA256 :one
+SELECT * FROM assets
+WHERE sha256 = $1;
+
+-- name: UpsertAsset :one
+-- Returns the existing row when the content is already stored. The no-op
+-- update locks it, so a concurrent orphan sweep cannot delete it before the
+-- caller attaches it.
+INSERT INTO assets (id, sha256, size_bytes, mime_type, kind, storage_key, thumbnail_key, width, height)
+VALUES (@id, @sha256, @size_bytes, @mime_type, @kind, @storage_key, sqlc.narg('thumbnail_key'), sqlc.narg('width'), sqlc.narg('height'))
+ON CONFLICT (sha256) DO UPDATE SET sha256 = EXCLUDED.sha256
+RETURNING *;
+
+-- name: AttachCampaignAsset :one
+-- Returns no row when the asset is already attached to the campaign.
+INSERT INTO campaign_assets (campaign_id, asset_id, filename, uploaded_by)
+VALUES (@campaign_id, @asset_id, @filename, @uploaded_by)
+ON CONFLICT (campaign_id, asset_id) DO NOTHING
+RETURNING *;
+
+-- name: GetCampaignAsset :one
+SELECT
+    ca.id, ca.campaign_id, ca.asset_id, ca.filename, ca.uploaded_by, ca.created_at,
+    a.sha256, a.size_bytes, a.mime_type, a.kind, a.storage_key, a.thumbnail_key, a.width, a.height
+FROM campaign_assets ca
+JOIN assets a ON a.id = ca.asset_id
+WHERE ca.id = @id AND ca.campaign_id = @campaign_id;
+
+-- name: GetCampaignAssetByAsset :one
+SELECT
+    ca.id, ca.campaign_id, ca.asset_id, ca.filename, ca.uploaded_by, ca.created_at,
+    a.sha256, a.size_bytes, a.mime_type, a.kind, a.storage_key, a.thumbnail_key, a.width, a.height
+FROM campaign_assets ca
+JOIN assets a ON a.id = ca.asset_id
+WHERE ca.campaign_id = @campaign_id AND ca.asset_id = @asset_id;
+
+-- name: ListCampaignAssets :many
+SELECT
+    ca.id, ca.campaign_id, ca.asset_id, ca.filename, ca.uploaded_by, ca.created_at,
+    a.sha256, a.size_bytes, a.mime_type, a.kind, a.storage_key, a.thumbnail_key, a.width, a.height
+FROM campaign_assets ca
+JOIN assets a ON a.id = ca.asset_id
+WHERE ca.campaign_id = @campaign_id
+  AND (sqlc.narg('kind')::text IS NULL OR a.kind = sqlc.narg('kind'))
+ORDER BY ca.created_at DESC, ca.id DESC;
+
+-- name: DeleteCampaignAsset :one
+DELETE FROM campaign_assets
+WHERE id = @id AND campaign_id = @campaign_id
+RETURNING asset_id;
+
+-- name: DeleteAssetIfOrphaned :one
+-- Returns no row while another campaign still uses the asset.
+DELETE FROM assets a
+WHERE a.id = $1
+  AND NOT EXISTS (SELECT 1 FROM campaign_assets ca WHERE ca.asset_id = a.id)
+RETURNING *;
+
+-- name: DeleteOrphanedAssets :many
+-- Sweeps assets left behind when campaigns were purged from the trash.
+DELETE FROM assets a
+WHERE NOT EXISTS (SELECT 1 FROM campaign_assets ca WHERE ca.asset_id = a.id)
+  AND a.created_at < @created_before
+RETURNING *;
@@ -0,0 +1,42 @@
+-- name: ListAttributionTouches :many
+-- Conversion events in the window that a click on one of the user's live
+-- campaigns led to, each with every click by a person of the same visitor
+-- within the lookback before it, from first to last. Clicks on other
+-- campaigns are included as they share the credit.
+WITH conv AS (
+    SELECT cv.id, cv.visitor_id, cv.converted_at, cv.value, cv.currency
+    FROM conversions cv
+    WHERE cv.visitor_id IS NOT NULL
+      AND cv.event = 'conversion'
+      AND cv.converted_at >= @from_time
+      AND cv.converted_at < @to_time
+)
+SELECT
+    conv.id AS conversion_id,
+    conv.converted_at,
+    conv.value,
+    conv.currency,
+    k.clicked_at,
+    l.campaign_id,
+    c.title AS campaign_title,
+    (c.user_id = @user_id AND c.deleted_at IS NULL)::boolean AS owned
+FROM conv
+JOIN link_clicks k ON k.visitor_id = conv.visitor_id
+    AND NOT k.is_bot
+    AND k.clicked_at <= conv.converted_at
+    AND k.clicked_at >= conv.converted_at - make_interval(secs => @lookback_seconds::float8)
+JOIN tracked_links l ON l.id = k.link_id
+JOIN campaigns c ON c.id = l.campaign_id
+WHERE EXISTS (
+    SELECT 1
+    FROM link_clicks mk
+    JOIN tracked_links ml ON ml.id = mk.link_id
+    JOIN campaigns mc ON mc.id = ml.campaign_id
+    WHERE mk.visitor_id = conv.visitor_id
+      AND NOT mk.is_bot
+      AND mk.clicked_at <= conv.converted_at
+      AND mk.clicked_at >= conv.converted_at - make_interval(secs => @lookback_seconds::float8)
+      AND mc.user_id = @user_id
+      AND mc.deleted_at IS NULL
+)
+ORDER BY conv.converted_at, conv.id, k.clicked_at, k.id;
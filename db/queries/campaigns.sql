@@ -3,19 +3,104 @@ INSERT INTO campaigns (
     user_id, title, description, status, start_date, end_date, budget
 ) VALUES (
              $1, $2, $3, $4, $5, $6, $7
-         ) RETURNING id, user_id, title, description, status, budget, created_at;
+         ) RETURNING *;
 
 -- name: GetCampaign :one
 SELECT * FROM campaigns
-WHERE id = $1 AND user_id = $2
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
     LIMIT 1;
 
+-- name: GetCampaignForUpdate :one
+SELECT * FROM campaigns
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+    FOR UPDATE;
+
 -- name: ListCampaigns :many
-SELECT id, user_id, title, description, status, start_date, end_date, budget, created_at
+WITH filtered AS (
+    SELECT
+        id, user_id, title, description, status, start_date, end_date, budget, created_at, version,
+        (CASE @sort_by::text
+            WHEN 'created_at' THEN created_at
+            WHEN 'start_date' THEN start_date
+            WHEN 'end_date' THEN end_date
+        END)::timestamptz AS sort_time_raw,
+        (CASE WHEN @sort_by::text = 'budget' THEN budget ELSE 0 END)::float8 AS sort_number,
+        (CASE @sort_by::text
+            WHEN 'title' THEN title
+            WHEN 'status' THEN status
+            ELSE ''
+        END)::text AS sort_text
+    FROM campaigns
+    WHERE user_id = @user_id
+      AND deleted_at IS NULL
+      AND (COALESCE(cardinality(@statuses::text[]), 0) = 0 OR status = ANY(@statuses::text[]))
+      AND (sqlc.narg('active_to')::timestamptz IS NULL OR start_date IS NULL OR start_date <= sqlc.narg('active_to'))
+      AND (sqlc.narg('active_from')::timestamptz IS NULL OR end_date IS NULL OR end_date >= sqlc.narg('active_from'))
+      AND (sqlc.narg('min_budget')::float8 IS NULL OR budget >= sqlc.narg('min_budget'))
+      AND (sqlc.narg('max_budget')::float8 IS NULL OR budget <= sqlc.narg('max_budget'))
+      AND (sqlc.narg('search')::text IS NULL OR title ILIKE sqlc.narg('search') OR description ILIKE sqlc.narg('search'))
+      AND (COALESCE(cardinality(@tag_ids::uuid[]), 0) = 0 OR (
+          SELECT COUNT(*) FROM campaign_tags ct
+          WHERE ct.campaign_id = campaigns.id AND ct.tag_id = ANY(@tag_ids::uuid[])
+      ) >= CASE WHEN @tag_match_all::boolean THEN cardinality(@tag_ids::uuid[]) ELSE 1 END)
+), keyed AS (
+    -- Missing dates sort last in either direction.
+    SELECT filtered.*,
+           COALESCE(sort_time_raw, CASE WHEN @sort_order::text = 'asc'
+               THEN '9999-12-31 00:00:00+00'::timestamptz
+               ELSE '0001-01-01 00:00:00+00'::timestamptz
+           END)::timestamptz AS sort_time
+    FROM filtered
+)
+SELECT id, user_id, title, description, status, start_date, end_date, budget, created_at, version, sort_time, sort_number, sort_text
+FROM keyed
+WHERE sqlc.narg('cursor_id')::uuid IS NULL
+   OR (@scan_order::text = 'asc' AND (sort_time, sort_number, sort_text, id) > (@cursor_time::timestamptz, @cursor_number::float8, @cursor_text::text, sqlc.narg('cursor_id')::uuid))
+   OR (@scan_order::text = 'desc' AND (sort_time, sort_number, sort_text, id) < (@cursor_time::timestamptz, @cursor_number::float8, @cursor_text::text, sqlc.narg('cursor_id')::uuid))
+ORDER BY
+    CASE WHEN @scan_order::text = 'asc' THEN sort_time END ASC,
+    CASE WHEN @scan_order::text = 'asc' THEN sort_number END ASC,
+    CASE WHEN @scan_order::text = 'asc' THEN sort_text END ASC,
+    CASE WHEN @scan_order::text = 'asc' THEN id END ASC,
+    CASE WHEN @scan_order::text = 'desc' THEN sort_time END DESC,
+    CASE WHEN @scan_order::text = 'desc' THEN sort_number END DESC,
+    CASE WHEN @scan_order::text = 'desc' THEN sort_text END DESC,
+    CASE WHEN @scan_order::text = 'desc' THEN id END DESC
+LIMIT @row_limit;
+
+-- name: CountCampaigns :one
+SELECT COUNT(*)::bigint
 FROM campaigns
-WHERE user_id = $1
-ORDER BY created_at DESC
-    LIMIT $2 OFFSET $3;
+WHERE user_id = @user_id
+  AND deleted_at IS NULL
+  AND (COALESCE(cardinality(@statuses::text[]), 0) = 0 OR status = ANY(@statuses::text[]))
+  AND (sqlc.narg('active_to')::timestamptz IS NULL OR start_date IS NULL OR start_date <= sqlc.narg('active_to'))
+  AND (sqlc.narg('active_from')::timestamptz IS NULL OR end_date IS NULL OR end_date >= sqlc.narg('active_from'))
+  AND (sqlc.narg('min_budget')::float8 IS NULL OR budget >= sqlc.narg('min_budget'))
+  AND (sqlc.narg('max_budget')::float8 IS NULL OR budget <= sqlc.narg('max_budget'))
+  AND (sqlc.narg('search')::text IS NULL OR title ILIKE sqlc.narg('search') OR description ILIKE sqlc.narg('search'))
+  AND (COALESCE(cardinality(@tag_ids::uuid[]), 0) = 0 OR (
+      SELECT COUNT(*) FROM campaign_tags ct
+      WHERE ct.campaign_id = campaigns.id AND ct.tag_id = ANY(@tag_ids::uuid[])
+  ) >= CASE WHEN @tag_match_all::boolean THEN cardinality(@tag_ids::uuid[]) ELSE 1 END);
+
+-- name: ListCampaignIDs :many
+SELECT id
+FROM campaigns
+WHERE user_id = @user_id
+  AND deleted_at IS NULL
+  AND (COALESCE(cardinality(@statuses::text[]), 0) = 0 OR status = ANY(@statuses::text[]))
+  AND (sqlc.narg('active_to')::timestamptz IS NULL OR start_date IS NULL OR start_date <= sqlc.narg('active_to'))
+  AND (sqlc.narg('active_from')::timestamptz IS NULL OR end_date IS NULL OR end_date >= sqlc.narg('active_from'))
+  AND (sqlc.narg('min_budget')::float8 IS NULL OR budget >= sqlc.narg('min_budget'))
+  AND (sqlc.narg('max_budget')::float8 IS NULL OR budget <= sqlc.narg('max_budget'))
+  AND (sqlc.narg('search')::text IS NULL OR title ILIKE sqlc.narg('search') OR description ILIKE sqlc.narg('search'))
+  AND (COALESCE(cardinality(@tag_ids::uuid[]), 0) = 0 OR (
+      SELECT COUNT(*) FROM campaign_tags ct
+      WHERE ct.campaign_id = campaigns.id AND ct.tag_id = ANY(@tag_ids::uuid[])
+  ) >= CASE WHEN @tag_match_all::boolean THEN cardinality(@tag_ids::uuid[]) ELSE 1 END)
+ORDER BY id
+LIMIT @row_limit;
 
 -- name: UpdateCampaign :one
 UPDATE campaigns
@@ -26,10 +111,53 @@ SET
     start_date = COALESCE(sqlc.narg('start_date'), start_date),
     end_date = COALESCE(sqlc.narg('end_date'), end_date),
     budget = COALESCE(sqlc.narg('budget'), budget),
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+    RETURNING *;
+
+-- name: SetCampaignFields :one
+UPDATE campaigns
+SET
+    title = @title,
+    description = sqlc.narg('description'),
+    status = @status,
+    start_date = sqlc.narg('start_date'),
+    end_date = sqlc.narg('end_date'),
+    budget = @budget,
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = @id AND user_id = @user_id AND deleted_at IS NULL
+    RETURNING *;
+
+-- name: DeleteCampaign :one
+UPDATE campaigns
+SET deleted_at = NOW(),
+    version = version + 1
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+    RETURNING *;
+
+-- name: ListDeletedCampaigns :many
+SELECT * FROM campaigns
+WHERE user_id = @user_id
+  AND deleted_at IS NOT NULL
+  AND (sqlc.narg('cursor_id')::uuid IS NULL OR (deleted_at, id) < (@cursor_time::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY deleted_at DESC, id DESC
+LIMIT @row_limit;
+
+-- name: CountDeletedCampaigns :one
+SELECT COUNT(*)::bigint
+FROM campaigns
+WHERE user_id = $1 AND deleted_at IS NOT NULL;
+
+-- name: RestoreCampaign :one
+UPDATE campaigns
+SET deleted_at = NULL,
+    version = version + 1,
     updated_at = NOW()
-WHERE id = $1 AND user_id = $2
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
     RETURNING *;
 
--- name: DeleteCampaign :exec
+-- name: PurgeDeletedCampaigns :execrows
 DELETE FROM campaigns
-WHERE id = $1 AND user_id = $2;
\ No newline at end of file
+WHERE deleted_at IS NOT NULL AND deleted_at < @cutoff::timestamptz;
\ No newline at end of file
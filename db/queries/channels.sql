@@ -0,0 +1,88 @@
+-- name: ListCampaignChannels :many
+SELECT * FROM campaign_channels
+WHERE campaign_id = $1
+ORDER BY allocated_budget DESC, channel;
+
+-- name: GetCampaignChannelAllocation :one
+SELECT
+    COUNT(*)::bigint AS channels,
+    COALESCE(SUM(allocated_budget), 0)::float8 AS allocated
+FROM campaign_channels
+WHERE campaign_id = $1;
+
+-- name: UpsertCampaignChannel :one
+INSERT INTO campaign_channels (campaign_id, channel, allocated_budget, status)
+VALUES (@campaign_id, @channel, @allocated_budget, @status)
+ON CONFLICT (campaign_id, channel) DO UPDATE
+SET
+    allocated_budget = EXCLUDED.allocated_budget,
+    status = EXCLUDED.status,
+    updated_at = NOW()
+WHERE (campaign_channels.allocated_budget, campaign_channels.status) IS DISTINCT FROM (EXCLUDED.allocated_budget, EXCLUDED.status)
+RETURNING *;
+
+-- name: ListRemovedChannelsWithMetrics :many
+-- Channels that replacing the set with keep_channels would remove but that
+-- already have metrics recorded against them.
+SELECT ch.channel
+FROM campaign_channels ch
+WHERE ch.campaign_id = @campaign_id
+  AND NOT (ch.channel = ANY(@keep_channels::text[]))
+  AND EXISTS (SELECT 1 FROM campaign_metrics m WHERE m.channel_id = ch.id)
+ORDER BY ch.channel;
+
+-- name: RemoveOtherCampaignChannels :execrows
+DELETE FROM campaign_channels
+WHERE campaign_id = @campaign_id
+  AND NOT (channel = ANY(@keep_channels::text[]));
+
+-- name: UpdateCampaignChannelStatus :one
+UPDATE campaign_channels
+SET status = @status,
+    updated_at = NOW()
+WHERE campaign_id = @campaign_id AND channel = @channel
+RETURNING *;
+
+-- name: GetCampaignChannelTotals :many
+-- Lifetime totals per channel; the row with a NULL channel_id holds the
+-- unattributed metrics.
+SELECT
+    channel_id,
+    COALESCE(SUM(impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(spend), 0)::float8 AS spend,
+    COALESCE(SUM(revenue), 0)::float8 AS revenue
+FROM campaign_metrics
+WHERE campaign_id = $1
+GROUP BY channel_id;
+
+-- name: GetPortfolioChannelAllocations :many
+SELECT
+    ch.channel,
+    COUNT(*)::bigint AS campaigns,
+    COALESCE(SUM(ch.allocated_budget), 0)::float8 AS allocated
+FROM campaign_channels ch
+JOIN campaigns c ON c.id = ch.campaign_id
+WHERE c.user_id = @user_id
+  AND c.deleted_at IS NULL
+GROUP BY ch.channel;
+
+-- name: GetPortfolioChannelTotals :many
+-- Metrics in the window per channel type; unattributed metrics come back
+-- with a NULL channel.
+SELECT
+    ch.channel,
+    COALESCE(SUM(m.impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(m.clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(m.conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(m.spend), 0)::float8 AS spend,
+    COALESCE(SUM(m.revenue), 0)::float8 AS revenue
+FROM campaign_metrics m
+JOIN campaigns c ON c.id = m.campaign_id
+LEFT JOIN campaign_channels ch ON ch.id = m.channel_id
+WHERE c.user_id = @user_id
+  AND c.deleted_at IS NULL
+  AND m.recorded_at >= @from_time::timestamptz
+  AND m.recorded_at < @to_time::timestamptz
+GROUP BY ch.channel;
@@ -0,0 +1,37 @@
+-- name: ListCohortEvents :many
+-- Visitors whose first click by a person on the user's live campaigns was in
+-- the window, with everything they did on the user's campaigns (or, for
+-- conversions without a click, on their own) from then until the given
+-- time, in time order. Clicks on other users' campaigns do not count as a
+-- first touch.
+WITH first_touch AS (
+    SELECT DISTINCT ON (k.visitor_id) k.visitor_id, k.clicked_at, l.campaign_id, c.title AS campaign_title
+    FROM link_clicks k
+    JOIN tracked_links l ON l.id = k.link_id
+    JOIN campaigns c ON c.id = l.campaign_id
+    WHERE NOT k.is_bot
+      AND c.user_id = @user_id::uuid
+      AND c.deleted_at IS NULL
+    ORDER BY k.visitor_id, k.clicked_at, k.id
+)
+SELECT
+    ft.visitor_id,
+    ft.campaign_id,
+    ft.campaign_title,
+    ft.clicked_at AS first_touch_at,
+    e.event,
+    e.occurred_at
+FROM first_touch ft
+JOIN visitor_events e ON e.visitor_id = ft.visitor_id
+    AND e.occurred_at >= ft.clicked_at
+    AND e.occurred_at < @until_time::timestamptz
+    AND (e.campaign_id IS NULL OR EXISTS (
+        SELECT 1 FROM campaigns oc
+        WHERE oc.id = e.campaign_id
+          AND oc.user_id = @user_id::uuid
+          AND oc.deleted_at IS NULL
+    ))
+WHERE ft.clicked_at >= @from_time::timestamptz
+  AND ft.clicked_at < @to_time::timestamptz
+  AND (sqlc.narg('campaign_id')::uuid IS NULL OR ft.campaign_id = sqlc.narg('campaign_id'))
+ORDER BY ft.visitor_id, e.occurred_at;
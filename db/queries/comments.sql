@@ -0,0 +1,101 @@
+-- name: GetDiscussableCampaign :one
+-- A campaign's discussion is open to its owner, to managers and admins
+-- (can_view_all) and to anyone mentioned in it.
+SELECT c.id, c.user_id, c.title
+FROM campaigns c
+WHERE c.id = @campaign_id
+  AND c.deleted_at IS NULL
+  AND (@can_view_all::boolean
+       OR c.user_id = @user_id
+       OR EXISTS (
+           SELECT 1 FROM comment_mentions m
+           JOIN campaign_comments cc ON cc.id = m.comment_id
+           WHERE cc.campaign_id = c.id AND m.user_id = @user_id
+       ));
+
+-- name: CreateComment :one
+INSERT INTO campaign_comments (campaign_id, parent_id, root_id, author_id, body)
+VALUES (@campaign_id, sqlc.narg('parent_id'), sqlc.narg('root_id'), @author_id, @body)
+RETURNING *;
+
+-- name: GetComment :one
+SELECT * FROM campaign_comments
+WHERE id = @id AND campaign_id = @campaign_id;
+
+-- name: GetCommentForUpdate :one
+SELECT * FROM campaign_comments
+WHERE id = @id AND campaign_id = @campaign_id
+    FOR UPDATE;
+
+-- name: UpdateCommentBody :one
+UPDATE campaign_comments
+SET body = @body,
+    edited_at = NOW()
+WHERE id = @id
+RETURNING *;
+
+-- name: SoftDeleteComment :one
+UPDATE campaign_comments
+SET body = '',
+    deleted_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListRootComments :many
+SELECT cc.*, u.full_name AS author_name, u.email AS author_email
+FROM campaign_comments cc
+LEFT JOIN users u ON u.id = cc.author_id
+WHERE cc.campaign_id = @campaign_id
+  AND cc.parent_id IS NULL
+  AND (sqlc.narg('cursor_id')::uuid IS NULL OR (cc.created_at, cc.id) > (@cursor_time::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY cc.created_at, cc.id
+LIMIT @row_limit;
+
+-- name: CountRootComments :one
+SELECT COUNT(*)::bigint
+FROM campaign_comments
+WHERE campaign_id = $1 AND parent_id IS NULL;
+
+-- name: ListCommentReplies :many
+SELECT cc.*, u.full_name AS author_name, u.email AS author_email
+FROM campaign_comments cc
+LEFT JOIN users u ON u.id = cc.author_id
+WHERE cc.root_id = ANY(@root_ids::uuid[])
+ORDER BY cc.created_at, cc.id;
+
+-- name: GetCommentWithAuthor :one
+SELECT cc.*, u.full_name AS author_name, u.email AS author_email
+FROM campaign_comments cc
+LEFT JOIN users u ON u.id = cc.author_id
+WHERE cc.id = $1;
+
+-- name: CountCampaignComments :many
+SELECT campaign_id, COUNT(*)::bigint AS comment_count
+FROM campaign_comments
+WHERE campaign_id = ANY(@campaign_ids::uuid[]) AND deleted_at IS NULL
+GROUP BY campaign_id;
+
+-- name: GetUsersByEmails :many
+SELECT id, full_name, email
+FROM users
+WHERE lower(email) = ANY(@emails::text[]);
+
+-- name: AddCommentMentions :many
+-- Returns only the users that were not mentioned before, so an edit
+-- notifies newly mentioned members once.
+INSERT INTO comment_mentions (comment_id, user_id)
+SELECT @comment_id::uuid, unnest(@user_ids::uuid[])
+ON CONFLICT DO NOTHING
+RETURNING user_id;
+
+-- name: RemoveOtherCommentMentions :exec
+DELETE FROM comment_mentions
+WHERE comment_id = @comment_id
+  AND NOT (user_id = ANY(@keep_user_ids::uuid[]));
+
+-- name: ListCommentMentions :many
+SELECT m.comment_id, u.id, u.full_name, u.email
+FROM comment_mentions m
+JOIN users u ON u.id = m.user_id
+WHERE m.comment_id = ANY(@comment_ids::uuid[])
+ORDER BY m.comment_id, lower(u.full_name);
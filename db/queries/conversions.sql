@@ -0,0 +1,46 @@
+-- name: CreateConversion :one
+-- Returns no row when the transaction ID was already recorded for the same
+-- campaign, by either source.
+INSERT INTO conversions (
+    campaign_id, link_id, click_id, visitor_id, transaction_id, event, value, currency, source, converted_at
+) VALUES (
+    @campaign_id, @link_id, @click_id, @visitor_id, @transaction_id, @event, @value, @currency, @source, @converted_at
+)
+ON CONFLICT (campaign_id, transaction_id) WHERE transaction_id IS NOT NULL DO NOTHING
+RETURNING *;
+
+-- name: GetConversionByTransaction :one
+SELECT * FROM conversions
+WHERE campaign_id IS NOT DISTINCT FROM sqlc.narg('campaign_id')::uuid
+  AND transaction_id = @transaction_id;
+
+-- name: AttachConversionClick :one
+-- Ties a conversion that has lost or never had its click to the click a
+-- signed postback reported for the same transaction.
+UPDATE conversions
+SET
+    link_id = @link_id,
+    click_id = @click_id,
+    visitor_id = COALESCE(visitor_id, @visitor_id)
+WHERE id = @id AND click_id IS NULL
+    RETURNING *;
+
+-- name: ListCampaignConversions :many
+-- Conversions of a campaign, newest first, with the link that led to them.
+SELECT
+    cv.*,
+    l.code AS link_code,
+    ch.channel,
+    v.name AS variant_name
+FROM conversions cv
+LEFT JOIN tracked_links l ON l.id = cv.link_id
+LEFT JOIN campaign_channels ch ON ch.id = l.channel_id
+LEFT JOIN campaign_variants v ON v.id = l.variant_id
+WHERE cv.campaign_id = @campaign_id
+  AND (sqlc.narg('cursor_id')::uuid IS NULL OR (cv.converted_at, cv.id) < (@cursor_time::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY cv.converted_at DESC, cv.id DESC
+LIMIT @row_limit;
+
+-- name: CountCampaignConversions :one
+SELECT COUNT(*)::bigint FROM conversions
+WHERE campaign_id = @campaign_id;
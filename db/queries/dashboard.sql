@@ -0,0 +1,66 @@
+-- name: CountCampaignsByStatus :many
+SELECT status, COUNT(*)::bigint AS total
+FROM campaigns
+WHERE user_id = $1 AND deleted_at IS NULL
+GROUP BY status
+ORDER BY status;
+
+-- name: GetBudgetSummary :one
+SELECT
+    COUNT(*)::bigint AS total_campaigns,
+    COALESCE(SUM(c.budget), 0)::float8 AS total_budget,
+    COALESCE(SUM(s.spend), 0)::float8 AS total_spend,
+    COALESCE(SUM(GREATEST(c.budget - COALESCE(s.spend, 0), 0)), 0)::float8 AS remaining_budget
+FROM campaigns c
+LEFT JOIN (
+    SELECT campaign_id, SUM(spend) AS spend
+    FROM campaign_metrics
+    GROUP BY campaign_id
+) s ON s.campaign_id = c.id
+WHERE c.user_id = $1 AND c.deleted_at IS NULL;
+
+-- name: ListCampaignsStartingBetween :many
+SELECT id, title, status, start_date, end_date, budget
+FROM campaigns
+WHERE user_id = @user_id
+  AND deleted_at IS NULL
+  AND start_date >= @from_time::timestamptz
+  AND start_date < @to_time::timestamptz
+ORDER BY start_date
+LIMIT @row_limit;
+
+-- name: ListCampaignsEndingBetween :many
+SELECT id, title, status, start_date, end_date, budget
+FROM campaigns
+WHERE user_id = @user_id
+  AND deleted_at IS NULL
+  AND end_date >= @from_time::timestamptz
+  AND end_date < @to_time::timestamptz
+ORDER BY end_date
+LIMIT @row_limit;
+
+-- name: ListTopCampaigns :many
+SELECT
+    c.id,
+    c.title,
+    c.status,
+    COALESCE(SUM(m.impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(m.clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(m.conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(m.spend), 0)::float8 AS spend,
+    COALESCE(SUM(m.revenue), 0)::float8 AS revenue
+FROM campaigns c
+JOIN campaign_metrics m ON m.campaign_id = c.id
+WHERE c.user_id = @user_id
+  AND c.deleted_at IS NULL
+  AND m.recorded_at >= @since::timestamptz
+GROUP BY c.id, c.title, c.status
+ORDER BY revenue DESC, conversions DESC, c.id
+LIMIT @row_limit;
+
+-- name: ListRecentCampaignActivity :many
+SELECT id, title, status, created_at, updated_at
+FROM campaigns
+WHERE user_id = @user_id AND deleted_at IS NULL
+ORDER BY updated_at DESC NULLS LAST
+LIMIT @row_limit;
@@ -0,0 +1,85 @@
+-- name: ExportCampaigns :many
+-- Export queries are streamed through a server-side cursor (see
+-- internal/db/cursor.go), so they have no LIMIT.
+SELECT
+    c.id, c.title, c.description, c.status, c.start_date, c.end_date, c.budget, c.version, c.created_at, c.updated_at,
+    COALESCE(m.impressions, 0)::bigint AS impressions,
+    COALESCE(m.clicks, 0)::bigint AS clicks,
+    COALESCE(m.conversions, 0)::bigint AS conversions,
+    COALESCE(m.spend, 0)::float8 AS spend,
+    COALESCE(m.revenue, 0)::float8 AS revenue,
+    COALESCE((
+        SELECT string_agg(t.name, ', ' ORDER BY lower(t.name))
+        FROM campaign_tags ct
+        JOIN tags t ON t.id = ct.tag_id
+        WHERE ct.campaign_id = c.id
+    ), '')::text AS tags
+FROM campaigns c
+LEFT JOIN (
+    SELECT campaign_id,
+           SUM(impressions) AS impressions,
+           SUM(clicks) AS clicks,
+           SUM(conversions) AS conversions,
+           SUM(spend) AS spend,
+           SUM(revenue) AS revenue
+    FROM campaign_metrics
+    GROUP BY campaign_id
+) m ON m.campaign_id = c.id
+WHERE c.user_id = @user_id
+  AND c.deleted_at IS NULL
+  AND (COALESCE(cardinality(@statuses::text[]), 0) = 0 OR c.status = ANY(@statuses::text[]))
+  AND (sqlc.narg('active_to')::timestamptz IS NULL OR c.start_date IS NULL OR c.start_date <= sqlc.narg('active_to'))
+  AND (sqlc.narg('active_from')::timestamptz IS NULL OR c.end_date IS NULL OR c.end_date >= sqlc.narg('active_from'))
+  AND (sqlc.narg('min_budget')::float8 IS NULL OR c.budget >= sqlc.narg('min_budget'))
+  AND (sqlc.narg('max_budget')::float8 IS NULL OR c.budget <= sqlc.narg('max_budget'))
+  AND (sqlc.narg('search')::text IS NULL OR c.title ILIKE sqlc.narg('search') OR c.description ILIKE sqlc.narg('search'))
+  AND (COALESCE(cardinality(@tag_ids::uuid[]), 0) = 0 OR (
+      SELECT COUNT(*) FROM campaign_tags ct
+      WHERE ct.campaign_id = c.id AND ct.tag_id = ANY(@tag_ids::uuid[])
+  ) >= CASE WHEN @tag_match_all::boolean THEN cardinality(@tag_ids::uuid[]) ELSE 1 END)
+ORDER BY
+    CASE WHEN @sort_order::text = 'asc' THEN
+        (CASE @sort_by::text
+            WHEN 'start_date' THEN c.start_date
+            WHEN 'end_date' THEN c.end_date
+            WHEN 'created_at' THEN c.created_at
+        END)
+    END ASC NULLS LAST,
+    CASE WHEN @sort_order::text = 'desc' THEN
+        (CASE @sort_by::text
+            WHEN 'start_date' THEN c.start_date
+            WHEN 'end_date' THEN c.end_date
+            WHEN 'created_at' THEN c.created_at
+        END)
+    END DESC NULLS LAST,
+    CASE WHEN @sort_order::text = 'asc' AND @sort_by::text = 'budget' THEN c.budget END ASC,
+    CASE WHEN @sort_order::text = 'desc' AND @sort_by::text = 'budget' THEN c.budget END DESC,
+    CASE WHEN @sort_order::text = 'asc' THEN
+        (CASE @sort_by::text WHEN 'title' THEN c.title WHEN 'status' THEN c.status END)
+    END ASC,
+    CASE WHEN @sort_order::text = 'desc' THEN
+        (CASE @sort_by::text WHEN 'title' THEN c.title WHEN 'status' THEN c.status END)
+    END DESC,
+    c.id;
+
+-- name: ExportCampaignMetricSeries :many
+SELECT
+    (date_trunc(@bucket::text, m.recorded_at AT TIME ZONE @time_zone::text) AT TIME ZONE @time_zone::text)::timestamptz AS bucket_start,
+    c.id AS campaign_id,
+    c.title AS campaign_title,
+    COALESCE(SUM(m.impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(m.clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(m.conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(m.spend), 0)::float8 AS spend,
+    COALESCE(SUM(m.revenue), 0)::float8 AS revenue
+FROM campaign_metrics m
+JOIN campaigns c ON c.id = m.campaign_id
+WHERE c.user_id = @user_id
+  AND c.deleted_at IS NULL
+  AND (sqlc.narg('campaign_id')::uuid IS NULL OR c.id = sqlc.narg('campaign_id'))
+  AND (sqlc.narg('channel')::text IS NULL OR m.channel_id IN (
+      SELECT ch.id FROM campaign_channels ch WHERE ch.channel = sqlc.narg('channel')))
+  AND m.recorded_at >= @from_time::timestamptz
+  AND m.recorded_at < @to_time::timestamptz
+GROUP BY bucket_start, c.id, c.title
+ORDER BY bucket_start, c.title, c.id;
@@ -0,0 +1,71 @@
+-- name: CreateFunnel :one
+INSERT INTO funnels (name, description, steps, window_days, created_by)
+VALUES (@name, @description, @steps, @window_days, @created_by)
+RETURNING *;
+
+-- name: GetFunnel :one
+SELECT * FROM funnels
+WHERE id = $1;
+
+-- name: GetFunnelForUpdate :one
+SELECT * FROM funnels
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListFunnels :many
+SELECT * FROM funnels
+ORDER BY lower(name);
+
+-- name: UpdateFunnel :one
+UPDATE funnels
+SET
+    name = COALESCE(sqlc.narg('name'), name),
+    description = COALESCE(sqlc.narg('description'), description),
+    steps = COALESCE(sqlc.narg('steps')::text[], steps),
+    window_days = COALESCE(sqlc.narg('window_days'), window_days),
+    updated_at = NOW()
+WHERE id = @id
+RETURNING *;
+
+-- name: DeleteFunnel :execrows
+DELETE FROM funnels
+WHERE id = $1;
+
+-- name: ListFunnelEvents :many
+-- Visitors entering a funnel in the window, by their first first-step
+-- event on one of the user's live campaigns, with their events of the
+-- funnel's steps from entering until the window closes, in time order.
+-- Later events only count on the user's campaigns or, for conversions
+-- without a click, as the visitor's own. A campaign ID narrows the entries
+-- to that campaign.
+WITH entries AS (
+    SELECT DISTINCT ON (e.visitor_id) e.visitor_id, e.campaign_id, e.occurred_at AS entered_at
+    FROM visitor_events e
+    JOIN campaigns c ON c.id = e.campaign_id
+    WHERE e.event = @first_step::text
+      AND e.occurred_at >= @from_time::timestamptz
+      AND e.occurred_at < @to_time::timestamptz
+      AND c.user_id = @user_id::uuid
+      AND c.deleted_at IS NULL
+      AND (sqlc.narg('campaign_id')::uuid IS NULL OR e.campaign_id = sqlc.narg('campaign_id'))
+    ORDER BY e.visitor_id, e.occurred_at
+)
+SELECT
+    en.visitor_id,
+    en.campaign_id,
+    c.title AS campaign_title,
+    e.event,
+    e.occurred_at
+FROM entries en
+JOIN campaigns c ON c.id = en.campaign_id
+JOIN visitor_events e ON e.visitor_id = en.visitor_id
+    AND e.occurred_at >= en.entered_at
+    AND e.occurred_at <= en.entered_at + make_interval(days => @window_days::int)
+    AND e.event = ANY(@steps::text[])
+    AND (e.campaign_id IS NULL OR EXISTS (
+        SELECT 1 FROM campaigns oc
+        WHERE oc.id = e.campaign_id
+          AND oc.user_id = @user_id::uuid
+          AND oc.deleted_at IS NULL
+    ))
+ORDER BY en.visitor_id, e.occurred_at, e.event = @first_step::text DESC;
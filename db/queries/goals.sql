@@ -0,0 +1,81 @@
+-- name: CreateCampaignGoal :one
+INSERT INTO campaign_goals (campaign_id, metric, direction, target, deadline, created_by)
+VALUES (@campaign_id, @metric, @direction, @target, @deadline, @created_by)
+RETURNING *;
+
+-- name: GetCampaignGoalForUpdate :one
+SELECT * FROM campaign_goals
+WHERE id = @id AND campaign_id = @campaign_id
+FOR UPDATE;
+
+-- name: UpdateCampaignGoal :one
+-- Changing a goal reopens it; it is decided again on its next evaluation.
+UPDATE campaign_goals
+SET
+    direction = @direction,
+    target = @target,
+    deadline = @deadline,
+    achieved_at = NULL,
+    missed_at = NULL,
+    updated_at = NOW()
+WHERE id = @id
+RETURNING *;
+
+-- name: DeleteCampaignGoal :execrows
+DELETE FROM campaign_goals
+WHERE id = @id AND campaign_id = @campaign_id;
+
+-- name: ListGoalProgress :many
+-- Goals with the campaign totals recorded before their deadline. Without
+-- campaign IDs every goal of the user's live campaigns is listed.
+SELECT
+    g.id, g.campaign_id, g.metric, g.direction, g.target, g.deadline,
+    g.achieved_at, g.missed_at, g.created_at, g.updated_at,
+    c.user_id, c.title AS campaign_title, c.start_date AS campaign_start,
+    COALESCE(SUM(m.impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(m.clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(m.conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(m.spend), 0)::float8 AS spend,
+    COALESCE(SUM(m.revenue), 0)::float8 AS revenue
+FROM campaign_goals g
+JOIN campaigns c ON c.id = g.campaign_id
+LEFT JOIN campaign_metrics m ON m.campaign_id = g.campaign_id AND m.recorded_at < g.deadline
+WHERE c.user_id = @user_id
+  AND c.deleted_at IS NULL
+  AND (COALESCE(cardinality(@campaign_ids::uuid[]), 0) = 0 OR g.campaign_id = ANY(@campaign_ids::uuid[]))
+GROUP BY g.id, c.id
+ORDER BY g.deadline, g.metric, g.id;
+
+-- name: ListOpenGoalProgress :many
+-- Undecided goals of live campaigns, across all users, with the same
+-- totals as ListGoalProgress. With only_due set just the goals whose
+-- deadline has passed are listed.
+SELECT
+    g.id, g.campaign_id, g.metric, g.direction, g.target, g.deadline,
+    g.achieved_at, g.missed_at, g.created_at, g.updated_at,
+    c.user_id, c.title AS campaign_title, c.start_date AS campaign_start,
+    COALESCE(SUM(m.impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(m.clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(m.conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(m.spend), 0)::float8 AS spend,
+    COALESCE(SUM(m.revenue), 0)::float8 AS revenue
+FROM campaign_goals g
+JOIN campaigns c ON c.id = g.campaign_id
+LEFT JOIN campaign_metrics m ON m.campaign_id = g.campaign_id AND m.recorded_at < g.deadline
+WHERE g.achieved_at IS NULL
+  AND g.missed_at IS NULL
+  AND c.deleted_at IS NULL
+  AND (COALESCE(cardinality(@campaign_ids::uuid[]), 0) = 0 OR g.campaign_id = ANY(@campaign_ids::uuid[]))
+  AND (NOT @only_due::boolean OR g.deadline <= @now::timestamptz)
+GROUP BY g.id, c.id
+ORDER BY g.deadline, g.id;
+
+-- name: MarkGoalAchieved :execrows
+UPDATE campaign_goals
+SET achieved_at = @decided_at
+WHERE id = @id AND achieved_at IS NULL AND missed_at IS NULL;
+
+-- name: MarkGoalMissed :execrows
+UPDATE campaign_goals
+SET missed_at = @decided_at
+WHERE id = @id AND achieved_at IS NULL AND missed_at IS NULL;
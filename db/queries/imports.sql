@@ -0,0 +1,37 @@
+-- name: CreateCampaignImport :one
+INSERT INTO campaign_imports (
+    user_id, filename, format, mode, total_rows
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetCampaignImport :one
+SELECT * FROM campaign_imports
+WHERE id = $1 AND user_id = $2
+LIMIT 1;
+
+-- name: MarkCampaignImportProcessing :exec
+UPDATE campaign_imports
+SET status = 'processing'
+WHERE id = $1;
+
+-- name: FinishCampaignImport :one
+UPDATE campaign_imports
+SET
+    status = @status,
+    imported_rows = @imported_rows,
+    failed_rows = @failed_rows,
+    row_errors = @row_errors,
+    error = sqlc.narg('error'),
+    completed_at = NOW()
+WHERE id = @id
+    RETURNING *;
+
+-- name: FailCampaignImport :one
+UPDATE campaign_imports
+SET
+    status = 'failed',
+    error = @error,
+    completed_at = NOW()
+WHERE id = @id AND status IN ('pending', 'processing')
+    RETURNING *;
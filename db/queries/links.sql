@@ -0,0 +1,64 @@
+-- name: CreateTrackedLink :one
+INSERT INTO tracked_links (
+    campaign_id, code, destination_url, channel_id, variant_id,
+    utm_source, utm_medium, utm_campaign, utm_term, utm_content, created_by
+) VALUES (
+    @campaign_id, @code, @destination_url, @channel_id, @variant_id,
+    @utm_source, @utm_medium, @utm_campaign, @utm_term, @utm_content, @created_by
+)
+RETURNING *;
+
+-- name: ListTrackedLinks :many
+-- Links of a campaign, newest first, with their click counts. Bot clicks
+-- are counted apart; visitors are distinct visitor cookies. A link ID
+-- narrows the list to that link.
+SELECT
+    l.*,
+    ch.channel,
+    v.name AS variant_name,
+    COUNT(k.id) FILTER (WHERE NOT k.is_bot)::bigint AS clicks,
+    COUNT(DISTINCT k.visitor_id) FILTER (WHERE NOT k.is_bot)::bigint AS visitors,
+    COUNT(k.id) FILTER (WHERE k.is_bot)::bigint AS bot_clicks,
+    MAX(k.clicked_at) FILTER (WHERE NOT k.is_bot)::timestamptz AS last_clicked_at
+FROM tracked_links l
+LEFT JOIN campaign_channels ch ON ch.id = l.channel_id
+LEFT JOIN campaign_variants v ON v.id = l.variant_id
+LEFT JOIN link_clicks k ON k.link_id = l.id
+WHERE l.campaign_id = @campaign_id
+  AND (sqlc.narg('link_id')::uuid IS NULL OR l.id = sqlc.narg('link_id'))
+GROUP BY l.id, ch.id, v.id
+ORDER BY l.created_at DESC, l.id;
+
+-- name: GetTrackedLinkByCode :one
+-- Links of trashed campaigns no longer redirect.
+SELECT l.*
+FROM tracked_links l
+JOIN campaigns c ON c.id = l.campaign_id
+WHERE l.code = @code AND c.deleted_at IS NULL;
+
+-- name: DeleteTrackedLink :execrows
+DELETE FROM tracked_links
+WHERE id = @id AND campaign_id = @campaign_id;
+
+-- name: CreateLinkClick :one
+INSERT INTO link_clicks (link_id, clicked_at, visitor_id, ip_hash, referrer, user_agent, is_bot)
+VALUES (@link_id, @clicked_at, @visitor_id, @ip_hash, @referrer, @user_agent, @is_bot)
+RETURNING id;
+
+-- name: GetLinkClick :one
+SELECT k.id, k.visitor_id, k.clicked_at, l.id AS link_id, l.campaign_id, l.channel_id, l.variant_id
+FROM link_clicks k
+JOIN tracked_links l ON l.id = k.link_id
+WHERE k.id = @id;
+
+-- name: GetLatestVisitorClick :one
+-- The visitor's last click by a person since the given time.
+SELECT k.id, k.visitor_id, k.clicked_at, l.id AS link_id, l.campaign_id, l.channel_id, l.variant_id
+FROM link_clicks k
+JOIN tracked_links l ON l.id = k.link_id
+WHERE k.visitor_id = @visitor_id
+  AND NOT k.is_bot
+  AND k.clicked_at >= @since
+  AND k.clicked_at <= @until
+ORDER BY k.clicked_at DESC, k.id DESC
+LIMIT 1;
@@ -0,0 +1,95 @@
+-- name: UpsertCampaignMetric :one
+INSERT INTO campaign_metrics (
+    campaign_id, channel_id, variant_id, recorded_at, impressions, clicks, conversions, spend, revenue
+) VALUES (
+             $1, $2, $3, $4, $5, $6, $7, $8, $9
+         )
+ON CONFLICT (campaign_id, channel_id, variant_id, recorded_at) DO UPDATE
+SET
+    impressions = EXCLUDED.impressions,
+    clicks = EXCLUDED.clicks,
+    conversions = EXCLUDED.conversions,
+    spend = EXCLUDED.spend,
+    revenue = EXCLUDED.revenue,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: AddCampaignMetricEvents :exec
+-- Adds tracked clicks, conversions and revenue to a metric slice. Uploaded
+-- metrics for the same slice replace the counts, as the ad platform's
+-- figures take precedence.
+INSERT INTO campaign_metrics (campaign_id, channel_id, variant_id, recorded_at, clicks, conversions, revenue)
+VALUES (@campaign_id, @channel_id, @variant_id, @recorded_at, @clicks, @conversions, @revenue)
+ON CONFLICT (campaign_id, channel_id, variant_id, recorded_at) DO UPDATE
+SET
+    clicks = campaign_metrics.clicks + EXCLUDED.clicks,
+    conversions = campaign_metrics.conversions + EXCLUDED.conversions,
+    revenue = campaign_metrics.revenue + EXCLUDED.revenue,
+    updated_at = NOW();
+
+-- name: GetCampaignMetricTotals :one
+-- A channel narrows the totals to the metrics recorded against it.
+SELECT
+    COALESCE(SUM(impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(spend), 0)::float8 AS spend,
+    COALESCE(SUM(revenue), 0)::float8 AS revenue
+FROM campaign_metrics
+WHERE campaign_metrics.campaign_id = @campaign_id
+  AND recorded_at >= @from_time::timestamptz
+  AND recorded_at < @to_time::timestamptz
+  AND (sqlc.narg('channel')::text IS NULL OR channel_id IN (
+      SELECT ch.id FROM campaign_channels ch WHERE ch.channel = sqlc.narg('channel')));
+
+-- name: GetCampaignMetricSeries :many
+SELECT
+    (date_trunc(@bucket::text, recorded_at AT TIME ZONE @time_zone::text) AT TIME ZONE @time_zone::text)::timestamptz AS bucket_start,
+    COALESCE(SUM(impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(spend), 0)::float8 AS spend,
+    COALESCE(SUM(revenue), 0)::float8 AS revenue
+FROM campaign_metrics
+WHERE campaign_metrics.campaign_id = @campaign_id
+  AND recorded_at >= @from_time::timestamptz
+  AND recorded_at < @to_time::timestamptz
+  AND (sqlc.narg('channel')::text IS NULL OR channel_id IN (
+      SELECT ch.id FROM campaign_channels ch WHERE ch.channel = sqlc.narg('channel')))
+GROUP BY bucket_start
+ORDER BY bucket_start;
+
+-- name: GetPortfolioMetricTotals :one
+SELECT
+    COALESCE(SUM(m.impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(m.clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(m.conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(m.spend), 0)::float8 AS spend,
+    COALESCE(SUM(m.revenue), 0)::float8 AS revenue
+FROM campaign_metrics m
+JOIN campaigns c ON c.id = m.campaign_id
+WHERE c.user_id = @user_id
+  AND c.deleted_at IS NULL
+  AND m.recorded_at >= @from_time::timestamptz
+  AND m.recorded_at < @to_time::timestamptz
+  AND (sqlc.narg('channel')::text IS NULL OR m.channel_id IN (
+      SELECT ch.id FROM campaign_channels ch WHERE ch.channel = sqlc.narg('channel')));
+
+-- name: GetPortfolioMetricSeries :many
+SELECT
+    (date_trunc(@bucket::text, m.recorded_at AT TIME ZONE @time_zone::text) AT TIME ZONE @time_zone::text)::timestamptz AS bucket_start,
+    COALESCE(SUM(m.impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(m.clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(m.conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(m.spend), 0)::float8 AS spend,
+    COALESCE(SUM(m.revenue), 0)::float8 AS revenue
+FROM campaign_metrics m
+JOIN campaigns c ON c.id = m.campaign_id
+WHERE c.user_id = @user_id
+  AND c.deleted_at IS NULL
+  AND m.recorded_at >= @from_time::timestamptz
+  AND m.recorded_at < @to_time::timestamptz
+  AND (sqlc.narg('channel')::text IS NULL OR m.channel_id IN (
+      SELECT ch.id FROM campaign_channels ch WHERE ch.channel = sqlc.narg('channel')))
+GROUP BY bucket_start
+ORDER BY bucket_start;
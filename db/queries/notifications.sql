@@ -0,0 +1,43 @@
+-- name: CreateNotifications :execrows
+INSERT INTO notifications (user_id, type, actor_id, campaign_id, comment_id, goal_id)
+SELECT unnest(@user_ids::uuid[]), unnest(@types::text[]), sqlc.narg('actor_id')::uuid, sqlc.narg('campaign_id')::uuid, sqlc.narg('comment_id')::uuid, sqlc.narg('goal_id')::uuid;
+
+-- name: ListNotifications :many
+SELECT
+    n.*,
+    a.full_name AS actor_name,
+    a.email AS actor_email,
+    c.title AS campaign_title,
+    cc.body AS comment_body,
+    g.metric AS goal_metric,
+    g.direction AS goal_direction,
+    g.target AS goal_target
+FROM notifications n
+LEFT JOIN users a ON a.id = n.actor_id
+LEFT JOIN campaigns c ON c.id = n.campaign_id
+LEFT JOIN campaign_comments cc ON cc.id = n.comment_id
+LEFT JOIN campaign_goals g ON g.id = n.goal_id
+WHERE n.user_id = @user_id
+  AND (NOT @unread_only::boolean OR n.read_at IS NULL)
+  AND (sqlc.narg('cursor_id')::uuid IS NULL OR (n.created_at, n.id) < (@cursor_time::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY n.created_at DESC, n.id DESC
+LIMIT @row_limit;
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*)::bigint
+FROM notifications
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = COALESCE(read_at, NOW())
+WHERE id = @id AND user_id = @user_id;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: DeleteCommentNotifications :exec
+DELETE FROM notifications
+WHERE comment_id = $1;
@@ -0,0 +1,33 @@
+-- name: CreateCampaignRevision :one
+INSERT INTO campaign_revisions (
+    campaign_id, revision, actor_id, action, changes, snapshot, source_revision
+) VALUES (
+    @campaign_id,
+    (SELECT COALESCE(MAX(revision), 0) + 1 FROM campaign_revisions WHERE campaign_id = @campaign_id),
+    @actor_id,
+    @action,
+    @changes,
+    @snapshot,
+    sqlc.narg('source_revision')
+) RETURNING *;
+
+-- name: ListCampaignRevisions :many
+SELECT
+    r.id, r.campaign_id, r.revision, r.actor_id, u.email AS actor_email,
+    r.action, r.changes, r.snapshot, r.source_revision, r.created_at
+FROM campaign_revisions r
+LEFT JOIN users u ON u.id = r.actor_id
+WHERE r.campaign_id = @campaign_id
+  AND (sqlc.narg('cursor_id')::uuid IS NULL OR r.revision < @cursor_revision::int)
+ORDER BY r.revision DESC
+LIMIT @row_limit;
+
+-- name: CountCampaignRevisions :one
+SELECT COUNT(*)::bigint
+FROM campaign_revisions
+WHERE campaign_id = $1;
+
+-- name: GetCampaignRevision :one
+SELECT * FROM campaign_revisions
+WHERE campaign_id = $1 AND revision = $2
+LIMIT 1;
@@ -0,0 +1,30 @@
+-- name: SearchCampaigns :many
+WITH q AS (
+    SELECT to_tsquery('english', @query::text) AS query
+), ranked AS (
+    SELECT
+        c.id, c.user_id, c.title, c.description, c.status, c.start_date, c.end_date, c.budget, c.created_at, c.version,
+        ts_rank_cd(s.document, q.query)::float8 AS rank,
+        ts_headline('english', c.title, q.query, @highlight_options::text || ', HighlightAll=true')::text AS title_highlight,
+        ts_headline('english', COALESCE(c.description, ''), q.query, @highlight_options::text || ', MaxFragments=2, MaxWords=20, MinWords=5')::text AS snippet
+    FROM campaign_search_index s
+    JOIN campaigns c ON c.id = s.campaign_id
+    CROSS JOIN q
+    WHERE c.user_id = @user_id
+      AND c.deleted_at IS NULL
+      AND s.document @@ q.query
+)
+SELECT id, user_id, title, description, status, start_date, end_date, budget, created_at, version, rank, title_highlight, snippet
+FROM ranked
+WHERE sqlc.narg('cursor_id')::uuid IS NULL
+   OR (rank, id) < (@cursor_rank::float8, sqlc.narg('cursor_id')::uuid)
+ORDER BY rank DESC, id DESC
+LIMIT @row_limit;
+
+-- name: CountSearchCampaigns :one
+SELECT COUNT(*)::bigint
+FROM campaign_search_index s
+JOIN campaigns c ON c.id = s.campaign_id
+WHERE c.user_id = @user_id
+  AND c.deleted_at IS NULL
+  AND s.document @@ to_tsquery('english', @query::text);
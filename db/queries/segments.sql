@@ -0,0 +1,88 @@
+-- name: CreateSegment :one
+INSERT INTO audience_segments (name, description, rules, summary, created_by)
+VALUES (@name, @description, @rules, @summary, @created_by)
+RETURNING *;
+
+-- name: GetSegment :one
+SELECT * FROM audience_segments
+WHERE id = $1;
+
+-- name: GetSegmentForUpdate :one
+SELECT * FROM audience_segments
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListSegments :many
+-- campaign_count counts every live campaign using the segment.
+SELECT
+    s.id, s.name, s.description, s.rules, s.summary, s.created_by, s.created_at, s.updated_at,
+    (SELECT COUNT(*)
+     FROM campaign_segments cs
+     JOIN campaigns c ON c.id = cs.campaign_id
+     WHERE cs.segment_id = s.id
+       AND c.deleted_at IS NULL)::bigint AS campaign_count
+FROM audience_segments s
+WHERE sqlc.narg('search')::text IS NULL OR s.name ILIKE sqlc.narg('search') OR s.description ILIKE sqlc.narg('search')
+ORDER BY lower(s.name);
+
+-- name: CountExistingSegments :one
+SELECT COUNT(*)::bigint FROM audience_segments
+WHERE id = ANY(@ids::uuid[]);
+
+-- name: UpdateSegment :one
+UPDATE audience_segments
+SET
+    name = COALESCE(sqlc.narg('name'), name),
+    description = COALESCE(sqlc.narg('description'), description),
+    rules = COALESCE(sqlc.narg('rules'), rules),
+    summary = COALESCE(sqlc.narg('summary'), summary),
+    updated_at = NOW()
+WHERE id = @id
+RETURNING *;
+
+-- name: DeleteSegment :execrows
+DELETE FROM audience_segments
+WHERE id = $1;
+
+-- name: ListSegmentCampaigns :many
+-- Newest campaigns first. Without @all_owners only the caller's campaigns
+-- are listed.
+SELECT c.*
+FROM campaign_segments cs
+JOIN campaigns c ON c.id = cs.campaign_id
+WHERE cs.segment_id = @segment_id
+  AND c.deleted_at IS NULL
+  AND (@all_owners::boolean OR c.user_id = @user_id)
+  AND (sqlc.narg('cursor_id')::uuid IS NULL OR (c.created_at, c.id) < (@cursor_time::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT @row_limit;
+
+-- name: CountSegmentCampaigns :one
+SELECT COUNT(*)::bigint
+FROM campaign_segments cs
+JOIN campaigns c ON c.id = cs.campaign_id
+WHERE cs.segment_id = @segment_id
+  AND c.deleted_at IS NULL
+  AND (@all_owners::boolean OR c.user_id = @user_id);
+
+-- name: ListCampaignSegments :many
+SELECT cs.campaign_id, s.id, s.name, s.summary
+FROM campaign_segments cs
+JOIN audience_segments s ON s.id = cs.segment_id
+WHERE cs.campaign_id = ANY(@campaign_ids::uuid[])
+ORDER BY cs.campaign_id, lower(s.name);
+
+-- name: AddCampaignSegments :execrows
+INSERT INTO campaign_segments (campaign_id, segment_id)
+SELECT @campaign_id::uuid, unnest(@segment_ids::uuid[])
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveCampaignSegments :execrows
+DELETE FROM campaign_segments
+WHERE campaign_id = @campaign_id
+  AND segment_id = ANY(@segment_ids::uuid[]);
+
+-- name: RemoveOtherCampaignSegments :execrows
+DELETE FROM campaign_segments
+WHERE campaign_id = @campaign_id
+  AND NOT (segment_id = ANY(@keep_segment_ids::uuid[]));
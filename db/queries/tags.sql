@@ -0,0 +1,109 @@
+-- name: CreateTag :one
+INSERT INTO tags (name, color, created_by)
+VALUES (@name, @color, @created_by)
+RETURNING *;
+
+-- name: GetTag :one
+SELECT * FROM tags
+WHERE id = $1;
+
+-- name: ListTags :many
+-- campaign_count only counts the caller's campaigns.
+SELECT
+    t.id, t.name, t.color, t.created_by, t.created_at, t.updated_at,
+    (SELECT COUNT(*)
+     FROM campaign_tags ct
+     JOIN campaigns c ON c.id = ct.campaign_id
+     WHERE ct.tag_id = t.id
+       AND c.user_id = @user_id
+       AND c.deleted_at IS NULL)::bigint AS campaign_count
+FROM tags t
+WHERE sqlc.narg('search')::text IS NULL OR t.name ILIKE sqlc.narg('search')
+ORDER BY lower(t.name);
+
+-- name: CountExistingTags :one
+SELECT COUNT(*)::bigint FROM tags
+WHERE id = ANY(@ids::uuid[]);
+
+-- name: UpdateTag :one
+UPDATE tags
+SET
+    name = COALESCE(sqlc.narg('name'), name),
+    color = COALESCE(sqlc.narg('color'), color),
+    updated_at = NOW()
+WHERE id = @id
+RETURNING *;
+
+-- name: DeleteTag :execrows
+DELETE FROM tags
+WHERE id = $1;
+
+-- name: ListCampaignTags :many
+SELECT ct.campaign_id, t.id, t.name, t.color
+FROM campaign_tags ct
+JOIN tags t ON t.id = ct.tag_id
+WHERE ct.campaign_id = ANY(@campaign_ids::uuid[])
+ORDER BY ct.campaign_id, lower(t.name);
+
+-- name: AddCampaignTags :execrows
+INSERT INTO campaign_tags (campaign_id, tag_id)
+SELECT @campaign_id::uuid, unnest(@tag_ids::uuid[])
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveCampaignTags :execrows
+DELETE FROM campaign_tags
+WHERE campaign_id = @campaign_id
+  AND tag_id = ANY(@tag_ids::uuid[]);
+
+-- name: RemoveOtherCampaignTags :execrows
+DELETE FROM campaign_tags
+WHERE campaign_id = @campaign_id
+  AND NOT (tag_id = ANY(@keep_tag_ids::uuid[]));
+
+-- name: TouchCampaign :one
+-- Tag changes are part of the campaign representation, so they move its
+-- version (and ETag) without recording a field revision.
+UPDATE campaigns
+SET version = version + 1,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetTagRollups :many
+-- A campaign with several tags counts towards each of them, so rollups of
+-- different tags can overlap.
+SELECT
+    t.id, t.name, t.color,
+    COUNT(c.id)::bigint AS campaign_count,
+    COALESCE(SUM(c.budget), 0)::float8 AS budget,
+    COALESCE(SUM(m.impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(m.clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(m.conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(m.spend), 0)::float8 AS spend,
+    COALESCE(SUM(m.revenue), 0)::float8 AS revenue
+FROM tags t
+JOIN campaign_tags ct ON ct.tag_id = t.id
+JOIN campaigns c ON c.id = ct.campaign_id
+LEFT JOIN (
+    SELECT campaign_id,
+           SUM(impressions) AS impressions,
+           SUM(clicks) AS clicks,
+           SUM(conversions) AS conversions,
+           SUM(spend) AS spend,
+           SUM(revenue) AS revenue
+    FROM campaign_metrics
+    WHERE (sqlc.narg('from_time')::timestamptz IS NULL OR recorded_at >= sqlc.narg('from_time'))
+      AND (sqlc.narg('to_time')::timestamptz IS NULL OR recorded_at < sqlc.narg('to_time'))
+    GROUP BY campaign_id
+) m ON m.campaign_id = c.id
+WHERE c.user_id = @user_id
+  AND c.deleted_at IS NULL
+  AND (COALESCE(cardinality(@statuses::text[]), 0) = 0 OR c.status = ANY(@statuses::text[]))
+  AND (COALESCE(cardinality(@tag_ids::uuid[]), 0) = 0 OR t.id = ANY(@tag_ids::uuid[]))
+GROUP BY t.id
+ORDER BY lower(t.name);
+
+-- name: CountCampaignTagsIn :one
+SELECT COUNT(*)::bigint FROM campaign_tags
+WHERE campaign_id = @campaign_id
+  AND tag_id = ANY(@tag_ids::uuid[]);
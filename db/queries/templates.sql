@@ -0,0 +1,42 @@
+-- name: CreateCampaignTemplate :one
+INSERT INTO campaign_templates (
+    owner_id, scope, name, title_template, description_template, default_budget, default_duration_days
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetCampaignTemplate :one
+-- A template is visible to its owner and, when workspace scoped, to everyone.
+SELECT * FROM campaign_templates
+WHERE id = @id AND (owner_id = @user_id OR scope = 'workspace')
+LIMIT 1;
+
+-- name: ListCampaignTemplates :many
+SELECT * FROM campaign_templates
+WHERE (owner_id = @user_id OR scope = 'workspace')
+  AND (sqlc.narg('scope')::text IS NULL OR scope = sqlc.narg('scope'))
+  AND (sqlc.narg('cursor_id')::uuid IS NULL OR (created_at, id) < (@cursor_time::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT @row_limit;
+
+-- name: CountCampaignTemplates :one
+SELECT COUNT(*)::bigint
+FROM campaign_templates
+WHERE (owner_id = @user_id OR scope = 'workspace')
+  AND (sqlc.narg('scope')::text IS NULL OR scope = sqlc.narg('scope'));
+
+-- name: UpdateCampaignTemplate :one
+UPDATE campaign_templates
+SET
+    name = COALESCE(sqlc.narg('name'), name),
+    title_template = COALESCE(sqlc.narg('title_template'), title_template),
+    description_template = COALESCE(sqlc.narg('description_template'), description_template),
+    default_budget = COALESCE(sqlc.narg('default_budget'), default_budget),
+    default_duration_days = COALESCE(sqlc.narg('default_duration_days'), default_duration_days),
+    updated_at = NOW()
+WHERE id = @id
+    RETURNING *;
+
+-- name: DeleteCampaignTemplate :exec
+DELETE FROM campaign_templates
+WHERE id = $1;
@@ -0,0 +1,55 @@
+-- name: ListCampaignVariants :many
+SELECT * FROM campaign_variants
+WHERE campaign_id = $1
+ORDER BY is_control DESC, lower(name);
+
+-- name: ListRemovedVariantsWithMetrics :many
+-- Variants that replacing the set with keep_names (lower-cased) would remove
+-- but that already have metrics recorded against them.
+SELECT v.name
+FROM campaign_variants v
+WHERE v.campaign_id = @campaign_id
+  AND NOT (lower(v.name) = ANY(@keep_names::text[]))
+  AND EXISTS (SELECT 1 FROM campaign_metrics m WHERE m.variant_id = v.id)
+ORDER BY lower(v.name);
+
+-- name: RemoveOtherCampaignVariants :execrows
+DELETE FROM campaign_variants
+WHERE campaign_id = @campaign_id
+  AND NOT (lower(name) = ANY(@keep_names::text[]));
+
+-- name: ClearCampaignVariantControl :exec
+-- Runs before the upserts of a replacement so moving the control from one
+-- variant to another never has two controls at once.
+UPDATE campaign_variants
+SET is_control = FALSE
+WHERE campaign_id = $1 AND is_control;
+
+-- name: UpsertCampaignVariant :one
+INSERT INTO campaign_variants (campaign_id, name, description, traffic_split, is_control)
+VALUES (@campaign_id, @name, @description, @traffic_split, @is_control)
+ON CONFLICT (campaign_id, lower(name)) DO UPDATE
+SET
+    name = EXCLUDED.name,
+    description = EXCLUDED.description,
+    traffic_split = EXCLUDED.traffic_split,
+    is_control = EXCLUDED.is_control,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetCampaignVariantTotals :many
+-- Totals per variant, optionally limited to a window. Metrics without a
+-- variant are left out.
+SELECT
+    variant_id::uuid AS variant_id,
+    COALESCE(SUM(impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(spend), 0)::float8 AS spend,
+    COALESCE(SUM(revenue), 0)::float8 AS revenue
+FROM campaign_metrics
+WHERE campaign_id = @campaign_id
+  AND variant_id IS NOT NULL
+  AND (sqlc.narg('from_time')::timestamptz IS NULL OR recorded_at >= sqlc.narg('from_time'))
+  AND (sqlc.narg('to_time')::timestamptz IS NULL OR recorded_at < sqlc.narg('to_time'))
+GROUP BY variant_id;
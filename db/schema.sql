@@ -28,10 +28,394 @@ CREATE EXTENSION IF NOT EXISTS pgcrypto WITH SCHEMA public;
 COMMENT ON EXTENSION pgcrypto IS 'cryptographic functions';
 
 
+--
+-- Name: campaign_search_document(text, text, text); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.campaign_search_document(title text, description text, tags text) RETURNS tsvector
+    LANGUAGE sql IMMUTABLE
+    AS $$
+    SELECT setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+           setweight(to_tsvector('english', COALESCE(tags, '')), 'B') ||
+           setweight(to_tsvector('english', COALESCE(description, '')), 'B');
+$$;
+
+
+--
+-- Name: refresh_campaign_search_document(uuid); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.refresh_campaign_search_document(target_id uuid) RETURNS void
+    LANGUAGE sql
+    AS $$
+    INSERT INTO campaign_search_index (campaign_id, document, updated_at)
+    SELECT
+        c.id,
+        campaign_search_document(c.title, c.description, (
+            SELECT string_agg(t.name, ' ')
+            FROM campaign_tags ct
+            JOIN tags t ON t.id = ct.tag_id
+            WHERE ct.campaign_id = c.id
+        )),
+        NOW()
+    FROM campaigns c
+    WHERE c.id = target_id
+    ON CONFLICT (campaign_id) DO UPDATE
+    SET document = EXCLUDED.document,
+        updated_at = EXCLUDED.updated_at;
+$$;
+
+
+--
+-- Name: refresh_campaign_search_index(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.refresh_campaign_search_index() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    PERFORM refresh_campaign_search_document(NEW.id);
+    RETURN NEW;
+END;
+$$;
+
+
+--
+-- Name: refresh_campaign_tag_search_index(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.refresh_campaign_tag_search_index() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM refresh_campaign_search_document(OLD.campaign_id);
+        RETURN OLD;
+    END IF;
+    PERFORM refresh_campaign_search_document(NEW.campaign_id);
+    RETURN NEW;
+END;
+$$;
+
+
+--
+-- Name: refresh_tag_search_index(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.refresh_tag_search_index() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    PERFORM refresh_campaign_search_document(ct.campaign_id)
+    FROM campaign_tags ct
+    WHERE ct.tag_id = NEW.id;
+    RETURN NEW;
+END;
+$$;
+
+
 SET default_tablespace = '';
 
 SET default_table_access_method = heap;
 
+--
+-- Name: approval_decisions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.approval_decisions (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    approval_id uuid NOT NULL,
+    step integer NOT NULL,
+    approver_id uuid,
+    decision character varying(20) NOT NULL,
+    comment text,
+    created_at timestamp with time zone DEFAULT now(),
+    CONSTRAINT approval_decisions_decision_check CHECK (((decision)::text = ANY ((ARRAY['approved'::character varying, 'rejected'::character varying])::text[])))
+);
+
+
+--
+-- Name: approval_policies; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.approval_policies (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    name character varying(255) NOT NULL,
+    min_budget numeric(15,2) NOT NULL,
+    approver_roles text[] NOT NULL,
+    applies_to_roles text[] DEFAULT '{}'::text[] NOT NULL,
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now(),
+    CONSTRAINT approval_policies_approver_roles_check CHECK ((cardinality(approver_roles) > 0)),
+    CONSTRAINT approval_policies_min_budget_check CHECK ((min_budget >= (0)::numeric))
+);
+
+
+--
+-- Name: assets; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.assets (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    sha256 character(64) NOT NULL,
+    size_bytes bigint NOT NULL,
+    mime_type character varying(100) NOT NULL,
+    kind character varying(20) NOT NULL,
+    storage_key text NOT NULL,
+    thumbnail_key text,
+    width integer,
+    height integer,
+    created_at timestamp with time zone DEFAULT now(),
+    CONSTRAINT assets_kind_check CHECK (((kind)::text = ANY ((ARRAY['image'::character varying, 'video'::character varying, 'document'::character varying])::text[]))),
+    CONSTRAINT assets_size_bytes_check CHECK ((size_bytes > 0))
+);
+
+
+--
+-- Name: audience_segments; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.audience_segments (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    name character varying(100) NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    rules jsonb NOT NULL,
+    summary text NOT NULL,
+    created_by uuid,
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now()
+);
+
+
+--
+-- Name: campaign_approvals; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.campaign_approvals (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    campaign_id uuid NOT NULL,
+    requested_by uuid,
+    policy_id uuid,
+    status character varying(20) DEFAULT 'pending'::character varying NOT NULL,
+    budget numeric(15,2) NOT NULL,
+    approver_roles text[] NOT NULL,
+    current_step integer DEFAULT 0 NOT NULL,
+    comment text,
+    created_at timestamp with time zone DEFAULT now(),
+    decided_at timestamp with time zone,
+    CONSTRAINT campaign_approvals_status_check CHECK (((status)::text = ANY ((ARRAY['pending'::character varying, 'approved'::character varying, 'rejected'::character varying, 'cancelled'::character varying])::text[])))
+);
+
+
+--
+-- Name: campaign_assets; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.campaign_assets (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    campaign_id uuid NOT NULL,
+    asset_id uuid NOT NULL,
+    filename character varying(255) NOT NULL,
+    uploaded_by uuid,
+    created_at timestamp with time zone DEFAULT now()
+);
+
+
+--
+-- Name: campaign_channels; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.campaign_channels (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    campaign_id uuid NOT NULL,
+    channel character varying(20) NOT NULL,
+    allocated_budget numeric(15,2) NOT NULL,
+    status character varying(20) DEFAULT 'planned'::character varying NOT NULL,
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now(),
+    CONSTRAINT campaign_channels_allocated_budget_check CHECK ((allocated_budget >= (0)::numeric)),
+    CONSTRAINT campaign_channels_channel_check CHECK (((channel)::text = ANY ((ARRAY['search'::character varying, 'social'::character varying, 'email'::character varying, 'display'::character varying, 'video'::character varying, 'affiliate'::character varying, 'other'::character varying])::text[]))),
+    CONSTRAINT campaign_channels_status_check CHECK (((status)::text = ANY ((ARRAY['planned'::character varying, 'active'::character varying, 'paused'::character varying, 'completed'::character varying])::text[])))
+);
+
+
+--
+-- Name: campaign_comments; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.campaign_comments (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    campaign_id uuid NOT NULL,
+    parent_id uuid,
+    root_id uuid,
+    author_id uuid,
+    body text NOT NULL,
+    created_at timestamp with time zone DEFAULT now(),
+    edited_at timestamp with time zone,
+    deleted_at timestamp with time zone
+);
+
+
+--
+-- Name: campaign_goals; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.campaign_goals (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    campaign_id uuid NOT NULL,
+    metric character varying(20) NOT NULL,
+    direction character varying(10) NOT NULL,
+    target numeric(15,4) NOT NULL,
+    deadline timestamp with time zone NOT NULL,
+    achieved_at timestamp with time zone,
+    missed_at timestamp with time zone,
+    created_by uuid,
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now(),
+    CONSTRAINT campaign_goals_direction_check CHECK (((direction)::text = ANY ((ARRAY['at_least'::character varying, 'at_most'::character varying])::text[]))),
+    CONSTRAINT campaign_goals_metric_check CHECK (((metric)::text = ANY ((ARRAY['impressions'::character varying, 'clicks'::character varying, 'conversions'::character varying, 'spend'::character varying, 'revenue'::character varying, 'ctr'::character varying, 'cpc'::character varying, 'cpa'::character varying, 'cpm'::character varying, 'conversion_rate'::character varying, 'roas'::character varying])::text[]))),
+    CONSTRAINT campaign_goals_target_check CHECK ((target > (0)::numeric))
+);
+
+
+--
+-- Name: campaign_imports; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.campaign_imports (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    user_id uuid NOT NULL,
+    filename character varying(255) NOT NULL,
+    format character varying(10) NOT NULL,
+    mode character varying(20) NOT NULL,
+    status character varying(20) DEFAULT 'pending'::character varying NOT NULL,
+    total_rows integer DEFAULT 0 NOT NULL,
+    imported_rows integer DEFAULT 0 NOT NULL,
+    failed_rows integer DEFAULT 0 NOT NULL,
+    row_errors jsonb DEFAULT '[]'::jsonb NOT NULL,
+    error text,
+    created_at timestamp with time zone DEFAULT now(),
+    completed_at timestamp with time zone,
+    CONSTRAINT campaign_imports_format_check CHECK (((format)::text = ANY ((ARRAY['csv'::character varying, 'xlsx'::character varying])::text[]))),
+    CONSTRAINT campaign_imports_mode_check CHECK (((mode)::text = ANY ((ARRAY['all_or_nothing'::character varying, 'partial'::character varying])::text[]))),
+    CONSTRAINT campaign_imports_status_check CHECK (((status)::text = ANY ((ARRAY['pending'::character varying, 'processing'::character varying, 'completed'::character varying, 'failed'::character varying])::text[])))
+);
+
+
+--
+-- Name: campaign_metrics; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.campaign_metrics (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    campaign_id uuid NOT NULL,
+    recorded_at timestamp with time zone NOT NULL,
+    impressions bigint DEFAULT 0 NOT NULL,
+    clicks bigint DEFAULT 0 NOT NULL,
+    conversions bigint DEFAULT 0 NOT NULL,
+    spend numeric(15,2) DEFAULT 0 NOT NULL,
+    revenue numeric(15,2) DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now(),
+    channel_id uuid,
+    variant_id uuid,
+    CONSTRAINT campaign_metrics_clicks_check CHECK ((clicks >= 0)),
+    CONSTRAINT campaign_metrics_conversions_check CHECK ((conversions >= 0)),
+    CONSTRAINT campaign_metrics_impressions_check CHECK ((impressions >= 0)),
+    CONSTRAINT campaign_metrics_revenue_check CHECK ((revenue >= (0)::numeric)),
+    CONSTRAINT campaign_metrics_spend_check CHECK ((spend >= (0)::numeric))
+);
+
+
+--
+-- Name: campaign_revisions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.campaign_revisions (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    campaign_id uuid NOT NULL,
+    revision integer NOT NULL,
+    actor_id uuid,
+    action character varying(32) NOT NULL,
+    changes jsonb DEFAULT '{}'::jsonb NOT NULL,
+    snapshot jsonb NOT NULL,
+    source_revision integer,
+    created_at timestamp with time zone DEFAULT now(),
+    CONSTRAINT campaign_revisions_revision_check CHECK ((revision > 0))
+);
+
+
+--
+-- Name: campaign_search_index; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.campaign_search_index (
+    campaign_id uuid NOT NULL,
+    document tsvector NOT NULL,
+    updated_at timestamp with time zone DEFAULT now()
+);
+
+
+--
+-- Name: campaign_segments; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.campaign_segments (
+    campaign_id uuid NOT NULL,
+    segment_id uuid NOT NULL,
+    created_at timestamp with time zone DEFAULT now()
+);
+
+
+--
+-- Name: campaign_tags; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.campaign_tags (
+    campaign_id uuid NOT NULL,
+    tag_id uuid NOT NULL,
+    created_at timestamp with time zone DEFAULT now()
+);
+
+
+--
+-- Name: campaign_templates; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.campaign_templates (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    owner_id uuid NOT NULL,
+    scope character varying(20) DEFAULT 'user'::character varying NOT NULL,
+    name character varying(255) NOT NULL,
+    title_template character varying(255) NOT NULL,
+    description_template text,
+    default_budget numeric(15,2),
+    default_duration_days integer,
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now(),
+    CONSTRAINT campaign_templates_default_budget_check CHECK ((default_budget >= (0)::numeric)),
+    CONSTRAINT campaign_templates_default_duration_days_check CHECK ((default_duration_days > 0)),
+    CONSTRAINT campaign_templates_scope_check CHECK (((scope)::text = ANY ((ARRAY['user'::character varying, 'workspace'::character varying])::text[])))
+);
+
+
+--
+-- Name: campaign_variants; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.campaign_variants (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    campaign_id uuid NOT NULL,
+    name character varying(50) NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    traffic_split numeric(5,2) NOT NULL,
+    is_control boolean DEFAULT false NOT NULL,
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now(),
+    CONSTRAINT campaign_variants_traffic_split_check CHECK (((traffic_split > (0)::numeric) AND (traffic_split <= (100)::numeric)))
+);
+
+
 --
 -- Name: campaigns; Type: TABLE; Schema: public; Owner: -
 --
@@ -46,7 +430,94 @@ CREATE TABLE public.campaigns (
     end_date timestamp with time zone,
     budget numeric(15,2) DEFAULT 0 NOT NULL,
     created_at timestamp with time zone DEFAULT now(),
-    updated_at timestamp with time zone DEFAULT now()
+    updated_at timestamp with time zone DEFAULT now(),
+    deleted_at timestamp with time zone,
+    version integer DEFAULT 1 NOT NULL
+);
+
+
+--
+-- Name: comment_mentions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.comment_mentions (
+    comment_id uuid NOT NULL,
+    user_id uuid NOT NULL
+);
+
+
+--
+-- Name: conversions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.conversions (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    campaign_id uuid,
+    link_id uuid,
+    click_id uuid,
+    visitor_id uuid,
+    transaction_id character varying(100),
+    value numeric(15,2) DEFAULT 0 NOT NULL,
+    currency character(3) NOT NULL,
+    source character varying(10) NOT NULL,
+    converted_at timestamp with time zone NOT NULL,
+    created_at timestamp with time zone DEFAULT now(),
+    event character varying(50) DEFAULT 'conversion'::character varying NOT NULL,
+    CONSTRAINT conversions_event_check CHECK ((((event)::text ~ '^[a-z0-9_]+$'::text) AND ((event)::text <> 'click'::text))),
+    CONSTRAINT conversions_source_check CHECK (((source)::text = ANY ((ARRAY['pixel'::character varying, 'postback'::character varying])::text[]))),
+    CONSTRAINT conversions_value_check CHECK ((value >= (0)::numeric))
+);
+
+
+--
+-- Name: funnels; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.funnels (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    name character varying(100) NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    steps text[] NOT NULL,
+    window_days integer DEFAULT 30 NOT NULL,
+    created_by uuid,
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now(),
+    CONSTRAINT funnels_steps_check CHECK (((cardinality(steps) >= 2) AND (cardinality(steps) <= 10))),
+    CONSTRAINT funnels_window_days_check CHECK (((window_days >= 1) AND (window_days <= 90)))
+);
+
+
+--
+-- Name: link_clicks; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.link_clicks (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    link_id uuid NOT NULL,
+    clicked_at timestamp with time zone DEFAULT now() NOT NULL,
+    visitor_id uuid NOT NULL,
+    ip_hash character varying(64) NOT NULL,
+    referrer text DEFAULT ''::text NOT NULL,
+    user_agent text DEFAULT ''::text NOT NULL,
+    is_bot boolean DEFAULT false NOT NULL
+);
+
+
+--
+-- Name: notifications; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.notifications (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    user_id uuid NOT NULL,
+    type character varying(30) NOT NULL,
+    actor_id uuid,
+    campaign_id uuid,
+    comment_id uuid,
+    read_at timestamp with time zone,
+    created_at timestamp with time zone DEFAULT now(),
+    goal_id uuid,
+    CONSTRAINT notifications_type_check CHECK (((type)::text = ANY ((ARRAY['mention'::character varying, 'reply'::character varying, 'comment'::character varying, 'goal_achieved'::character varying])::text[])))
 );
 
 
@@ -59,6 +530,43 @@ CREATE TABLE public.schema_migrations (
 );
 
 
+--
+-- Name: tags; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.tags (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    name character varying(50) NOT NULL,
+    color character varying(7) DEFAULT '#6B7280'::character varying NOT NULL,
+    created_by uuid,
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now(),
+    CONSTRAINT tags_color_check CHECK (((color)::text ~ '^#[0-9A-F]{6}$'::text))
+);
+
+
+--
+-- Name: tracked_links; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.tracked_links (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    campaign_id uuid NOT NULL,
+    code character varying(16) NOT NULL,
+    destination_url text NOT NULL,
+    channel_id uuid,
+    variant_id uuid,
+    utm_source character varying(100) NOT NULL,
+    utm_medium character varying(100) NOT NULL,
+    utm_campaign character varying(100) NOT NULL,
+    utm_term character varying(100) DEFAULT ''::character varying NOT NULL,
+    utm_content character varying(100) DEFAULT ''::character varying NOT NULL,
+    created_by uuid,
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now()
+);
+
+
 --
 -- Name: users; Type: TABLE; Schema: public; Owner: -
 --
@@ -75,50 +583,946 @@ CREATE TABLE public.users (
 
 
 --
--- Name: campaigns campaigns_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: visitor_events; Type: VIEW; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.campaigns
-    ADD CONSTRAINT campaigns_pkey PRIMARY KEY (id);
+CREATE VIEW public.visitor_events AS
+ SELECT k.visitor_id,
+    'click'::character varying AS event,
+    k.clicked_at AS occurred_at,
+    l.campaign_id
+   FROM (public.link_clicks k
+     JOIN public.tracked_links l ON ((l.id = k.link_id)))
+  WHERE (NOT k.is_bot)
+UNION ALL
+ SELECT cv.visitor_id,
+    cv.event,
+    cv.converted_at AS occurred_at,
+    cv.campaign_id
+   FROM public.conversions cv
+  WHERE (cv.visitor_id IS NOT NULL);
 
 
 --
--- Name: schema_migrations schema_migrations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: approval_decisions approval_decisions_approval_id_step_key; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.schema_migrations
-    ADD CONSTRAINT schema_migrations_pkey PRIMARY KEY (version);
+ALTER TABLE ONLY public.approval_decisions
+    ADD CONSTRAINT approval_decisions_approval_id_step_key UNIQUE (approval_id, step);
 
 
 --
--- Name: users users_email_key; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: approval_decisions approval_decisions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.users
-    ADD CONSTRAINT users_email_key UNIQUE (email);
+ALTER TABLE ONLY public.approval_decisions
+    ADD CONSTRAINT approval_decisions_pkey PRIMARY KEY (id);
 
 
 --
--- Name: users users_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: approval_policies approval_policies_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.users
-    ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.approval_policies
+    ADD CONSTRAINT approval_policies_pkey PRIMARY KEY (id);
 
 
 --
--- Name: idx_campaigns_user_id; Type: INDEX; Schema: public; Owner: -
+-- Name: assets assets_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_campaigns_user_id ON public.campaigns USING btree (user_id);
+ALTER TABLE ONLY public.assets
+    ADD CONSTRAINT assets_pkey PRIMARY KEY (id);
 
 
 --
--- Name: campaigns campaigns_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+-- Name: audience_segments audience_segments_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.campaigns
-    ADD CONSTRAINT campaigns_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+ALTER TABLE ONLY public.audience_segments
+    ADD CONSTRAINT audience_segments_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: campaign_approvals campaign_approvals_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_approvals
+    ADD CONSTRAINT campaign_approvals_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: campaign_assets campaign_assets_campaign_id_asset_id_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_assets
+    ADD CONSTRAINT campaign_assets_campaign_id_asset_id_key UNIQUE (campaign_id, asset_id);
+
+
+--
+-- Name: campaign_assets campaign_assets_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_assets
+    ADD CONSTRAINT campaign_assets_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: campaign_channels campaign_channels_campaign_id_channel_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_channels
+    ADD CONSTRAINT campaign_channels_campaign_id_channel_key UNIQUE (campaign_id, channel);
+
+
+--
+-- Name: campaign_channels campaign_channels_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_channels
+    ADD CONSTRAINT campaign_channels_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: campaign_comments campaign_comments_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_comments
+    ADD CONSTRAINT campaign_comments_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: campaign_goals campaign_goals_campaign_id_metric_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_goals
+    ADD CONSTRAINT campaign_goals_campaign_id_metric_key UNIQUE (campaign_id, metric);
+
+
+--
+-- Name: campaign_goals campaign_goals_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_goals
+    ADD CONSTRAINT campaign_goals_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: campaign_imports campaign_imports_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_imports
+    ADD CONSTRAINT campaign_imports_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: campaign_metrics campaign_metrics_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_metrics
+    ADD CONSTRAINT campaign_metrics_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: campaign_metrics campaign_metrics_slice_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_metrics
+    ADD CONSTRAINT campaign_metrics_slice_key UNIQUE NULLS NOT DISTINCT (campaign_id, channel_id, variant_id, recorded_at);
+
+
+--
+-- Name: campaign_revisions campaign_revisions_campaign_id_revision_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_revisions
+    ADD CONSTRAINT campaign_revisions_campaign_id_revision_key UNIQUE (campaign_id, revision);
+
+
+--
+-- Name: campaign_revisions campaign_revisions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_revisions
+    ADD CONSTRAINT campaign_revisions_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: campaign_search_index campaign_search_index_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_search_index
+    ADD CONSTRAINT campaign_search_index_pkey PRIMARY KEY (campaign_id);
+
+
+--
+-- Name: campaign_segments campaign_segments_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_segments
+    ADD CONSTRAINT campaign_segments_pkey PRIMARY KEY (campaign_id, segment_id);
+
+
+--
+-- Name: campaign_tags campaign_tags_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_tags
+    ADD CONSTRAINT campaign_tags_pkey PRIMARY KEY (campaign_id, tag_id);
+
+
+--
+-- Name: campaign_templates campaign_templates_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_templates
+    ADD CONSTRAINT campaign_templates_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: campaign_variants campaign_variants_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_variants
+    ADD CONSTRAINT campaign_variants_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: campaigns campaigns_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaigns
+    ADD CONSTRAINT campaigns_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: comment_mentions comment_mentions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment_mentions
+    ADD CONSTRAINT comment_mentions_pkey PRIMARY KEY (comment_id, user_id);
+
+
+--
+-- Name: conversions conversions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversions
+    ADD CONSTRAINT conversions_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: funnels funnels_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.funnels
+    ADD CONSTRAINT funnels_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: link_clicks link_clicks_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.link_clicks
+    ADD CONSTRAINT link_clicks_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: notifications notifications_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notifications
+    ADD CONSTRAINT notifications_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: schema_migrations schema_migrations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.schema_migrations
+    ADD CONSTRAINT schema_migrations_pkey PRIMARY KEY (version);
+
+
+--
+-- Name: tags tags_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.tags
+    ADD CONSTRAINT tags_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: tracked_links tracked_links_code_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.tracked_links
+    ADD CONSTRAINT tracked_links_code_key UNIQUE (code);
+
+
+--
+-- Name: tracked_links tracked_links_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.tracked_links
+    ADD CONSTRAINT tracked_links_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: users users_email_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.users
+    ADD CONSTRAINT users_email_key UNIQUE (email);
+
+
+--
+-- Name: users users_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.users
+    ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: idx_assets_sha256; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_assets_sha256 ON public.assets USING btree (sha256);
+
+
+--
+-- Name: idx_audience_segments_name; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_audience_segments_name ON public.audience_segments USING btree (lower((name)::text));
+
+
+--
+-- Name: idx_campaign_approvals_campaign_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaign_approvals_campaign_id ON public.campaign_approvals USING btree (campaign_id);
+
+
+--
+-- Name: idx_campaign_approvals_one_pending; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_campaign_approvals_one_pending ON public.campaign_approvals USING btree (campaign_id) WHERE ((status)::text = 'pending'::text);
+
+
+--
+-- Name: idx_campaign_approvals_status; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaign_approvals_status ON public.campaign_approvals USING btree (status, created_at);
+
+
+--
+-- Name: idx_campaign_assets_asset_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaign_assets_asset_id ON public.campaign_assets USING btree (asset_id);
+
+
+--
+-- Name: idx_campaign_comments_campaign_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaign_comments_campaign_id ON public.campaign_comments USING btree (campaign_id, created_at) WHERE (parent_id IS NULL);
+
+
+--
+-- Name: idx_campaign_comments_root_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaign_comments_root_id ON public.campaign_comments USING btree (root_id, created_at);
+
+
+--
+-- Name: idx_campaign_goals_open_deadline; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaign_goals_open_deadline ON public.campaign_goals USING btree (deadline) WHERE ((achieved_at IS NULL) AND (missed_at IS NULL));
+
+
+--
+-- Name: idx_campaign_imports_user_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaign_imports_user_id ON public.campaign_imports USING btree (user_id);
+
+
+--
+-- Name: idx_campaign_metrics_channel_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaign_metrics_channel_id ON public.campaign_metrics USING btree (channel_id);
+
+
+--
+-- Name: idx_campaign_metrics_recorded_at; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaign_metrics_recorded_at ON public.campaign_metrics USING btree (recorded_at);
+
+
+--
+-- Name: idx_campaign_metrics_variant_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaign_metrics_variant_id ON public.campaign_metrics USING btree (variant_id);
+
+
+--
+-- Name: idx_campaign_search_index_document; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaign_search_index_document ON public.campaign_search_index USING gin (document);
+
+
+--
+-- Name: idx_campaign_segments_segment_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaign_segments_segment_id ON public.campaign_segments USING btree (segment_id);
+
+
+--
+-- Name: idx_campaign_tags_tag_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaign_tags_tag_id ON public.campaign_tags USING btree (tag_id);
+
+
+--
+-- Name: idx_campaign_templates_owner_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaign_templates_owner_id ON public.campaign_templates USING btree (owner_id);
+
+
+--
+-- Name: idx_campaign_templates_scope; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaign_templates_scope ON public.campaign_templates USING btree (scope);
+
+
+--
+-- Name: idx_campaign_variants_control; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_campaign_variants_control ON public.campaign_variants USING btree (campaign_id) WHERE is_control;
+
+
+--
+-- Name: idx_campaign_variants_name; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_campaign_variants_name ON public.campaign_variants USING btree (campaign_id, lower((name)::text));
+
+
+--
+-- Name: idx_campaigns_deleted_at; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaigns_deleted_at ON public.campaigns USING btree (deleted_at) WHERE (deleted_at IS NOT NULL);
+
+
+--
+-- Name: idx_campaigns_user_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaigns_user_id ON public.campaigns USING btree (user_id);
+
+
+--
+-- Name: idx_campaigns_user_id_end_date; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaigns_user_id_end_date ON public.campaigns USING btree (user_id, end_date);
+
+
+--
+-- Name: idx_campaigns_user_id_start_date; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaigns_user_id_start_date ON public.campaigns USING btree (user_id, start_date);
+
+
+--
+-- Name: idx_comment_mentions_user_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_comment_mentions_user_id ON public.comment_mentions USING btree (user_id);
+
+
+--
+-- Name: idx_conversions_campaign_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_conversions_campaign_id ON public.conversions USING btree (campaign_id, converted_at);
+
+
+--
+-- Name: idx_conversions_transaction; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_conversions_transaction ON public.conversions USING btree (campaign_id, transaction_id) NULLS NOT DISTINCT WHERE (transaction_id IS NOT NULL);
+
+
+--
+-- Name: idx_conversions_visitor_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_conversions_visitor_id ON public.conversions USING btree (visitor_id, converted_at);
+
+
+--
+-- Name: idx_funnels_name; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_funnels_name ON public.funnels USING btree (lower((name)::text));
+
+
+--
+-- Name: idx_link_clicks_link_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_link_clicks_link_id ON public.link_clicks USING btree (link_id, clicked_at);
+
+
+--
+-- Name: idx_link_clicks_visitor_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_link_clicks_visitor_id ON public.link_clicks USING btree (visitor_id, clicked_at);
+
+
+--
+-- Name: idx_notifications_unread; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notifications_unread ON public.notifications USING btree (user_id) WHERE (read_at IS NULL);
+
+
+--
+-- Name: idx_notifications_user_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notifications_user_id ON public.notifications USING btree (user_id, created_at DESC);
+
+
+--
+-- Name: idx_tags_name; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_tags_name ON public.tags USING btree (lower((name)::text));
+
+
+--
+-- Name: idx_tracked_links_campaign_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_tracked_links_campaign_id ON public.tracked_links USING btree (campaign_id);
+
+
+--
+-- Name: campaign_tags trg_campaign_tags_search_index; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER trg_campaign_tags_search_index AFTER INSERT OR DELETE ON public.campaign_tags FOR EACH ROW EXECUTE FUNCTION public.refresh_campaign_tag_search_index();
+
+
+--
+-- Name: campaigns trg_campaigns_search_index; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER trg_campaigns_search_index AFTER INSERT OR UPDATE OF title, description ON public.campaigns FOR EACH ROW EXECUTE FUNCTION public.refresh_campaign_search_index();
+
+
+--
+-- Name: tags trg_tags_search_index; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER trg_tags_search_index AFTER UPDATE OF name ON public.tags FOR EACH ROW EXECUTE FUNCTION public.refresh_tag_search_index();
+
+
+--
+-- Name: approval_decisions approval_decisions_approval_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.approval_decisions
+    ADD CONSTRAINT approval_decisions_approval_id_fkey FOREIGN KEY (approval_id) REFERENCES public.campaign_approvals(id) ON DELETE CASCADE;
+
+
+--
+-- Name: approval_decisions approval_decisions_approver_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.approval_decisions
+    ADD CONSTRAINT approval_decisions_approver_id_fkey FOREIGN KEY (approver_id) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: audience_segments audience_segments_created_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.audience_segments
+    ADD CONSTRAINT audience_segments_created_by_fkey FOREIGN KEY (created_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: campaign_approvals campaign_approvals_campaign_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_approvals
+    ADD CONSTRAINT campaign_approvals_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES public.campaigns(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaign_approvals campaign_approvals_policy_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_approvals
+    ADD CONSTRAINT campaign_approvals_policy_id_fkey FOREIGN KEY (policy_id) REFERENCES public.approval_policies(id) ON DELETE SET NULL;
+
+
+--
+-- Name: campaign_approvals campaign_approvals_requested_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_approvals
+    ADD CONSTRAINT campaign_approvals_requested_by_fkey FOREIGN KEY (requested_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: campaign_assets campaign_assets_asset_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_assets
+    ADD CONSTRAINT campaign_assets_asset_id_fkey FOREIGN KEY (asset_id) REFERENCES public.assets(id) ON DELETE RESTRICT;
+
+
+--
+-- Name: campaign_assets campaign_assets_campaign_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_assets
+    ADD CONSTRAINT campaign_assets_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES public.campaigns(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaign_assets campaign_assets_uploaded_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_assets
+    ADD CONSTRAINT campaign_assets_uploaded_by_fkey FOREIGN KEY (uploaded_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: campaign_channels campaign_channels_campaign_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_channels
+    ADD CONSTRAINT campaign_channels_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES public.campaigns(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaign_comments campaign_comments_author_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_comments
+    ADD CONSTRAINT campaign_comments_author_id_fkey FOREIGN KEY (author_id) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: campaign_comments campaign_comments_campaign_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_comments
+    ADD CONSTRAINT campaign_comments_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES public.campaigns(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaign_comments campaign_comments_parent_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_comments
+    ADD CONSTRAINT campaign_comments_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES public.campaign_comments(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaign_comments campaign_comments_root_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_comments
+    ADD CONSTRAINT campaign_comments_root_id_fkey FOREIGN KEY (root_id) REFERENCES public.campaign_comments(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaign_goals campaign_goals_campaign_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_goals
+    ADD CONSTRAINT campaign_goals_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES public.campaigns(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaign_goals campaign_goals_created_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_goals
+    ADD CONSTRAINT campaign_goals_created_by_fkey FOREIGN KEY (created_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: campaign_imports campaign_imports_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_imports
+    ADD CONSTRAINT campaign_imports_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaign_metrics campaign_metrics_campaign_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_metrics
+    ADD CONSTRAINT campaign_metrics_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES public.campaigns(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaign_metrics campaign_metrics_channel_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_metrics
+    ADD CONSTRAINT campaign_metrics_channel_id_fkey FOREIGN KEY (channel_id) REFERENCES public.campaign_channels(id);
+
+
+--
+-- Name: campaign_metrics campaign_metrics_variant_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_metrics
+    ADD CONSTRAINT campaign_metrics_variant_id_fkey FOREIGN KEY (variant_id) REFERENCES public.campaign_variants(id);
+
+
+--
+-- Name: campaign_revisions campaign_revisions_actor_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_revisions
+    ADD CONSTRAINT campaign_revisions_actor_id_fkey FOREIGN KEY (actor_id) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: campaign_revisions campaign_revisions_campaign_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_revisions
+    ADD CONSTRAINT campaign_revisions_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES public.campaigns(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaign_search_index campaign_search_index_campaign_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_search_index
+    ADD CONSTRAINT campaign_search_index_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES public.campaigns(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaign_segments campaign_segments_campaign_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_segments
+    ADD CONSTRAINT campaign_segments_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES public.campaigns(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaign_segments campaign_segments_segment_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_segments
+    ADD CONSTRAINT campaign_segments_segment_id_fkey FOREIGN KEY (segment_id) REFERENCES public.audience_segments(id) ON DELETE RESTRICT;
+
+
+--
+-- Name: campaign_tags campaign_tags_campaign_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_tags
+    ADD CONSTRAINT campaign_tags_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES public.campaigns(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaign_tags campaign_tags_tag_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_tags
+    ADD CONSTRAINT campaign_tags_tag_id_fkey FOREIGN KEY (tag_id) REFERENCES public.tags(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaign_templates campaign_templates_owner_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_templates
+    ADD CONSTRAINT campaign_templates_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaign_variants campaign_variants_campaign_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaign_variants
+    ADD CONSTRAINT campaign_variants_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES public.campaigns(id) ON DELETE CASCADE;
+
+
+--
+-- Name: campaigns campaigns_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaigns
+    ADD CONSTRAINT campaigns_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+
+--
+-- Name: comment_mentions comment_mentions_comment_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment_mentions
+    ADD CONSTRAINT comment_mentions_comment_id_fkey FOREIGN KEY (comment_id) REFERENCES public.campaign_comments(id) ON DELETE CASCADE;
+
+
+--
+-- Name: comment_mentions comment_mentions_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment_mentions
+    ADD CONSTRAINT comment_mentions_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+
+--
+-- Name: conversions conversions_campaign_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversions
+    ADD CONSTRAINT conversions_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES public.campaigns(id) ON DELETE CASCADE;
+
+
+--
+-- Name: conversions conversions_click_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversions
+    ADD CONSTRAINT conversions_click_id_fkey FOREIGN KEY (click_id) REFERENCES public.link_clicks(id) ON DELETE SET NULL;
+
+
+--
+-- Name: conversions conversions_link_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversions
+    ADD CONSTRAINT conversions_link_id_fkey FOREIGN KEY (link_id) REFERENCES public.tracked_links(id) ON DELETE SET NULL;
+
+
+--
+-- Name: funnels funnels_created_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.funnels
+    ADD CONSTRAINT funnels_created_by_fkey FOREIGN KEY (created_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: link_clicks link_clicks_link_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.link_clicks
+    ADD CONSTRAINT link_clicks_link_id_fkey FOREIGN KEY (link_id) REFERENCES public.tracked_links(id) ON DELETE CASCADE;
+
+
+--
+-- Name: notifications notifications_actor_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notifications
+    ADD CONSTRAINT notifications_actor_id_fkey FOREIGN KEY (actor_id) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: notifications notifications_campaign_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notifications
+    ADD CONSTRAINT notifications_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES public.campaigns(id) ON DELETE CASCADE;
+
+
+--
+-- Name: notifications notifications_comment_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notifications
+    ADD CONSTRAINT notifications_comment_id_fkey FOREIGN KEY (comment_id) REFERENCES public.campaign_comments(id) ON DELETE CASCADE;
+
+
+--
+-- Name: notifications notifications_goal_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notifications
+    ADD CONSTRAINT notifications_goal_id_fkey FOREIGN KEY (goal_id) REFERENCES public.campaign_goals(id) ON DELETE CASCADE;
+
+
+--
+-- Name: notifications notifications_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notifications
+    ADD CONSTRAINT notifications_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+
+--
+-- Name: tags tags_created_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.tags
+    ADD CONSTRAINT tags_created_by_fkey FOREIGN KEY (created_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: tracked_links tracked_links_campaign_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.tracked_links
+    ADD CONSTRAINT tracked_links_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES public.campaigns(id) ON DELETE CASCADE;
+
+
+--
+-- Name: tracked_links tracked_links_channel_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.tracked_links
+    ADD CONSTRAINT tracked_links_channel_id_fkey FOREIGN KEY (channel_id) REFERENCES public.campaign_channels(id) ON DELETE SET NULL;
+
+
+--
+-- Name: tracked_links tracked_links_created_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.tracked_links
+    ADD CONSTRAINT tracked_links_created_by_fkey FOREIGN KEY (created_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: tracked_links tracked_links_variant_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.tracked_links
+    ADD CONSTRAINT tracked_links_variant_id_fkey FOREIGN KEY (variant_id) REFERENCES public.campaign_variants(id) ON DELETE SET NULL;
 
 
 --
@@ -134,4 +1538,24 @@ ALTER TABLE ONLY public.campaigns
 
 INSERT INTO public.schema_migrations (version) VALUES
     ('20260205100317'),
-    ('20260205123623');
+    ('20260205123623'),
+    ('20261019090000'),
+    ('20261019100000'),
+    ('20261019110000'),
+    ('20261019120000'),
+    ('20261019130000'),
+    ('20261019140000'),
+    ('20261019150000'),
+    ('20261019160000'),
+    ('20261019170000'),
+    ('20261019180000'),
+    ('20261019190000'),
+    ('20261019200000'),
+    ('20261019210000'),
+    ('20261019220000'),
+    ('20261019230000'),
+    ('20261020000000'),
+    ('20261020010000'),
+    ('20261020020000'),
+    ('20261020030000'),
+    ('20261020040000');
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type AnalyticsHandler struct {
+	analyticsService *service.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService *service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// Record Campaign Metrics
+// @Summary      Record campaign metrics
+// @Description  Upsert raw delivery metrics for a campaign, one entry per reporting period (e.g. hour) and channel. Entries without a channel are unattributed; a channel must exist on the campaign. Entries for an experiment name the variant.
+// @Tags         Analytics
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      string                    true  "Campaign ID"
+// @Param        request body      dto.RecordMetricsRequest  true  "Metrics Payload"
+// @Success      200     {object}  dto.APIResponse{data=[]dto.MetricEntryResponse}
+// @Failure      400     {object}  dto.APIResponse
+// @Failure      401     {object}  dto.APIResponse
+// @Failure      404     {object}  dto.APIResponse
+// @Router       /campaigns/{id}/metrics [post]
+func (h *AnalyticsHandler) RecordMetrics(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.RecordMetricsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.analyticsService.RecordMetrics(c.Request.Context(), authPayload.UserID, campaignID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrCampaignNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found"})
+			return
+		}
+		if errors.Is(err, service.ErrChannelNotFound) || errors.Is(err, service.ErrVariantNotFound) {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+			return
+		}
+		zap.L().Error("RecordMetrics failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Metrics recorded",
+		Data:    res,
+	})
+}
+
+// Campaign Analytics
+// @Summary      Get campaign analytics
+// @Description  KPIs (CTR, CPC, CPA, CPM, conversion rate, ROAS) bucketed by interval, compared with the previous equivalent period
+// @Tags         Analytics
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path   string  true   "Campaign ID"
+// @Param        from      query  string  false  "Range start (YYYY-MM-DD or RFC3339), defaults to 30 days before to"
+// @Param        to        query  string  false  "Range end, inclusive when a date (YYYY-MM-DD or RFC3339), defaults to today"
+// @Param        interval  query  string  false  "Bucket size" Enums(day, week, month) default(day)
+// @Param        timezone  query  string  false  "IANA timezone used for bucketing" default(UTC)
+// @Param        channel   query  string  false  "Only metrics recorded against this channel" Enums(search, social, email, display, video, affiliate, other)
+// @Success      200  {object}  dto.APIResponse{data=dto.AnalyticsResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      401  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/analytics [get]
+func (h *AnalyticsHandler) CampaignAnalytics(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.AnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.analyticsService.CampaignAnalytics(c.Request.Context(), authPayload.UserID, campaignID, req)
+	if err != nil {
+		h.handleError(c, "CampaignAnalytics", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Campaign analytics retrieved",
+		Data:    res,
+	})
+}
+
+// Portfolio Analytics
+// @Summary      Get portfolio analytics
+// @Description  KPIs across all of the caller's campaigns, bucketed by interval and compared with the previous equivalent period
+// @Tags         Analytics
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        from      query  string  false  "Range start (YYYY-MM-DD or RFC3339), defaults to 30 days before to"
+// @Param        to        query  string  false  "Range end, inclusive when a date (YYYY-MM-DD or RFC3339), defaults to today"
+// @Param        interval  query  string  false  "Bucket size" Enums(day, week, month) default(day)
+// @Param        timezone  query  string  false  "IANA timezone used for bucketing" default(UTC)
+// @Param        channel   query  string  false  "Only metrics recorded against this channel" Enums(search, social, email, display, video, affiliate, other)
+// @Success      200  {object}  dto.APIResponse{data=dto.AnalyticsResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      401  {object}  dto.APIResponse
+// @Router       /analytics [get]
+func (h *AnalyticsHandler) PortfolioAnalytics(c *gin.Context) {
+	var req dto.AnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.analyticsService.PortfolioAnalytics(c.Request.Context(), authPayload.UserID, req)
+	if err != nil {
+		h.handleError(c, "PortfolioAnalytics", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Portfolio analytics retrieved",
+		Data:    res,
+	})
+}
+
+func (h *AnalyticsHandler) handleError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrCampaignNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found"})
+	case errors.Is(err, service.ErrInvalidTimezone):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid timezone, expected an IANA name such as Asia/Jakarta"})
+	case errors.Is(err, service.ErrInvalidAnalyticsRange):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid date range: from must be before to and the range must not exceed 400 buckets"})
+	default:
+		zap.L().Error(op+" failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+	}
+}
@@ -0,0 +1,437 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type ApprovalHandler struct {
+	approvalService *service.ApprovalService
+}
+
+func NewApprovalHandler(approvalService *service.ApprovalService) *ApprovalHandler {
+	return &ApprovalHandler{
+		approvalService: approvalService,
+	}
+}
+
+// Create Approval Policy
+// @Summary      Create approval policy
+// @Description  Require campaigns with a budget of at least min_budget to be approved by each role in approver_roles (manager or admin), in order, before they go live. applies_to_roles limits the policy to campaigns owned by those roles; empty means everyone. When several policies match, the one with the highest min_budget applies. Admin only.
+// @Tags         Approvals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body dto.ApprovalPolicyRequest true "Policy Payload"
+// @Success      201  {object}  dto.APIResponse{data=dto.ApprovalPolicyResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Router       /approval-policies [post]
+func (h *ApprovalHandler) CreatePolicy(c *gin.Context) {
+	var req dto.ApprovalPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	res, err := h.approvalService.CreatePolicy(c.Request.Context(), req)
+	if err != nil {
+		h.handleError(c, "CreateApprovalPolicy", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Message: "Approval policy created successfully",
+		Data:    res,
+	})
+}
+
+// List Approval Policies
+// @Summary      List approval policies
+// @Description  List approval policies by budget threshold (admin only)
+// @Tags         Approvals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.APIResponse{data=[]dto.ApprovalPolicyResponse}
+// @Router       /approval-policies [get]
+func (h *ApprovalHandler) ListPolicies(c *gin.Context) {
+	res, err := h.approvalService.ListPolicies(c.Request.Context())
+	if err != nil {
+		h.handleError(c, "ListApprovalPolicies", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Approval policies retrieved",
+		Data:    res,
+	})
+}
+
+// Update Approval Policy
+// @Summary      Update approval policy
+// @Description  Replace an approval policy. Requests already submitted keep their approver chain. Admin only.
+// @Tags         Approvals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                     true  "Policy ID"
+// @Param        request  body  dto.ApprovalPolicyRequest  true  "Policy Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.ApprovalPolicyResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /approval-policies/{id} [put]
+func (h *ApprovalHandler) UpdatePolicy(c *gin.Context) {
+	policyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid policy ID format"})
+		return
+	}
+
+	var req dto.ApprovalPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	res, err := h.approvalService.UpdatePolicy(c.Request.Context(), policyID, req)
+	if err != nil {
+		h.handleError(c, "UpdateApprovalPolicy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Approval policy updated successfully",
+		Data:    res,
+	})
+}
+
+// Delete Approval Policy
+// @Summary      Delete approval policy
+// @Description  Delete an approval policy (admin only). Pending requests under it can still be decided.
+// @Tags         Approvals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Policy ID"
+// @Success      200  {object}  dto.APIResponse
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /approval-policies/{id} [delete]
+func (h *ApprovalHandler) DeletePolicy(c *gin.Context) {
+	policyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid policy ID format"})
+		return
+	}
+
+	if err := h.approvalService.DeletePolicy(c.Request.Context(), policyID); err != nil {
+		h.handleError(c, "DeleteApprovalPolicy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Approval policy deleted successfully",
+	})
+}
+
+// Submit Approval
+// @Summary      Submit campaign for approval
+// @Description  Ask for approval of a campaign at its current budget. Fails with 400 when no policy covers the budget or it is already approved, and with 409 when a request is already pending.
+// @Tags         Approvals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                     true   "Campaign ID"
+// @Param        request  body  dto.SubmitApprovalRequest  false  "Submission comment"
+// @Success      201  {object}  dto.APIResponse{data=dto.ApprovalResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Failure      409  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/approvals [post]
+func (h *ApprovalHandler) Submit(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.SubmitApprovalRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.approvalService.Submit(c.Request.Context(), authPayload.UserID, campaignID, req)
+	if err != nil {
+		h.handleError(c, "SubmitApproval", err)
+		return
+	}
+
+	zap.L().Info("Campaign submitted for approval", zap.String("id", res.ID), zap.String("campaign_id", res.CampaignID))
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Message: "Campaign submitted for approval",
+		Data:    res,
+	})
+}
+
+// List Campaign Approvals
+// @Summary      List campaign approvals
+// @Description  Every approval request of one of my campaigns with its decisions, newest first
+// @Tags         Approvals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Campaign ID"
+// @Success      200  {object}  dto.APIResponse{data=[]dto.ApprovalResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/approvals [get]
+func (h *ApprovalHandler) ListCampaignApprovals(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.approvalService.ListCampaignApprovals(c.Request.Context(), authPayload.UserID, campaignID)
+	if err != nil {
+		h.handleError(c, "ListCampaignApprovals", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Campaign approvals retrieved",
+		Data:    res,
+	})
+}
+
+// Approval Queue
+// @Summary      Approval queue
+// @Description  Pending requests whose current step I can decide, oldest first. Managers see manager steps, admins see every step. Requests for my own campaigns are left out.
+// @Tags         Approvals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        cursor         query  string  false  "Cursor from a previous page"
+// @Param        limit          query  int     false  "Page size (max 100)" default(10)
+// @Param        include_total  query  bool    false  "Include the total count"
+// @Success      200  {object}  dto.APIResponse{data=dto.PageResponse{items=[]dto.ApprovalResponse}}
+// @Failure      400  {object}  dto.APIResponse
+// @Router       /approvals [get]
+func (h *ApprovalHandler) Queue(c *gin.Context) {
+	var req dto.PageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.approvalService.Queue(c.Request.Context(), authPayload.UserID, authPayload.Role, req)
+	if err != nil {
+		h.handleError(c, "ApprovalQueue", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Approval queue retrieved",
+		Data:    res,
+	})
+}
+
+// Get Approval
+// @Summary      Get approval
+// @Description  An approval request with its decisions. Visible to the requester, the campaign owner and any manager or admin.
+// @Tags         Approvals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Approval ID"
+// @Success      200  {object}  dto.APIResponse{data=dto.ApprovalResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /approvals/{id} [get]
+func (h *ApprovalHandler) Get(c *gin.Context) {
+	approvalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid approval ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.approvalService.GetApproval(c.Request.Context(), authPayload.UserID, authPayload.Role, approvalID)
+	if err != nil {
+		h.handleError(c, "GetApproval", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Approval retrieved",
+		Data:    res,
+	})
+}
+
+// Approve
+// @Summary      Approve step
+// @Description  Sign off the current step. Needs the step's role (admins may stand in for any step); nobody may decide requests for their own campaigns or sign two steps of one chain. The request is approved after the last step.
+// @Tags         Approvals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                       true   "Approval ID"
+// @Param        request  body  dto.ApprovalDecisionRequest  false  "Decision comment"
+// @Success      200  {object}  dto.APIResponse{data=dto.ApprovalResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      403  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Failure      409  {object}  dto.APIResponse
+// @Router       /approvals/{id}/approve [post]
+func (h *ApprovalHandler) Approve(c *gin.Context) {
+	h.decide(c, "ApproveApproval", "Approval step approved", h.approvalService.Approve)
+}
+
+// Reject
+// @Summary      Reject approval
+// @Description  Reject the request at its current step; a comment is required. Same permissions as approve.
+// @Tags         Approvals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                       true  "Approval ID"
+// @Param        request  body  dto.ApprovalDecisionRequest  true  "Decision comment"
+// @Success      200  {object}  dto.APIResponse{data=dto.ApprovalResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      403  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Failure      409  {object}  dto.APIResponse
+// @Router       /approvals/{id}/reject [post]
+func (h *ApprovalHandler) Reject(c *gin.Context) {
+	h.decide(c, "RejectApproval", "Approval rejected", h.approvalService.Reject)
+}
+
+// Cancel Approval
+// @Summary      Cancel approval
+// @Description  Withdraw a pending request. Only the requester or the campaign owner may cancel it.
+// @Tags         Approvals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Approval ID"
+// @Success      200  {object}  dto.APIResponse{data=dto.ApprovalResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Failure      409  {object}  dto.APIResponse
+// @Router       /approvals/{id}/cancel [post]
+func (h *ApprovalHandler) Cancel(c *gin.Context) {
+	approvalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid approval ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.approvalService.Cancel(c.Request.Context(), authPayload.UserID, approvalID)
+	if err != nil {
+		h.handleError(c, "CancelApproval", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Approval cancelled",
+		Data:    res,
+	})
+}
+
+type approvalDecisionFunc func(ctx context.Context, userID uuid.UUID, role string, approvalID uuid.UUID, req dto.ApprovalDecisionRequest) (*dto.ApprovalResponse, error)
+
+func (h *ApprovalHandler) decide(c *gin.Context, op string, message string, decide approvalDecisionFunc) {
+	approvalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid approval ID format"})
+		return
+	}
+
+	var req dto.ApprovalDecisionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := decide(c.Request.Context(), authPayload.UserID, authPayload.Role, approvalID, req)
+	if err != nil {
+		h.handleError(c, op, err)
+		return
+	}
+
+	zap.L().Info("Approval decided", zap.String("id", res.ID), zap.String("status", res.Status), zap.String("approver_id", authPayload.UserID.String()))
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: message,
+		Data:    res,
+	})
+}
+
+func (h *ApprovalHandler) handleError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrApprovalNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Approval not found"})
+	case errors.Is(err, service.ErrApprovalPolicyNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Approval policy not found"})
+	case errors.Is(err, service.ErrCampaignNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found or not owned by user"})
+	case errors.Is(err, service.ErrApprovalForbidden):
+		c.JSON(http.StatusForbidden, dto.APIResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrApprovalPending), errors.Is(err, service.ErrApprovalClosed):
+		c.JSON(http.StatusConflict, dto.APIResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidApprovalPolicy), errors.Is(err, service.ErrInvalidApproval),
+		errors.Is(err, service.ErrApprovalNotRequired), errors.Is(err, service.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+	default:
+		zap.L().Error(op+" failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+	}
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type AssetHandler struct {
+	assetService *service.AssetService
+}
+
+func NewAssetHandler(assetService *service.AssetService) *AssetHandler {
+	return &AssetHandler{
+		assetService: assetService,
+	}
+}
+
+// Upload Asset
+// @Summary      Upload campaign asset
+// @Description  Attach a banner, video or copy document to a campaign. The type is sniffed from the content: JPEG, PNG, GIF and WebP images (max 10 MB), MP4 and WebM videos (max 200 MB), PDF, plain text, DOCX, XLSX and PPTX documents (max 25 MB). Images get a thumbnail. Identical files are stored once; uploading a file the campaign already has returns it with 200 and deduplicated set. Download URLs are signed and expire.
+// @Tags         Assets
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      string  true  "Campaign ID"
+// @Param        file  formData  file    true  "Asset file"
+// @Success      201  {object}  dto.APIResponse{data=dto.AssetResponse}
+// @Success      200  {object}  dto.APIResponse{data=dto.AssetResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Failure      413  {object}  dto.APIResponse
+// @Failure      415  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/assets [post]
+func (h *AssetHandler) Upload(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	// Stream the file part straight to the service instead of letting the
+	// form parser buffer it first.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxAssetBytes+1<<20)
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "file is required"})
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "file is required"})
+			return
+		}
+		if err != nil {
+			h.handleError(c, "UploadAsset", err)
+			return
+		}
+		if part.FormName() != "file" || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		res, created, err := h.assetService.Upload(c.Request.Context(), authPayload.UserID, campaignID, part.FileName(), part)
+		part.Close()
+		if err != nil {
+			h.handleError(c, "UploadAsset", err)
+			return
+		}
+
+		if !created {
+			c.JSON(http.StatusOK, dto.APIResponse{
+				Message: "Campaign already has this file",
+				Data:    res,
+			})
+			return
+		}
+		zap.L().Info("Asset uploaded", zap.String("campaign_id", campaignID.String()), zap.String("kind", res.Kind), zap.Int64("size", res.SizeBytes))
+		c.JSON(http.StatusCreated, dto.APIResponse{
+			Message: "Asset uploaded successfully",
+			Data:    res,
+		})
+		return
+	}
+}
+
+// List Assets
+// @Summary      List campaign assets
+// @Description  Files attached to a campaign, newest first, with signed download URLs
+// @Tags         Assets
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path   string  true   "Campaign ID"
+// @Param        kind  query  string  false  "Only this kind" Enums(image, video, document)
+// @Success      200  {object}  dto.APIResponse{data=[]dto.AssetResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/assets [get]
+func (h *AssetHandler) List(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.ListAssetsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.assetService.ListAssets(c.Request.Context(), authPayload.UserID, campaignID, req)
+	if err != nil {
+		h.handleError(c, "ListAssets", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Assets retrieved",
+		Data:    res,
+	})
+}
+
+// Get Asset
+// @Summary      Get campaign asset
+// @Description  One attached file with fresh signed download and thumbnail URLs
+// @Tags         Assets
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string  true  "Campaign ID"
+// @Param        assetId  path  string  true  "Asset ID"
+// @Success      200  {object}  dto.APIResponse{data=dto.AssetResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/assets/{assetId} [get]
+func (h *AssetHandler) Get(c *gin.Context) {
+	campaignID, assetID, ok := assetParams(c)
+	if !ok {
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.assetService.GetAsset(c.Request.Context(), authPayload.UserID, campaignID, assetID)
+	if err != nil {
+		h.handleError(c, "GetAsset", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Asset retrieved",
+		Data:    res,
+	})
+}
+
+// Delete Asset
+// @Summary      Delete campaign asset
+// @Description  Detach a file from a campaign. The stored file is removed once no campaign uses it.
+// @Tags         Assets
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string  true  "Campaign ID"
+// @Param        assetId  path  string  true  "Asset ID"
+// @Success      200  {object}  dto.APIResponse
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/assets/{assetId} [delete]
+func (h *AssetHandler) Delete(c *gin.Context) {
+	campaignID, assetID, ok := assetParams(c)
+	if !ok {
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	if err := h.assetService.DeleteAsset(c.Request.Context(), authPayload.UserID, campaignID, assetID); err != nil {
+		h.handleError(c, "DeleteAsset", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Asset deleted successfully",
+	})
+}
+
+func assetParams(c *gin.Context) (campaignID uuid.UUID, assetID uuid.UUID, ok bool) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return campaignID, assetID, false
+	}
+	assetID, err = uuid.Parse(c.Param("assetId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid asset ID format"})
+		return campaignID, assetID, false
+	}
+	return campaignID, assetID, true
+}
+
+func (h *AssetHandler) handleError(c *gin.Context, op string, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, service.ErrCampaignNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found"})
+	case errors.Is(err, service.ErrAssetNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Asset not found"})
+	case errors.Is(err, service.ErrAssetTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, dto.APIResponse{Error: err.Error()})
+	case errors.As(err, &maxBytesErr):
+		c.JSON(http.StatusRequestEntityTooLarge, dto.APIResponse{Error: fmt.Sprintf("%s: files must not exceed %d MB", service.ErrAssetTooLarge, service.MaxAssetBytes>>20)})
+	case errors.Is(err, service.ErrUnsupportedAsset):
+		c.JSON(http.StatusUnsupportedMediaType, dto.APIResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidAsset):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+	default:
+		zap.L().Error(op+" failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+	}
+}
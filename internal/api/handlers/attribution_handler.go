@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type AttributionHandler struct {
+	attributionService *service.AttributionService
+}
+
+func NewAttributionHandler(attributionService *service.AttributionService) *AttributionHandler {
+	return &AttributionHandler{
+		attributionService: attributionService,
+	}
+}
+
+// Attribution
+// @Summary      Get attributed conversions
+// @Description  Credit the caller's campaigns with the conversions their tracked link clicks led to. Each conversion is split between the visitor's clicks within the lookback window according to the model: last_click, first_click, linear, time_decay (shares halve every half_life_days before the conversion) or position_based (40% to the first and last click each, 20% to the ones in between).
+// @Tags         Analytics
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        model           query  string  false  "Attribution model" Enums(last_click, first_click, linear, time_decay, position_based) default(last_click)
+// @Param        lookback_days   query  int     false  "Days before a conversion that clicks count (max 90)" default(30)
+// @Param        half_life_days  query  number  false  "Half-life of time_decay in days (0.1 to 90)" default(7)
+// @Param        from            query  string  false  "Range start (YYYY-MM-DD or RFC3339), defaults to 30 days before to"
+// @Param        to              query  string  false  "Range end, inclusive when a date (YYYY-MM-DD or RFC3339), defaults to today"
+// @Param        timezone        query  string  false  "IANA timezone of plain dates" default(UTC)
+// @Success      200  {object}  dto.APIResponse{data=dto.AttributionResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Router       /analytics/attribution [get]
+func (h *AttributionHandler) Attribution(c *gin.Context) {
+	var req dto.AttributionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.attributionService.Attribute(c.Request.Context(), authPayload.UserID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTimezone):
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid timezone, expected an IANA name such as Asia/Jakarta"})
+		case errors.Is(err, service.ErrInvalidAnalyticsRange):
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid date range: from must be before to and the range must not exceed 400 days"})
+		default:
+			zap.L().Error("Attribution failed", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Attribution retrieved",
+		Data:    res,
+	})
+}
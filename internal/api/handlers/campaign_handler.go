@@ -3,7 +3,6 @@ package handlers
 import (
 	"errors"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,6 +13,8 @@ import (
 	"github.com/valenrio66/be-project/internal/service"
 )
 
+const maxPatchBodyBytes = 64 << 10
+
 type CampaignHandler struct {
 	campaignService *service.CampaignService
 }
@@ -52,6 +53,10 @@ func (h *CampaignHandler) Create(c *gin.Context) {
 
 	res, err := h.campaignService.CreateCampaign(c.Request.Context(), authPayload.UserID, req)
 	if err != nil {
+		if errors.Is(err, service.ErrUnknownTags) || errors.Is(err, service.ErrInvalidTag) {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+			return
+		}
 		zap.L().Error("CreateCampaign failed: service error", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Failed to create campaign"})
 		return
@@ -64,19 +69,150 @@ func (h *CampaignHandler) Create(c *gin.Context) {
 	})
 }
 
+// Bulk Campaigns
+// @Summary      Bulk campaign operation
+// @Description  Apply update_status, set_fields, add_tag, remove_tag or delete (admin only) to up to 500 campaigns selected by ids or filter, in one transaction. dry_run previews the per-item results; atomic saves nothing unless every item succeeds. Items that would go live without a required approval are reported as approval_required.
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body dto.BulkCampaignRequest true "Bulk Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.BulkCampaignResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      403  {object}  dto.APIResponse
+// @Router       /campaigns/bulk [post]
+func (h *CampaignHandler) Bulk(c *gin.Context) {
+	var req dto.BulkCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.campaignService.BulkCampaigns(c.Request.Context(), authPayload.UserID, authPayload.Role, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrBulkForbidden):
+			c.JSON(http.StatusForbidden, dto.APIResponse{Error: "Forbidden: You don't have permission to access this resource"})
+		case errors.Is(err, service.ErrInvalidBulkRequest), errors.Is(err, service.ErrInvalidCampaignFilter),
+			errors.Is(err, service.ErrUnknownTags):
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		default:
+			zap.L().Error("BulkCampaigns failed", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		}
+		return
+	}
+
+	message := "Bulk operation completed"
+	switch {
+	case res.DryRun:
+		message = "Bulk operation previewed, nothing was saved"
+	case !res.Committed:
+		message = "Bulk operation rolled back, some items failed"
+	}
+	zap.L().Info("Bulk campaign operation", zap.String("action", res.Action), zap.Bool("committed", res.Committed), zap.Int("matched", res.Matched), zap.Int("failed", res.Failed))
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: message,
+		Data:    res,
+	})
+}
+
+// Clone Campaign
+// @Summary      Clone campaign
+// @Description  Copy a campaign, optionally shifting its dates by shift_days or to a new start_date. The copy is reset to draft unless reset_status is false.
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                    true   "Campaign ID"
+// @Param        request  body  dto.CloneCampaignRequest  false  "Clone options"
+// @Success      201  {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Failure      409  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/clone [post]
+func (h *CampaignHandler) Clone(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.CloneCampaignRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.campaignService.CloneCampaign(c.Request.Context(), authPayload.UserID, campaignID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrCampaignNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidCampaign) {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrApprovalRequired) {
+			c.JSON(http.StatusConflict, dto.APIResponse{Error: err.Error()})
+			return
+		}
+		zap.L().Error("CloneCampaign failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		return
+	}
+
+	zap.L().Info("Campaign cloned", zap.String("id", res.ID), zap.String("source_id", campaignID.String()))
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Message: "Campaign cloned successfully",
+		Data:    res,
+	})
+}
+
 // List Campaigns
 // @Summary      List my campaigns
+// @Description  List campaigns with optional filters and whitelisted sorting
 // @Tags         Campaigns
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        page query int false "Page number" default(1)
-// @Param        limit query int false "Limit per page" default(10)
-// @Success      200  {object}  dto.APIResponse{data=[]dto.CampaignResponse}
+// @Param        cursor         query  string    false  "Opaque cursor from page.next_cursor or page.prev_cursor"
+// @Param        limit          query  int       false  "Limit per page" default(10)
+// @Param        include_total  query  bool      false  "Include the total number of matching campaigns"
+// @Param        status      query  []string  false  "Status filter, repeatable or comma separated" collectionFormat(multi)
+// @Param        from        query  string    false  "Only campaigns running on or after this date (YYYY-MM-DD or RFC3339)"
+// @Param        to          query  string    false  "Only campaigns running on or before this date (YYYY-MM-DD or RFC3339)"
+// @Param        min_budget  query  number    false  "Minimum budget"
+// @Param        max_budget  query  number    false  "Maximum budget"
+// @Param        q           query  string    false  "Case-insensitive search in title and description"
+// @Param        tag         query  []string  false  "Tag ID filter, repeatable or comma separated" collectionFormat(multi)
+// @Param        tag_match   query  string    false  "Match any or all of the tags" Enums(any, all) default(any)
+// @Param        sort_by     query  string    false  "Sort field" Enums(created_at, start_date, end_date, budget, title, status) default(created_at)
+// @Param        sort_order  query  string    false  "Sort direction" Enums(asc, desc) default(desc)
+// @Success      200  {object}  dto.APIResponse{data=dto.PageResponse{items=[]dto.CampaignResponse}}
+// @Failure      400  {object}  dto.APIResponse
 // @Router       /campaigns [get]
 func (h *CampaignHandler) List(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	var req dto.ListCampaignsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
 
 	authPayload, err := middleware.GetAuthPayload(c)
 	if err != nil {
@@ -84,8 +220,16 @@ func (h *CampaignHandler) List(c *gin.Context) {
 		return
 	}
 
-	res, err := h.campaignService.ListCampaigns(c.Request.Context(), authPayload.UserID, page, limit)
+	res, err := h.campaignService.ListCampaigns(c.Request.Context(), authPayload.UserID, req)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidCampaignFilter) {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid cursor"})
+			return
+		}
 		zap.L().Error("ListCampaigns failed", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal Server Error"})
 		return
@@ -105,7 +249,10 @@ func (h *CampaignHandler) List(c *gin.Context) {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id   path      string  true  "Campaign ID (UUID)"
+// @Param        If-None-Match  header  string  false  "ETag from a previous response; 304 when unchanged"
 // @Success      200  {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Header       200  {string}  ETag  "Campaign version and digest of the response"
+// @Success      304
 // @Failure      400  {object}  dto.APIResponse
 // @Failure      401  {object}  dto.APIResponse
 // @Failure      404  {object}  dto.APIResponse
@@ -135,6 +282,13 @@ func (h *CampaignHandler) Get(c *gin.Context) {
 		return
 	}
 
+	etag := campaignETag(res)
+	c.Header("ETag", etag)
+	if inm := c.GetHeader("If-None-Match"); inm != "" && noneMatch(inm, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
 	c.JSON(http.StatusOK, dto.APIResponse{
 		Message: "Campaign detail retrieved",
 		Data:    res,
@@ -143,17 +297,22 @@ func (h *CampaignHandler) Get(c *gin.Context) {
 
 // Update Campaign
 // @Summary      Update campaign
-// @Description  Update campaign details (Partial Update supported)
+// @Description  Update campaign details; omitted fields are left unchanged and fields cannot be cleared. Prefer PATCH. Requires If-Match with the campaign ETag. Setting status to active, or raising the budget of an active campaign, fails with 409 when an approval policy covers the budget and no approval does.
 // @Tags         campaigns
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id      path      string                     true  "Campaign ID"
-// @Param        request body      dto.UpdateCampaignRequest  true  "Update Payload"
-// @Success      200     {object}  dto.APIResponse{data=dto.CampaignResponse}
-// @Failure      400     {object}  dto.APIResponse
-// @Failure      401     {object}  dto.APIResponse
-// @Failure      404     {object}  dto.APIResponse
+// @Param        id       path      string                     true  "Campaign ID"
+// @Param        If-Match header    string                     true  "ETag of the version being edited, or *"
+// @Param        request  body      dto.UpdateCampaignRequest  true  "Update Payload"
+// @Success      200      {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Header       200      {string}  ETag  "New campaign version"
+// @Failure      400      {object}  dto.APIResponse
+// @Failure      401      {object}  dto.APIResponse
+// @Failure      404      {object}  dto.APIResponse
+// @Failure      409      {object}  dto.APIResponse
+// @Failure      412      {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Failure      428      {object}  dto.APIResponse
 // @Router       /campaigns/{id} [put]
 func (h *CampaignHandler) Update(c *gin.Context) {
 	idParam := c.Param("id")
@@ -163,6 +322,12 @@ func (h *CampaignHandler) Update(c *gin.Context) {
 		return
 	}
 
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		c.JSON(http.StatusPreconditionRequired, dto.APIResponse{Error: "If-Match header is required"})
+		return
+	}
+
 	var req dto.UpdateCampaignRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
@@ -175,17 +340,115 @@ func (h *CampaignHandler) Update(c *gin.Context) {
 		return
 	}
 
-	res, err := h.campaignService.UpdateCampaign(c.Request.Context(), authPayload.UserID, campaignID, req)
+	res, err := h.campaignService.UpdateCampaign(c.Request.Context(), authPayload.UserID, campaignID, parseIfMatch(ifMatch), req)
 	if err != nil {
 		if errors.Is(err, service.ErrCampaignNotFound) {
 			c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found or not owned by user"})
 			return
 		}
+		if errors.Is(err, service.ErrVersionConflict) {
+			h.respondVersionConflict(c, authPayload.UserID, campaignID)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidCampaign) {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrApprovalRequired) {
+			c.JSON(http.StatusConflict, dto.APIResponse{Error: err.Error()})
+			return
+		}
 		zap.L().Error("UpdateCampaign failed", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
 		return
 	}
 
+	c.Header("ETag", campaignETag(res))
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Campaign updated successfully",
+		Data:    res,
+	})
+}
+
+// Patch Campaign
+// @Summary      Patch campaign
+// @Description  Apply an RFC 7396 merge patch (null clears a field) or an RFC 6902 JSON Patch to title, description, status, start_date, end_date and budget. The patched campaign is validated as a whole. Requires If-Match with the campaign ETag.
+// @Tags         campaigns
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      string  true  "Campaign ID"
+// @Param        If-Match  header    string  true  "ETag of the version being edited, or *"
+// @Param        request   body      object  true  "Merge patch object or JSON Patch operation array"
+// @Success      200       {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Header       200       {string}  ETag  "New campaign version"
+// @Failure      400       {object}  dto.APIResponse
+// @Failure      401       {object}  dto.APIResponse
+// @Failure      404       {object}  dto.APIResponse
+// @Failure      409       {object}  dto.APIResponse
+// @Failure      412       {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Failure      415       {object}  dto.APIResponse
+// @Failure      428       {object}  dto.APIResponse
+// @Router       /campaigns/{id} [patch]
+func (h *CampaignHandler) Patch(c *gin.Context) {
+	idParam := c.Param("id")
+	campaignID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var patchType string
+	switch c.ContentType() {
+	case "application/merge-patch+json", "application/json":
+		patchType = service.PatchTypeMerge
+	case "application/json-patch+json":
+		patchType = service.PatchTypeJSON
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, dto.APIResponse{Error: "Content-Type must be application/merge-patch+json or application/json-patch+json"})
+		return
+	}
+
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		c.JSON(http.StatusPreconditionRequired, dto.APIResponse{Error: "If-Match header is required"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxPatchBodyBytes)
+	patch, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Failed to read request body"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.campaignService.PatchCampaign(c.Request.Context(), authPayload.UserID, campaignID, parseIfMatch(ifMatch), patchType, patch)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCampaignNotFound):
+			c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found"})
+		case errors.Is(err, service.ErrVersionConflict):
+			h.respondVersionConflict(c, authPayload.UserID, campaignID)
+		case errors.Is(err, service.ErrPatchTestFailed), errors.Is(err, service.ErrApprovalRequired):
+			c.JSON(http.StatusConflict, dto.APIResponse{Error: err.Error()})
+		case errors.Is(err, service.ErrInvalidPatch), errors.Is(err, service.ErrInvalidCampaign):
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		default:
+			zap.L().Error("PatchCampaign failed", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		}
+		return
+	}
+
+	c.Header("ETag", campaignETag(res))
 	c.JSON(http.StatusOK, dto.APIResponse{
 		Message: "Campaign updated successfully",
 		Data:    res,
@@ -194,15 +457,19 @@ func (h *CampaignHandler) Update(c *gin.Context) {
 
 // Delete Campaign
 // @Summary      Delete campaign
-// @Description  Delete specific campaign
+// @Description  Move a campaign to the trash; it can be restored until the retention period ends
 // @Tags         campaigns
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path      string  true  "Campaign ID"
+// @Param        id        path      string  true  "Campaign ID"
+// @Param        If-Match  header    string  true  "ETag of the version being deleted, or *"
 // @Success      200  {object}  dto.APIResponse
 // @Failure      400  {object}  dto.APIResponse
 // @Failure      401  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Failure      412  {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Failure      428  {object}  dto.APIResponse
 // @Failure      500  {object}  dto.APIResponse
 // @Router       /campaigns/{id} [delete]
 func (h *CampaignHandler) Delete(c *gin.Context) {
@@ -219,8 +486,22 @@ func (h *CampaignHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	err = h.campaignService.DeleteCampaign(c.Request.Context(), authPayload.UserID, campaignID)
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		c.JSON(http.StatusPreconditionRequired, dto.APIResponse{Error: "If-Match header is required"})
+		return
+	}
+
+	err = h.campaignService.DeleteCampaign(c.Request.Context(), authPayload.UserID, campaignID, parseIfMatch(ifMatch))
 	if err != nil {
+		if errors.Is(err, service.ErrCampaignNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found"})
+			return
+		}
+		if errors.Is(err, service.ErrVersionConflict) {
+			h.respondVersionConflict(c, authPayload.UserID, campaignID)
+			return
+		}
 		zap.L().Error("DeleteCampaign failed", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
 		return
@@ -230,3 +511,235 @@ func (h *CampaignHandler) Delete(c *gin.Context) {
 		Message: "Campaign deleted successfully",
 	})
 }
+
+// List Trash
+// @Summary      List trashed campaigns
+// @Description  List soft-deleted campaigns, most recently deleted first
+// @Tags         campaigns
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        cursor         query  string  false  "Opaque cursor from page.next_cursor"
+// @Param        limit          query  int     false  "Limit per page" default(10)
+// @Param        include_total  query  bool    false  "Include the total number of trashed campaigns"
+// @Success      200  {object}  dto.APIResponse{data=dto.PageResponse{items=[]dto.TrashedCampaignResponse}}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      401  {object}  dto.APIResponse
+// @Router       /campaigns/trash [get]
+func (h *CampaignHandler) ListTrash(c *gin.Context) {
+	var req dto.PageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.campaignService.ListTrash(c.Request.Context(), authPayload.UserID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid cursor"})
+			return
+		}
+		zap.L().Error("ListTrash failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Trashed campaigns retrieved",
+		Data:    res,
+	})
+}
+
+// Restore Campaign
+// @Summary      Restore campaign
+// @Description  Restore a campaign from the trash
+// @Tags         campaigns
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Campaign ID"
+// @Success      200  {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      401  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/restore [post]
+func (h *CampaignHandler) Restore(c *gin.Context) {
+	idParam := c.Param("id")
+	campaignID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.campaignService.RestoreCampaign(c.Request.Context(), authPayload.UserID, campaignID)
+	if err != nil {
+		if errors.Is(err, service.ErrCampaignNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found in trash"})
+			return
+		}
+		zap.L().Error("RestoreCampaign failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		return
+	}
+
+	zap.L().Info("Campaign restored", zap.String("id", res.ID), zap.String("user_id", authPayload.UserID.String()))
+	c.Header("ETag", campaignETag(res))
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Campaign restored successfully",
+		Data:    res,
+	})
+}
+
+// Campaign History
+// @Summary      Get campaign history
+// @Description  List the revisions of a campaign, newest first, with field-level before/after values
+// @Tags         campaigns
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id             path   string  true   "Campaign ID"
+// @Param        cursor         query  string  false  "Opaque cursor from page.next_cursor"
+// @Param        limit          query  int     false  "Limit per page" default(10)
+// @Param        include_total  query  bool    false  "Include the total number of revisions"
+// @Success      200  {object}  dto.APIResponse{data=dto.PageResponse{items=[]dto.CampaignRevisionResponse}}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      401  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/history [get]
+func (h *CampaignHandler) History(c *gin.Context) {
+	idParam := c.Param("id")
+	campaignID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.PageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.campaignService.ListHistory(c.Request.Context(), authPayload.UserID, campaignID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrCampaignNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid cursor"})
+			return
+		}
+		zap.L().Error("ListHistory failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Campaign history retrieved",
+		Data:    res,
+	})
+}
+
+// Revert Campaign
+// @Summary      Revert campaign
+// @Description  Restore the fields of a campaign to the state recorded in an earlier revision
+// @Tags         campaigns
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                     true  "Campaign ID"
+// @Param        request  body  dto.RevertCampaignRequest  true  "Revision to revert to"
+// @Success      200  {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      401  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Failure      409  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/revert [post]
+func (h *CampaignHandler) Revert(c *gin.Context) {
+	idParam := c.Param("id")
+	campaignID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.RevertCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.campaignService.RevertCampaign(c.Request.Context(), authPayload.UserID, campaignID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrCampaignNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found"})
+			return
+		}
+		if errors.Is(err, service.ErrRevisionNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Revision not found"})
+			return
+		}
+		// The revision may hold a budget that no longer matches the
+		// channel allocations.
+		if errors.Is(err, service.ErrApprovalRequired) || errors.Is(err, service.ErrInvalidCampaign) {
+			c.JSON(http.StatusConflict, dto.APIResponse{Error: err.Error()})
+			return
+		}
+		zap.L().Error("RevertCampaign failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		return
+	}
+
+	zap.L().Info("Campaign reverted", zap.String("id", res.ID), zap.Int32("revision", req.Revision))
+	c.Header("ETag", campaignETag(res))
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Campaign reverted successfully",
+		Data:    res,
+	})
+}
+
+// respondVersionConflict answers a failed If-Match with the campaign as it is
+// now, so the client can merge and retry without another round trip.
+func (h *CampaignHandler) respondVersionConflict(c *gin.Context, userID uuid.UUID, campaignID uuid.UUID) {
+	current, err := h.campaignService.GetCampaign(c.Request.Context(), userID, campaignID)
+	if err != nil {
+		if errors.Is(err, service.ErrCampaignNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found"})
+			return
+		}
+		zap.L().Error("GetCampaign after version conflict failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		return
+	}
+
+	c.Header("ETag", campaignETag(current))
+	c.JSON(http.StatusPreconditionFailed, dto.APIResponse{
+		Error: "Campaign has been modified since it was read",
+		Data:  current,
+	})
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type ChannelHandler struct {
+	channelService *service.ChannelService
+}
+
+func NewChannelHandler(channelService *service.ChannelService) *ChannelHandler {
+	return &ChannelHandler{
+		channelService: channelService,
+	}
+}
+
+// List Channels
+// @Summary      Get campaign channels
+// @Description  Channel breakdown of a campaign: allocated budget, status and lifetime metrics per channel, plus unattributed metrics
+// @Tags         Channels
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Campaign ID"
+// @Success      200  {object}  dto.APIResponse{data=dto.CampaignChannelsResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/channels [get]
+func (h *ChannelHandler) List(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.channelService.ListChannels(c.Request.Context(), authPayload.UserID, campaignID)
+	if err != nil {
+		h.handleError(c, "ListChannels", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Channels retrieved",
+		Data:    res,
+	})
+}
+
+// Replace Channels
+// @Summary      Set campaign channels
+// @Description  Replace the channel breakdown of a campaign. Allocations must add up to the campaign budget; send budget to change the campaign budget in the same step. Channels with recorded metrics cannot be removed. An empty list removes the breakdown.
+// @Tags         Channels
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string                      true   "Campaign ID"
+// @Param        If-Match  header  string                      false  "ETag of the campaign version being edited"
+// @Param        request   body    dto.ReplaceChannelsRequest  true   "Channels Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.CampaignChannelsResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Failure      409  {object}  dto.APIResponse
+// @Failure      412  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/channels [put]
+func (h *ChannelHandler) Replace(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.ReplaceChannelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var ifMatch []int32
+	if header := c.GetHeader("If-Match"); header != "" {
+		ifMatch = parseIfMatch(header)
+	}
+
+	res, err := h.channelService.ReplaceChannels(c.Request.Context(), authPayload.UserID, campaignID, ifMatch, req)
+	if err != nil {
+		h.handleError(c, "ReplaceChannels", err)
+		return
+	}
+
+	c.Header("ETag", versionETag(res.Version))
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Channels updated successfully",
+		Data:    res,
+	})
+}
+
+// Update Channel
+// @Summary      Update campaign channel status
+// @Tags         Channels
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                    true  "Campaign ID"
+// @Param        channel  path  string                    true  "Channel" Enums(search, social, email, display, video, affiliate, other)
+// @Param        request  body  dto.UpdateChannelRequest  true  "Channel Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.CampaignChannelsResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/channels/{channel} [patch]
+func (h *ChannelHandler) Update(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.UpdateChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.channelService.UpdateChannel(c.Request.Context(), authPayload.UserID, campaignID, c.Param("channel"), req)
+	if err != nil {
+		h.handleError(c, "UpdateChannel", err)
+		return
+	}
+
+	c.Header("ETag", versionETag(res.Version))
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Channel updated successfully",
+		Data:    res,
+	})
+}
+
+// Channel Analytics
+// @Summary      Get spend by channel
+// @Description  Current allocations and the metrics of the window per channel, across all of the caller's campaigns
+// @Tags         Analytics
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        from      query  string  false  "Range start (YYYY-MM-DD or RFC3339), defaults to 30 days before to"
+// @Param        to        query  string  false  "Range end, inclusive when a date (YYYY-MM-DD or RFC3339), defaults to today"
+// @Param        timezone  query  string  false  "IANA timezone of plain dates" default(UTC)
+// @Success      200  {object}  dto.APIResponse{data=dto.ChannelAnalyticsResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Router       /analytics/channels [get]
+func (h *ChannelHandler) Analytics(c *gin.Context) {
+	var req dto.ChannelAnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.channelService.PortfolioChannels(c.Request.Context(), authPayload.UserID, req)
+	if err != nil {
+		h.handleError(c, "ChannelAnalytics", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Channel analytics retrieved",
+		Data:    res,
+	})
+}
+
+func (h *ChannelHandler) handleError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrCampaignNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found"})
+	case errors.Is(err, service.ErrChannelNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Channel not found"})
+	case errors.Is(err, service.ErrVersionConflict):
+		c.JSON(http.StatusPreconditionFailed, dto.APIResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrChannelHasMetrics), errors.Is(err, service.ErrApprovalRequired):
+		c.JSON(http.StatusConflict, dto.APIResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidChannels), errors.Is(err, service.ErrInvalidCampaign):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidTimezone):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid timezone, expected an IANA name such as Asia/Jakarta"})
+	case errors.Is(err, service.ErrInvalidAnalyticsRange):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid date range: from must be before to and the range must not exceed 400 days"})
+	default:
+		zap.L().Error(op+" failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type CohortHandler struct {
+	cohortService *service.CohortService
+}
+
+func NewCohortHandler(cohortService *service.CohortService) *CohortHandler {
+	return &CohortHandler{
+		cohortService: cohortService,
+	}
+}
+
+// Cohorts
+// @Summary      Get visitor cohorts
+// @Description  Group the visitors whose first tracked link click was in the range on one of the caller's campaigns by that campaign and the week of the click (weeks start on Monday in the timezone). Each cohort is followed week by week: how many were active, and the shares that were active, had converted and had converted more than once. Weeks that have not started yet are left out.
+// @Tags         Analytics
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        campaign_id  query  string  false  "Only visitors first clicking on this campaign"
+// @Param        weeks        query  int     false  "Weeks to follow each cohort for (max 26)" default(8)
+// @Param        from         query  string  false  "Range start (YYYY-MM-DD or RFC3339), defaults to 30 days before to"
+// @Param        to           query  string  false  "Range end, inclusive when a date (YYYY-MM-DD or RFC3339), defaults to today"
+// @Param        timezone     query  string  false  "IANA timezone of plain dates and weeks" default(UTC)
+// @Success      200  {object}  dto.APIResponse{data=dto.CohortAnalysisResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Router       /analytics/cohorts [get]
+func (h *CohortHandler) Cohorts(c *gin.Context) {
+	var req dto.CohortRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.cohortService.Cohorts(c.Request.Context(), authPayload.UserID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTimezone):
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid timezone, expected an IANA name such as Asia/Jakarta"})
+		case errors.Is(err, service.ErrInvalidAnalyticsRange):
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid date range: from must be before to and the range must not exceed 400 days"})
+		default:
+			zap.L().Error("Cohorts failed", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Cohorts retrieved",
+		Data:    res,
+	})
+}
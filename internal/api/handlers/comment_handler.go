@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type CommentHandler struct {
+	commentService *service.CommentService
+}
+
+func NewCommentHandler(commentService *service.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+// Create Comment
+// @Summary      Comment on campaign
+// @Description  Add a Markdown comment to a campaign, or a reply when parent_id is set. Mention members as @email; they are notified and can then join the discussion. The campaign owner and the author of the comment replied to are notified too. Discussions are open to the owner, managers, admins and mentioned members.
+// @Tags         Comments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                    true  "Campaign ID"
+// @Param        request  body  dto.CreateCommentRequest  true  "Comment Payload"
+// @Success      201  {object}  dto.APIResponse{data=dto.CommentResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/comments [post]
+func (h *CommentHandler) Create(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.commentService.CreateComment(c.Request.Context(), authPayload.UserID, authPayload.Role, campaignID, req)
+	if err != nil {
+		h.handleError(c, "CreateComment", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Message: "Comment created successfully",
+		Data:    res,
+	})
+}
+
+// List Comments
+// @Summary      List campaign comments
+// @Description  Top-level comments of a campaign, oldest first, each with its replies
+// @Tags         Comments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id             path   string  true   "Campaign ID"
+// @Param        cursor         query  string  false  "Cursor from a previous page"
+// @Param        limit          query  int     false  "Page size (max 100)" default(10)
+// @Param        include_total  query  bool    false  "Include the number of top-level comments"
+// @Success      200  {object}  dto.APIResponse{data=dto.PageResponse{items=[]dto.CommentResponse}}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/comments [get]
+func (h *CommentHandler) List(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.PageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.commentService.ListComments(c.Request.Context(), authPayload.UserID, authPayload.Role, campaignID, req)
+	if err != nil {
+		h.handleError(c, "ListComments", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Comments retrieved",
+		Data:    res,
+	})
+}
+
+// Update Comment
+// @Summary      Edit comment
+// @Description  Replace the body of my own comment. Newly mentioned members are notified.
+// @Tags         Comments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path  string                    true  "Campaign ID"
+// @Param        commentId  path  string                    true  "Comment ID"
+// @Param        request    body  dto.UpdateCommentRequest  true  "Comment Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.CommentResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      403  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/comments/{commentId} [put]
+func (h *CommentHandler) Update(c *gin.Context) {
+	campaignID, commentID, ok := commentParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.commentService.UpdateComment(c.Request.Context(), authPayload.UserID, authPayload.Role, campaignID, commentID, req)
+	if err != nil {
+		h.handleError(c, "UpdateComment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Comment updated successfully",
+		Data:    res,
+	})
+}
+
+// Delete Comment
+// @Summary      Delete comment
+// @Description  Delete my own comment. Replies stay in the thread under a placeholder.
+// @Tags         Comments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path  string  true  "Campaign ID"
+// @Param        commentId  path  string  true  "Comment ID"
+// @Success      200  {object}  dto.APIResponse
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      403  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/comments/{commentId} [delete]
+func (h *CommentHandler) Delete(c *gin.Context) {
+	campaignID, commentID, ok := commentParams(c)
+	if !ok {
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	if err := h.commentService.DeleteComment(c.Request.Context(), authPayload.UserID, authPayload.Role, campaignID, commentID); err != nil {
+		h.handleError(c, "DeleteComment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Comment deleted successfully",
+	})
+}
+
+func commentParams(c *gin.Context) (campaignID uuid.UUID, commentID uuid.UUID, ok bool) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return campaignID, commentID, false
+	}
+	commentID, err = uuid.Parse(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid comment ID format"})
+		return campaignID, commentID, false
+	}
+	return campaignID, commentID, true
+}
+
+func (h *CommentHandler) handleError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrCampaignNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found"})
+	case errors.Is(err, service.ErrCommentNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Comment not found"})
+	case errors.Is(err, service.ErrCommentForbidden):
+		c.JSON(http.StatusForbidden, dto.APIResponse{Error: "Only the author can change a comment"})
+	case errors.Is(err, service.ErrInvalidComment), errors.Is(err, service.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+	default:
+		zap.L().Error(op+" failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+	}
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+// maxPostbackSize caps the body of a postback.
+const maxPostbackSize = 64 << 10
+
+// transparentGIF is a 1x1 transparent GIF.
+var transparentGIF = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+type ConversionHandler struct {
+	conversionService *service.ConversionService
+}
+
+func NewConversionHandler(conversionService *service.ConversionService) *ConversionHandler {
+	return &ConversionHandler{
+		conversionService: conversionService,
+	}
+}
+
+// Pixel serves the conversion pixel at /pixel.gif, outside the API base
+// path, for pages such as an order confirmation to embed. It always answers
+// with the image, so a bad request never shows as a broken image; problems
+// are only logged.
+func (h *ConversionHandler) Pixel(c *gin.Context) {
+	c.Header("Cache-Control", "private, no-store")
+	defer c.Data(http.StatusOK, "image/gif", transparentGIF)
+
+	var req dto.PixelRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		zap.L().Debug("Ignored conversion pixel", zap.Error(err))
+		return
+	}
+
+	event := service.ConversionEvent{
+		Source:        service.ConversionPixel,
+		Event:         req.Event,
+		TransactionID: req.TransactionID,
+		Value:         req.Value,
+		Currency:      req.Currency,
+		At:            time.Now(),
+	}
+	if id, err := uuid.Parse(req.ClickID); err == nil {
+		event.ClickID = &id
+	}
+	if id, err := uuid.Parse(cookieValue(c, visitorCookie)); err == nil {
+		event.VisitorID = &id
+	}
+
+	if _, err := h.conversionService.Record(c.Request.Context(), event); err != nil {
+		if errors.Is(err, service.ErrInvalidConversion) {
+			zap.L().Debug("Ignored conversion pixel", zap.Error(err))
+			return
+		}
+		zap.L().Error("RecordPixelConversion failed", zap.Error(err))
+	}
+}
+
+// Postback
+// @Summary      Report conversion
+// @Description  Server-to-server postback of a conversion on a tracked link click. Sign the request with the postback secret: X-Postback-Signature is the hex HMAC-SHA256 of the X-Postback-Timestamp value (Unix seconds), a dot and the raw body. Requests older than five minutes are rejected. A transaction ID recorded before for the same campaign, by the pixel or a postback, is acknowledged as a duplicate and counted once.
+// @Tags         Conversions
+// @Accept       json
+// @Produce      json
+// @Param        X-Postback-Timestamp  header  string               true  "Unix timestamp of the request"
+// @Param        X-Postback-Signature  header  string               true  "Request signature"
+// @Param        request               body    dto.PostbackRequest  true  "Conversion Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.PostbackResponse}
+// @Success      201  {object}  dto.APIResponse{data=dto.PostbackResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      401  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /conversions/postback [post]
+func (h *ConversionHandler) Postback(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxPostbackSize)
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Request body is too large"})
+		return
+	}
+
+	if err := h.conversionService.VerifyPostback(c.GetHeader("X-Postback-Timestamp"), c.GetHeader("X-Postback-Signature"), body, time.Now()); err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Invalid or expired signature"})
+		return
+	}
+
+	var req dto.PostbackRequest
+	if err := binding.JSON.BindBody(body, &req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	clickID := uuid.MustParse(req.ClickID)
+	event := service.ConversionEvent{
+		Source:        service.ConversionPostback,
+		Event:         req.Event,
+		ClickID:       &clickID,
+		TransactionID: req.TransactionID,
+		Value:         req.Value,
+		Currency:      req.Currency,
+		At:            time.Now(),
+	}
+	if req.ConvertedAt != nil {
+		event.At = *req.ConvertedAt
+	}
+
+	res, err := h.conversionService.Record(c.Request.Context(), event)
+	if err != nil {
+		h.handleError(c, "RecordPostbackConversion", err)
+		return
+	}
+
+	if res.Duplicate {
+		c.JSON(http.StatusOK, dto.APIResponse{
+			Message: "Conversion already recorded",
+			Data:    res,
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Message: "Conversion recorded",
+		Data:    res,
+	})
+}
+
+// List Conversions
+// @Summary      Get campaign conversions
+// @Description  Conversions credited to a campaign's tracked links, newest first
+// @Tags         Conversions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id             path   string  true   "Campaign ID"
+// @Param        cursor         query  string  false  "Cursor from a previous page"
+// @Param        limit          query  int     false  "Page size (max 100)" default(10)
+// @Param        include_total  query  bool    false  "Include the total count"
+// @Success      200  {object}  dto.APIResponse{data=dto.PageResponse{items=[]dto.ConversionResponse}}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/conversions [get]
+func (h *ConversionHandler) List(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.ListConversionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.conversionService.ListConversions(c.Request.Context(), authPayload.UserID, campaignID, req)
+	if err != nil {
+		h.handleError(c, "ListConversions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Conversions retrieved",
+		Data:    res,
+	})
+}
+
+func (h *ConversionHandler) handleError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrCampaignNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found"})
+	case errors.Is(err, service.ErrClickNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Click not found"})
+	case errors.Is(err, service.ErrInvalidConversion):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+	default:
+		zap.L().Error(op+" failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type DashboardHandler struct {
+	dashboardService *service.DashboardService
+}
+
+func NewDashboardHandler(dashboardService *service.DashboardService) *DashboardHandler {
+	return &DashboardHandler{
+		dashboardService: dashboardService,
+	}
+}
+
+// Dashboard Summary
+// @Summary      Get dashboard summary
+// @Description  Campaign counts by status, budget totals, upcoming starts/ends, top performers and recent activity in one call
+// @Tags         Dashboard
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        upcoming_days  query  int  false  "Horizon for starting/ending soon lists" default(7)
+// @Param        limit          query  int  false  "Max items per list" default(5)
+// @Success      200  {object}  dto.APIResponse{data=dto.DashboardSummaryResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      401  {object}  dto.APIResponse
+// @Router       /dashboard/summary [get]
+func (h *DashboardHandler) Summary(c *gin.Context) {
+	var req dto.DashboardSummaryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.dashboardService.GetSummary(c.Request.Context(), authPayload.UserID, req)
+	if err != nil {
+		zap.L().Error("DashboardSummary failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Dashboard summary retrieved",
+		Data:    res,
+	})
+}
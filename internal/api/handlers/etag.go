@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/valenrio66/be-project/internal/dto"
+)
+
+// versionETag renders a campaign version as a strong entity tag.
+func versionETag(version int32) string {
+	return `"` + strconv.FormatInt(int64(version), 10) + `"`
+}
+
+// campaignETag tags a campaign representation: its version followed by a
+// digest of the response. Comment counts and tag names or colours change
+// without a new version, so the version alone would let conditional GETs
+// keep stale data.
+func campaignETag(c *dto.CampaignResponse) string {
+	body, err := json.Marshal(c)
+	if err != nil {
+		return versionETag(c.Version)
+	}
+	sum := sha256.Sum256(body)
+	return `"` + strconv.FormatInt(int64(c.Version), 10) + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// parseIfMatch extracts the campaign versions listed in an If-Match header;
+// of a tag from campaignETag only the version counts. "*" yields nil,
+// meaning any version. Weak or foreign tags never match under the strong
+// comparison If-Match requires, so they are dropped; a header with no
+// usable tag yields an empty, non-nil slice.
+func parseIfMatch(header string) []int32 {
+	versions := []int32{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil
+		}
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		value, _, _ := strings.Cut(tag[1:len(tag)-1], "-")
+		version, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, int32(version))
+	}
+	return versions
+}
+
+// noneMatch reports whether an If-None-Match header matches etag, using the
+// weak comparison that conditional GETs call for.
+func noneMatch(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type ExportHandler struct {
+	exportService *service.ExportService
+}
+
+func NewExportHandler(exportService *service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// Export Campaigns
+// @Summary      Export campaigns
+// @Description  Stream the filtered campaign list with lifetime metric totals as CSV, XLSX or NDJSON. Filters and sort match the list endpoint.
+// @Tags         Campaigns
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce      application/x-ndjson
+// @Security     BearerAuth
+// @Param        format      query  string    false  "Output format" Enums(csv, xlsx, ndjson) default(csv)
+// @Param        columns     query  []string  false  "Columns to include, in order (repeat or comma separate)" collectionFormat(multi)
+// @Param        locale      query  string    false  "Number and date formatting, e.g. en-US, de-DE, id-ID"
+// @Param        timezone    query  string    false  "IANA timezone for dates, e.g. Asia/Jakarta" default(UTC)
+// @Param        status      query  []string  false  "Filter by status (repeat or comma separate)" collectionFormat(multi)
+// @Param        from        query  string    false  "Campaigns active on or after this date (YYYY-MM-DD or RFC3339)"
+// @Param        to          query  string    false  "Campaigns active on or before this date (YYYY-MM-DD or RFC3339)"
+// @Param        min_budget  query  number    false  "Minimum budget"
+// @Param        max_budget  query  number    false  "Maximum budget"
+// @Param        q           query  string    false  "Search title and description"
+// @Param        tag         query  []string  false  "Tag ID filter (repeat or comma separate)" collectionFormat(multi)
+// @Param        tag_match   query  string    false  "Match any or all of the tags" Enums(any, all) default(any)
+// @Param        sort_by     query  string    false  "Sort field" Enums(created_at, start_date, end_date, budget, title, status) default(created_at)
+// @Param        sort_order  query  string    false  "Sort order" Enums(asc, desc) default(desc)
+// @Success      200  {file}    file
+// @Failure      400  {object}  dto.APIResponse
+// @Router       /campaigns/export [get]
+func (h *ExportHandler) Campaigns(c *gin.Context) {
+	var req dto.ExportCampaignsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	export, err := h.exportService.ExportCampaigns(authPayload.UserID, req)
+	if err != nil {
+		h.handleError(c, "ExportCampaigns", err)
+		return
+	}
+	h.stream(c, "ExportCampaigns", export)
+}
+
+// Export Analytics
+// @Summary      Export analytics
+// @Description  Stream one row per campaign and interval with totals and KPIs over the analytics window as CSV, XLSX or NDJSON
+// @Tags         Analytics
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce      application/x-ndjson
+// @Security     BearerAuth
+// @Param        format       query  string    false  "Output format" Enums(csv, xlsx, ndjson) default(csv)
+// @Param        columns      query  []string  false  "Columns to include, in order (repeat or comma separate)" collectionFormat(multi)
+// @Param        locale       query  string    false  "Number and date formatting, e.g. en-US, de-DE, id-ID"
+// @Param        campaign_id  query  string    false  "Only this campaign"
+// @Param        channel      query  string    false  "Only metrics recorded against this channel" Enums(search, social, email, display, video, affiliate, other)
+// @Param        from         query  string    false  "Start date (YYYY-MM-DD or RFC3339)"
+// @Param        to           query  string    false  "End date (YYYY-MM-DD or RFC3339)"
+// @Param        interval     query  string    false  "Bucket size" Enums(day, week, month) default(day)
+// @Param        timezone     query  string    false  "IANA timezone" default(UTC)
+// @Success      200  {file}    file
+// @Failure      400  {object}  dto.APIResponse
+// @Router       /analytics/export [get]
+func (h *ExportHandler) Analytics(c *gin.Context) {
+	var req dto.ExportAnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	export, err := h.exportService.ExportAnalytics(authPayload.UserID, req)
+	if err != nil {
+		h.handleError(c, "ExportAnalytics", err)
+		return
+	}
+	h.stream(c, "ExportAnalytics", export)
+}
+
+// stream writes the export as the response body. Once the first byte is out
+// the status cannot change, so a failure closes the connection instead: the
+// client sees an incomplete transfer rather than a short but valid file.
+func (h *ExportHandler) stream(c *gin.Context, op string, export *service.Export) {
+	c.Header("Content-Type", export.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	if err := export.WriteTo(c.Request.Context(), c.Writer); err != nil {
+		zap.L().Error(op+" failed while streaming", zap.Error(err))
+		c.Abort()
+		if conn, _, hijackErr := c.Writer.Hijack(); hijackErr == nil {
+			_ = conn.Close()
+		}
+	}
+}
+
+func (h *ExportHandler) handleError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidExport), errors.Is(err, service.ErrInvalidCampaignFilter):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidTimezone):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid timezone, expected an IANA name such as Asia/Jakarta"})
+	case errors.Is(err, service.ErrInvalidAnalyticsRange):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid date range: from must be before to and the range must not exceed 400 buckets"})
+	default:
+		zap.L().Error(op+" failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/storage"
+)
+
+// FileHandler serves files kept by the local storage backend to holders of
+// a signed URL. The S3 backend signs URLs that point at the bucket instead.
+type FileHandler struct {
+	storage *storage.Local
+}
+
+func NewFileHandler(store *storage.Local) *FileHandler {
+	return &FileHandler{
+		storage: store,
+	}
+}
+
+// Download File
+// @Summary      Download stored file
+// @Description  Target of the signed download and thumbnail URLs handed out with assets. No token is needed; the signature authorizes the request until it expires. Supports range requests.
+// @Tags         Assets
+// @Produce      application/octet-stream
+// @Param        key        path   string  true   "Object key"
+// @Param        expires    query  int     true   "Expiry as a Unix timestamp"
+// @Param        signature  query  string  true   "URL signature"
+// @Param        filename   query  string  false  "Name to save the download under"
+// @Success      200
+// @Failure      403  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /files/{key} [get]
+func (h *FileHandler) Download(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	filename := c.Query("filename")
+	if err := h.storage.Verify(key, c.Query("expires"), filename, c.Query("signature")); err != nil {
+		c.JSON(http.StatusForbidden, dto.APIResponse{Error: "Download link is invalid or has expired"})
+		return
+	}
+
+	file, err := h.storage.Get(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Error: "File not found"})
+			return
+		}
+		zap.L().Error("DownloadFile failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		return
+	}
+	defer file.Close()
+
+	// Uploads are only accepted as sniffed, so sniffing again gives the same
+	// type; stop browsers from second-guessing it or running active content.
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "sandbox")
+	if filename != "" {
+		c.Header("Content-Disposition", storage.ContentDisposition(filename))
+	}
+	if rs, ok := file.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, "", time.Time{}, rs)
+		return
+	}
+	c.DataFromReader(http.StatusOK, -1, "application/octet-stream", file, nil)
+}
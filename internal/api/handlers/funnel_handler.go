@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type FunnelHandler struct {
+	funnelService *service.FunnelService
+}
+
+func NewFunnelHandler(funnelService *service.FunnelService) *FunnelHandler {
+	return &FunnelHandler{
+		funnelService: funnelService,
+	}
+}
+
+// Create Funnel
+// @Summary      Create funnel
+// @Description  Define a workspace funnel as an ordered list of 2 to 10 events: click for a tracked link click, or a conversion event name such as signup or purchase. Names are unique regardless of case.
+// @Tags         Funnels
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body  dto.CreateFunnelRequest  true  "Funnel Payload"
+// @Success      201  {object}  dto.APIResponse{data=dto.FunnelResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      409  {object}  dto.APIResponse
+// @Router       /funnels [post]
+func (h *FunnelHandler) Create(c *gin.Context) {
+	var req dto.CreateFunnelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.funnelService.CreateFunnel(c.Request.Context(), authPayload.UserID, req)
+	if err != nil {
+		h.handleError(c, "CreateFunnel", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Message: "Funnel created successfully",
+		Data:    res,
+	})
+}
+
+// List Funnels
+// @Summary      List funnels
+// @Description  Every workspace funnel by name
+// @Tags         Funnels
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.APIResponse{data=[]dto.FunnelResponse}
+// @Router       /funnels [get]
+func (h *FunnelHandler) List(c *gin.Context) {
+	res, err := h.funnelService.ListFunnels(c.Request.Context())
+	if err != nil {
+		h.handleError(c, "ListFunnels", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Funnels retrieved",
+		Data:    res,
+	})
+}
+
+// Get Funnel
+// @Summary      Get funnel
+// @Tags         Funnels
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  string  true  "Funnel ID"
+// @Success      200  {object}  dto.APIResponse{data=dto.FunnelResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /funnels/{id} [get]
+func (h *FunnelHandler) Get(c *gin.Context) {
+	funnelID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid funnel ID format"})
+		return
+	}
+
+	res, err := h.funnelService.GetFunnel(c.Request.Context(), funnelID)
+	if err != nil {
+		h.handleError(c, "GetFunnel", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Funnel retrieved",
+		Data:    res,
+	})
+}
+
+// Update Funnel
+// @Summary      Update funnel
+// @Description  Change the name, description, steps or window of a funnel. Only its creator or an admin may edit it.
+// @Tags         Funnels
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                   true  "Funnel ID"
+// @Param        request  body  dto.UpdateFunnelRequest  true  "Funnel Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.FunnelResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      403  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Failure      409  {object}  dto.APIResponse
+// @Router       /funnels/{id} [put]
+func (h *FunnelHandler) Update(c *gin.Context) {
+	funnelID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid funnel ID format"})
+		return
+	}
+
+	var req dto.UpdateFunnelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.funnelService.UpdateFunnel(c.Request.Context(), authPayload.UserID, authPayload.Role, funnelID, req)
+	if err != nil {
+		h.handleError(c, "UpdateFunnel", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Funnel updated successfully",
+		Data:    res,
+	})
+}
+
+// Delete Funnel
+// @Summary      Delete funnel
+// @Description  Only its creator or an admin may delete a funnel.
+// @Tags         Funnels
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  string  true  "Funnel ID"
+// @Success      200  {object}  dto.APIResponse
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      403  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /funnels/{id} [delete]
+func (h *FunnelHandler) Delete(c *gin.Context) {
+	funnelID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid funnel ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	if err := h.funnelService.DeleteFunnel(c.Request.Context(), authPayload.UserID, authPayload.Role, funnelID); err != nil {
+		h.handleError(c, "DeleteFunnel", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Funnel deleted successfully",
+	})
+}
+
+// Funnel Report
+// @Summary      Get funnel report
+// @Description  Follow the visitors whose first step happened in the range on one of the caller's campaigns through the rest of the steps, in order and within the funnel's window. Each step has the visitors reaching it, conversion from the previous and first step, drop-off and median time from the previous step; the same figures are broken down by the campaign of the first step.
+// @Tags         Funnels
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id           path   string  true   "Funnel ID"
+// @Param        campaign_id  query  string  false  "Only visitors entering on this campaign"
+// @Param        from         query  string  false  "Range start (YYYY-MM-DD or RFC3339), defaults to 30 days before to"
+// @Param        to           query  string  false  "Range end, inclusive when a date (YYYY-MM-DD or RFC3339), defaults to today"
+// @Param        timezone     query  string  false  "IANA timezone of plain dates" default(UTC)
+// @Success      200  {object}  dto.APIResponse{data=dto.FunnelReportResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /funnels/{id}/report [get]
+func (h *FunnelHandler) Report(c *gin.Context) {
+	funnelID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid funnel ID format"})
+		return
+	}
+
+	var req dto.FunnelReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.funnelService.Report(c.Request.Context(), authPayload.UserID, funnelID, req)
+	if err != nil {
+		h.handleError(c, "FunnelReport", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Funnel report retrieved",
+		Data:    res,
+	})
+}
+
+func (h *FunnelHandler) handleError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrFunnelNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Funnel not found"})
+	case errors.Is(err, service.ErrFunnelForbidden):
+		c.JSON(http.StatusForbidden, dto.APIResponse{Error: "Only the creator or an admin can change a funnel"})
+	case errors.Is(err, service.ErrFunnelExists):
+		c.JSON(http.StatusConflict, dto.APIResponse{Error: "A funnel with this name already exists"})
+	case errors.Is(err, service.ErrInvalidFunnel):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidTimezone):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid timezone, expected an IANA name such as Asia/Jakarta"})
+	case errors.Is(err, service.ErrInvalidAnalyticsRange):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid date range: from must be before to and the range must not exceed 400 days"})
+	default:
+		zap.L().Error(op+" failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+	}
+}
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type GoalHandler struct {
+	goalService *service.GoalService
+}
+
+func NewGoalHandler(goalService *service.GoalService) *GoalHandler {
+	return &GoalHandler{
+		goalService: goalService,
+	}
+}
+
+// Create Goal
+// @Summary      Create campaign goal
+// @Description  Set a target for one campaign metric, e.g. 10000 conversions or a CPA of at most 12, by a deadline. The deadline defaults to the campaign end date. A campaign has at most one goal per metric.
+// @Tags         Goals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                 true  "Campaign ID"
+// @Param        request  body  dto.CreateGoalRequest  true  "Goal Payload"
+// @Success      201  {object}  dto.APIResponse{data=dto.GoalResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Failure      409  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/goals [post]
+func (h *GoalHandler) Create(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.CreateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.goalService.CreateGoal(c.Request.Context(), authPayload.UserID, campaignID, req)
+	if err != nil {
+		h.handleError(c, "CreateGoal", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Message: "Goal created successfully",
+		Data:    res,
+	})
+}
+
+// List Goals
+// @Summary      Get campaign goals
+// @Description  Goals of a campaign with their current value, progress and status, by deadline
+// @Tags         Goals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Campaign ID"
+// @Success      200  {object}  dto.APIResponse{data=[]dto.GoalResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/goals [get]
+func (h *GoalHandler) List(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.goalService.ListCampaignGoals(c.Request.Context(), authPayload.UserID, campaignID)
+	if err != nil {
+		h.handleError(c, "ListCampaignGoals", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Goals retrieved",
+		Data:    res,
+	})
+}
+
+// List All Goals
+// @Summary      Get goals across campaigns
+// @Description  Goals of all of the caller's campaigns by deadline, e.g. status=at_risk,off_track for the ones that need attention
+// @Tags         Goals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status  query  []string  false  "Only goals with these statuses" collectionFormat(multi) Enums(on_track, at_risk, off_track, achieved, missed)
+// @Success      200  {object}  dto.APIResponse{data=[]dto.GoalResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Router       /goals [get]
+func (h *GoalHandler) ListAll(c *gin.Context) {
+	var req dto.ListGoalsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.goalService.ListGoals(c.Request.Context(), authPayload.UserID, req)
+	if err != nil {
+		h.handleError(c, "ListGoals", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Goals retrieved",
+		Data:    res,
+	})
+}
+
+// Update Goal
+// @Summary      Update campaign goal
+// @Description  Change the direction, target or deadline of a goal. A changed goal is open again and re-evaluated.
+// @Tags         Goals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                 true  "Campaign ID"
+// @Param        goalId   path  string                 true  "Goal ID"
+// @Param        request  body  dto.UpdateGoalRequest  true  "Goal Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.GoalResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/goals/{goalId} [put]
+func (h *GoalHandler) Update(c *gin.Context) {
+	campaignID, goalID, ok := goalParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.goalService.UpdateGoal(c.Request.Context(), authPayload.UserID, campaignID, goalID, req)
+	if err != nil {
+		h.handleError(c, "UpdateGoal", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Goal updated successfully",
+		Data:    res,
+	})
+}
+
+// Delete Goal
+// @Summary      Delete campaign goal
+// @Tags         Goals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  string  true  "Campaign ID"
+// @Param        goalId  path  string  true  "Goal ID"
+// @Success      200  {object}  dto.APIResponse
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/goals/{goalId} [delete]
+func (h *GoalHandler) Delete(c *gin.Context) {
+	campaignID, goalID, ok := goalParams(c)
+	if !ok {
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	if err := h.goalService.DeleteGoal(c.Request.Context(), authPayload.UserID, campaignID, goalID); err != nil {
+		h.handleError(c, "DeleteGoal", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Goal deleted successfully",
+	})
+}
+
+func goalParams(c *gin.Context) (campaignID uuid.UUID, goalID uuid.UUID, ok bool) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return campaignID, goalID, false
+	}
+	goalID, err = uuid.Parse(c.Param("goalId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid goal ID format"})
+		return campaignID, goalID, false
+	}
+	return campaignID, goalID, true
+}
+
+func (h *GoalHandler) handleError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrCampaignNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found"})
+	case errors.Is(err, service.ErrGoalNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Goal not found"})
+	case errors.Is(err, service.ErrGoalExists):
+		c.JSON(http.StatusConflict, dto.APIResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidGoal):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+	default:
+		zap.L().Error(op+" failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+	}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+const maxImportFileBytes = 10 << 20
+
+type ImportHandler struct {
+	importService *service.ImportService
+}
+
+func NewImportHandler(importService *service.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// Import Campaigns
+// @Summary      Import campaigns from CSV or XLSX
+// @Description  Upload a .csv or .xlsx file (max 10 MB, 10000 rows) whose header row maps to title, description, start_date, end_date and budget. Every row is validated; all_or_nothing imports nothing if any row fails, partial imports the valid rows. Files over 200 rows are processed in the background (202); poll the import for the report.
+// @Tags         Campaigns
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file     formData  file    true   "CSV or XLSX file"
+// @Param        mode     formData  string  false  "Import mode" Enums(all_or_nothing, partial) default(all_or_nothing)
+// @Param        mapping  formData  string  false  "JSON object mapping header to field, e.g. {\"Campaign Name\":\"title\"}"
+// @Success      201  {object}  dto.APIResponse{data=dto.ImportResponse}
+// @Success      202  {object}  dto.APIResponse{data=dto.ImportResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      401  {object}  dto.APIResponse
+// @Router       /campaigns/import [post]
+func (h *ImportHandler) Import(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileBytes+1<<20)
+
+	var req dto.ImportCampaignsRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "file is required"})
+		return
+	}
+	if fileHeader.Size > maxImportFileBytes {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "file must not exceed 10 MB"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Failed to read file"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Failed to read file"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, async, err := h.importService.ImportCampaigns(c.Request.Context(), authPayload.UserID, req, fileHeader.Filename, data)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidImport) {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+			return
+		}
+		zap.L().Error("ImportCampaigns failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		return
+	}
+
+	zap.L().Info("Campaign import accepted", zap.String("id", res.ID), zap.Int32("rows", res.TotalRows), zap.Bool("async", async))
+	if async {
+		c.Header("Location", "/api/v1/campaigns/imports/"+res.ID)
+		c.JSON(http.StatusAccepted, dto.APIResponse{
+			Message: "Import accepted and is being processed",
+			Data:    res,
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Message: "Import finished",
+		Data:    res,
+	})
+}
+
+// Get Import
+// @Summary      Get campaign import
+// @Description  Status and row-by-row validation report of an import. An import still unfinished 30 minutes after upload was interrupted and is reported as failed.
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Import ID"
+// @Success      200  {object}  dto.APIResponse{data=dto.ImportResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/imports/{id} [get]
+func (h *ImportHandler) Get(c *gin.Context) {
+	importID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid import ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.importService.GetImport(c.Request.Context(), authPayload.UserID, importID)
+	if err != nil {
+		if errors.Is(err, service.ErrImportNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Import not found"})
+			return
+		}
+		zap.L().Error("GetImport failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Import retrieved",
+		Data:    res,
+	})
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+const (
+	// visitorCookie identifies a browser across clicks on tracked links.
+	visitorCookie = "vid"
+	// visitorCookieMaxAge keeps the visitor ID for a year.
+	visitorCookieMaxAge = 365 * 24 * 60 * 60
+)
+
+type LinkHandler struct {
+	linkService *service.LinkService
+}
+
+func NewLinkHandler(linkService *service.LinkService) *LinkHandler {
+	return &LinkHandler{
+		linkService: linkService,
+	}
+}
+
+// Create Link
+// @Summary      Create tracked link
+// @Description  Tag a destination URL with normalised UTM parameters and get a short link that counts clicks. utm_medium defaults to the channel and utm_campaign to the campaign title.
+// @Tags         Links
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                 true  "Campaign ID"
+// @Param        request  body  dto.CreateLinkRequest  true  "Link Payload"
+// @Success      201  {object}  dto.APIResponse{data=dto.LinkResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/links [post]
+func (h *LinkHandler) Create(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.CreateLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.linkService.CreateLink(c.Request.Context(), authPayload.UserID, campaignID, req)
+	if err != nil {
+		h.handleError(c, "CreateLink", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Message: "Link created successfully",
+		Data:    res,
+	})
+}
+
+// List Links
+// @Summary      Get tracked links
+// @Description  Tracked links of a campaign, newest first, with click and visitor counts
+// @Tags         Links
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Campaign ID"
+// @Success      200  {object}  dto.APIResponse{data=[]dto.LinkResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/links [get]
+func (h *LinkHandler) List(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.linkService.ListLinks(c.Request.Context(), authPayload.UserID, campaignID)
+	if err != nil {
+		h.handleError(c, "ListLinks", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Links retrieved",
+		Data:    res,
+	})
+}
+
+// Get Link
+// @Summary      Get tracked link
+// @Tags         Links
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  string  true  "Campaign ID"
+// @Param        linkId  path  string  true  "Link ID"
+// @Success      200  {object}  dto.APIResponse{data=dto.LinkResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/links/{linkId} [get]
+func (h *LinkHandler) Get(c *gin.Context) {
+	campaignID, linkID, ok := linkParams(c)
+	if !ok {
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.linkService.GetLink(c.Request.Context(), authPayload.UserID, campaignID, linkID)
+	if err != nil {
+		h.handleError(c, "GetLink", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Link retrieved",
+		Data:    res,
+	})
+}
+
+// Delete Link
+// @Summary      Delete tracked link
+// @Description  The short link stops redirecting. Clicks already counted in the campaign metrics stay there.
+// @Tags         Links
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path  string  true  "Campaign ID"
+// @Param        linkId  path  string  true  "Link ID"
+// @Success      200  {object}  dto.APIResponse
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/links/{linkId} [delete]
+func (h *LinkHandler) Delete(c *gin.Context) {
+	campaignID, linkID, ok := linkParams(c)
+	if !ok {
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	if err := h.linkService.DeleteLink(c.Request.Context(), authPayload.UserID, campaignID, linkID); err != nil {
+		h.handleError(c, "DeleteLink", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Link deleted successfully",
+	})
+}
+
+// Redirect serves the short links at /r/{code}, outside the API base path.
+// It logs the click, sets the visitor cookie and redirects to the tagged
+// destination. Responses are not cacheable so every click reaches us.
+func (h *LinkHandler) Redirect(c *gin.Context) {
+	visitorID, err := uuid.Parse(cookieValue(c, visitorCookie))
+	if err != nil {
+		visitorID = uuid.New()
+	}
+
+	target, err := h.linkService.Resolve(c.Request.Context(), c.Param("code"), service.LinkClick{
+		VisitorID: visitorID,
+		IP:        c.ClientIP(),
+		Referrer:  c.Request.Referer(),
+		UserAgent: c.Request.UserAgent(),
+		At:        time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrLinkNotFound) {
+			c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Link not found"})
+			return
+		}
+		zap.L().Error("ResolveLink failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		return
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     visitorCookie,
+		Value:    visitorID.String(),
+		Path:     "/",
+		MaxAge:   visitorCookieMaxAge,
+		HttpOnly: true,
+		Secure:   c.Request.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	c.Header("Cache-Control", "private, no-store")
+	c.Redirect(http.StatusFound, target)
+}
+
+func cookieValue(c *gin.Context, name string) string {
+	value, err := c.Cookie(name)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+func linkParams(c *gin.Context) (campaignID uuid.UUID, linkID uuid.UUID, ok bool) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return campaignID, linkID, false
+	}
+	linkID, err = uuid.Parse(c.Param("linkId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid link ID format"})
+		return campaignID, linkID, false
+	}
+	return campaignID, linkID, true
+}
+
+func (h *LinkHandler) handleError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrCampaignNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found"})
+	case errors.Is(err, service.ErrLinkNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Link not found"})
+	case errors.Is(err, service.ErrInvalidLink), errors.Is(err, service.ErrChannelNotFound), errors.Is(err, service.ErrVariantNotFound):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+	default:
+		zap.L().Error(op+" failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+	}
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// List Notifications
+// @Summary      List notifications
+// @Description  My notifications, newest first. include_total counts the unread ones.
+// @Tags         Notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        unread         query  bool    false  "Only unread notifications"
+// @Param        cursor         query  string  false  "Cursor from a previous page"
+// @Param        limit          query  int     false  "Page size (max 100)" default(10)
+// @Param        include_total  query  bool    false  "Include the unread count"
+// @Success      200  {object}  dto.APIResponse{data=dto.PageResponse{items=[]dto.NotificationResponse}}
+// @Failure      400  {object}  dto.APIResponse
+// @Router       /notifications [get]
+func (h *NotificationHandler) List(c *gin.Context) {
+	var req dto.ListNotificationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.notificationService.ListNotifications(c.Request.Context(), authPayload.UserID, req)
+	if err != nil {
+		h.handleError(c, "ListNotifications", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Notifications retrieved",
+		Data:    res,
+	})
+}
+
+// Unread Notifications
+// @Summary      Unread notification count
+// @Tags         Notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.APIResponse{data=dto.UnreadNotificationsResponse}
+// @Router       /notifications/unread-count [get]
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.notificationService.UnreadCount(c.Request.Context(), authPayload.UserID)
+	if err != nil {
+		h.handleError(c, "UnreadNotifications", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Unread notifications counted",
+		Data:    res,
+	})
+}
+
+// Mark Notification Read
+// @Summary      Mark notification read
+// @Tags         Notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Notification ID"
+// @Success      200  {object}  dto.APIResponse
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid notification ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	if err := h.notificationService.MarkRead(c.Request.Context(), authPayload.UserID, notificationID); err != nil {
+		h.handleError(c, "MarkNotificationRead", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Notification marked as read",
+	})
+}
+
+// Mark All Notifications Read
+// @Summary      Mark all notifications read
+// @Tags         Notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.APIResponse{data=dto.UnreadNotificationsResponse}
+// @Router       /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	marked, err := h.notificationService.MarkAllRead(c.Request.Context(), authPayload.UserID)
+	if err != nil {
+		h.handleError(c, "MarkAllNotificationsRead", err)
+		return
+	}
+
+	zap.L().Info("Notifications marked as read", zap.String("user_id", authPayload.UserID.String()), zap.Int64("count", marked))
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "All notifications marked as read",
+		Data:    dto.UnreadNotificationsResponse{Unread: 0},
+	})
+}
+
+func (h *NotificationHandler) handleError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotificationNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Notification not found"})
+	case errors.Is(err, service.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+	default:
+		zap.L().Error(op+" failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type SearchHandler struct {
+	searchService *service.SearchService
+}
+
+func NewSearchHandler(searchService *service.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search Campaigns
+// @Summary      Full-text search
+// @Description  Ranked prefix search over the title, description and tag names of the caller's campaigns with highlighted matches
+// @Tags         Search
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q              query  string  true   "Search text"
+// @Param        cursor         query  string  false  "Opaque cursor from page.next_cursor"
+// @Param        limit          query  int     false  "Limit per page" default(10)
+// @Param        include_total  query  bool    false  "Include the total number of matches"
+// @Success      200  {object}  dto.APIResponse{data=dto.PageResponse{items=[]dto.SearchResultResponse}}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      401  {object}  dto.APIResponse
+// @Router       /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	var req dto.SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.searchService.SearchCampaigns(c.Request.Context(), authPayload.UserID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmptySearchQuery):
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Search query must contain letters or digits"})
+		case errors.Is(err, service.ErrInvalidCursor):
+			c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid cursor"})
+		default:
+			zap.L().Error("SearchCampaigns failed", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Search results retrieved",
+		Data:    res,
+	})
+}
@@ -0,0 +1,434 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type SegmentHandler struct {
+	segmentService *service.SegmentService
+}
+
+func NewSegmentHandler(segmentService *service.SegmentService) *SegmentHandler {
+	return &SegmentHandler{
+		segmentService: segmentService,
+	}
+}
+
+// Segment Fields
+// @Summary      List segment fields
+// @Description  Fields segment rules can test, with their operators. Custom attributes are written as custom.<key> with a lower-case key.
+// @Tags         Segments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.APIResponse{data=[]dto.SegmentFieldResponse}
+// @Router       /segments/fields [get]
+func (h *SegmentHandler) Fields(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Segment fields retrieved",
+		Data:    h.segmentService.Fields(),
+	})
+}
+
+// Validate Segment
+// @Summary      Validate segment rules
+// @Description  Check a rule tree without saving it. A valid tree comes back in canonical form with a human-readable summary; an invalid one lists every problem with its path.
+// @Tags         Segments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body  dto.ValidateSegmentRequest  true  "Rules Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.SegmentValidationResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Router       /segments/validate [post]
+func (h *SegmentHandler) Validate(c *gin.Context) {
+	var req dto.ValidateSegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	res, err := h.segmentService.ValidateRules(req)
+	if err != nil {
+		h.handleError(c, "ValidateSegment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Segment rules checked",
+		Data:    res,
+	})
+}
+
+// Create Segment
+// @Summary      Create segment
+// @Description  Create a workspace audience segment from a rule tree. Names are unique regardless of case. Invalid rules are rejected with the list of problems in data.
+// @Tags         Segments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body  dto.CreateSegmentRequest  true  "Segment Payload"
+// @Success      201  {object}  dto.APIResponse{data=dto.SegmentResponse}
+// @Failure      400  {object}  dto.APIResponse{data=[]dto.SegmentRuleError}
+// @Failure      409  {object}  dto.APIResponse
+// @Router       /segments [post]
+func (h *SegmentHandler) Create(c *gin.Context) {
+	var req dto.CreateSegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.segmentService.CreateSegment(c.Request.Context(), authPayload.UserID, req)
+	if err != nil {
+		h.handleError(c, "CreateSegment", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Message: "Segment created successfully",
+		Data:    res,
+	})
+}
+
+// List Segments
+// @Summary      List segments
+// @Description  Every workspace segment by name, each with the number of campaigns using it
+// @Tags         Segments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q  query  string  false  "Search in name and description"
+// @Success      200  {object}  dto.APIResponse{data=[]dto.SegmentResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Router       /segments [get]
+func (h *SegmentHandler) List(c *gin.Context) {
+	var req dto.ListSegmentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	res, err := h.segmentService.ListSegments(c.Request.Context(), req)
+	if err != nil {
+		h.handleError(c, "ListSegments", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Segments retrieved",
+		Data:    res,
+	})
+}
+
+// Get Segment
+// @Summary      Get segment
+// @Tags         Segments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  string  true  "Segment ID"
+// @Success      200  {object}  dto.APIResponse{data=dto.SegmentResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /segments/{id} [get]
+func (h *SegmentHandler) Get(c *gin.Context) {
+	segmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid segment ID format"})
+		return
+	}
+
+	res, err := h.segmentService.GetSegment(c.Request.Context(), segmentID)
+	if err != nil {
+		h.handleError(c, "GetSegment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Segment retrieved",
+		Data:    res,
+	})
+}
+
+// Update Segment
+// @Summary      Update segment
+// @Description  Change the name, description or rules of a segment. Campaigns using it target the new definition. Only its creator or an admin may edit it.
+// @Tags         Segments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                    true  "Segment ID"
+// @Param        request  body  dto.UpdateSegmentRequest  true  "Segment Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.SegmentResponse}
+// @Failure      400  {object}  dto.APIResponse{data=[]dto.SegmentRuleError}
+// @Failure      403  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Failure      409  {object}  dto.APIResponse
+// @Router       /segments/{id} [put]
+func (h *SegmentHandler) Update(c *gin.Context) {
+	segmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid segment ID format"})
+		return
+	}
+
+	var req dto.UpdateSegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.segmentService.UpdateSegment(c.Request.Context(), authPayload.UserID, authPayload.Role, segmentID, req)
+	if err != nil {
+		h.handleError(c, "UpdateSegment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Segment updated successfully",
+		Data:    res,
+	})
+}
+
+// Delete Segment
+// @Summary      Delete segment
+// @Description  Delete a segment no campaign uses, including campaigns in the trash. Only its creator or an admin may delete it.
+// @Tags         Segments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  string  true  "Segment ID"
+// @Success      200  {object}  dto.APIResponse
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      403  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Failure      409  {object}  dto.APIResponse
+// @Router       /segments/{id} [delete]
+func (h *SegmentHandler) Delete(c *gin.Context) {
+	segmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid segment ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	if err := h.segmentService.DeleteSegment(c.Request.Context(), authPayload.UserID, authPayload.Role, segmentID); err != nil {
+		h.handleError(c, "DeleteSegment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Segment deleted successfully",
+	})
+}
+
+// Segment Campaigns
+// @Summary      List campaigns using a segment
+// @Description  Newest first. Managers and admins see every campaign, others only their own.
+// @Tags         Segments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id             path   string  true   "Segment ID"
+// @Param        cursor         query  string  false  "Cursor from a previous page"
+// @Param        limit          query  int     false  "Page size (max 100)" default(10)
+// @Param        include_total  query  bool    false  "Include the number of campaigns"
+// @Success      200  {object}  dto.APIResponse{data=dto.PageResponse{items=[]dto.CampaignResponse}}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /segments/{id}/campaigns [get]
+func (h *SegmentHandler) Campaigns(c *gin.Context) {
+	segmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid segment ID format"})
+		return
+	}
+
+	var req dto.PageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.segmentService.ListSegmentCampaigns(c.Request.Context(), authPayload.UserID, authPayload.Role, segmentID, req)
+	if err != nil {
+		h.handleError(c, "ListSegmentCampaigns", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Segment campaigns retrieved",
+		Data:    res,
+	})
+}
+
+// Set Campaign Segments
+// @Summary      Replace campaign segments
+// @Description  Make segment_ids the exact set of audience segments a campaign targets; an empty list removes all segments
+// @Tags         Segments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                       true  "Campaign ID"
+// @Param        request  body  dto.CampaignSegmentsRequest  true  "Segments Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/segments [put]
+func (h *SegmentHandler) ReplaceCampaignSegments(c *gin.Context) {
+	h.changeCampaignSegments(c, "ReplaceCampaignSegments", h.segmentService.ReplaceCampaignSegments)
+}
+
+// Attach Campaign Segments
+// @Summary      Attach campaign segments
+// @Description  Attach segments to a campaign; segments it already targets are ignored
+// @Tags         Segments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                       true  "Campaign ID"
+// @Param        request  body  dto.CampaignSegmentsRequest  true  "Segments Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/segments [post]
+func (h *SegmentHandler) AddCampaignSegments(c *gin.Context) {
+	h.changeCampaignSegments(c, "AddCampaignSegments", h.segmentService.AddCampaignSegments)
+}
+
+// Detach Campaign Segment
+// @Summary      Detach campaign segment
+// @Tags         Segments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path  string  true  "Campaign ID"
+// @Param        segmentId  path  string  true  "Segment ID"
+// @Success      200  {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/segments/{segmentId} [delete]
+func (h *SegmentHandler) RemoveCampaignSegment(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+	segmentID, err := uuid.Parse(c.Param("segmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid segment ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.segmentService.RemoveCampaignSegment(c.Request.Context(), authPayload.UserID, campaignID, segmentID)
+	if err != nil {
+		h.handleError(c, "RemoveCampaignSegment", err)
+		return
+	}
+
+	c.Header("ETag", campaignETag(res))
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Campaign segments updated",
+		Data:    res,
+	})
+}
+
+type campaignSegmentsFunc func(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.CampaignSegmentsRequest) (*dto.CampaignResponse, error)
+
+func (h *SegmentHandler) changeCampaignSegments(c *gin.Context, op string, change campaignSegmentsFunc) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.CampaignSegmentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := change(c.Request.Context(), authPayload.UserID, campaignID, req)
+	if err != nil {
+		h.handleError(c, op, err)
+		return
+	}
+
+	c.Header("ETag", campaignETag(res))
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Campaign segments updated",
+		Data:    res,
+	})
+}
+
+func (h *SegmentHandler) handleError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrSegmentNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Segment not found"})
+	case errors.Is(err, service.ErrCampaignNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found or not owned by user"})
+	case errors.Is(err, service.ErrSegmentForbidden):
+		c.JSON(http.StatusForbidden, dto.APIResponse{Error: "Only the creator or an admin can change a segment"})
+	case errors.Is(err, service.ErrSegmentExists):
+		c.JSON(http.StatusConflict, dto.APIResponse{Error: "A segment with this name already exists"})
+	case errors.Is(err, service.ErrSegmentInUse):
+		c.JSON(http.StatusConflict, dto.APIResponse{Error: "Segment is used by campaigns; detach it first"})
+	case errors.Is(err, service.ErrInvalidSegment):
+		res := dto.APIResponse{Error: err.Error()}
+		if ruleErrs := service.SegmentRuleErrors(err); ruleErrs != nil {
+			res.Data = ruleErrs
+		}
+		c.JSON(http.StatusBadRequest, res)
+	case errors.Is(err, service.ErrUnknownSegments), errors.Is(err, service.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+	default:
+		zap.L().Error(op+" failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+	}
+}
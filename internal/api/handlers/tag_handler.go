@@ -0,0 +1,336 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type TagHandler struct {
+	tagService *service.TagService
+}
+
+func NewTagHandler(tagService *service.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// Create Tag
+// @Summary      Create tag
+// @Description  Create a workspace tag. Names are unique regardless of case.
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body dto.CreateTagRequest true "Tag Payload"
+// @Success      201  {object}  dto.APIResponse{data=dto.TagResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      409  {object}  dto.APIResponse
+// @Router       /tags [post]
+func (h *TagHandler) Create(c *gin.Context) {
+	var req dto.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.tagService.CreateTag(c.Request.Context(), authPayload.UserID, req)
+	if err != nil {
+		h.handleError(c, "CreateTag", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Message: "Tag created successfully",
+		Data:    res,
+	})
+}
+
+// List Tags
+// @Summary      List tags
+// @Description  List all workspace tags by name with the number of my campaigns using each
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q    query     string  false  "Case-insensitive search in tag names"
+// @Success      200  {object}  dto.APIResponse{data=[]dto.TagResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Router       /tags [get]
+func (h *TagHandler) List(c *gin.Context) {
+	var req dto.ListTagsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.tagService.ListTags(c.Request.Context(), authPayload.UserID, req)
+	if err != nil {
+		h.handleError(c, "ListTags", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Tags retrieved",
+		Data:    res,
+	})
+}
+
+// Update Tag
+// @Summary      Update tag
+// @Description  Rename or recolor a workspace tag (admin only)
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                true  "Tag ID"
+// @Param        request  body  dto.UpdateTagRequest  true  "Update Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.TagResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Failure      409  {object}  dto.APIResponse
+// @Router       /tags/{id} [put]
+func (h *TagHandler) Update(c *gin.Context) {
+	tagID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid tag ID format"})
+		return
+	}
+
+	var req dto.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	res, err := h.tagService.UpdateTag(c.Request.Context(), tagID, req)
+	if err != nil {
+		h.handleError(c, "UpdateTag", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Tag updated successfully",
+		Data:    res,
+	})
+}
+
+// Delete Tag
+// @Summary      Delete tag
+// @Description  Delete a workspace tag and remove it from every campaign (admin only)
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Tag ID"
+// @Success      200  {object}  dto.APIResponse
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /tags/{id} [delete]
+func (h *TagHandler) Delete(c *gin.Context) {
+	tagID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid tag ID format"})
+		return
+	}
+
+	if err := h.tagService.DeleteTag(c.Request.Context(), tagID); err != nil {
+		h.handleError(c, "DeleteTag", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Tag deleted successfully",
+	})
+}
+
+// Tag Rollups
+// @Summary      Tag rollups
+// @Description  Campaign count, total budget and metric KPIs per tag over my campaigns. A campaign with several tags counts towards each of them. Without from/to metrics cover all time.
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        from      query  string    false  "Metrics from this date (YYYY-MM-DD or RFC3339)"
+// @Param        to        query  string    false  "Metrics up to this date, inclusive for YYYY-MM-DD"
+// @Param        timezone  query  string    false  "IANA timezone for date-only from/to" default(UTC)
+// @Param        status    query  []string  false  "Only campaigns with these statuses" collectionFormat(multi)
+// @Param        tag       query  []string  false  "Only these tag IDs" collectionFormat(multi)
+// @Success      200  {object}  dto.APIResponse{data=[]dto.TagRollupResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Router       /tags/rollups [get]
+func (h *TagHandler) Rollups(c *gin.Context) {
+	var req dto.TagRollupRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.tagService.Rollups(c.Request.Context(), authPayload.UserID, req)
+	if err != nil {
+		h.handleError(c, "TagRollups", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Tag rollups retrieved",
+		Data:    res,
+	})
+}
+
+// Set Campaign Tags
+// @Summary      Replace campaign tags
+// @Description  Make tag_ids the exact set of tags on a campaign; an empty list removes all tags
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                   true  "Campaign ID"
+// @Param        request  body  dto.CampaignTagsRequest  true  "Tags Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/tags [put]
+func (h *TagHandler) ReplaceCampaignTags(c *gin.Context) {
+	h.changeCampaignTags(c, "ReplaceCampaignTags", h.tagService.ReplaceCampaignTags)
+}
+
+// Add Campaign Tags
+// @Summary      Add campaign tags
+// @Description  Add tags to a campaign; tags it already has are ignored
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                   true  "Campaign ID"
+// @Param        request  body  dto.CampaignTagsRequest  true  "Tags Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/tags [post]
+func (h *TagHandler) AddCampaignTags(c *gin.Context) {
+	h.changeCampaignTags(c, "AddCampaignTags", h.tagService.AddCampaignTags)
+}
+
+// Remove Campaign Tag
+// @Summary      Remove campaign tag
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      string  true  "Campaign ID"
+// @Param        tagId   path      string  true  "Tag ID"
+// @Success      200  {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/tags/{tagId} [delete]
+func (h *TagHandler) RemoveCampaignTag(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+	tagID, err := uuid.Parse(c.Param("tagId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid tag ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.tagService.RemoveCampaignTag(c.Request.Context(), authPayload.UserID, campaignID, tagID)
+	if err != nil {
+		h.handleError(c, "RemoveCampaignTag", err)
+		return
+	}
+
+	c.Header("ETag", campaignETag(res))
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Campaign tags updated",
+		Data:    res,
+	})
+}
+
+type campaignTagsFunc func(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.CampaignTagsRequest) (*dto.CampaignResponse, error)
+
+func (h *TagHandler) changeCampaignTags(c *gin.Context, op string, change campaignTagsFunc) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.CampaignTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := change(c.Request.Context(), authPayload.UserID, campaignID, req)
+	if err != nil {
+		h.handleError(c, op, err)
+		return
+	}
+
+	c.Header("ETag", campaignETag(res))
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Campaign tags updated",
+		Data:    res,
+	})
+}
+
+func (h *TagHandler) handleError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrTagNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Tag not found"})
+	case errors.Is(err, service.ErrCampaignNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found or not owned by user"})
+	case errors.Is(err, service.ErrTagExists):
+		c.JSON(http.StatusConflict, dto.APIResponse{Error: "A tag with this name already exists"})
+	case errors.Is(err, service.ErrInvalidTag), errors.Is(err, service.ErrUnknownTags),
+		errors.Is(err, service.ErrInvalidCampaignFilter):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidTimezone):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid timezone, expected an IANA name such as Asia/Jakarta"})
+	default:
+		zap.L().Error(op+" failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+	}
+}
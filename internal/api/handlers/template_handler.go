@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type TemplateHandler struct {
+	templateService *service.TemplateService
+}
+
+func NewTemplateHandler(templateService *service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// Create Template
+// @Summary      Create campaign template
+// @Description  Create a personal template, or a workspace template shared with everyone (admin only). Title and description may contain {{variable}} placeholders.
+// @Tags         Templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body dto.CreateTemplateRequest true "Template Payload"
+// @Success      201  {object}  dto.APIResponse{data=dto.TemplateResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      403  {object}  dto.APIResponse
+// @Router       /templates [post]
+func (h *TemplateHandler) Create(c *gin.Context) {
+	var req dto.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.templateService.CreateTemplate(c.Request.Context(), authPayload.UserID, authPayload.Role, req)
+	if err != nil {
+		h.handleError(c, "CreateTemplate", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Message: "Template created successfully",
+		Data:    res,
+	})
+}
+
+// List Templates
+// @Summary      List campaign templates
+// @Description  List my personal templates and all workspace templates
+// @Tags         Templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        cursor         query  string  false  "Opaque cursor from page.next_cursor"
+// @Param        limit          query  int     false  "Limit per page" default(10)
+// @Param        include_total  query  bool    false  "Include the total number of templates"
+// @Param        scope          query  string  false  "Only templates with this scope" Enums(user, workspace)
+// @Success      200  {object}  dto.APIResponse{data=dto.PageResponse{items=[]dto.TemplateResponse}}
+// @Failure      400  {object}  dto.APIResponse
+// @Router       /templates [get]
+func (h *TemplateHandler) List(c *gin.Context) {
+	var req dto.ListTemplatesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.templateService.ListTemplates(c.Request.Context(), authPayload.UserID, req)
+	if err != nil {
+		h.handleError(c, "ListTemplates", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Templates retrieved",
+		Data:    res,
+	})
+}
+
+// Get Template
+// @Summary      Get campaign template
+// @Tags         Templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Template ID"
+// @Success      200  {object}  dto.APIResponse{data=dto.TemplateResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /templates/{id} [get]
+func (h *TemplateHandler) Get(c *gin.Context) {
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid template ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.templateService.GetTemplate(c.Request.Context(), authPayload.UserID, templateID)
+	if err != nil {
+		h.handleError(c, "GetTemplate", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Template retrieved",
+		Data:    res,
+	})
+}
+
+// Update Template
+// @Summary      Update campaign template
+// @Description  Owners may update personal templates; workspace templates require an admin
+// @Tags         Templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                     true  "Template ID"
+// @Param        request  body  dto.UpdateTemplateRequest  true  "Update Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.TemplateResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      403  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /templates/{id} [put]
+func (h *TemplateHandler) Update(c *gin.Context) {
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid template ID format"})
+		return
+	}
+
+	var req dto.UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.templateService.UpdateTemplate(c.Request.Context(), authPayload.UserID, authPayload.Role, templateID, req)
+	if err != nil {
+		h.handleError(c, "UpdateTemplate", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Template updated successfully",
+		Data:    res,
+	})
+}
+
+// Delete Template
+// @Summary      Delete campaign template
+// @Description  Owners may delete personal templates; workspace templates require an admin
+// @Tags         Templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Template ID"
+// @Success      200  {object}  dto.APIResponse
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      403  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /templates/{id} [delete]
+func (h *TemplateHandler) Delete(c *gin.Context) {
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid template ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(c.Request.Context(), authPayload.UserID, authPayload.Role, templateID); err != nil {
+		h.handleError(c, "DeleteTemplate", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Template deleted successfully",
+	})
+}
+
+// Instantiate Template
+// @Summary      Create campaign from template
+// @Description  Create a draft campaign from a template. Placeholders are replaced from variables; year, quarter, month and start_date are derived from start_date unless supplied.
+// @Tags         Templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path  string                          true  "Template ID"
+// @Param        request  body  dto.InstantiateTemplateRequest  true  "Instantiate Payload"
+// @Success      201  {object}  dto.APIResponse{data=dto.CampaignResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /templates/{id}/instantiate [post]
+func (h *TemplateHandler) Instantiate(c *gin.Context) {
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid template ID format"})
+		return
+	}
+
+	var req dto.InstantiateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.templateService.Instantiate(c.Request.Context(), authPayload.UserID, templateID, req)
+	if err != nil {
+		h.handleError(c, "InstantiateTemplate", err)
+		return
+	}
+
+	zap.L().Info("Campaign created from template", zap.String("id", res.ID), zap.String("template_id", templateID.String()))
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Message: "Campaign created successfully",
+		Data:    res,
+	})
+}
+
+func (h *TemplateHandler) handleError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrTemplateNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Template not found"})
+	case errors.Is(err, service.ErrTemplateForbidden):
+		c.JSON(http.StatusForbidden, dto.APIResponse{Error: "Forbidden: You don't have permission to manage this template"})
+	case errors.Is(err, service.ErrMissingTemplateVariables), errors.Is(err, service.ErrInvalidCampaign):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid cursor"})
+	default:
+		zap.L().Error(op+" failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+	}
+}
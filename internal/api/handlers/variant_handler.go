@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/internal/middleware"
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+type VariantHandler struct {
+	variantService *service.VariantService
+}
+
+func NewVariantHandler(variantService *service.VariantService) *VariantHandler {
+	return &VariantHandler{
+		variantService: variantService,
+	}
+}
+
+// List Variants
+// @Summary      Get campaign variants
+// @Description  Experiment variants of a campaign, control first, with traffic split and lifetime metrics
+// @Tags         Experiments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Campaign ID"
+// @Success      200  {object}  dto.APIResponse{data=dto.CampaignVariantsResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/variants [get]
+func (h *VariantHandler) List(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.variantService.ListVariants(c.Request.Context(), authPayload.UserID, campaignID)
+	if err != nil {
+		h.handleError(c, "ListVariants", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Variants retrieved",
+		Data:    res,
+	})
+}
+
+// Replace Variants
+// @Summary      Set campaign variants
+// @Description  Replace the experiment variants of a campaign: 2 to 10 variants whose traffic splits add up to 100. The first variant is the control unless another is marked. Variants are matched by name; those with recorded metrics cannot be removed. An empty list ends the experiment.
+// @Tags         Experiments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path    string                      true   "Campaign ID"
+// @Param        If-Match  header  string                      false  "ETag of the campaign version being edited"
+// @Param        request   body    dto.ReplaceVariantsRequest  true   "Variants Payload"
+// @Success      200  {object}  dto.APIResponse{data=dto.CampaignVariantsResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Failure      409  {object}  dto.APIResponse
+// @Failure      412  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/variants [put]
+func (h *VariantHandler) Replace(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.ReplaceVariantsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var ifMatch []int32
+	if header := c.GetHeader("If-Match"); header != "" {
+		ifMatch = parseIfMatch(header)
+	}
+
+	res, err := h.variantService.ReplaceVariants(c.Request.Context(), authPayload.UserID, campaignID, ifMatch, req)
+	if err != nil {
+		h.handleError(c, "ReplaceVariants", err)
+		return
+	}
+
+	c.Header("ETag", versionETag(res.Version))
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Variants updated successfully",
+		Data:    res,
+	})
+}
+
+// Experiment Results
+// @Summary      Get experiment results
+// @Description  Compare every variant with the control: rate with its confidence interval, relative lift, two-proportion z-test and Bayesian probability to beat the control. A winner is flagged once every variant has min_sample trials and the thresholds are met; the z-test alpha is Bonferroni-corrected for the number of challengers.
+// @Tags         Experiments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id          path   string  true   "Campaign ID"
+// @Param        metric      query  string  false  "Rate to compare" Enums(conversion_rate, ctr) default(conversion_rate)
+// @Param        confidence  query  number  false  "Confidence level between 0.8 and 0.999" default(0.95)
+// @Param        min_sample  query  int     false  "Trials every variant needs before a winner is called" default(100)
+// @Param        from        query  string  false  "Range start (YYYY-MM-DD or RFC3339), defaults to the first metric"
+// @Param        to          query  string  false  "Range end, inclusive when a date (YYYY-MM-DD or RFC3339)"
+// @Param        timezone    query  string  false  "IANA timezone of plain dates" default(UTC)
+// @Success      200  {object}  dto.APIResponse{data=dto.ExperimentResponse}
+// @Failure      400  {object}  dto.APIResponse
+// @Failure      404  {object}  dto.APIResponse
+// @Router       /campaigns/{id}/experiment [get]
+func (h *VariantHandler) Experiment(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid campaign ID format"})
+		return
+	}
+
+	var req dto.ExperimentRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload, err := middleware.GetAuthPayload(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.APIResponse{Error: "Unauthorized"})
+		return
+	}
+
+	res, err := h.variantService.Experiment(c.Request.Context(), authPayload.UserID, campaignID, req)
+	if err != nil {
+		h.handleError(c, "Experiment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Message: "Experiment results retrieved",
+		Data:    res,
+	})
+}
+
+func (h *VariantHandler) handleError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrCampaignNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{Error: "Campaign not found"})
+	case errors.Is(err, service.ErrVersionConflict):
+		c.JSON(http.StatusPreconditionFailed, dto.APIResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrVariantHasMetrics):
+		c.JSON(http.StatusConflict, dto.APIResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidVariants), errors.Is(err, service.ErrInvalidAnalyticsRange):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidTimezone):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{Error: "Invalid timezone, expected an IANA name such as Asia/Jakarta"})
+	default:
+		zap.L().Error(op+" failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{Error: "Internal server error"})
+	}
+}
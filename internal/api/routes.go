@@ -10,31 +10,181 @@ import (
 	"github.com/valenrio66/be-project/pkg/utils"
 )
 
-func SetupRoutes(r *gin.Engine, userHandler *handlers.UserHandler, campaignHandler *handlers.CampaignHandler, tokenMaker *token.JWTMaker) {
+// Handlers are the HTTP handlers SetupRoutes registers. File is nil unless
+// assets are stored on the local disk.
+type Handlers struct {
+	User         *handlers.UserHandler
+	Campaign     *handlers.CampaignHandler
+	Analytics    *handlers.AnalyticsHandler
+	Dashboard    *handlers.DashboardHandler
+	Search       *handlers.SearchHandler
+	Template     *handlers.TemplateHandler
+	Import       *handlers.ImportHandler
+	Export       *handlers.ExportHandler
+	Tag          *handlers.TagHandler
+	Approval     *handlers.ApprovalHandler
+	Comment      *handlers.CommentHandler
+	Notification *handlers.NotificationHandler
+	Asset        *handlers.AssetHandler
+	Channel      *handlers.ChannelHandler
+	Segment      *handlers.SegmentHandler
+	Variant      *handlers.VariantHandler
+	Goal         *handlers.GoalHandler
+	Link         *handlers.LinkHandler
+	Conversion   *handlers.ConversionHandler
+	Attribution  *handlers.AttributionHandler
+	Funnel       *handlers.FunnelHandler
+	Cohort       *handlers.CohortHandler
+	File         *handlers.FileHandler
+}
+
+func SetupRoutes(r *gin.Engine, h Handlers, tokenMaker *token.JWTMaker) {
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	r.GET("/ping", func(c *gin.Context) {
 		c.JSON(200, gin.H{"message": "pong"})
 	})
 
+	// Short links and the pixel are embedded publicly, so they live outside
+	// the API.
+	r.GET("/r/:code", h.Link.Redirect)
+	r.GET("/pixel.gif", h.Conversion.Pixel)
+
 	api := r.Group("/api/v1")
 	{
-		api.POST("/register", userHandler.Register)
-		api.POST("/login", userHandler.Login)
+		api.POST("/register", h.User.Register)
+		api.POST("/login", h.User.Login)
+		// Postbacks authenticate with a signature instead of a token.
+		api.POST("/conversions/postback", h.Conversion.Postback)
+		// Only the local storage backend serves files itself.
+		if h.File != nil {
+			api.GET("/files/*key", h.File.Download)
+		}
 		protected := api.Group("/")
 		protected.Use(middleware.AuthMiddleware(tokenMaker))
 		{
-			protected.GET("/me", userHandler.GetMe)
+			protected.GET("/me", h.User.GetMe)
+			commonRoles := middleware.RoleMiddleware(utils.RoleUser, utils.RoleManager, utils.RoleAdmin)
+			approvers := middleware.RoleMiddleware(utils.RoleManager, utils.RoleAdmin)
+			adminOnly := middleware.RoleMiddleware(utils.RoleAdmin)
+			protected.GET("/analytics", commonRoles, h.Analytics.PortfolioAnalytics)
+			protected.GET("/analytics/export", commonRoles, h.Export.Analytics)
+			protected.GET("/analytics/channels", commonRoles, h.Channel.Analytics)
+			protected.GET("/analytics/attribution", commonRoles, h.Attribution.Attribution)
+			protected.GET("/analytics/cohorts", commonRoles, h.Cohort.Cohorts)
+			protected.GET("/dashboard/summary", commonRoles, h.Dashboard.Summary)
+			protected.GET("/search", commonRoles, h.Search.Search)
+			protected.GET("/goals", commonRoles, h.Goal.ListAll)
+			templates := protected.Group("/templates")
+			{
+				templates.POST("", commonRoles, h.Template.Create)
+				templates.GET("", commonRoles, h.Template.List)
+				templates.GET("/:id", commonRoles, h.Template.Get)
+				templates.PUT("/:id", commonRoles, h.Template.Update)
+				templates.DELETE("/:id", commonRoles, h.Template.Delete)
+				templates.POST("/:id/instantiate", commonRoles, h.Template.Instantiate)
+			}
+			tags := protected.Group("/tags")
+			{
+				tags.POST("", commonRoles, h.Tag.Create)
+				tags.GET("", commonRoles, h.Tag.List)
+				tags.GET("/rollups", commonRoles, h.Tag.Rollups)
+				tags.PUT("/:id", adminOnly, h.Tag.Update)
+				tags.DELETE("/:id", adminOnly, h.Tag.Delete)
+			}
+			segments := protected.Group("/segments")
+			{
+				segments.GET("/fields", commonRoles, h.Segment.Fields)
+				segments.POST("/validate", commonRoles, h.Segment.Validate)
+				segments.POST("", commonRoles, h.Segment.Create)
+				segments.GET("", commonRoles, h.Segment.List)
+				segments.GET("/:id", commonRoles, h.Segment.Get)
+				segments.PUT("/:id", commonRoles, h.Segment.Update)
+				segments.DELETE("/:id", commonRoles, h.Segment.Delete)
+				segments.GET("/:id/campaigns", commonRoles, h.Segment.Campaigns)
+			}
+			funnels := protected.Group("/funnels")
+			{
+				funnels.POST("", commonRoles, h.Funnel.Create)
+				funnels.GET("", commonRoles, h.Funnel.List)
+				funnels.GET("/:id", commonRoles, h.Funnel.Get)
+				funnels.PUT("/:id", commonRoles, h.Funnel.Update)
+				funnels.DELETE("/:id", commonRoles, h.Funnel.Delete)
+				funnels.GET("/:id/report", commonRoles, h.Funnel.Report)
+			}
+			policies := protected.Group("/approval-policies")
+			{
+				policies.POST("", adminOnly, h.Approval.CreatePolicy)
+				policies.GET("", adminOnly, h.Approval.ListPolicies)
+				policies.PUT("/:id", adminOnly, h.Approval.UpdatePolicy)
+				policies.DELETE("/:id", adminOnly, h.Approval.DeletePolicy)
+			}
+			approvals := protected.Group("/approvals")
+			{
+				approvals.GET("", approvers, h.Approval.Queue)
+				approvals.GET("/:id", commonRoles, h.Approval.Get)
+				approvals.POST("/:id/approve", approvers, h.Approval.Approve)
+				approvals.POST("/:id/reject", approvers, h.Approval.Reject)
+				approvals.POST("/:id/cancel", commonRoles, h.Approval.Cancel)
+			}
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", commonRoles, h.Notification.List)
+				notifications.GET("/unread-count", commonRoles, h.Notification.UnreadCount)
+				notifications.POST("/read-all", commonRoles, h.Notification.MarkAllRead)
+				notifications.POST("/:id/read", commonRoles, h.Notification.MarkRead)
+			}
 			campaigns := protected.Group("/campaigns")
 			{
-				commonRoles := middleware.RoleMiddleware(utils.RoleUser, utils.RoleAdmin)
-				campaigns.POST("", commonRoles, campaignHandler.Create)
-				campaigns.GET("", commonRoles, campaignHandler.List)
-				campaigns.GET("/:id", commonRoles, campaignHandler.Get)
-				campaigns.PUT("/:id", commonRoles, campaignHandler.Update)
+				campaigns.POST("", commonRoles, h.Campaign.Create)
+				campaigns.GET("", commonRoles, h.Campaign.List)
+				campaigns.POST("/bulk", commonRoles, h.Campaign.Bulk)
+				campaigns.POST("/import", commonRoles, h.Import.Import)
+				campaigns.GET("/imports/:id", commonRoles, h.Import.Get)
+				campaigns.GET("/export", commonRoles, h.Export.Campaigns)
+				campaigns.GET("/:id", commonRoles, h.Campaign.Get)
+				campaigns.PUT("/:id", commonRoles, h.Campaign.Update)
+				campaigns.PATCH("/:id", commonRoles, h.Campaign.Patch)
+				campaigns.POST("/:id/clone", commonRoles, h.Campaign.Clone)
+				campaigns.GET("/:id/history", commonRoles, h.Campaign.History)
+				campaigns.POST("/:id/revert", commonRoles, h.Campaign.Revert)
+				campaigns.PUT("/:id/tags", commonRoles, h.Tag.ReplaceCampaignTags)
+				campaigns.POST("/:id/tags", commonRoles, h.Tag.AddCampaignTags)
+				campaigns.DELETE("/:id/tags/:tagId", commonRoles, h.Tag.RemoveCampaignTag)
+				campaigns.PUT("/:id/segments", commonRoles, h.Segment.ReplaceCampaignSegments)
+				campaigns.POST("/:id/segments", commonRoles, h.Segment.AddCampaignSegments)
+				campaigns.DELETE("/:id/segments/:segmentId", commonRoles, h.Segment.RemoveCampaignSegment)
+				campaigns.POST("/:id/approvals", commonRoles, h.Approval.Submit)
+				campaigns.GET("/:id/approvals", commonRoles, h.Approval.ListCampaignApprovals)
+				campaigns.POST("/:id/comments", commonRoles, h.Comment.Create)
+				campaigns.GET("/:id/comments", commonRoles, h.Comment.List)
+				campaigns.PUT("/:id/comments/:commentId", commonRoles, h.Comment.Update)
+				campaigns.DELETE("/:id/comments/:commentId", commonRoles, h.Comment.Delete)
+				campaigns.POST("/:id/assets", commonRoles, h.Asset.Upload)
+				campaigns.GET("/:id/assets", commonRoles, h.Asset.List)
+				campaigns.GET("/:id/assets/:assetId", commonRoles, h.Asset.Get)
+				campaigns.DELETE("/:id/assets/:assetId", commonRoles, h.Asset.Delete)
+				campaigns.GET("/:id/channels", commonRoles, h.Channel.List)
+				campaigns.PUT("/:id/channels", commonRoles, h.Channel.Replace)
+				campaigns.PATCH("/:id/channels/:channel", commonRoles, h.Channel.Update)
+				campaigns.GET("/:id/variants", commonRoles, h.Variant.List)
+				campaigns.PUT("/:id/variants", commonRoles, h.Variant.Replace)
+				campaigns.GET("/:id/experiment", commonRoles, h.Variant.Experiment)
+				campaigns.POST("/:id/goals", commonRoles, h.Goal.Create)
+				campaigns.GET("/:id/goals", commonRoles, h.Goal.List)
+				campaigns.PUT("/:id/goals/:goalId", commonRoles, h.Goal.Update)
+				campaigns.DELETE("/:id/goals/:goalId", commonRoles, h.Goal.Delete)
+				campaigns.POST("/:id/links", commonRoles, h.Link.Create)
+				campaigns.GET("/:id/links", commonRoles, h.Link.List)
+				campaigns.GET("/:id/links/:linkId", commonRoles, h.Link.Get)
+				campaigns.DELETE("/:id/links/:linkId", commonRoles, h.Link.Delete)
+				campaigns.GET("/:id/conversions", commonRoles, h.Conversion.List)
+				campaigns.GET("/:id/analytics", commonRoles, h.Analytics.CampaignAnalytics)
+				campaigns.POST("/:id/metrics", commonRoles, h.Analytics.RecordMetrics)
 
-				adminOnly := middleware.RoleMiddleware(utils.RoleAdmin)
-				campaigns.DELETE("/:id", adminOnly, campaignHandler.Delete)
+				campaigns.DELETE("/:id", adminOnly, h.Campaign.Delete)
+				campaigns.GET("/trash", adminOnly, h.Campaign.ListTrash)
+				campaigns.POST("/:id/restore", adminOnly, h.Campaign.Restore)
 			}
 		}
 	}
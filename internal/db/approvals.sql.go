@@ -0,0 +1,625 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: approvals.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const advanceCampaignApproval = `-- name: AdvanceCampaignApproval :one
+UPDATE campaign_approvals
+SET current_step = current_step + 1,
+    status = CASE WHEN current_step + 1 >= cardinality(approver_roles) THEN 'approved' ELSE status END,
+    decided_at = CASE WHEN current_step + 1 >= cardinality(approver_roles) THEN NOW() ELSE decided_at END
+WHERE id = $1
+RETURNING id, campaign_id, requested_by, policy_id, status, budget, approver_roles, current_step, comment, created_at, decided_at
+`
+
+func (q *Queries) AdvanceCampaignApproval(ctx context.Context, id uuid.UUID) (CampaignApproval, error) {
+	row := q.db.QueryRow(ctx, advanceCampaignApproval, id)
+	var i CampaignApproval
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.RequestedBy,
+		&i.PolicyID,
+		&i.Status,
+		&i.Budget,
+		&i.ApproverRoles,
+		&i.CurrentStep,
+		&i.Comment,
+		&i.CreatedAt,
+		&i.DecidedAt,
+	)
+	return i, err
+}
+
+const closeCampaignApproval = `-- name: CloseCampaignApproval :one
+UPDATE campaign_approvals
+SET status = $1,
+    decided_at = NOW()
+WHERE id = $2
+RETURNING id, campaign_id, requested_by, policy_id, status, budget, approver_roles, current_step, comment, created_at, decided_at
+`
+
+type CloseCampaignApprovalParams struct {
+	Status string    `json:"status"`
+	ID     uuid.UUID `json:"id"`
+}
+
+func (q *Queries) CloseCampaignApproval(ctx context.Context, arg CloseCampaignApprovalParams) (CampaignApproval, error) {
+	row := q.db.QueryRow(ctx, closeCampaignApproval, arg.Status, arg.ID)
+	var i CampaignApproval
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.RequestedBy,
+		&i.PolicyID,
+		&i.Status,
+		&i.Budget,
+		&i.ApproverRoles,
+		&i.CurrentStep,
+		&i.Comment,
+		&i.CreatedAt,
+		&i.DecidedAt,
+	)
+	return i, err
+}
+
+const countPendingApprovals = `-- name: CountPendingApprovals :one
+SELECT COUNT(*)::bigint
+FROM campaign_approvals a
+JOIN campaigns c ON c.id = a.campaign_id
+WHERE a.status = 'pending'
+  AND c.deleted_at IS NULL
+  AND a.approver_roles[a.current_step + 1] = ANY($1::text[])
+  AND c.user_id <> $2
+  AND a.requested_by IS DISTINCT FROM $2
+`
+
+type CountPendingApprovalsParams struct {
+	Roles  []string  `json:"roles"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) CountPendingApprovals(ctx context.Context, arg CountPendingApprovalsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countPendingApprovals, arg.Roles, arg.UserID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const createApprovalDecision = `-- name: CreateApprovalDecision :one
+INSERT INTO approval_decisions (approval_id, step, approver_id, decision, comment)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, approval_id, step, approver_id, decision, comment, created_at
+`
+
+type CreateApprovalDecisionParams struct {
+	ApprovalID uuid.UUID   `json:"approval_id"`
+	Step       int32       `json:"step"`
+	ApproverID pgtype.UUID `json:"approver_id"`
+	Decision   string      `json:"decision"`
+	Comment    *string     `json:"comment"`
+}
+
+func (q *Queries) CreateApprovalDecision(ctx context.Context, arg CreateApprovalDecisionParams) (ApprovalDecision, error) {
+	row := q.db.QueryRow(ctx, createApprovalDecision,
+		arg.ApprovalID,
+		arg.Step,
+		arg.ApproverID,
+		arg.Decision,
+		arg.Comment,
+	)
+	var i ApprovalDecision
+	err := row.Scan(
+		&i.ID,
+		&i.ApprovalID,
+		&i.Step,
+		&i.ApproverID,
+		&i.Decision,
+		&i.Comment,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createApprovalPolicy = `-- name: CreateApprovalPolicy :one
+INSERT INTO approval_policies (name, min_budget, approver_roles, applies_to_roles)
+VALUES ($1, $2, $3::text[], $4::text[])
+RETURNING id, name, min_budget, approver_roles, applies_to_roles, created_at, updated_at
+`
+
+type CreateApprovalPolicyParams struct {
+	Name           string   `json:"name"`
+	MinBudget      float64  `json:"min_budget"`
+	ApproverRoles  []string `json:"approver_roles"`
+	AppliesToRoles []string `json:"applies_to_roles"`
+}
+
+func (q *Queries) CreateApprovalPolicy(ctx context.Context, arg CreateApprovalPolicyParams) (ApprovalPolicy, error) {
+	row := q.db.QueryRow(ctx, createApprovalPolicy,
+		arg.Name,
+		arg.MinBudget,
+		arg.ApproverRoles,
+		arg.AppliesToRoles,
+	)
+	var i ApprovalPolicy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.MinBudget,
+		&i.ApproverRoles,
+		&i.AppliesToRoles,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createCampaignApproval = `-- name: CreateCampaignApproval :one
+INSERT INTO campaign_approvals (campaign_id, requested_by, policy_id, budget, approver_roles, comment)
+VALUES ($1, $2, $3, $4, $5::text[], $6)
+RETURNING id, campaign_id, requested_by, policy_id, status, budget, approver_roles, current_step, comment, created_at, decided_at
+`
+
+type CreateCampaignApprovalParams struct {
+	CampaignID    uuid.UUID   `json:"campaign_id"`
+	RequestedBy   pgtype.UUID `json:"requested_by"`
+	PolicyID      pgtype.UUID `json:"policy_id"`
+	Budget        float64     `json:"budget"`
+	ApproverRoles []string    `json:"approver_roles"`
+	Comment       *string     `json:"comment"`
+}
+
+func (q *Queries) CreateCampaignApproval(ctx context.Context, arg CreateCampaignApprovalParams) (CampaignApproval, error) {
+	row := q.db.QueryRow(ctx, createCampaignApproval,
+		arg.CampaignID,
+		arg.RequestedBy,
+		arg.PolicyID,
+		arg.Budget,
+		arg.ApproverRoles,
+		arg.Comment,
+	)
+	var i CampaignApproval
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.RequestedBy,
+		&i.PolicyID,
+		&i.Status,
+		&i.Budget,
+		&i.ApproverRoles,
+		&i.CurrentStep,
+		&i.Comment,
+		&i.CreatedAt,
+		&i.DecidedAt,
+	)
+	return i, err
+}
+
+const deleteApprovalPolicy = `-- name: DeleteApprovalPolicy :execrows
+DELETE FROM approval_policies
+WHERE id = $1
+`
+
+func (q *Queries) DeleteApprovalPolicy(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteApprovalPolicy, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getApplicableApprovalPolicy = `-- name: GetApplicableApprovalPolicy :one
+SELECT id, name, min_budget, approver_roles, applies_to_roles, created_at, updated_at FROM approval_policies
+WHERE min_budget <= $1::float8
+  AND (cardinality(applies_to_roles) = 0 OR $2::text = ANY(applies_to_roles))
+ORDER BY min_budget DESC, created_at
+LIMIT 1
+`
+
+type GetApplicableApprovalPolicyParams struct {
+	Budget    float64 `json:"budget"`
+	OwnerRole string  `json:"owner_role"`
+}
+
+func (q *Queries) GetApplicableApprovalPolicy(ctx context.Context, arg GetApplicableApprovalPolicyParams) (ApprovalPolicy, error) {
+	row := q.db.QueryRow(ctx, getApplicableApprovalPolicy, arg.Budget, arg.OwnerRole)
+	var i ApprovalPolicy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.MinBudget,
+		&i.ApproverRoles,
+		&i.AppliesToRoles,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCampaignApproval = `-- name: GetCampaignApproval :one
+SELECT id, campaign_id, requested_by, policy_id, status, budget, approver_roles, current_step, comment, created_at, decided_at FROM campaign_approvals
+WHERE id = $1
+`
+
+func (q *Queries) GetCampaignApproval(ctx context.Context, id uuid.UUID) (CampaignApproval, error) {
+	row := q.db.QueryRow(ctx, getCampaignApproval, id)
+	var i CampaignApproval
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.RequestedBy,
+		&i.PolicyID,
+		&i.Status,
+		&i.Budget,
+		&i.ApproverRoles,
+		&i.CurrentStep,
+		&i.Comment,
+		&i.CreatedAt,
+		&i.DecidedAt,
+	)
+	return i, err
+}
+
+const getCampaignApprovalForUpdate = `-- name: GetCampaignApprovalForUpdate :one
+SELECT a.id, a.campaign_id, a.requested_by, a.policy_id, a.status, a.budget, a.approver_roles, a.current_step, a.comment, a.created_at, a.decided_at, c.user_id AS campaign_owner_id
+FROM campaign_approvals a
+JOIN campaigns c ON c.id = a.campaign_id
+WHERE a.id = $1
+    FOR UPDATE OF a
+`
+
+type GetCampaignApprovalForUpdateRow struct {
+	ID              uuid.UUID          `json:"id"`
+	CampaignID      uuid.UUID          `json:"campaign_id"`
+	RequestedBy     pgtype.UUID        `json:"requested_by"`
+	PolicyID        pgtype.UUID        `json:"policy_id"`
+	Status          string             `json:"status"`
+	Budget          float64            `json:"budget"`
+	ApproverRoles   []string           `json:"approver_roles"`
+	CurrentStep     int32              `json:"current_step"`
+	Comment         *string            `json:"comment"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	DecidedAt       pgtype.Timestamptz `json:"decided_at"`
+	CampaignOwnerID uuid.UUID          `json:"campaign_owner_id"`
+}
+
+func (q *Queries) GetCampaignApprovalForUpdate(ctx context.Context, id uuid.UUID) (GetCampaignApprovalForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, getCampaignApprovalForUpdate, id)
+	var i GetCampaignApprovalForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.RequestedBy,
+		&i.PolicyID,
+		&i.Status,
+		&i.Budget,
+		&i.ApproverRoles,
+		&i.CurrentStep,
+		&i.Comment,
+		&i.CreatedAt,
+		&i.DecidedAt,
+		&i.CampaignOwnerID,
+	)
+	return i, err
+}
+
+const getLatestApprovedBudget = `-- name: GetLatestApprovedBudget :one
+SELECT COALESCE(MAX(budget), -1)::float8
+FROM campaign_approvals
+WHERE campaign_id = $1 AND status = 'approved'
+`
+
+// The largest budget ever approved for the campaign; launching with more
+// than that needs a new approval.
+func (q *Queries) GetLatestApprovedBudget(ctx context.Context, campaignID uuid.UUID) (float64, error) {
+	row := q.db.QueryRow(ctx, getLatestApprovedBudget, campaignID)
+	var column_1 float64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const getPendingCampaignApproval = `-- name: GetPendingCampaignApproval :one
+SELECT id, campaign_id, requested_by, policy_id, status, budget, approver_roles, current_step, comment, created_at, decided_at FROM campaign_approvals
+WHERE campaign_id = $1 AND status = 'pending'
+`
+
+func (q *Queries) GetPendingCampaignApproval(ctx context.Context, campaignID uuid.UUID) (CampaignApproval, error) {
+	row := q.db.QueryRow(ctx, getPendingCampaignApproval, campaignID)
+	var i CampaignApproval
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.RequestedBy,
+		&i.PolicyID,
+		&i.Status,
+		&i.Budget,
+		&i.ApproverRoles,
+		&i.CurrentStep,
+		&i.Comment,
+		&i.CreatedAt,
+		&i.DecidedAt,
+	)
+	return i, err
+}
+
+const getUserRole = `-- name: GetUserRole :one
+SELECT role FROM users
+WHERE id = $1
+`
+
+func (q *Queries) GetUserRole(ctx context.Context, id uuid.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, getUserRole, id)
+	var role string
+	err := row.Scan(&role)
+	return role, err
+}
+
+const hasApprovalDecisionBy = `-- name: HasApprovalDecisionBy :one
+SELECT EXISTS (
+    SELECT 1 FROM approval_decisions
+    WHERE approval_id = $1 AND approver_id = $2
+)::boolean
+`
+
+type HasApprovalDecisionByParams struct {
+	ApprovalID uuid.UUID   `json:"approval_id"`
+	ApproverID pgtype.UUID `json:"approver_id"`
+}
+
+func (q *Queries) HasApprovalDecisionBy(ctx context.Context, arg HasApprovalDecisionByParams) (bool, error) {
+	row := q.db.QueryRow(ctx, hasApprovalDecisionBy, arg.ApprovalID, arg.ApproverID)
+	var column_1 bool
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const listApprovalDecisions = `-- name: ListApprovalDecisions :many
+SELECT d.id, d.approval_id, d.step, d.approver_id, d.decision, d.comment, d.created_at, u.email AS approver_email
+FROM approval_decisions d
+LEFT JOIN users u ON u.id = d.approver_id
+WHERE d.approval_id = ANY($1::uuid[])
+ORDER BY d.approval_id, d.step
+`
+
+type ListApprovalDecisionsRow struct {
+	ID            uuid.UUID          `json:"id"`
+	ApprovalID    uuid.UUID          `json:"approval_id"`
+	Step          int32              `json:"step"`
+	ApproverID    pgtype.UUID        `json:"approver_id"`
+	Decision      string             `json:"decision"`
+	Comment       *string            `json:"comment"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	ApproverEmail *string            `json:"approver_email"`
+}
+
+func (q *Queries) ListApprovalDecisions(ctx context.Context, approvalIds []uuid.UUID) ([]ListApprovalDecisionsRow, error) {
+	rows, err := q.db.Query(ctx, listApprovalDecisions, approvalIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListApprovalDecisionsRow
+	for rows.Next() {
+		var i ListApprovalDecisionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ApprovalID,
+			&i.Step,
+			&i.ApproverID,
+			&i.Decision,
+			&i.Comment,
+			&i.CreatedAt,
+			&i.ApproverEmail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listApprovalPolicies = `-- name: ListApprovalPolicies :many
+SELECT id, name, min_budget, approver_roles, applies_to_roles, created_at, updated_at FROM approval_policies
+ORDER BY min_budget, created_at
+`
+
+func (q *Queries) ListApprovalPolicies(ctx context.Context) ([]ApprovalPolicy, error) {
+	rows, err := q.db.Query(ctx, listApprovalPolicies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ApprovalPolicy
+	for rows.Next() {
+		var i ApprovalPolicy
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.MinBudget,
+			&i.ApproverRoles,
+			&i.AppliesToRoles,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaignApprovals = `-- name: ListCampaignApprovals :many
+SELECT id, campaign_id, requested_by, policy_id, status, budget, approver_roles, current_step, comment, created_at, decided_at FROM campaign_approvals
+WHERE campaign_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListCampaignApprovals(ctx context.Context, campaignID uuid.UUID) ([]CampaignApproval, error) {
+	rows, err := q.db.Query(ctx, listCampaignApprovals, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CampaignApproval
+	for rows.Next() {
+		var i CampaignApproval
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.RequestedBy,
+			&i.PolicyID,
+			&i.Status,
+			&i.Budget,
+			&i.ApproverRoles,
+			&i.CurrentStep,
+			&i.Comment,
+			&i.CreatedAt,
+			&i.DecidedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingApprovals = `-- name: ListPendingApprovals :many
+SELECT a.id, a.campaign_id, a.requested_by, a.policy_id, a.status, a.budget, a.approver_roles, a.current_step, a.comment, a.created_at, a.decided_at, c.title AS campaign_title, c.user_id AS campaign_owner_id
+FROM campaign_approvals a
+JOIN campaigns c ON c.id = a.campaign_id
+WHERE a.status = 'pending'
+  AND c.deleted_at IS NULL
+  AND a.approver_roles[a.current_step + 1] = ANY($1::text[])
+  AND c.user_id <> $2
+  AND a.requested_by IS DISTINCT FROM $2
+  AND ($3::uuid IS NULL
+       OR (a.created_at, a.id) > ($4::timestamptz, $3::uuid))
+ORDER BY a.created_at, a.id
+LIMIT $5
+`
+
+type ListPendingApprovalsParams struct {
+	Roles      []string           `json:"roles"`
+	UserID     uuid.UUID          `json:"user_id"`
+	CursorID   pgtype.UUID        `json:"cursor_id"`
+	CursorTime pgtype.Timestamptz `json:"cursor_time"`
+	RowLimit   int32              `json:"row_limit"`
+}
+
+type ListPendingApprovalsRow struct {
+	ID              uuid.UUID          `json:"id"`
+	CampaignID      uuid.UUID          `json:"campaign_id"`
+	RequestedBy     pgtype.UUID        `json:"requested_by"`
+	PolicyID        pgtype.UUID        `json:"policy_id"`
+	Status          string             `json:"status"`
+	Budget          float64            `json:"budget"`
+	ApproverRoles   []string           `json:"approver_roles"`
+	CurrentStep     int32              `json:"current_step"`
+	Comment         *string            `json:"comment"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	DecidedAt       pgtype.Timestamptz `json:"decided_at"`
+	CampaignTitle   string             `json:"campaign_title"`
+	CampaignOwnerID uuid.UUID          `json:"campaign_owner_id"`
+}
+
+// The approval queue: pending requests whose current step any of the given
+// roles may decide, oldest first. Requests the user made or owns are left out
+// because they cannot decide them.
+func (q *Queries) ListPendingApprovals(ctx context.Context, arg ListPendingApprovalsParams) ([]ListPendingApprovalsRow, error) {
+	rows, err := q.db.Query(ctx, listPendingApprovals,
+		arg.Roles,
+		arg.UserID,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPendingApprovalsRow
+	for rows.Next() {
+		var i ListPendingApprovalsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.RequestedBy,
+			&i.PolicyID,
+			&i.Status,
+			&i.Budget,
+			&i.ApproverRoles,
+			&i.CurrentStep,
+			&i.Comment,
+			&i.CreatedAt,
+			&i.DecidedAt,
+			&i.CampaignTitle,
+			&i.CampaignOwnerID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateApprovalPolicy = `-- name: UpdateApprovalPolicy :one
+UPDATE approval_policies
+SET
+    name = $1,
+    min_budget = $2,
+    approver_roles = $3::text[],
+    applies_to_roles = $4::text[],
+    updated_at = NOW()
+WHERE id = $5
+RETURNING id, name, min_budget, approver_roles, applies_to_roles, created_at, updated_at
+`
+
+type UpdateApprovalPolicyParams struct {
+	Name           string    `json:"name"`
+	MinBudget      float64   `json:"min_budget"`
+	ApproverRoles  []string  `json:"approver_roles"`
+	AppliesToRoles []string  `json:"applies_to_roles"`
+	ID             uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateApprovalPolicy(ctx context.Context, arg UpdateApprovalPolicyParams) (ApprovalPolicy, error) {
+	row := q.db.QueryRow(ctx, updateApprovalPolicy,
+		arg.Name,
+		arg.MinBudget,
+		arg.ApproverRoles,
+		arg.AppliesToRoles,
+		arg.ID,
+	)
+	var i ApprovalPolicy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.MinBudget,
+		&i.ApproverRoles,
+		&i.AppliesToRoles,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,377 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: assets.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const attachCampaignAsset = `-- name: AttachCampaignAsset :one
+INSERT INTO campaign_assets (campaign_id, asset_id, filename, uploaded_by)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (campaign_id, asset_id) DO NOTHING
+RETURNING id, campaign_id, asset_id, filename, uploaded_by, created_at
+`
+
+type AttachCampaignAssetParams struct {
+	CampaignID uuid.UUID   `json:"campaign_id"`
+	AssetID    uuid.UUID   `json:"asset_id"`
+	Filename   string      `json:"filename"`
+	UploadedBy pgtype.UUID `json:"uploaded_by"`
+}
+
+// Returns no row when the asset is already attached to the campaign.
+func (q *Queries) AttachCampaignAsset(ctx context.Context, arg AttachCampaignAssetParams) (CampaignAsset, error) {
+	row := q.db.QueryRow(ctx, attachCampaignAsset,
+		arg.CampaignID,
+		arg.AssetID,
+		arg.Filename,
+		arg.UploadedBy,
+	)
+	var i CampaignAsset
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.AssetID,
+		&i.Filename,
+		&i.UploadedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteAssetIfOrphaned = `-- name: DeleteAssetIfOrphaned :one
+DELETE FROM assets a
+WHERE a.id = $1
+  AND NOT EXISTS (SELECT 1 FROM campaign_assets ca WHERE ca.asset_id = a.id)
+RETURNING id, sha256, size_bytes, mime_type, kind, storage_key, thumbnail_key, width, height, created_at
+`
+
+// Returns no row while another campaign still uses the asset.
+func (q *Queries) DeleteAssetIfOrphaned(ctx context.Context, id uuid.UUID) (Asset, error) {
+	row := q.db.QueryRow(ctx, deleteAssetIfOrphaned, id)
+	var i Asset
+	err := row.Scan(
+		&i.ID,
+		&i.Sha256,
+		&i.SizeBytes,
+		&i.MimeType,
+		&i.Kind,
+		&i.StorageKey,
+		&i.ThumbnailKey,
+		&i.Width,
+		&i.Height,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteCampaignAsset = `-- name: DeleteCampaignAsset :one
+DELETE FROM campaign_assets
+WHERE id = $1 AND campaign_id = $2
+RETURNING asset_id
+`
+
+type DeleteCampaignAssetParams struct {
+	ID         uuid.UUID `json:"id"`
+	CampaignID uuid.UUID `json:"campaign_id"`
+}
+
+func (q *Queries) DeleteCampaignAsset(ctx context.Context, arg DeleteCampaignAssetParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, deleteCampaignAsset, arg.ID, arg.CampaignID)
+	var asset_id uuid.UUID
+	err := row.Scan(&asset_id)
+	return asset_id, err
+}
+
+const deleteOrphanedAssets = `-- name: DeleteOrphanedAssets :many
+DELETE FROM assets a
+WHERE NOT EXISTS (SELECT 1 FROM campaign_assets ca WHERE ca.asset_id = a.id)
+  AND a.created_at < $1
+RETURNING id, sha256, size_bytes, mime_type, kind, storage_key, thumbnail_key, width, height, created_at
+`
+
+// Sweeps assets left behind when campaigns were purged from the trash.
+func (q *Queries) DeleteOrphanedAssets(ctx context.Context, createdBefore pgtype.Timestamptz) ([]Asset, error) {
+	rows, err := q.db.Query(ctx, deleteOrphanedAssets, createdBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Asset
+	for rows.Next() {
+		var i Asset
+		if err := rows.Scan(
+			&i.ID,
+			&i.Sha256,
+			&i.SizeBytes,
+			&i.MimeType,
+			&i.Kind,
+			&i.StorageKey,
+			&i.ThumbnailKey,
+			&i.Width,
+			&i.Height,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAssetBySHA256 = `-- name: GetAssetBySHA256 :one
+SELECT id, sha256, size_bytes, mime_type, kind, storage_key, thumbnail_key, width, height, created_at FROM assets
+WHERE sha256 = $1
+`
+
+func (q *Queries) GetAssetBySHA256(ctx context.Context, sha256 string) (Asset, error) {
+	row := q.db.QueryRow(ctx, getAssetBySHA256, sha256)
+	var i Asset
+	err := row.Scan(
+		&i.ID,
+		&i.Sha256,
+		&i.SizeBytes,
+		&i.MimeType,
+		&i.Kind,
+		&i.StorageKey,
+		&i.ThumbnailKey,
+		&i.Width,
+		&i.Height,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getCampaignAsset = `-- name: GetCampaignAsset :one
+SELECT
+    ca.id, ca.campaign_id, ca.asset_id, ca.filename, ca.uploaded_by, ca.created_at,
+    a.sha256, a.size_bytes, a.mime_type, a.kind, a.storage_key, a.thumbnail_key, a.width, a.height
+FROM campaign_assets ca
+JOIN assets a ON a.id = ca.asset_id
+WHERE ca.id = $1 AND ca.campaign_id = $2
+`
+
+type GetCampaignAssetParams struct {
+	ID         uuid.UUID `json:"id"`
+	CampaignID uuid.UUID `json:"campaign_id"`
+}
+
+type GetCampaignAssetRow struct {
+	ID           uuid.UUID          `json:"id"`
+	CampaignID   uuid.UUID          `json:"campaign_id"`
+	AssetID      uuid.UUID          `json:"asset_id"`
+	Filename     string             `json:"filename"`
+	UploadedBy   pgtype.UUID        `json:"uploaded_by"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	Sha256       string             `json:"sha256"`
+	SizeBytes    int64              `json:"size_bytes"`
+	MimeType     string             `json:"mime_type"`
+	Kind         string             `json:"kind"`
+	StorageKey   string             `json:"storage_key"`
+	ThumbnailKey *string            `json:"thumbnail_key"`
+	Width        *int32             `json:"width"`
+	Height       *int32             `json:"height"`
+}
+
+func (q *Queries) GetCampaignAsset(ctx context.Context, arg GetCampaignAssetParams) (GetCampaignAssetRow, error) {
+	row := q.db.QueryRow(ctx, getCampaignAsset, arg.ID, arg.CampaignID)
+	var i GetCampaignAssetRow
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.AssetID,
+		&i.Filename,
+		&i.UploadedBy,
+		&i.CreatedAt,
+		&i.Sha256,
+		&i.SizeBytes,
+		&i.MimeType,
+		&i.Kind,
+		&i.StorageKey,
+		&i.ThumbnailKey,
+		&i.Width,
+		&i.Height,
+	)
+	return i, err
+}
+
+const getCampaignAssetByAsset = `-- name: GetCampaignAssetByAsset :one
+SELECT
+    ca.id, ca.campaign_id, ca.asset_id, ca.filename, ca.uploaded_by, ca.created_at,
+    a.sha256, a.size_bytes, a.mime_type, a.kind, a.storage_key, a.thumbnail_key, a.width, a.height
+FROM campaign_assets ca
+JOIN assets a ON a.id = ca.asset_id
+WHERE ca.campaign_id = $1 AND ca.asset_id = $2
+`
+
+type GetCampaignAssetByAssetParams struct {
+	CampaignID uuid.UUID `json:"campaign_id"`
+	AssetID    uuid.UUID `json:"asset_id"`
+}
+
+type GetCampaignAssetByAssetRow struct {
+	ID           uuid.UUID          `json:"id"`
+	CampaignID   uuid.UUID          `json:"campaign_id"`
+	AssetID      uuid.UUID          `json:"asset_id"`
+	Filename     string             `json:"filename"`
+	UploadedBy   pgtype.UUID        `json:"uploaded_by"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	Sha256       string             `json:"sha256"`
+	SizeBytes    int64              `json:"size_bytes"`
+	MimeType     string             `json:"mime_type"`
+	Kind         string             `json:"kind"`
+	StorageKey   string             `json:"storage_key"`
+	ThumbnailKey *string            `json:"thumbnail_key"`
+	Width        *int32             `json:"width"`
+	Height       *int32             `json:"height"`
+}
+
+func (q *Queries) GetCampaignAssetByAsset(ctx context.Context, arg GetCampaignAssetByAssetParams) (GetCampaignAssetByAssetRow, error) {
+	row := q.db.QueryRow(ctx, getCampaignAssetByAsset, arg.CampaignID, arg.AssetID)
+	var i GetCampaignAssetByAssetRow
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.AssetID,
+		&i.Filename,
+		&i.UploadedBy,
+		&i.CreatedAt,
+		&i.Sha256,
+		&i.SizeBytes,
+		&i.MimeType,
+		&i.Kind,
+		&i.StorageKey,
+		&i.ThumbnailKey,
+		&i.Width,
+		&i.Height,
+	)
+	return i, err
+}
+
+const listCampaignAssets = `-- name: ListCampaignAssets :many
+SELECT
+    ca.id, ca.campaign_id, ca.asset_id, ca.filename, ca.uploaded_by, ca.created_at,
+    a.sha256, a.size_bytes, a.mime_type, a.kind, a.storage_key, a.thumbnail_key, a.width, a.height
+FROM campaign_assets ca
+JOIN assets a ON a.id = ca.asset_id
+WHERE ca.campaign_id = $1
+  AND ($2::text IS NULL OR a.kind = $2)
+ORDER BY ca.created_at DESC, ca.id DESC
+`
+
+type ListCampaignAssetsParams struct {
+	CampaignID uuid.UUID `json:"campaign_id"`
+	Kind       *string   `json:"kind"`
+}
+
+type ListCampaignAssetsRow struct {
+	ID           uuid.UUID          `json:"id"`
+	CampaignID   uuid.UUID          `json:"campaign_id"`
+	AssetID      uuid.UUID          `json:"asset_id"`
+	Filename     string             `json:"filename"`
+	UploadedBy   pgtype.UUID        `json:"uploaded_by"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	Sha256       string             `json:"sha256"`
+	SizeBytes    int64              `json:"size_bytes"`
+	MimeType     string             `json:"mime_type"`
+	Kind         string             `json:"kind"`
+	StorageKey   string             `json:"storage_key"`
+	ThumbnailKey *string            `json:"thumbnail_key"`
+	Width        *int32             `json:"width"`
+	Height       *int32             `json:"height"`
+}
+
+func (q *Queries) ListCampaignAssets(ctx context.Context, arg ListCampaignAssetsParams) ([]ListCampaignAssetsRow, error) {
+	rows, err := q.db.Query(ctx, listCampaignAssets, arg.CampaignID, arg.Kind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCampaignAssetsRow
+	for rows.Next() {
+		var i ListCampaignAssetsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.AssetID,
+			&i.Filename,
+			&i.UploadedBy,
+			&i.CreatedAt,
+			&i.Sha256,
+			&i.SizeBytes,
+			&i.MimeType,
+			&i.Kind,
+			&i.StorageKey,
+			&i.ThumbnailKey,
+			&i.Width,
+			&i.Height,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertAsset = `-- name: UpsertAsset :one
+INSERT INTO assets (id, sha256, size_bytes, mime_type, kind, storage_key, thumbnail_key, width, height)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (sha256) DO UPDATE SET sha256 = EXCLUDED.sha256
+RETURNING id, sha256, size_bytes, mime_type, kind, storage_key, thumbnail_key, width, height, created_at
+`
+
+type UpsertAssetParams struct {
+	ID           uuid.UUID `json:"id"`
+	Sha256       string    `json:"sha256"`
+	SizeBytes    int64     `json:"size_bytes"`
+	MimeType     string    `json:"mime_type"`
+	Kind         string    `json:"kind"`
+	StorageKey   string    `json:"storage_key"`
+	ThumbnailKey *string   `json:"thumbnail_key"`
+	Width        *int32    `json:"width"`
+	Height       *int32    `json:"height"`
+}
+
+// Returns the existing row when the content is already stored. The no-op
+// update locks it, so a concurrent orphan sweep cannot delete it before the
+// caller attaches it.
+func (q *Queries) UpsertAsset(ctx context.Context, arg UpsertAssetParams) (Asset, error) {
+	row := q.db.QueryRow(ctx, upsertAsset,
+		arg.ID,
+		arg.Sha256,
+		arg.SizeBytes,
+		arg.MimeType,
+		arg.Kind,
+		arg.StorageKey,
+		arg.ThumbnailKey,
+		arg.Width,
+		arg.Height,
+	)
+	var i Asset
+	err := row.Scan(
+		&i.ID,
+		&i.Sha256,
+		&i.SizeBytes,
+		&i.MimeType,
+		&i.Kind,
+		&i.StorageKey,
+		&i.ThumbnailKey,
+		&i.Width,
+		&i.Height,
+		&i.CreatedAt,
+	)
+	return i, err
+}
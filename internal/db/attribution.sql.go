@@ -0,0 +1,109 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: attribution.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const listAttributionTouches = `-- name: ListAttributionTouches :many
+WITH conv AS (
+    SELECT cv.id, cv.visitor_id, cv.converted_at, cv.value, cv.currency
+    FROM conversions cv
+    WHERE cv.visitor_id IS NOT NULL
+      AND cv.event = 'conversion'
+      AND cv.converted_at >= $3
+      AND cv.converted_at < $4
+)
+SELECT
+    conv.id AS conversion_id,
+    conv.converted_at,
+    conv.value,
+    conv.currency,
+    k.clicked_at,
+    l.campaign_id,
+    c.title AS campaign_title,
+    (c.user_id = $1 AND c.deleted_at IS NULL)::boolean AS owned
+FROM conv
+JOIN link_clicks k ON k.visitor_id = conv.visitor_id
+    AND NOT k.is_bot
+    AND k.clicked_at <= conv.converted_at
+    AND k.clicked_at >= conv.converted_at - make_interval(secs => $2::float8)
+JOIN tracked_links l ON l.id = k.link_id
+JOIN campaigns c ON c.id = l.campaign_id
+WHERE EXISTS (
+    SELECT 1
+    FROM link_clicks mk
+    JOIN tracked_links ml ON ml.id = mk.link_id
+    JOIN campaigns mc ON mc.id = ml.campaign_id
+    WHERE mk.visitor_id = conv.visitor_id
+      AND NOT mk.is_bot
+      AND mk.clicked_at <= conv.converted_at
+      AND mk.clicked_at >= conv.converted_at - make_interval(secs => $2::float8)
+      AND mc.user_id = $1
+      AND mc.deleted_at IS NULL
+)
+ORDER BY conv.converted_at, conv.id, k.clicked_at, k.id
+`
+
+type ListAttributionTouchesParams struct {
+	UserID          uuid.UUID          `json:"user_id"`
+	LookbackSeconds float64            `json:"lookback_seconds"`
+	FromTime        pgtype.Timestamptz `json:"from_time"`
+	ToTime          pgtype.Timestamptz `json:"to_time"`
+}
+
+type ListAttributionTouchesRow struct {
+	ConversionID  uuid.UUID          `json:"conversion_id"`
+	ConvertedAt   pgtype.Timestamptz `json:"converted_at"`
+	Value         float64            `json:"value"`
+	Currency      string             `json:"currency"`
+	ClickedAt     pgtype.Timestamptz `json:"clicked_at"`
+	CampaignID    uuid.UUID          `json:"campaign_id"`
+	CampaignTitle string             `json:"campaign_title"`
+	Owned         bool               `json:"owned"`
+}
+
+// Conversion events in the window that a click on one of the user's live
+// campaigns led to, each with every click by a person of the same visitor
+// within the lookback before it, from first to last. Clicks on other
+// campaigns are included as they share the credit.
+func (q *Queries) ListAttributionTouches(ctx context.Context, arg ListAttributionTouchesParams) ([]ListAttributionTouchesRow, error) {
+	rows, err := q.db.Query(ctx, listAttributionTouches,
+		arg.UserID,
+		arg.LookbackSeconds,
+		arg.FromTime,
+		arg.ToTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAttributionTouchesRow
+	for rows.Next() {
+		var i ListAttributionTouchesRow
+		if err := rows.Scan(
+			&i.ConversionID,
+			&i.ConvertedAt,
+			&i.Value,
+			&i.Currency,
+			&i.ClickedAt,
+			&i.CampaignID,
+			&i.CampaignTitle,
+			&i.Owned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -12,12 +12,71 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countCampaigns = `-- name: CountCampaigns :one
+SELECT COUNT(*)::bigint
+FROM campaigns
+WHERE user_id = $1
+  AND deleted_at IS NULL
+  AND (COALESCE(cardinality($2::text[]), 0) = 0 OR status = ANY($2::text[]))
+  AND ($3::timestamptz IS NULL OR start_date IS NULL OR start_date <= $3)
+  AND ($4::timestamptz IS NULL OR end_date IS NULL OR end_date >= $4)
+  AND ($5::float8 IS NULL OR budget >= $5)
+  AND ($6::float8 IS NULL OR budget <= $6)
+  AND ($7::text IS NULL OR title ILIKE $7 OR description ILIKE $7)
+  AND (COALESCE(cardinality($8::uuid[]), 0) = 0 OR (
+      SELECT COUNT(*) FROM campaign_tags ct
+      WHERE ct.campaign_id = campaigns.id AND ct.tag_id = ANY($8::uuid[])
+  ) >= CASE WHEN $9::boolean THEN cardinality($8::uuid[]) ELSE 1 END)
+`
+
+type CountCampaignsParams struct {
+	UserID      uuid.UUID          `json:"user_id"`
+	Statuses    []string           `json:"statuses"`
+	ActiveTo    pgtype.Timestamptz `json:"active_to"`
+	ActiveFrom  pgtype.Timestamptz `json:"active_from"`
+	MinBudget   *float64           `json:"min_budget"`
+	MaxBudget   *float64           `json:"max_budget"`
+	Search      *string            `json:"search"`
+	TagIds      []uuid.UUID        `json:"tag_ids"`
+	TagMatchAll bool               `json:"tag_match_all"`
+}
+
+func (q *Queries) CountCampaigns(ctx context.Context, arg CountCampaignsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countCampaigns,
+		arg.UserID,
+		arg.Statuses,
+		arg.ActiveTo,
+		arg.ActiveFrom,
+		arg.MinBudget,
+		arg.MaxBudget,
+		arg.Search,
+		arg.TagIds,
+		arg.TagMatchAll,
+	)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const countDeletedCampaigns = `-- name: CountDeletedCampaigns :one
+SELECT COUNT(*)::bigint
+FROM campaigns
+WHERE user_id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) CountDeletedCampaigns(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countDeletedCampaigns, userID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const createCampaign = `-- name: CreateCampaign :one
 INSERT INTO campaigns (
     user_id, title, description, status, start_date, end_date, budget
 ) VALUES (
              $1, $2, $3, $4, $5, $6, $7
-         ) RETURNING id, user_id, title, description, status, budget, created_at
+         ) RETURNING id, user_id, title, description, status, start_date, end_date, budget, created_at, updated_at, deleted_at, version
 `
 
 type CreateCampaignParams struct {
@@ -30,17 +89,7 @@ type CreateCampaignParams struct {
 	Budget      float64            `json:"budget"`
 }
 
-type CreateCampaignRow struct {
-	ID          uuid.UUID          `json:"id"`
-	UserID      uuid.UUID          `json:"user_id"`
-	Title       string             `json:"title"`
-	Description *string            `json:"description"`
-	Status      string             `json:"status"`
-	Budget      float64            `json:"budget"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-}
-
-func (q *Queries) CreateCampaign(ctx context.Context, arg CreateCampaignParams) (CreateCampaignRow, error) {
+func (q *Queries) CreateCampaign(ctx context.Context, arg CreateCampaignParams) (Campaign, error) {
 	row := q.db.QueryRow(ctx, createCampaign,
 		arg.UserID,
 		arg.Title,
@@ -50,22 +99,30 @@ func (q *Queries) CreateCampaign(ctx context.Context, arg CreateCampaignParams)
 		arg.EndDate,
 		arg.Budget,
 	)
-	var i CreateCampaignRow
+	var i Campaign
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
 		&i.Title,
 		&i.Description,
 		&i.Status,
+		&i.StartDate,
+		&i.EndDate,
 		&i.Budget,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
-const deleteCampaign = `-- name: DeleteCampaign :exec
-DELETE FROM campaigns
-WHERE id = $1 AND user_id = $2
+const deleteCampaign = `-- name: DeleteCampaign :one
+UPDATE campaigns
+SET deleted_at = NOW(),
+    version = version + 1
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+    RETURNING id, user_id, title, description, status, start_date, end_date, budget, created_at, updated_at, deleted_at, version
 `
 
 type DeleteCampaignParams struct {
@@ -73,14 +130,29 @@ type DeleteCampaignParams struct {
 	UserID uuid.UUID `json:"user_id"`
 }
 
-func (q *Queries) DeleteCampaign(ctx context.Context, arg DeleteCampaignParams) error {
-	_, err := q.db.Exec(ctx, deleteCampaign, arg.ID, arg.UserID)
-	return err
+func (q *Queries) DeleteCampaign(ctx context.Context, arg DeleteCampaignParams) (Campaign, error) {
+	row := q.db.QueryRow(ctx, deleteCampaign, arg.ID, arg.UserID)
+	var i Campaign
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Budget,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
 }
 
 const getCampaign = `-- name: GetCampaign :one
-SELECT id, user_id, title, description, status, start_date, end_date, budget, created_at, updated_at FROM campaigns
-WHERE id = $1 AND user_id = $2
+SELECT id, user_id, title, description, status, start_date, end_date, budget, created_at, updated_at, deleted_at, version FROM campaigns
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
     LIMIT 1
 `
 
@@ -103,22 +175,178 @@ func (q *Queries) GetCampaign(ctx context.Context, arg GetCampaignParams) (Campa
 		&i.Budget,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
-const listCampaigns = `-- name: ListCampaigns :many
-SELECT id, user_id, title, description, status, start_date, end_date, budget, created_at
+const getCampaignForUpdate = `-- name: GetCampaignForUpdate :one
+SELECT id, user_id, title, description, status, start_date, end_date, budget, created_at, updated_at, deleted_at, version FROM campaigns
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+    FOR UPDATE
+`
+
+type GetCampaignForUpdateParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetCampaignForUpdate(ctx context.Context, arg GetCampaignForUpdateParams) (Campaign, error) {
+	row := q.db.QueryRow(ctx, getCampaignForUpdate, arg.ID, arg.UserID)
+	var i Campaign
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Budget,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const listCampaignIDs = `-- name: ListCampaignIDs :many
+SELECT id
 FROM campaigns
 WHERE user_id = $1
-ORDER BY created_at DESC
-    LIMIT $2 OFFSET $3
+  AND deleted_at IS NULL
+  AND (COALESCE(cardinality($2::text[]), 0) = 0 OR status = ANY($2::text[]))
+  AND ($3::timestamptz IS NULL OR start_date IS NULL OR start_date <= $3)
+  AND ($4::timestamptz IS NULL OR end_date IS NULL OR end_date >= $4)
+  AND ($5::float8 IS NULL OR budget >= $5)
+  AND ($6::float8 IS NULL OR budget <= $6)
+  AND ($7::text IS NULL OR title ILIKE $7 OR description ILIKE $7)
+  AND (COALESCE(cardinality($8::uuid[]), 0) = 0 OR (
+      SELECT COUNT(*) FROM campaign_tags ct
+      WHERE ct.campaign_id = campaigns.id AND ct.tag_id = ANY($8::uuid[])
+  ) >= CASE WHEN $9::boolean THEN cardinality($8::uuid[]) ELSE 1 END)
+ORDER BY id
+LIMIT $10
+`
+
+type ListCampaignIDsParams struct {
+	UserID      uuid.UUID          `json:"user_id"`
+	Statuses    []string           `json:"statuses"`
+	ActiveTo    pgtype.Timestamptz `json:"active_to"`
+	ActiveFrom  pgtype.Timestamptz `json:"active_from"`
+	MinBudget   *float64           `json:"min_budget"`
+	MaxBudget   *float64           `json:"max_budget"`
+	Search      *string            `json:"search"`
+	TagIds      []uuid.UUID        `json:"tag_ids"`
+	TagMatchAll bool               `json:"tag_match_all"`
+	RowLimit    int32              `json:"row_limit"`
+}
+
+func (q *Queries) ListCampaignIDs(ctx context.Context, arg ListCampaignIDsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listCampaignIDs,
+		arg.UserID,
+		arg.Statuses,
+		arg.ActiveTo,
+		arg.ActiveFrom,
+		arg.MinBudget,
+		arg.MaxBudget,
+		arg.Search,
+		arg.TagIds,
+		arg.TagMatchAll,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaigns = `-- name: ListCampaigns :many
+WITH filtered AS (
+    SELECT
+        id, user_id, title, description, status, start_date, end_date, budget, created_at, version,
+        (CASE $7::text
+            WHEN 'created_at' THEN created_at
+            WHEN 'start_date' THEN start_date
+            WHEN 'end_date' THEN end_date
+        END)::timestamptz AS sort_time_raw,
+        (CASE WHEN $7::text = 'budget' THEN budget ELSE 0 END)::float8 AS sort_number,
+        (CASE $7::text
+            WHEN 'title' THEN title
+            WHEN 'status' THEN status
+            ELSE ''
+        END)::text AS sort_text
+    FROM campaigns
+    WHERE user_id = $8
+      AND deleted_at IS NULL
+      AND (COALESCE(cardinality($9::text[]), 0) = 0 OR status = ANY($9::text[]))
+      AND ($10::timestamptz IS NULL OR start_date IS NULL OR start_date <= $10)
+      AND ($11::timestamptz IS NULL OR end_date IS NULL OR end_date >= $11)
+      AND ($12::float8 IS NULL OR budget >= $12)
+      AND ($13::float8 IS NULL OR budget <= $13)
+      AND ($14::text IS NULL OR title ILIKE $14 OR description ILIKE $14)
+      AND (COALESCE(cardinality($15::uuid[]), 0) = 0 OR (
+          SELECT COUNT(*) FROM campaign_tags ct
+          WHERE ct.campaign_id = campaigns.id AND ct.tag_id = ANY($15::uuid[])
+      ) >= CASE WHEN $16::boolean THEN cardinality($15::uuid[]) ELSE 1 END)
+), keyed AS (
+    -- Missing dates sort last in either direction.
+    SELECT filtered.id, filtered.user_id, filtered.title, filtered.description, filtered.status, filtered.start_date, filtered.end_date, filtered.budget, filtered.created_at, filtered.version, filtered.sort_time_raw, filtered.sort_number, filtered.sort_text,
+           COALESCE(sort_time_raw, CASE WHEN $17::text = 'asc'
+               THEN '9999-12-31 00:00:00+00'::timestamptz
+               ELSE '0001-01-01 00:00:00+00'::timestamptz
+           END)::timestamptz AS sort_time
+    FROM filtered
+)
+SELECT id, user_id, title, description, status, start_date, end_date, budget, created_at, version, sort_time, sort_number, sort_text
+FROM keyed
+WHERE $1::uuid IS NULL
+   OR ($2::text = 'asc' AND (sort_time, sort_number, sort_text, id) > ($3::timestamptz, $4::float8, $5::text, $1::uuid))
+   OR ($2::text = 'desc' AND (sort_time, sort_number, sort_text, id) < ($3::timestamptz, $4::float8, $5::text, $1::uuid))
+ORDER BY
+    CASE WHEN $2::text = 'asc' THEN sort_time END ASC,
+    CASE WHEN $2::text = 'asc' THEN sort_number END ASC,
+    CASE WHEN $2::text = 'asc' THEN sort_text END ASC,
+    CASE WHEN $2::text = 'asc' THEN id END ASC,
+    CASE WHEN $2::text = 'desc' THEN sort_time END DESC,
+    CASE WHEN $2::text = 'desc' THEN sort_number END DESC,
+    CASE WHEN $2::text = 'desc' THEN sort_text END DESC,
+    CASE WHEN $2::text = 'desc' THEN id END DESC
+LIMIT $6
 `
 
 type ListCampaignsParams struct {
-	UserID uuid.UUID `json:"user_id"`
-	Limit  int32     `json:"limit"`
-	Offset int32     `json:"offset"`
+	CursorID     pgtype.UUID        `json:"cursor_id"`
+	ScanOrder    string             `json:"scan_order"`
+	CursorTime   pgtype.Timestamptz `json:"cursor_time"`
+	CursorNumber float64            `json:"cursor_number"`
+	CursorText   string             `json:"cursor_text"`
+	RowLimit     int32              `json:"row_limit"`
+	SortBy       string             `json:"sort_by"`
+	UserID       uuid.UUID          `json:"user_id"`
+	Statuses     []string           `json:"statuses"`
+	ActiveTo     pgtype.Timestamptz `json:"active_to"`
+	ActiveFrom   pgtype.Timestamptz `json:"active_from"`
+	MinBudget    *float64           `json:"min_budget"`
+	MaxBudget    *float64           `json:"max_budget"`
+	Search       *string            `json:"search"`
+	TagIds       []uuid.UUID        `json:"tag_ids"`
+	TagMatchAll  bool               `json:"tag_match_all"`
+	SortOrder    string             `json:"sort_order"`
 }
 
 type ListCampaignsRow struct {
@@ -131,10 +359,32 @@ type ListCampaignsRow struct {
 	EndDate     pgtype.Timestamptz `json:"end_date"`
 	Budget      float64            `json:"budget"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	Version     int32              `json:"version"`
+	SortTime    pgtype.Timestamptz `json:"sort_time"`
+	SortNumber  float64            `json:"sort_number"`
+	SortText    string             `json:"sort_text"`
 }
 
 func (q *Queries) ListCampaigns(ctx context.Context, arg ListCampaignsParams) ([]ListCampaignsRow, error) {
-	rows, err := q.db.Query(ctx, listCampaigns, arg.UserID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listCampaigns,
+		arg.CursorID,
+		arg.ScanOrder,
+		arg.CursorTime,
+		arg.CursorNumber,
+		arg.CursorText,
+		arg.RowLimit,
+		arg.SortBy,
+		arg.UserID,
+		arg.Statuses,
+		arg.ActiveTo,
+		arg.ActiveFrom,
+		arg.MinBudget,
+		arg.MaxBudget,
+		arg.Search,
+		arg.TagIds,
+		arg.TagMatchAll,
+		arg.SortOrder,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -152,6 +402,64 @@ func (q *Queries) ListCampaigns(ctx context.Context, arg ListCampaignsParams) ([
 			&i.EndDate,
 			&i.Budget,
 			&i.CreatedAt,
+			&i.Version,
+			&i.SortTime,
+			&i.SortNumber,
+			&i.SortText,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeletedCampaigns = `-- name: ListDeletedCampaigns :many
+SELECT id, user_id, title, description, status, start_date, end_date, budget, created_at, updated_at, deleted_at, version FROM campaigns
+WHERE user_id = $1
+  AND deleted_at IS NOT NULL
+  AND ($2::uuid IS NULL OR (deleted_at, id) < ($3::timestamptz, $2::uuid))
+ORDER BY deleted_at DESC, id DESC
+LIMIT $4
+`
+
+type ListDeletedCampaignsParams struct {
+	UserID     uuid.UUID          `json:"user_id"`
+	CursorID   pgtype.UUID        `json:"cursor_id"`
+	CursorTime pgtype.Timestamptz `json:"cursor_time"`
+	RowLimit   int32              `json:"row_limit"`
+}
+
+func (q *Queries) ListDeletedCampaigns(ctx context.Context, arg ListDeletedCampaignsParams) ([]Campaign, error) {
+	rows, err := q.db.Query(ctx, listDeletedCampaigns,
+		arg.UserID,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Campaign
+	for rows.Next() {
+		var i Campaign
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Budget,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -163,6 +471,108 @@ func (q *Queries) ListCampaigns(ctx context.Context, arg ListCampaignsParams) ([
 	return items, nil
 }
 
+const purgeDeletedCampaigns = `-- name: PurgeDeletedCampaigns :execrows
+DELETE FROM campaigns
+WHERE deleted_at IS NOT NULL AND deleted_at < $1::timestamptz
+`
+
+func (q *Queries) PurgeDeletedCampaigns(ctx context.Context, cutoff pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedCampaigns, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreCampaign = `-- name: RestoreCampaign :one
+UPDATE campaigns
+SET deleted_at = NULL,
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+    RETURNING id, user_id, title, description, status, start_date, end_date, budget, created_at, updated_at, deleted_at, version
+`
+
+type RestoreCampaignParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) RestoreCampaign(ctx context.Context, arg RestoreCampaignParams) (Campaign, error) {
+	row := q.db.QueryRow(ctx, restoreCampaign, arg.ID, arg.UserID)
+	var i Campaign
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Budget,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const setCampaignFields = `-- name: SetCampaignFields :one
+UPDATE campaigns
+SET
+    title = $1,
+    description = $2,
+    status = $3,
+    start_date = $4,
+    end_date = $5,
+    budget = $6,
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = $7 AND user_id = $8 AND deleted_at IS NULL
+    RETURNING id, user_id, title, description, status, start_date, end_date, budget, created_at, updated_at, deleted_at, version
+`
+
+type SetCampaignFieldsParams struct {
+	Title       string             `json:"title"`
+	Description *string            `json:"description"`
+	Status      string             `json:"status"`
+	StartDate   pgtype.Timestamptz `json:"start_date"`
+	EndDate     pgtype.Timestamptz `json:"end_date"`
+	Budget      float64            `json:"budget"`
+	ID          uuid.UUID          `json:"id"`
+	UserID      uuid.UUID          `json:"user_id"`
+}
+
+func (q *Queries) SetCampaignFields(ctx context.Context, arg SetCampaignFieldsParams) (Campaign, error) {
+	row := q.db.QueryRow(ctx, setCampaignFields,
+		arg.Title,
+		arg.Description,
+		arg.Status,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Budget,
+		arg.ID,
+		arg.UserID,
+	)
+	var i Campaign
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Budget,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
 const updateCampaign = `-- name: UpdateCampaign :one
 UPDATE campaigns
 SET
@@ -172,9 +582,10 @@ SET
     start_date = COALESCE($6, start_date),
     end_date = COALESCE($7, end_date),
     budget = COALESCE($8, budget),
+    version = version + 1,
     updated_at = NOW()
-WHERE id = $1 AND user_id = $2
-    RETURNING id, user_id, title, description, status, start_date, end_date, budget, created_at, updated_at
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+    RETURNING id, user_id, title, description, status, start_date, end_date, budget, created_at, updated_at, deleted_at, version
 `
 
 type UpdateCampaignParams struct {
@@ -185,7 +596,7 @@ type UpdateCampaignParams struct {
 	Status      *string            `json:"status"`
 	StartDate   pgtype.Timestamptz `json:"start_date"`
 	EndDate     pgtype.Timestamptz `json:"end_date"`
-	Budget      pgtype.Numeric     `json:"budget"`
+	Budget      *float64           `json:"budget"`
 }
 
 func (q *Queries) UpdateCampaign(ctx context.Context, arg UpdateCampaignParams) (Campaign, error) {
@@ -211,6 +622,8 @@ func (q *Queries) UpdateCampaign(ctx context.Context, arg UpdateCampaignParams)
 		&i.Budget,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -0,0 +1,341 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: channels.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getCampaignChannelAllocation = `-- name: GetCampaignChannelAllocation :one
+SELECT
+    COUNT(*)::bigint AS channels,
+    COALESCE(SUM(allocated_budget), 0)::float8 AS allocated
+FROM campaign_channels
+WHERE campaign_id = $1
+`
+
+type GetCampaignChannelAllocationRow struct {
+	Channels  int64   `json:"channels"`
+	Allocated float64 `json:"allocated"`
+}
+
+func (q *Queries) GetCampaignChannelAllocation(ctx context.Context, campaignID uuid.UUID) (GetCampaignChannelAllocationRow, error) {
+	row := q.db.QueryRow(ctx, getCampaignChannelAllocation, campaignID)
+	var i GetCampaignChannelAllocationRow
+	err := row.Scan(&i.Channels, &i.Allocated)
+	return i, err
+}
+
+const getCampaignChannelTotals = `-- name: GetCampaignChannelTotals :many
+SELECT
+    channel_id,
+    COALESCE(SUM(impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(spend), 0)::float8 AS spend,
+    COALESCE(SUM(revenue), 0)::float8 AS revenue
+FROM campaign_metrics
+WHERE campaign_id = $1
+GROUP BY channel_id
+`
+
+type GetCampaignChannelTotalsRow struct {
+	ChannelID   pgtype.UUID `json:"channel_id"`
+	Impressions int64       `json:"impressions"`
+	Clicks      int64       `json:"clicks"`
+	Conversions int64       `json:"conversions"`
+	Spend       float64     `json:"spend"`
+	Revenue     float64     `json:"revenue"`
+}
+
+// Lifetime totals per channel; the row with a NULL channel_id holds the
+// unattributed metrics.
+func (q *Queries) GetCampaignChannelTotals(ctx context.Context, campaignID uuid.UUID) ([]GetCampaignChannelTotalsRow, error) {
+	rows, err := q.db.Query(ctx, getCampaignChannelTotals, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCampaignChannelTotalsRow
+	for rows.Next() {
+		var i GetCampaignChannelTotalsRow
+		if err := rows.Scan(
+			&i.ChannelID,
+			&i.Impressions,
+			&i.Clicks,
+			&i.Conversions,
+			&i.Spend,
+			&i.Revenue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPortfolioChannelAllocations = `-- name: GetPortfolioChannelAllocations :many
+SELECT
+    ch.channel,
+    COUNT(*)::bigint AS campaigns,
+    COALESCE(SUM(ch.allocated_budget), 0)::float8 AS allocated
+FROM campaign_channels ch
+JOIN campaigns c ON c.id = ch.campaign_id
+WHERE c.user_id = $1
+  AND c.deleted_at IS NULL
+GROUP BY ch.channel
+`
+
+type GetPortfolioChannelAllocationsRow struct {
+	Channel   string  `json:"channel"`
+	Campaigns int64   `json:"campaigns"`
+	Allocated float64 `json:"allocated"`
+}
+
+func (q *Queries) GetPortfolioChannelAllocations(ctx context.Context, userID uuid.UUID) ([]GetPortfolioChannelAllocationsRow, error) {
+	rows, err := q.db.Query(ctx, getPortfolioChannelAllocations, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPortfolioChannelAllocationsRow
+	for rows.Next() {
+		var i GetPortfolioChannelAllocationsRow
+		if err := rows.Scan(&i.Channel, &i.Campaigns, &i.Allocated); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPortfolioChannelTotals = `-- name: GetPortfolioChannelTotals :many
+SELECT
+    ch.channel,
+    COALESCE(SUM(m.impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(m.clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(m.conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(m.spend), 0)::float8 AS spend,
+    COALESCE(SUM(m.revenue), 0)::float8 AS revenue
+FROM campaign_metrics m
+JOIN campaigns c ON c.id = m.campaign_id
+LEFT JOIN campaign_channels ch ON ch.id = m.channel_id
+WHERE c.user_id = $1
+  AND c.deleted_at IS NULL
+  AND m.recorded_at >= $2::timestamptz
+  AND m.recorded_at < $3::timestamptz
+GROUP BY ch.channel
+`
+
+type GetPortfolioChannelTotalsParams struct {
+	UserID   uuid.UUID          `json:"user_id"`
+	FromTime pgtype.Timestamptz `json:"from_time"`
+	ToTime   pgtype.Timestamptz `json:"to_time"`
+}
+
+type GetPortfolioChannelTotalsRow struct {
+	Channel     *string `json:"channel"`
+	Impressions int64   `json:"impressions"`
+	Clicks      int64   `json:"clicks"`
+	Conversions int64   `json:"conversions"`
+	Spend       float64 `json:"spend"`
+	Revenue     float64 `json:"revenue"`
+}
+
+// Metrics in the window per channel type; unattributed metrics come back
+// with a NULL channel.
+func (q *Queries) GetPortfolioChannelTotals(ctx context.Context, arg GetPortfolioChannelTotalsParams) ([]GetPortfolioChannelTotalsRow, error) {
+	rows, err := q.db.Query(ctx, getPortfolioChannelTotals, arg.UserID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPortfolioChannelTotalsRow
+	for rows.Next() {
+		var i GetPortfolioChannelTotalsRow
+		if err := rows.Scan(
+			&i.Channel,
+			&i.Impressions,
+			&i.Clicks,
+			&i.Conversions,
+			&i.Spend,
+			&i.Revenue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaignChannels = `-- name: ListCampaignChannels :many
+SELECT id, campaign_id, channel, allocated_budget, status, created_at, updated_at FROM campaign_channels
+WHERE campaign_id = $1
+ORDER BY allocated_budget DESC, channel
+`
+
+func (q *Queries) ListCampaignChannels(ctx context.Context, campaignID uuid.UUID) ([]CampaignChannel, error) {
+	rows, err := q.db.Query(ctx, listCampaignChannels, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CampaignChannel
+	for rows.Next() {
+		var i CampaignChannel
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.Channel,
+			&i.AllocatedBudget,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRemovedChannelsWithMetrics = `-- name: ListRemovedChannelsWithMetrics :many
+SELECT ch.channel
+FROM campaign_channels ch
+WHERE ch.campaign_id = $1
+  AND NOT (ch.channel = ANY($2::text[]))
+  AND EXISTS (SELECT 1 FROM campaign_metrics m WHERE m.channel_id = ch.id)
+ORDER BY ch.channel
+`
+
+type ListRemovedChannelsWithMetricsParams struct {
+	CampaignID   uuid.UUID `json:"campaign_id"`
+	KeepChannels []string  `json:"keep_channels"`
+}
+
+// Channels that replacing the set with keep_channels would remove but that
+// already have metrics recorded against them.
+func (q *Queries) ListRemovedChannelsWithMetrics(ctx context.Context, arg ListRemovedChannelsWithMetricsParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listRemovedChannelsWithMetrics, arg.CampaignID, arg.KeepChannels)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var channel string
+		if err := rows.Scan(&channel); err != nil {
+			return nil, err
+		}
+		items = append(items, channel)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeOtherCampaignChannels = `-- name: RemoveOtherCampaignChannels :execrows
+DELETE FROM campaign_channels
+WHERE campaign_id = $1
+  AND NOT (channel = ANY($2::text[]))
+`
+
+type RemoveOtherCampaignChannelsParams struct {
+	CampaignID   uuid.UUID `json:"campaign_id"`
+	KeepChannels []string  `json:"keep_channels"`
+}
+
+func (q *Queries) RemoveOtherCampaignChannels(ctx context.Context, arg RemoveOtherCampaignChannelsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, removeOtherCampaignChannels, arg.CampaignID, arg.KeepChannels)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateCampaignChannelStatus = `-- name: UpdateCampaignChannelStatus :one
+UPDATE campaign_channels
+SET status = $1,
+    updated_at = NOW()
+WHERE campaign_id = $2 AND channel = $3
+RETURNING id, campaign_id, channel, allocated_budget, status, created_at, updated_at
+`
+
+type UpdateCampaignChannelStatusParams struct {
+	Status     string    `json:"status"`
+	CampaignID uuid.UUID `json:"campaign_id"`
+	Channel    string    `json:"channel"`
+}
+
+func (q *Queries) UpdateCampaignChannelStatus(ctx context.Context, arg UpdateCampaignChannelStatusParams) (CampaignChannel, error) {
+	row := q.db.QueryRow(ctx, updateCampaignChannelStatus, arg.Status, arg.CampaignID, arg.Channel)
+	var i CampaignChannel
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Channel,
+		&i.AllocatedBudget,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertCampaignChannel = `-- name: UpsertCampaignChannel :one
+INSERT INTO campaign_channels (campaign_id, channel, allocated_budget, status)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (campaign_id, channel) DO UPDATE
+SET
+    allocated_budget = EXCLUDED.allocated_budget,
+    status = EXCLUDED.status,
+    updated_at = NOW()
+WHERE (campaign_channels.allocated_budget, campaign_channels.status) IS DISTINCT FROM (EXCLUDED.allocated_budget, EXCLUDED.status)
+RETURNING id, campaign_id, channel, allocated_budget, status, created_at, updated_at
+`
+
+type UpsertCampaignChannelParams struct {
+	CampaignID      uuid.UUID `json:"campaign_id"`
+	Channel         string    `json:"channel"`
+	AllocatedBudget float64   `json:"allocated_budget"`
+	Status          string    `json:"status"`
+}
+
+func (q *Queries) UpsertCampaignChannel(ctx context.Context, arg UpsertCampaignChannelParams) (CampaignChannel, error) {
+	row := q.db.QueryRow(ctx, upsertCampaignChannel,
+		arg.CampaignID,
+		arg.Channel,
+		arg.AllocatedBudget,
+		arg.Status,
+	)
+	var i CampaignChannel
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Channel,
+		&i.AllocatedBudget,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
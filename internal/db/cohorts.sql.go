@@ -0,0 +1,102 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: cohorts.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const listCohortEvents = `-- name: ListCohortEvents :many
+WITH first_touch AS (
+    SELECT DISTINCT ON (k.visitor_id) k.visitor_id, k.clicked_at, l.campaign_id, c.title AS campaign_title
+    FROM link_clicks k
+    JOIN tracked_links l ON l.id = k.link_id
+    JOIN campaigns c ON c.id = l.campaign_id
+    WHERE NOT k.is_bot
+      AND c.user_id = $2::uuid
+      AND c.deleted_at IS NULL
+    ORDER BY k.visitor_id, k.clicked_at, k.id
+)
+SELECT
+    ft.visitor_id,
+    ft.campaign_id,
+    ft.campaign_title,
+    ft.clicked_at AS first_touch_at,
+    e.event,
+    e.occurred_at
+FROM first_touch ft
+JOIN visitor_events e ON e.visitor_id = ft.visitor_id
+    AND e.occurred_at >= ft.clicked_at
+    AND e.occurred_at < $1::timestamptz
+    AND (e.campaign_id IS NULL OR EXISTS (
+        SELECT 1 FROM campaigns oc
+        WHERE oc.id = e.campaign_id
+          AND oc.user_id = $2::uuid
+          AND oc.deleted_at IS NULL
+    ))
+WHERE ft.clicked_at >= $3::timestamptz
+  AND ft.clicked_at < $4::timestamptz
+  AND ($5::uuid IS NULL OR ft.campaign_id = $5)
+ORDER BY ft.visitor_id, e.occurred_at
+`
+
+type ListCohortEventsParams struct {
+	UntilTime  pgtype.Timestamptz `json:"until_time"`
+	UserID     uuid.UUID          `json:"user_id"`
+	FromTime   pgtype.Timestamptz `json:"from_time"`
+	ToTime     pgtype.Timestamptz `json:"to_time"`
+	CampaignID pgtype.UUID        `json:"campaign_id"`
+}
+
+type ListCohortEventsRow struct {
+	VisitorID     uuid.UUID          `json:"visitor_id"`
+	CampaignID    uuid.UUID          `json:"campaign_id"`
+	CampaignTitle string             `json:"campaign_title"`
+	FirstTouchAt  pgtype.Timestamptz `json:"first_touch_at"`
+	Event         string             `json:"event"`
+	OccurredAt    pgtype.Timestamptz `json:"occurred_at"`
+}
+
+// Visitors whose first click by a person on the user's live campaigns was in
+// the window, with everything they did on the user's campaigns (or, for
+// conversions without a click, on their own) from then until the given
+// time, in time order. Clicks on other users' campaigns do not count as a
+// first touch.
+func (q *Queries) ListCohortEvents(ctx context.Context, arg ListCohortEventsParams) ([]ListCohortEventsRow, error) {
+	rows, err := q.db.Query(ctx, listCohortEvents,
+		arg.UntilTime,
+		arg.UserID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.CampaignID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCohortEventsRow
+	for rows.Next() {
+		var i ListCohortEventsRow
+		if err := rows.Scan(
+			&i.VisitorID,
+			&i.CampaignID,
+			&i.CampaignTitle,
+			&i.FirstTouchAt,
+			&i.Event,
+			&i.OccurredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,525 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: comments.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addCommentMentions = `-- name: AddCommentMentions :many
+INSERT INTO comment_mentions (comment_id, user_id)
+SELECT $1::uuid, unnest($2::uuid[])
+ON CONFLICT DO NOTHING
+RETURNING user_id
+`
+
+type AddCommentMentionsParams struct {
+	CommentID uuid.UUID   `json:"comment_id"`
+	UserIds   []uuid.UUID `json:"user_ids"`
+}
+
+// Returns only the users that were not mentioned before, so an edit
+// notifies newly mentioned members once.
+func (q *Queries) AddCommentMentions(ctx context.Context, arg AddCommentMentionsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, addCommentMentions, arg.CommentID, arg.UserIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var user_id uuid.UUID
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countCampaignComments = `-- name: CountCampaignComments :many
+SELECT campaign_id, COUNT(*)::bigint AS comment_count
+FROM campaign_comments
+WHERE campaign_id = ANY($1::uuid[]) AND deleted_at IS NULL
+GROUP BY campaign_id
+`
+
+type CountCampaignCommentsRow struct {
+	CampaignID   uuid.UUID `json:"campaign_id"`
+	CommentCount int64     `json:"comment_count"`
+}
+
+func (q *Queries) CountCampaignComments(ctx context.Context, campaignIds []uuid.UUID) ([]CountCampaignCommentsRow, error) {
+	rows, err := q.db.Query(ctx, countCampaignComments, campaignIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountCampaignCommentsRow
+	for rows.Next() {
+		var i CountCampaignCommentsRow
+		if err := rows.Scan(&i.CampaignID, &i.CommentCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countRootComments = `-- name: CountRootComments :one
+SELECT COUNT(*)::bigint
+FROM campaign_comments
+WHERE campaign_id = $1 AND parent_id IS NULL
+`
+
+func (q *Queries) CountRootComments(ctx context.Context, campaignID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countRootComments, campaignID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const createComment = `-- name: CreateComment :one
+INSERT INTO campaign_comments (campaign_id, parent_id, root_id, author_id, body)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, campaign_id, parent_id, root_id, author_id, body, created_at, edited_at, deleted_at
+`
+
+type CreateCommentParams struct {
+	CampaignID uuid.UUID   `json:"campaign_id"`
+	ParentID   pgtype.UUID `json:"parent_id"`
+	RootID     pgtype.UUID `json:"root_id"`
+	AuthorID   pgtype.UUID `json:"author_id"`
+	Body       string      `json:"body"`
+}
+
+func (q *Queries) CreateComment(ctx context.Context, arg CreateCommentParams) (CampaignComment, error) {
+	row := q.db.QueryRow(ctx, createComment,
+		arg.CampaignID,
+		arg.ParentID,
+		arg.RootID,
+		arg.AuthorID,
+		arg.Body,
+	)
+	var i CampaignComment
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.ParentID,
+		&i.RootID,
+		&i.AuthorID,
+		&i.Body,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getComment = `-- name: GetComment :one
+SELECT id, campaign_id, parent_id, root_id, author_id, body, created_at, edited_at, deleted_at FROM campaign_comments
+WHERE id = $1 AND campaign_id = $2
+`
+
+type GetCommentParams struct {
+	ID         uuid.UUID `json:"id"`
+	CampaignID uuid.UUID `json:"campaign_id"`
+}
+
+func (q *Queries) GetComment(ctx context.Context, arg GetCommentParams) (CampaignComment, error) {
+	row := q.db.QueryRow(ctx, getComment, arg.ID, arg.CampaignID)
+	var i CampaignComment
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.ParentID,
+		&i.RootID,
+		&i.AuthorID,
+		&i.Body,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getCommentForUpdate = `-- name: GetCommentForUpdate :one
+SELECT id, campaign_id, parent_id, root_id, author_id, body, created_at, edited_at, deleted_at FROM campaign_comments
+WHERE id = $1 AND campaign_id = $2
+    FOR UPDATE
+`
+
+type GetCommentForUpdateParams struct {
+	ID         uuid.UUID `json:"id"`
+	CampaignID uuid.UUID `json:"campaign_id"`
+}
+
+func (q *Queries) GetCommentForUpdate(ctx context.Context, arg GetCommentForUpdateParams) (CampaignComment, error) {
+	row := q.db.QueryRow(ctx, getCommentForUpdate, arg.ID, arg.CampaignID)
+	var i CampaignComment
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.ParentID,
+		&i.RootID,
+		&i.AuthorID,
+		&i.Body,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getCommentWithAuthor = `-- name: GetCommentWithAuthor :one
+SELECT cc.id, cc.campaign_id, cc.parent_id, cc.root_id, cc.author_id, cc.body, cc.created_at, cc.edited_at, cc.deleted_at, u.full_name AS author_name, u.email AS author_email
+FROM campaign_comments cc
+LEFT JOIN users u ON u.id = cc.author_id
+WHERE cc.id = $1
+`
+
+type GetCommentWithAuthorRow struct {
+	ID          uuid.UUID          `json:"id"`
+	CampaignID  uuid.UUID          `json:"campaign_id"`
+	ParentID    pgtype.UUID        `json:"parent_id"`
+	RootID      pgtype.UUID        `json:"root_id"`
+	AuthorID    pgtype.UUID        `json:"author_id"`
+	Body        string             `json:"body"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	EditedAt    pgtype.Timestamptz `json:"edited_at"`
+	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
+	AuthorName  *string            `json:"author_name"`
+	AuthorEmail *string            `json:"author_email"`
+}
+
+func (q *Queries) GetCommentWithAuthor(ctx context.Context, id uuid.UUID) (GetCommentWithAuthorRow, error) {
+	row := q.db.QueryRow(ctx, getCommentWithAuthor, id)
+	var i GetCommentWithAuthorRow
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.ParentID,
+		&i.RootID,
+		&i.AuthorID,
+		&i.Body,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.DeletedAt,
+		&i.AuthorName,
+		&i.AuthorEmail,
+	)
+	return i, err
+}
+
+const getDiscussableCampaign = `-- name: GetDiscussableCampaign :one
+SELECT c.id, c.user_id, c.title
+FROM campaigns c
+WHERE c.id = $1
+  AND c.deleted_at IS NULL
+  AND ($2::boolean
+       OR c.user_id = $3
+       OR EXISTS (
+           SELECT 1 FROM comment_mentions m
+           JOIN campaign_comments cc ON cc.id = m.comment_id
+           WHERE cc.campaign_id = c.id AND m.user_id = $3
+       ))
+`
+
+type GetDiscussableCampaignParams struct {
+	CampaignID uuid.UUID `json:"campaign_id"`
+	CanViewAll bool      `json:"can_view_all"`
+	UserID     uuid.UUID `json:"user_id"`
+}
+
+type GetDiscussableCampaignRow struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	Title  string    `json:"title"`
+}
+
+// A campaign's discussion is open to its owner, to managers and admins
+// (can_view_all) and to anyone mentioned in it.
+func (q *Queries) GetDiscussableCampaign(ctx context.Context, arg GetDiscussableCampaignParams) (GetDiscussableCampaignRow, error) {
+	row := q.db.QueryRow(ctx, getDiscussableCampaign, arg.CampaignID, arg.CanViewAll, arg.UserID)
+	var i GetDiscussableCampaignRow
+	err := row.Scan(&i.ID, &i.UserID, &i.Title)
+	return i, err
+}
+
+const getUsersByEmails = `-- name: GetUsersByEmails :many
+SELECT id, full_name, email
+FROM users
+WHERE lower(email) = ANY($1::text[])
+`
+
+type GetUsersByEmailsRow struct {
+	ID       uuid.UUID `json:"id"`
+	FullName string    `json:"full_name"`
+	Email    string    `json:"email"`
+}
+
+func (q *Queries) GetUsersByEmails(ctx context.Context, emails []string) ([]GetUsersByEmailsRow, error) {
+	rows, err := q.db.Query(ctx, getUsersByEmails, emails)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUsersByEmailsRow
+	for rows.Next() {
+		var i GetUsersByEmailsRow
+		if err := rows.Scan(&i.ID, &i.FullName, &i.Email); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCommentMentions = `-- name: ListCommentMentions :many
+SELECT m.comment_id, u.id, u.full_name, u.email
+FROM comment_mentions m
+JOIN users u ON u.id = m.user_id
+WHERE m.comment_id = ANY($1::uuid[])
+ORDER BY m.comment_id, lower(u.full_name)
+`
+
+type ListCommentMentionsRow struct {
+	CommentID uuid.UUID `json:"comment_id"`
+	ID        uuid.UUID `json:"id"`
+	FullName  string    `json:"full_name"`
+	Email     string    `json:"email"`
+}
+
+func (q *Queries) ListCommentMentions(ctx context.Context, commentIds []uuid.UUID) ([]ListCommentMentionsRow, error) {
+	rows, err := q.db.Query(ctx, listCommentMentions, commentIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCommentMentionsRow
+	for rows.Next() {
+		var i ListCommentMentionsRow
+		if err := rows.Scan(
+			&i.CommentID,
+			&i.ID,
+			&i.FullName,
+			&i.Email,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCommentReplies = `-- name: ListCommentReplies :many
+SELECT cc.id, cc.campaign_id, cc.parent_id, cc.root_id, cc.author_id, cc.body, cc.created_at, cc.edited_at, cc.deleted_at, u.full_name AS author_name, u.email AS author_email
+FROM campaign_comments cc
+LEFT JOIN users u ON u.id = cc.author_id
+WHERE cc.root_id = ANY($1::uuid[])
+ORDER BY cc.created_at, cc.id
+`
+
+type ListCommentRepliesRow struct {
+	ID          uuid.UUID          `json:"id"`
+	CampaignID  uuid.UUID          `json:"campaign_id"`
+	ParentID    pgtype.UUID        `json:"parent_id"`
+	RootID      pgtype.UUID        `json:"root_id"`
+	AuthorID    pgtype.UUID        `json:"author_id"`
+	Body        string             `json:"body"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	EditedAt    pgtype.Timestamptz `json:"edited_at"`
+	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
+	AuthorName  *string            `json:"author_name"`
+	AuthorEmail *string            `json:"author_email"`
+}
+
+func (q *Queries) ListCommentReplies(ctx context.Context, rootIds []uuid.UUID) ([]ListCommentRepliesRow, error) {
+	rows, err := q.db.Query(ctx, listCommentReplies, rootIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCommentRepliesRow
+	for rows.Next() {
+		var i ListCommentRepliesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.ParentID,
+			&i.RootID,
+			&i.AuthorID,
+			&i.Body,
+			&i.CreatedAt,
+			&i.EditedAt,
+			&i.DeletedAt,
+			&i.AuthorName,
+			&i.AuthorEmail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRootComments = `-- name: ListRootComments :many
+SELECT cc.id, cc.campaign_id, cc.parent_id, cc.root_id, cc.author_id, cc.body, cc.created_at, cc.edited_at, cc.deleted_at, u.full_name AS author_name, u.email AS author_email
+FROM campaign_comments cc
+LEFT JOIN users u ON u.id = cc.author_id
+WHERE cc.campaign_id = $1
+  AND cc.parent_id IS NULL
+  AND ($2::uuid IS NULL OR (cc.created_at, cc.id) > ($3::timestamptz, $2::uuid))
+ORDER BY cc.created_at, cc.id
+LIMIT $4
+`
+
+type ListRootCommentsParams struct {
+	CampaignID uuid.UUID          `json:"campaign_id"`
+	CursorID   pgtype.UUID        `json:"cursor_id"`
+	CursorTime pgtype.Timestamptz `json:"cursor_time"`
+	RowLimit   int32              `json:"row_limit"`
+}
+
+type ListRootCommentsRow struct {
+	ID          uuid.UUID          `json:"id"`
+	CampaignID  uuid.UUID          `json:"campaign_id"`
+	ParentID    pgtype.UUID        `json:"parent_id"`
+	RootID      pgtype.UUID        `json:"root_id"`
+	AuthorID    pgtype.UUID        `json:"author_id"`
+	Body        string             `json:"body"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	EditedAt    pgtype.Timestamptz `json:"edited_at"`
+	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
+	AuthorName  *string            `json:"author_name"`
+	AuthorEmail *string            `json:"author_email"`
+}
+
+func (q *Queries) ListRootComments(ctx context.Context, arg ListRootCommentsParams) ([]ListRootCommentsRow, error) {
+	rows, err := q.db.Query(ctx, listRootComments,
+		arg.CampaignID,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRootCommentsRow
+	for rows.Next() {
+		var i ListRootCommentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.ParentID,
+			&i.RootID,
+			&i.AuthorID,
+			&i.Body,
+			&i.CreatedAt,
+			&i.EditedAt,
+			&i.DeletedAt,
+			&i.AuthorName,
+			&i.AuthorEmail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeOtherCommentMentions = `-- name: RemoveOtherCommentMentions :exec
+DELETE FROM comment_mentions
+WHERE comment_id = $1
+  AND NOT (user_id = ANY($2::uuid[]))
+`
+
+type RemoveOtherCommentMentionsParams struct {
+	CommentID   uuid.UUID   `json:"comment_id"`
+	KeepUserIds []uuid.UUID `json:"keep_user_ids"`
+}
+
+func (q *Queries) RemoveOtherCommentMentions(ctx context.Context, arg RemoveOtherCommentMentionsParams) error {
+	_, err := q.db.Exec(ctx, removeOtherCommentMentions, arg.CommentID, arg.KeepUserIds)
+	return err
+}
+
+const softDeleteComment = `-- name: SoftDeleteComment :one
+UPDATE campaign_comments
+SET body = '',
+    deleted_at = NOW()
+WHERE id = $1
+RETURNING id, campaign_id, parent_id, root_id, author_id, body, created_at, edited_at, deleted_at
+`
+
+func (q *Queries) SoftDeleteComment(ctx context.Context, id uuid.UUID) (CampaignComment, error) {
+	row := q.db.QueryRow(ctx, softDeleteComment, id)
+	var i CampaignComment
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.ParentID,
+		&i.RootID,
+		&i.AuthorID,
+		&i.Body,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateCommentBody = `-- name: UpdateCommentBody :one
+UPDATE campaign_comments
+SET body = $1,
+    edited_at = NOW()
+WHERE id = $2
+RETURNING id, campaign_id, parent_id, root_id, author_id, body, created_at, edited_at, deleted_at
+`
+
+type UpdateCommentBodyParams struct {
+	Body string    `json:"body"`
+	ID   uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateCommentBody(ctx context.Context, arg UpdateCommentBodyParams) (CampaignComment, error) {
+	row := q.db.QueryRow(ctx, updateCommentBody, arg.Body, arg.ID)
+	var i CampaignComment
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.ParentID,
+		&i.RootID,
+		&i.AuthorID,
+		&i.Body,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,239 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: conversions.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const attachConversionClick = `-- name: AttachConversionClick :one
+UPDATE conversions
+SET
+    link_id = $1,
+    click_id = $2,
+    visitor_id = COALESCE(visitor_id, $3)
+WHERE id = $4 AND click_id IS NULL
+    RETURNING id, campaign_id, link_id, click_id, visitor_id, transaction_id, value, currency, source, converted_at, created_at, event
+`
+
+type AttachConversionClickParams struct {
+	LinkID    pgtype.UUID `json:"link_id"`
+	ClickID   pgtype.UUID `json:"click_id"`
+	VisitorID pgtype.UUID `json:"visitor_id"`
+	ID        uuid.UUID   `json:"id"`
+}
+
+// Ties a conversion that has lost or never had its click to the click a
+// signed postback reported for the same transaction.
+func (q *Queries) AttachConversionClick(ctx context.Context, arg AttachConversionClickParams) (Conversion, error) {
+	row := q.db.QueryRow(ctx, attachConversionClick,
+		arg.LinkID,
+		arg.ClickID,
+		arg.VisitorID,
+		arg.ID,
+	)
+	var i Conversion
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.LinkID,
+		&i.ClickID,
+		&i.VisitorID,
+		&i.TransactionID,
+		&i.Value,
+		&i.Currency,
+		&i.Source,
+		&i.ConvertedAt,
+		&i.CreatedAt,
+		&i.Event,
+	)
+	return i, err
+}
+
+const countCampaignConversions = `-- name: CountCampaignConversions :one
+SELECT COUNT(*)::bigint FROM conversions
+WHERE campaign_id = $1
+`
+
+func (q *Queries) CountCampaignConversions(ctx context.Context, campaignID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countCampaignConversions, campaignID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const createConversion = `-- name: CreateConversion :one
+INSERT INTO conversions (
+    campaign_id, link_id, click_id, visitor_id, transaction_id, event, value, currency, source, converted_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+ON CONFLICT (campaign_id, transaction_id) WHERE transaction_id IS NOT NULL DO NOTHING
+RETURNING id, campaign_id, link_id, click_id, visitor_id, transaction_id, value, currency, source, converted_at, created_at, event
+`
+
+type CreateConversionParams struct {
+	CampaignID    pgtype.UUID        `json:"campaign_id"`
+	LinkID        pgtype.UUID        `json:"link_id"`
+	ClickID       pgtype.UUID        `json:"click_id"`
+	VisitorID     pgtype.UUID        `json:"visitor_id"`
+	TransactionID *string            `json:"transaction_id"`
+	Event         string             `json:"event"`
+	Value         float64            `json:"value"`
+	Currency      string             `json:"currency"`
+	Source        string             `json:"source"`
+	ConvertedAt   pgtype.Timestamptz `json:"converted_at"`
+}
+
+// Returns no row when the transaction ID was already recorded for the same
+// campaign, by either source.
+func (q *Queries) CreateConversion(ctx context.Context, arg CreateConversionParams) (Conversion, error) {
+	row := q.db.QueryRow(ctx, createConversion,
+		arg.CampaignID,
+		arg.LinkID,
+		arg.ClickID,
+		arg.VisitorID,
+		arg.TransactionID,
+		arg.Event,
+		arg.Value,
+		arg.Currency,
+		arg.Source,
+		arg.ConvertedAt,
+	)
+	var i Conversion
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.LinkID,
+		&i.ClickID,
+		&i.VisitorID,
+		&i.TransactionID,
+		&i.Value,
+		&i.Currency,
+		&i.Source,
+		&i.ConvertedAt,
+		&i.CreatedAt,
+		&i.Event,
+	)
+	return i, err
+}
+
+const getConversionByTransaction = `-- name: GetConversionByTransaction :one
+SELECT id, campaign_id, link_id, click_id, visitor_id, transaction_id, value, currency, source, converted_at, created_at, event FROM conversions
+WHERE campaign_id IS NOT DISTINCT FROM $1::uuid
+  AND transaction_id = $2
+`
+
+type GetConversionByTransactionParams struct {
+	CampaignID    pgtype.UUID `json:"campaign_id"`
+	TransactionID *string     `json:"transaction_id"`
+}
+
+func (q *Queries) GetConversionByTransaction(ctx context.Context, arg GetConversionByTransactionParams) (Conversion, error) {
+	row := q.db.QueryRow(ctx, getConversionByTransaction, arg.CampaignID, arg.TransactionID)
+	var i Conversion
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.LinkID,
+		&i.ClickID,
+		&i.VisitorID,
+		&i.TransactionID,
+		&i.Value,
+		&i.Currency,
+		&i.Source,
+		&i.ConvertedAt,
+		&i.CreatedAt,
+		&i.Event,
+	)
+	return i, err
+}
+
+const listCampaignConversions = `-- name: ListCampaignConversions :many
+SELECT
+    cv.id, cv.campaign_id, cv.link_id, cv.click_id, cv.visitor_id, cv.transaction_id, cv.value, cv.currency, cv.source, cv.converted_at, cv.created_at, cv.event,
+    l.code AS link_code,
+    ch.channel,
+    v.name AS variant_name
+FROM conversions cv
+LEFT JOIN tracked_links l ON l.id = cv.link_id
+LEFT JOIN campaign_channels ch ON ch.id = l.channel_id
+LEFT JOIN campaign_variants v ON v.id = l.variant_id
+WHERE cv.campaign_id = $1
+  AND ($2::uuid IS NULL OR (cv.converted_at, cv.id) < ($3::timestamptz, $2::uuid))
+ORDER BY cv.converted_at DESC, cv.id DESC
+LIMIT $4
+`
+
+type ListCampaignConversionsParams struct {
+	CampaignID pgtype.UUID        `json:"campaign_id"`
+	CursorID   pgtype.UUID        `json:"cursor_id"`
+	CursorTime pgtype.Timestamptz `json:"cursor_time"`
+	RowLimit   int32              `json:"row_limit"`
+}
+
+type ListCampaignConversionsRow struct {
+	ID            uuid.UUID          `json:"id"`
+	CampaignID    pgtype.UUID        `json:"campaign_id"`
+	LinkID        pgtype.UUID        `json:"link_id"`
+	ClickID       pgtype.UUID        `json:"click_id"`
+	VisitorID     pgtype.UUID        `json:"visitor_id"`
+	TransactionID *string            `json:"transaction_id"`
+	Value         float64            `json:"value"`
+	Currency      string             `json:"currency"`
+	Source        string             `json:"source"`
+	ConvertedAt   pgtype.Timestamptz `json:"converted_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	Event         string             `json:"event"`
+	LinkCode      *string            `json:"link_code"`
+	Channel       *string            `json:"channel"`
+	VariantName   *string            `json:"variant_name"`
+}
+
+// Conversions of a campaign, newest first, with the link that led to them.
+func (q *Queries) ListCampaignConversions(ctx context.Context, arg ListCampaignConversionsParams) ([]ListCampaignConversionsRow, error) {
+	rows, err := q.db.Query(ctx, listCampaignConversions,
+		arg.CampaignID,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCampaignConversionsRow
+	for rows.Next() {
+		var i ListCampaignConversionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.LinkID,
+			&i.ClickID,
+			&i.VisitorID,
+			&i.TransactionID,
+			&i.Value,
+			&i.Currency,
+			&i.Source,
+			&i.ConvertedAt,
+			&i.CreatedAt,
+			&i.Event,
+			&i.LinkCode,
+			&i.Channel,
+			&i.VariantName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,121 @@
+package db
+
+// This file is maintained by hand: sqlc has no row iterator for pgx, so the
+// export queries are re-run through a server-side cursor here.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// cursorFetchSize is the number of rows each FETCH pulls from the server.
+const cursorFetchSize = 500
+
+// StreamExportCampaigns runs ExportCampaigns and calls fn for every row
+// without loading the whole result. It must be called inside a transaction.
+func (q *Queries) StreamExportCampaigns(ctx context.Context, arg ExportCampaignsParams, fn func(ExportCampaignsRow) error) error {
+	args := []interface{}{
+		arg.UserID,
+		arg.Statuses,
+		arg.ActiveTo,
+		arg.ActiveFrom,
+		arg.MinBudget,
+		arg.MaxBudget,
+		arg.Search,
+		arg.TagIds,
+		arg.TagMatchAll,
+		arg.SortOrder,
+		arg.SortBy,
+	}
+	return q.streamCursor(ctx, "export_campaigns_cursor", exportCampaigns, args, func(rows pgx.Rows) error {
+		var i ExportCampaignsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Budget,
+			&i.Version,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Impressions,
+			&i.Clicks,
+			&i.Conversions,
+			&i.Spend,
+			&i.Revenue,
+			&i.Tags,
+		); err != nil {
+			return err
+		}
+		return fn(i)
+	})
+}
+
+// StreamExportCampaignMetricSeries runs ExportCampaignMetricSeries and calls
+// fn for every row without loading the whole result. It must be called
+// inside a transaction.
+func (q *Queries) StreamExportCampaignMetricSeries(ctx context.Context, arg ExportCampaignMetricSeriesParams, fn func(ExportCampaignMetricSeriesRow) error) error {
+	args := []interface{}{
+		arg.TimeZone,
+		arg.Bucket,
+		arg.UserID,
+		arg.CampaignID,
+		arg.Channel,
+		arg.FromTime,
+		arg.ToTime,
+	}
+	return q.streamCursor(ctx, "export_metric_series_cursor", exportCampaignMetricSeries, args, func(rows pgx.Rows) error {
+		var i ExportCampaignMetricSeriesRow
+		if err := rows.Scan(
+			&i.BucketStart,
+			&i.CampaignID,
+			&i.CampaignTitle,
+			&i.Impressions,
+			&i.Clicks,
+			&i.Conversions,
+			&i.Spend,
+			&i.Revenue,
+		); err != nil {
+			return err
+		}
+		return fn(i)
+	})
+}
+
+// streamCursor declares a cursor for query and fetches it in batches of
+// cursorFetchSize, passing each row to scan.
+func (q *Queries) streamCursor(ctx context.Context, name, query string, args []interface{}, scan func(pgx.Rows) error) error {
+	if _, err := q.db.Exec(ctx, "DECLARE "+name+" NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		return err
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", cursorFetchSize, name)
+	for {
+		rows, err := q.db.Query(ctx, fetch)
+		if err != nil {
+			return err
+		}
+		n := 0
+		for rows.Next() {
+			n++
+			if err := scan(rows); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if n < cursorFetchSize {
+			break
+		}
+	}
+
+	_, err := q.db.Exec(ctx, "CLOSE "+name)
+	return err
+}
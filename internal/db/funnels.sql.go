@@ -0,0 +1,273 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: funnels.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createFunnel = `-- name: CreateFunnel :one
+INSERT INTO funnels (name, description, steps, window_days, created_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, name, description, steps, window_days, created_by, created_at, updated_at
+`
+
+type CreateFunnelParams struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Steps       []string    `json:"steps"`
+	WindowDays  int32       `json:"window_days"`
+	CreatedBy   pgtype.UUID `json:"created_by"`
+}
+
+func (q *Queries) CreateFunnel(ctx context.Context, arg CreateFunnelParams) (Funnel, error) {
+	row := q.db.QueryRow(ctx, createFunnel,
+		arg.Name,
+		arg.Description,
+		arg.Steps,
+		arg.WindowDays,
+		arg.CreatedBy,
+	)
+	var i Funnel
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Steps,
+		&i.WindowDays,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteFunnel = `-- name: DeleteFunnel :execrows
+DELETE FROM funnels
+WHERE id = $1
+`
+
+func (q *Queries) DeleteFunnel(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteFunnel, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getFunnel = `-- name: GetFunnel :one
+SELECT id, name, description, steps, window_days, created_by, created_at, updated_at FROM funnels
+WHERE id = $1
+`
+
+func (q *Queries) GetFunnel(ctx context.Context, id uuid.UUID) (Funnel, error) {
+	row := q.db.QueryRow(ctx, getFunnel, id)
+	var i Funnel
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Steps,
+		&i.WindowDays,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getFunnelForUpdate = `-- name: GetFunnelForUpdate :one
+SELECT id, name, description, steps, window_days, created_by, created_at, updated_at FROM funnels
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetFunnelForUpdate(ctx context.Context, id uuid.UUID) (Funnel, error) {
+	row := q.db.QueryRow(ctx, getFunnelForUpdate, id)
+	var i Funnel
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Steps,
+		&i.WindowDays,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listFunnelEvents = `-- name: ListFunnelEvents :many
+WITH entries AS (
+    SELECT DISTINCT ON (e.visitor_id) e.visitor_id, e.campaign_id, e.occurred_at AS entered_at
+    FROM visitor_events e
+    JOIN campaigns c ON c.id = e.campaign_id
+    WHERE e.event = $4::text
+      AND e.occurred_at >= $5::timestamptz
+      AND e.occurred_at < $6::timestamptz
+      AND c.user_id = $3::uuid
+      AND c.deleted_at IS NULL
+      AND ($7::uuid IS NULL OR e.campaign_id = $7)
+    ORDER BY e.visitor_id, e.occurred_at
+)
+SELECT
+    en.visitor_id,
+    en.campaign_id,
+    c.title AS campaign_title,
+    e.event,
+    e.occurred_at
+FROM entries en
+JOIN campaigns c ON c.id = en.campaign_id
+JOIN visitor_events e ON e.visitor_id = en.visitor_id
+    AND e.occurred_at >= en.entered_at
+    AND e.occurred_at <= en.entered_at + make_interval(days => $1::int)
+    AND e.event = ANY($2::text[])
+    AND (e.campaign_id IS NULL OR EXISTS (
+        SELECT 1 FROM campaigns oc
+        WHERE oc.id = e.campaign_id
+          AND oc.user_id = $3::uuid
+          AND oc.deleted_at IS NULL
+    ))
+ORDER BY en.visitor_id, e.occurred_at, e.event = $4::text DESC
+`
+
+type ListFunnelEventsParams struct {
+	WindowDays int32              `json:"window_days"`
+	Steps      []string           `json:"steps"`
+	UserID     uuid.UUID          `json:"user_id"`
+	FirstStep  string             `json:"first_step"`
+	FromTime   pgtype.Timestamptz `json:"from_time"`
+	ToTime     pgtype.Timestamptz `json:"to_time"`
+	CampaignID pgtype.UUID        `json:"campaign_id"`
+}
+
+type ListFunnelEventsRow struct {
+	VisitorID     uuid.UUID          `json:"visitor_id"`
+	CampaignID    uuid.UUID          `json:"campaign_id"`
+	CampaignTitle string             `json:"campaign_title"`
+	Event         string             `json:"event"`
+	OccurredAt    pgtype.Timestamptz `json:"occurred_at"`
+}
+
+// Visitors entering a funnel in the window, by their first first-step
+// event on one of the user's live campaigns, with their events of the
+// funnel's steps from entering until the window closes, in time order.
+// Later events only count on the user's campaigns or, for conversions
+// without a click, as the visitor's own. A campaign ID narrows the entries
+// to that campaign.
+func (q *Queries) ListFunnelEvents(ctx context.Context, arg ListFunnelEventsParams) ([]ListFunnelEventsRow, error) {
+	rows, err := q.db.Query(ctx, listFunnelEvents,
+		arg.WindowDays,
+		arg.Steps,
+		arg.UserID,
+		arg.FirstStep,
+		arg.FromTime,
+		arg.ToTime,
+		arg.CampaignID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFunnelEventsRow
+	for rows.Next() {
+		var i ListFunnelEventsRow
+		if err := rows.Scan(
+			&i.VisitorID,
+			&i.CampaignID,
+			&i.CampaignTitle,
+			&i.Event,
+			&i.OccurredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFunnels = `-- name: ListFunnels :many
+SELECT id, name, description, steps, window_days, created_by, created_at, updated_at FROM funnels
+ORDER BY lower(name)
+`
+
+func (q *Queries) ListFunnels(ctx context.Context) ([]Funnel, error) {
+	rows, err := q.db.Query(ctx, listFunnels)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Funnel
+	for rows.Next() {
+		var i Funnel
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Steps,
+			&i.WindowDays,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateFunnel = `-- name: UpdateFunnel :one
+UPDATE funnels
+SET
+    name = COALESCE($1, name),
+    description = COALESCE($2, description),
+    steps = COALESCE($3::text[], steps),
+    window_days = COALESCE($4, window_days),
+    updated_at = NOW()
+WHERE id = $5
+RETURNING id, name, description, steps, window_days, created_by, created_at, updated_at
+`
+
+type UpdateFunnelParams struct {
+	Name        *string   `json:"name"`
+	Description *string   `json:"description"`
+	Steps       []string  `json:"steps"`
+	WindowDays  *int32    `json:"window_days"`
+	ID          uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateFunnel(ctx context.Context, arg UpdateFunnelParams) (Funnel, error) {
+	row := q.db.QueryRow(ctx, updateFunnel,
+		arg.Name,
+		arg.Description,
+		arg.Steps,
+		arg.WindowDays,
+		arg.ID,
+	)
+	var i Funnel
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Steps,
+		&i.WindowDays,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
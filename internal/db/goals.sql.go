@@ -0,0 +1,363 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: goals.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createCampaignGoal = `-- name: CreateCampaignGoal :one
+INSERT INTO campaign_goals (campaign_id, metric, direction, target, deadline, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, campaign_id, metric, direction, target, deadline, achieved_at, missed_at, created_by, created_at, updated_at
+`
+
+type CreateCampaignGoalParams struct {
+	CampaignID uuid.UUID          `json:"campaign_id"`
+	Metric     string             `json:"metric"`
+	Direction  string             `json:"direction"`
+	Target     float64            `json:"target"`
+	Deadline   pgtype.Timestamptz `json:"deadline"`
+	CreatedBy  pgtype.UUID        `json:"created_by"`
+}
+
+func (q *Queries) CreateCampaignGoal(ctx context.Context, arg CreateCampaignGoalParams) (CampaignGoal, error) {
+	row := q.db.QueryRow(ctx, createCampaignGoal,
+		arg.CampaignID,
+		arg.Metric,
+		arg.Direction,
+		arg.Target,
+		arg.Deadline,
+		arg.CreatedBy,
+	)
+	var i CampaignGoal
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Metric,
+		&i.Direction,
+		&i.Target,
+		&i.Deadline,
+		&i.AchievedAt,
+		&i.MissedAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteCampaignGoal = `-- name: DeleteCampaignGoal :execrows
+DELETE FROM campaign_goals
+WHERE id = $1 AND campaign_id = $2
+`
+
+type DeleteCampaignGoalParams struct {
+	ID         uuid.UUID `json:"id"`
+	CampaignID uuid.UUID `json:"campaign_id"`
+}
+
+func (q *Queries) DeleteCampaignGoal(ctx context.Context, arg DeleteCampaignGoalParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteCampaignGoal, arg.ID, arg.CampaignID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getCampaignGoalForUpdate = `-- name: GetCampaignGoalForUpdate :one
+SELECT id, campaign_id, metric, direction, target, deadline, achieved_at, missed_at, created_by, created_at, updated_at FROM campaign_goals
+WHERE id = $1 AND campaign_id = $2
+FOR UPDATE
+`
+
+type GetCampaignGoalForUpdateParams struct {
+	ID         uuid.UUID `json:"id"`
+	CampaignID uuid.UUID `json:"campaign_id"`
+}
+
+func (q *Queries) GetCampaignGoalForUpdate(ctx context.Context, arg GetCampaignGoalForUpdateParams) (CampaignGoal, error) {
+	row := q.db.QueryRow(ctx, getCampaignGoalForUpdate, arg.ID, arg.CampaignID)
+	var i CampaignGoal
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Metric,
+		&i.Direction,
+		&i.Target,
+		&i.Deadline,
+		&i.AchievedAt,
+		&i.MissedAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listGoalProgress = `-- name: ListGoalProgress :many
+SELECT
+    g.id, g.campaign_id, g.metric, g.direction, g.target, g.deadline,
+    g.achieved_at, g.missed_at, g.created_at, g.updated_at,
+    c.user_id, c.title AS campaign_title, c.start_date AS campaign_start,
+    COALESCE(SUM(m.impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(m.clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(m.conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(m.spend), 0)::float8 AS spend,
+    COALESCE(SUM(m.revenue), 0)::float8 AS revenue
+FROM campaign_goals g
+JOIN campaigns c ON c.id = g.campaign_id
+LEFT JOIN campaign_metrics m ON m.campaign_id = g.campaign_id AND m.recorded_at < g.deadline
+WHERE c.user_id = $1
+  AND c.deleted_at IS NULL
+  AND (COALESCE(cardinality($2::uuid[]), 0) = 0 OR g.campaign_id = ANY($2::uuid[]))
+GROUP BY g.id, c.id
+ORDER BY g.deadline, g.metric, g.id
+`
+
+type ListGoalProgressParams struct {
+	UserID      uuid.UUID   `json:"user_id"`
+	CampaignIds []uuid.UUID `json:"campaign_ids"`
+}
+
+type ListGoalProgressRow struct {
+	ID            uuid.UUID          `json:"id"`
+	CampaignID    uuid.UUID          `json:"campaign_id"`
+	Metric        string             `json:"metric"`
+	Direction     string             `json:"direction"`
+	Target        float64            `json:"target"`
+	Deadline      pgtype.Timestamptz `json:"deadline"`
+	AchievedAt    pgtype.Timestamptz `json:"achieved_at"`
+	MissedAt      pgtype.Timestamptz `json:"missed_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	UserID        uuid.UUID          `json:"user_id"`
+	CampaignTitle string             `json:"campaign_title"`
+	CampaignStart pgtype.Timestamptz `json:"campaign_start"`
+	Impressions   int64              `json:"impressions"`
+	Clicks        int64              `json:"clicks"`
+	Conversions   int64              `json:"conversions"`
+	Spend         float64            `json:"spend"`
+	Revenue       float64            `json:"revenue"`
+}
+
+// Goals with the campaign totals recorded before their deadline. Without
+// campaign IDs every goal of the user's live campaigns is listed.
+func (q *Queries) ListGoalProgress(ctx context.Context, arg ListGoalProgressParams) ([]ListGoalProgressRow, error) {
+	rows, err := q.db.Query(ctx, listGoalProgress, arg.UserID, arg.CampaignIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGoalProgressRow
+	for rows.Next() {
+		var i ListGoalProgressRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.Metric,
+			&i.Direction,
+			&i.Target,
+			&i.Deadline,
+			&i.AchievedAt,
+			&i.MissedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.CampaignTitle,
+			&i.CampaignStart,
+			&i.Impressions,
+			&i.Clicks,
+			&i.Conversions,
+			&i.Spend,
+			&i.Revenue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOpenGoalProgress = `-- name: ListOpenGoalProgress :many
+SELECT
+    g.id, g.campaign_id, g.metric, g.direction, g.target, g.deadline,
+    g.achieved_at, g.missed_at, g.created_at, g.updated_at,
+    c.user_id, c.title AS campaign_title, c.start_date AS campaign_start,
+    COALESCE(SUM(m.impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(m.clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(m.conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(m.spend), 0)::float8 AS spend,
+    COALESCE(SUM(m.revenue), 0)::float8 AS revenue
+FROM campaign_goals g
+JOIN campaigns c ON c.id = g.campaign_id
+LEFT JOIN campaign_metrics m ON m.campaign_id = g.campaign_id AND m.recorded_at < g.deadline
+WHERE g.achieved_at IS NULL
+  AND g.missed_at IS NULL
+  AND c.deleted_at IS NULL
+  AND (COALESCE(cardinality($1::uuid[]), 0) = 0 OR g.campaign_id = ANY($1::uuid[]))
+  AND (NOT $2::boolean OR g.deadline <= $3::timestamptz)
+GROUP BY g.id, c.id
+ORDER BY g.deadline, g.id
+`
+
+type ListOpenGoalProgressParams struct {
+	CampaignIds []uuid.UUID        `json:"campaign_ids"`
+	OnlyDue     bool               `json:"only_due"`
+	Now         pgtype.Timestamptz `json:"now"`
+}
+
+type ListOpenGoalProgressRow struct {
+	ID            uuid.UUID          `json:"id"`
+	CampaignID    uuid.UUID          `json:"campaign_id"`
+	Metric        string             `json:"metric"`
+	Direction     string             `json:"direction"`
+	Target        float64            `json:"target"`
+	Deadline      pgtype.Timestamptz `json:"deadline"`
+	AchievedAt    pgtype.Timestamptz `json:"achieved_at"`
+	MissedAt      pgtype.Timestamptz `json:"missed_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	UserID        uuid.UUID          `json:"user_id"`
+	CampaignTitle string             `json:"campaign_title"`
+	CampaignStart pgtype.Timestamptz `json:"campaign_start"`
+	Impressions   int64              `json:"impressions"`
+	Clicks        int64              `json:"clicks"`
+	Conversions   int64              `json:"conversions"`
+	Spend         float64            `json:"spend"`
+	Revenue       float64            `json:"revenue"`
+}
+
+// Undecided goals of live campaigns, across all users, with the same
+// totals as ListGoalProgress. With only_due set just the goals whose
+// deadline has passed are listed.
+func (q *Queries) ListOpenGoalProgress(ctx context.Context, arg ListOpenGoalProgressParams) ([]ListOpenGoalProgressRow, error) {
+	rows, err := q.db.Query(ctx, listOpenGoalProgress, arg.CampaignIds, arg.OnlyDue, arg.Now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOpenGoalProgressRow
+	for rows.Next() {
+		var i ListOpenGoalProgressRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.Metric,
+			&i.Direction,
+			&i.Target,
+			&i.Deadline,
+			&i.AchievedAt,
+			&i.MissedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.CampaignTitle,
+			&i.CampaignStart,
+			&i.Impressions,
+			&i.Clicks,
+			&i.Conversions,
+			&i.Spend,
+			&i.Revenue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markGoalAchieved = `-- name: MarkGoalAchieved :execrows
+UPDATE campaign_goals
+SET achieved_at = $1
+WHERE id = $2 AND achieved_at IS NULL AND missed_at IS NULL
+`
+
+type MarkGoalAchievedParams struct {
+	DecidedAt pgtype.Timestamptz `json:"decided_at"`
+	ID        uuid.UUID          `json:"id"`
+}
+
+func (q *Queries) MarkGoalAchieved(ctx context.Context, arg MarkGoalAchievedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markGoalAchieved, arg.DecidedAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markGoalMissed = `-- name: MarkGoalMissed :execrows
+UPDATE campaign_goals
+SET missed_at = $1
+WHERE id = $2 AND achieved_at IS NULL AND missed_at IS NULL
+`
+
+type MarkGoalMissedParams struct {
+	DecidedAt pgtype.Timestamptz `json:"decided_at"`
+	ID        uuid.UUID          `json:"id"`
+}
+
+func (q *Queries) MarkGoalMissed(ctx context.Context, arg MarkGoalMissedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markGoalMissed, arg.DecidedAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateCampaignGoal = `-- name: UpdateCampaignGoal :one
+UPDATE campaign_goals
+SET
+    direction = $1,
+    target = $2,
+    deadline = $3,
+    achieved_at = NULL,
+    missed_at = NULL,
+    updated_at = NOW()
+WHERE id = $4
+RETURNING id, campaign_id, metric, direction, target, deadline, achieved_at, missed_at, created_by, created_at, updated_at
+`
+
+type UpdateCampaignGoalParams struct {
+	Direction string             `json:"direction"`
+	Target    float64            `json:"target"`
+	Deadline  pgtype.Timestamptz `json:"deadline"`
+	ID        uuid.UUID          `json:"id"`
+}
+
+// Changing a goal reopens it; it is decided again on its next evaluation.
+func (q *Queries) UpdateCampaignGoal(ctx context.Context, arg UpdateCampaignGoalParams) (CampaignGoal, error) {
+	row := q.db.QueryRow(ctx, updateCampaignGoal,
+		arg.Direction,
+		arg.Target,
+		arg.Deadline,
+		arg.ID,
+	)
+	var i CampaignGoal
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Metric,
+		&i.Direction,
+		&i.Target,
+		&i.Deadline,
+		&i.AchievedAt,
+		&i.MissedAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,318 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: links.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createLinkClick = `-- name: CreateLinkClick :one
+INSERT INTO link_clicks (link_id, clicked_at, visitor_id, ip_hash, referrer, user_agent, is_bot)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id
+`
+
+type CreateLinkClickParams struct {
+	LinkID    uuid.UUID          `json:"link_id"`
+	ClickedAt pgtype.Timestamptz `json:"clicked_at"`
+	VisitorID uuid.UUID          `json:"visitor_id"`
+	IpHash    string             `json:"ip_hash"`
+	Referrer  string             `json:"referrer"`
+	UserAgent string             `json:"user_agent"`
+	IsBot     bool               `json:"is_bot"`
+}
+
+func (q *Queries) CreateLinkClick(ctx context.Context, arg CreateLinkClickParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createLinkClick,
+		arg.LinkID,
+		arg.ClickedAt,
+		arg.VisitorID,
+		arg.IpHash,
+		arg.Referrer,
+		arg.UserAgent,
+		arg.IsBot,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createTrackedLink = `-- name: CreateTrackedLink :one
+INSERT INTO tracked_links (
+    campaign_id, code, destination_url, channel_id, variant_id,
+    utm_source, utm_medium, utm_campaign, utm_term, utm_content, created_by
+) VALUES (
+    $1, $2, $3, $4, $5,
+    $6, $7, $8, $9, $10, $11
+)
+RETURNING id, campaign_id, code, destination_url, channel_id, variant_id, utm_source, utm_medium, utm_campaign, utm_term, utm_content, created_by, created_at, updated_at
+`
+
+type CreateTrackedLinkParams struct {
+	CampaignID     uuid.UUID   `json:"campaign_id"`
+	Code           string      `json:"code"`
+	DestinationUrl string      `json:"destination_url"`
+	ChannelID      pgtype.UUID `json:"channel_id"`
+	VariantID      pgtype.UUID `json:"variant_id"`
+	UtmSource      string      `json:"utm_source"`
+	UtmMedium      string      `json:"utm_medium"`
+	UtmCampaign    string      `json:"utm_campaign"`
+	UtmTerm        string      `json:"utm_term"`
+	UtmContent     string      `json:"utm_content"`
+	CreatedBy      pgtype.UUID `json:"created_by"`
+}
+
+func (q *Queries) CreateTrackedLink(ctx context.Context, arg CreateTrackedLinkParams) (TrackedLink, error) {
+	row := q.db.QueryRow(ctx, createTrackedLink,
+		arg.CampaignID,
+		arg.Code,
+		arg.DestinationUrl,
+		arg.ChannelID,
+		arg.VariantID,
+		arg.UtmSource,
+		arg.UtmMedium,
+		arg.UtmCampaign,
+		arg.UtmTerm,
+		arg.UtmContent,
+		arg.CreatedBy,
+	)
+	var i TrackedLink
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Code,
+		&i.DestinationUrl,
+		&i.ChannelID,
+		&i.VariantID,
+		&i.UtmSource,
+		&i.UtmMedium,
+		&i.UtmCampaign,
+		&i.UtmTerm,
+		&i.UtmContent,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteTrackedLink = `-- name: DeleteTrackedLink :execrows
+DELETE FROM tracked_links
+WHERE id = $1 AND campaign_id = $2
+`
+
+type DeleteTrackedLinkParams struct {
+	ID         uuid.UUID `json:"id"`
+	CampaignID uuid.UUID `json:"campaign_id"`
+}
+
+func (q *Queries) DeleteTrackedLink(ctx context.Context, arg DeleteTrackedLinkParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteTrackedLink, arg.ID, arg.CampaignID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getLatestVisitorClick = `-- name: GetLatestVisitorClick :one
+SELECT k.id, k.visitor_id, k.clicked_at, l.id AS link_id, l.campaign_id, l.channel_id, l.variant_id
+FROM link_clicks k
+JOIN tracked_links l ON l.id = k.link_id
+WHERE k.visitor_id = $1
+  AND NOT k.is_bot
+  AND k.clicked_at >= $2
+  AND k.clicked_at <= $3
+ORDER BY k.clicked_at DESC, k.id DESC
+LIMIT 1
+`
+
+type GetLatestVisitorClickParams struct {
+	VisitorID uuid.UUID          `json:"visitor_id"`
+	Since     pgtype.Timestamptz `json:"since"`
+	Until     pgtype.Timestamptz `json:"until"`
+}
+
+type GetLatestVisitorClickRow struct {
+	ID         uuid.UUID          `json:"id"`
+	VisitorID  uuid.UUID          `json:"visitor_id"`
+	ClickedAt  pgtype.Timestamptz `json:"clicked_at"`
+	LinkID     uuid.UUID          `json:"link_id"`
+	CampaignID uuid.UUID          `json:"campaign_id"`
+	ChannelID  pgtype.UUID        `json:"channel_id"`
+	VariantID  pgtype.UUID        `json:"variant_id"`
+}
+
+// The visitor's last click by a person since the given time.
+func (q *Queries) GetLatestVisitorClick(ctx context.Context, arg GetLatestVisitorClickParams) (GetLatestVisitorClickRow, error) {
+	row := q.db.QueryRow(ctx, getLatestVisitorClick, arg.VisitorID, arg.Since, arg.Until)
+	var i GetLatestVisitorClickRow
+	err := row.Scan(
+		&i.ID,
+		&i.VisitorID,
+		&i.ClickedAt,
+		&i.LinkID,
+		&i.CampaignID,
+		&i.ChannelID,
+		&i.VariantID,
+	)
+	return i, err
+}
+
+const getLinkClick = `-- name: GetLinkClick :one
+SELECT k.id, k.visitor_id, k.clicked_at, l.id AS link_id, l.campaign_id, l.channel_id, l.variant_id
+FROM link_clicks k
+JOIN tracked_links l ON l.id = k.link_id
+WHERE k.id = $1
+`
+
+type GetLinkClickRow struct {
+	ID         uuid.UUID          `json:"id"`
+	VisitorID  uuid.UUID          `json:"visitor_id"`
+	ClickedAt  pgtype.Timestamptz `json:"clicked_at"`
+	LinkID     uuid.UUID          `json:"link_id"`
+	CampaignID uuid.UUID          `json:"campaign_id"`
+	ChannelID  pgtype.UUID        `json:"channel_id"`
+	VariantID  pgtype.UUID        `json:"variant_id"`
+}
+
+func (q *Queries) GetLinkClick(ctx context.Context, id uuid.UUID) (GetLinkClickRow, error) {
+	row := q.db.QueryRow(ctx, getLinkClick, id)
+	var i GetLinkClickRow
+	err := row.Scan(
+		&i.ID,
+		&i.VisitorID,
+		&i.ClickedAt,
+		&i.LinkID,
+		&i.CampaignID,
+		&i.ChannelID,
+		&i.VariantID,
+	)
+	return i, err
+}
+
+const getTrackedLinkByCode = `-- name: GetTrackedLinkByCode :one
+SELECT l.id, l.campaign_id, l.code, l.destination_url, l.channel_id, l.variant_id, l.utm_source, l.utm_medium, l.utm_campaign, l.utm_term, l.utm_content, l.created_by, l.created_at, l.updated_at
+FROM tracked_links l
+JOIN campaigns c ON c.id = l.campaign_id
+WHERE l.code = $1 AND c.deleted_at IS NULL
+`
+
+// Links of trashed campaigns no longer redirect.
+func (q *Queries) GetTrackedLinkByCode(ctx context.Context, code string) (TrackedLink, error) {
+	row := q.db.QueryRow(ctx, getTrackedLinkByCode, code)
+	var i TrackedLink
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Code,
+		&i.DestinationUrl,
+		&i.ChannelID,
+		&i.VariantID,
+		&i.UtmSource,
+		&i.UtmMedium,
+		&i.UtmCampaign,
+		&i.UtmTerm,
+		&i.UtmContent,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listTrackedLinks = `-- name: ListTrackedLinks :many
+SELECT
+    l.id, l.campaign_id, l.code, l.destination_url, l.channel_id, l.variant_id, l.utm_source, l.utm_medium, l.utm_campaign, l.utm_term, l.utm_content, l.created_by, l.created_at, l.updated_at,
+    ch.channel,
+    v.name AS variant_name,
+    COUNT(k.id) FILTER (WHERE NOT k.is_bot)::bigint AS clicks,
+    COUNT(DISTINCT k.visitor_id) FILTER (WHERE NOT k.is_bot)::bigint AS visitors,
+    COUNT(k.id) FILTER (WHERE k.is_bot)::bigint AS bot_clicks,
+    MAX(k.clicked_at) FILTER (WHERE NOT k.is_bot)::timestamptz AS last_clicked_at
+FROM tracked_links l
+LEFT JOIN campaign_channels ch ON ch.id = l.channel_id
+LEFT JOIN campaign_variants v ON v.id = l.variant_id
+LEFT JOIN link_clicks k ON k.link_id = l.id
+WHERE l.campaign_id = $1
+  AND ($2::uuid IS NULL OR l.id = $2)
+GROUP BY l.id, ch.id, v.id
+ORDER BY l.created_at DESC, l.id
+`
+
+type ListTrackedLinksParams struct {
+	CampaignID uuid.UUID   `json:"campaign_id"`
+	LinkID     pgtype.UUID `json:"link_id"`
+}
+
+type ListTrackedLinksRow struct {
+	ID             uuid.UUID          `json:"id"`
+	CampaignID     uuid.UUID          `json:"campaign_id"`
+	Code           string             `json:"code"`
+	DestinationUrl string             `json:"destination_url"`
+	ChannelID      pgtype.UUID        `json:"channel_id"`
+	VariantID      pgtype.UUID        `json:"variant_id"`
+	UtmSource      string             `json:"utm_source"`
+	UtmMedium      string             `json:"utm_medium"`
+	UtmCampaign    string             `json:"utm_campaign"`
+	UtmTerm        string             `json:"utm_term"`
+	UtmContent     string             `json:"utm_content"`
+	CreatedBy      pgtype.UUID        `json:"created_by"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	Channel        *string            `json:"channel"`
+	VariantName    *string            `json:"variant_name"`
+	Clicks         int64              `json:"clicks"`
+	Visitors       int64              `json:"visitors"`
+	BotClicks      int64              `json:"bot_clicks"`
+	LastClickedAt  pgtype.Timestamptz `json:"last_clicked_at"`
+}
+
+// Links of a campaign, newest first, with their click counts. Bot clicks
+// are counted apart; visitors are distinct visitor cookies. A link ID
+// narrows the list to that link.
+func (q *Queries) ListTrackedLinks(ctx context.Context, arg ListTrackedLinksParams) ([]ListTrackedLinksRow, error) {
+	rows, err := q.db.Query(ctx, listTrackedLinks, arg.CampaignID, arg.LinkID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTrackedLinksRow
+	for rows.Next() {
+		var i ListTrackedLinksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.Code,
+			&i.DestinationUrl,
+			&i.ChannelID,
+			&i.VariantID,
+			&i.UtmSource,
+			&i.UtmMedium,
+			&i.UtmCampaign,
+			&i.UtmTerm,
+			&i.UtmContent,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Channel,
+			&i.VariantName,
+			&i.Clicks,
+			&i.Visitors,
+			&i.BotClicks,
+			&i.LastClickedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -9,6 +9,50 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ApprovalDecision struct {
+	ID         uuid.UUID          `json:"id"`
+	ApprovalID uuid.UUID          `json:"approval_id"`
+	Step       int32              `json:"step"`
+	ApproverID pgtype.UUID        `json:"approver_id"`
+	Decision   string             `json:"decision"`
+	Comment    *string            `json:"comment"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type ApprovalPolicy struct {
+	ID             uuid.UUID          `json:"id"`
+	Name           string             `json:"name"`
+	MinBudget      float64            `json:"min_budget"`
+	ApproverRoles  []string           `json:"approver_roles"`
+	AppliesToRoles []string           `json:"applies_to_roles"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Asset struct {
+	ID           uuid.UUID          `json:"id"`
+	Sha256       string             `json:"sha256"`
+	SizeBytes    int64              `json:"size_bytes"`
+	MimeType     string             `json:"mime_type"`
+	Kind         string             `json:"kind"`
+	StorageKey   string             `json:"storage_key"`
+	ThumbnailKey *string            `json:"thumbnail_key"`
+	Width        *int32             `json:"width"`
+	Height       *int32             `json:"height"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type AudienceSegment struct {
+	ID          uuid.UUID          `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Rules       []byte             `json:"rules"`
+	Summary     string             `json:"summary"`
+	CreatedBy   pgtype.UUID        `json:"created_by"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Campaign struct {
 	ID          uuid.UUID          `json:"id"`
 	UserID      uuid.UUID          `json:"user_id"`
@@ -20,6 +64,232 @@ type Campaign struct {
 	Budget      float64            `json:"budget"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
+	Version     int32              `json:"version"`
+}
+
+type CampaignApproval struct {
+	ID            uuid.UUID          `json:"id"`
+	CampaignID    uuid.UUID          `json:"campaign_id"`
+	RequestedBy   pgtype.UUID        `json:"requested_by"`
+	PolicyID      pgtype.UUID        `json:"policy_id"`
+	Status        string             `json:"status"`
+	Budget        float64            `json:"budget"`
+	ApproverRoles []string           `json:"approver_roles"`
+	CurrentStep   int32              `json:"current_step"`
+	Comment       *string            `json:"comment"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	DecidedAt     pgtype.Timestamptz `json:"decided_at"`
+}
+
+type CampaignAsset struct {
+	ID         uuid.UUID          `json:"id"`
+	CampaignID uuid.UUID          `json:"campaign_id"`
+	AssetID    uuid.UUID          `json:"asset_id"`
+	Filename   string             `json:"filename"`
+	UploadedBy pgtype.UUID        `json:"uploaded_by"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type CampaignChannel struct {
+	ID              uuid.UUID          `json:"id"`
+	CampaignID      uuid.UUID          `json:"campaign_id"`
+	Channel         string             `json:"channel"`
+	AllocatedBudget float64            `json:"allocated_budget"`
+	Status          string             `json:"status"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+}
+
+type CampaignComment struct {
+	ID         uuid.UUID          `json:"id"`
+	CampaignID uuid.UUID          `json:"campaign_id"`
+	ParentID   pgtype.UUID        `json:"parent_id"`
+	RootID     pgtype.UUID        `json:"root_id"`
+	AuthorID   pgtype.UUID        `json:"author_id"`
+	Body       string             `json:"body"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	EditedAt   pgtype.Timestamptz `json:"edited_at"`
+	DeletedAt  pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type CampaignGoal struct {
+	ID         uuid.UUID          `json:"id"`
+	CampaignID uuid.UUID          `json:"campaign_id"`
+	Metric     string             `json:"metric"`
+	Direction  string             `json:"direction"`
+	Target     float64            `json:"target"`
+	Deadline   pgtype.Timestamptz `json:"deadline"`
+	AchievedAt pgtype.Timestamptz `json:"achieved_at"`
+	MissedAt   pgtype.Timestamptz `json:"missed_at"`
+	CreatedBy  pgtype.UUID        `json:"created_by"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type CampaignImport struct {
+	ID           uuid.UUID          `json:"id"`
+	UserID       uuid.UUID          `json:"user_id"`
+	Filename     string             `json:"filename"`
+	Format       string             `json:"format"`
+	Mode         string             `json:"mode"`
+	Status       string             `json:"status"`
+	TotalRows    int32              `json:"total_rows"`
+	ImportedRows int32              `json:"imported_rows"`
+	FailedRows   int32              `json:"failed_rows"`
+	RowErrors    []byte             `json:"row_errors"`
+	Error        *string            `json:"error"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	CompletedAt  pgtype.Timestamptz `json:"completed_at"`
+}
+
+type CampaignMetric struct {
+	ID          uuid.UUID          `json:"id"`
+	CampaignID  uuid.UUID          `json:"campaign_id"`
+	RecordedAt  pgtype.Timestamptz `json:"recorded_at"`
+	Impressions int64              `json:"impressions"`
+	Clicks      int64              `json:"clicks"`
+	Conversions int64              `json:"conversions"`
+	Spend       float64            `json:"spend"`
+	Revenue     float64            `json:"revenue"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	ChannelID   pgtype.UUID        `json:"channel_id"`
+	VariantID   pgtype.UUID        `json:"variant_id"`
+}
+
+type CampaignRevision struct {
+	ID             uuid.UUID          `json:"id"`
+	CampaignID     uuid.UUID          `json:"campaign_id"`
+	Revision       int32              `json:"revision"`
+	ActorID        pgtype.UUID        `json:"actor_id"`
+	Action         string             `json:"action"`
+	Changes        []byte             `json:"changes"`
+	Snapshot       []byte             `json:"snapshot"`
+	SourceRevision *int32             `json:"source_revision"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type CampaignSearchIndex struct {
+	CampaignID uuid.UUID          `json:"campaign_id"`
+	Document   interface{}        `json:"document"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type CampaignSegment struct {
+	CampaignID uuid.UUID          `json:"campaign_id"`
+	SegmentID  uuid.UUID          `json:"segment_id"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type CampaignTag struct {
+	CampaignID uuid.UUID          `json:"campaign_id"`
+	TagID      uuid.UUID          `json:"tag_id"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type CampaignTemplate struct {
+	ID                  uuid.UUID          `json:"id"`
+	OwnerID             uuid.UUID          `json:"owner_id"`
+	Scope               string             `json:"scope"`
+	Name                string             `json:"name"`
+	TitleTemplate       string             `json:"title_template"`
+	DescriptionTemplate *string            `json:"description_template"`
+	DefaultBudget       *float64           `json:"default_budget"`
+	DefaultDurationDays *int32             `json:"default_duration_days"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
+}
+
+type CampaignVariant struct {
+	ID           uuid.UUID          `json:"id"`
+	CampaignID   uuid.UUID          `json:"campaign_id"`
+	Name         string             `json:"name"`
+	Description  string             `json:"description"`
+	TrafficSplit float64            `json:"traffic_split"`
+	IsControl    bool               `json:"is_control"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+type CommentMention struct {
+	CommentID uuid.UUID `json:"comment_id"`
+	UserID    uuid.UUID `json:"user_id"`
+}
+
+type Conversion struct {
+	ID            uuid.UUID          `json:"id"`
+	CampaignID    pgtype.UUID        `json:"campaign_id"`
+	LinkID        pgtype.UUID        `json:"link_id"`
+	ClickID       pgtype.UUID        `json:"click_id"`
+	VisitorID     pgtype.UUID        `json:"visitor_id"`
+	TransactionID *string            `json:"transaction_id"`
+	Value         float64            `json:"value"`
+	Currency      string             `json:"currency"`
+	Source        string             `json:"source"`
+	ConvertedAt   pgtype.Timestamptz `json:"converted_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	Event         string             `json:"event"`
+}
+
+type Funnel struct {
+	ID          uuid.UUID          `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Steps       []string           `json:"steps"`
+	WindowDays  int32              `json:"window_days"`
+	CreatedBy   pgtype.UUID        `json:"created_by"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type LinkClick struct {
+	ID        uuid.UUID          `json:"id"`
+	LinkID    uuid.UUID          `json:"link_id"`
+	ClickedAt pgtype.Timestamptz `json:"clicked_at"`
+	VisitorID uuid.UUID          `json:"visitor_id"`
+	IpHash    string             `json:"ip_hash"`
+	Referrer  string             `json:"referrer"`
+	UserAgent string             `json:"user_agent"`
+	IsBot     bool               `json:"is_bot"`
+}
+
+type Notification struct {
+	ID         uuid.UUID          `json:"id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	Type       string             `json:"type"`
+	ActorID    pgtype.UUID        `json:"actor_id"`
+	CampaignID pgtype.UUID        `json:"campaign_id"`
+	CommentID  pgtype.UUID        `json:"comment_id"`
+	ReadAt     pgtype.Timestamptz `json:"read_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	GoalID     pgtype.UUID        `json:"goal_id"`
+}
+
+type Tag struct {
+	ID        uuid.UUID          `json:"id"`
+	Name      string             `json:"name"`
+	Color     string             `json:"color"`
+	CreatedBy pgtype.UUID        `json:"created_by"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type TrackedLink struct {
+	ID             uuid.UUID          `json:"id"`
+	CampaignID     uuid.UUID          `json:"campaign_id"`
+	Code           string             `json:"code"`
+	DestinationUrl string             `json:"destination_url"`
+	ChannelID      pgtype.UUID        `json:"channel_id"`
+	VariantID      pgtype.UUID        `json:"variant_id"`
+	UtmSource      string             `json:"utm_source"`
+	UtmMedium      string             `json:"utm_medium"`
+	UtmCampaign    string             `json:"utm_campaign"`
+	UtmTerm        string             `json:"utm_term"`
+	UtmContent     string             `json:"utm_content"`
+	CreatedBy      pgtype.UUID        `json:"created_by"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
 }
 
 type User struct {
@@ -31,3 +301,10 @@ type User struct {
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 }
+
+type VisitorEvent struct {
+	VisitorID  uuid.UUID          `json:"visitor_id"`
+	Event      string             `json:"event"`
+	OccurredAt pgtype.Timestamptz `json:"occurred_at"`
+	CampaignID uuid.UUID          `json:"campaign_id"`
+}
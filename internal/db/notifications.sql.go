@@ -0,0 +1,190 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: notifications.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countUnreadNotifications = `-- name: CountUnreadNotifications :one
+SELECT COUNT(*)::bigint
+FROM notifications
+WHERE user_id = $1 AND read_at IS NULL
+`
+
+func (q *Queries) CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countUnreadNotifications, userID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const createNotifications = `-- name: CreateNotifications :execrows
+INSERT INTO notifications (user_id, type, actor_id, campaign_id, comment_id, goal_id)
+SELECT unnest($1::uuid[]), unnest($2::text[]), $3::uuid, $4::uuid, $5::uuid, $6::uuid
+`
+
+type CreateNotificationsParams struct {
+	UserIds    []uuid.UUID `json:"user_ids"`
+	Types      []string    `json:"types"`
+	ActorID    pgtype.UUID `json:"actor_id"`
+	CampaignID pgtype.UUID `json:"campaign_id"`
+	CommentID  pgtype.UUID `json:"comment_id"`
+	GoalID     pgtype.UUID `json:"goal_id"`
+}
+
+func (q *Queries) CreateNotifications(ctx context.Context, arg CreateNotificationsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createNotifications,
+		arg.UserIds,
+		arg.Types,
+		arg.ActorID,
+		arg.CampaignID,
+		arg.CommentID,
+		arg.GoalID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteCommentNotifications = `-- name: DeleteCommentNotifications :exec
+DELETE FROM notifications
+WHERE comment_id = $1
+`
+
+func (q *Queries) DeleteCommentNotifications(ctx context.Context, commentID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteCommentNotifications, commentID)
+	return err
+}
+
+const listNotifications = `-- name: ListNotifications :many
+SELECT
+    n.id, n.user_id, n.type, n.actor_id, n.campaign_id, n.comment_id, n.read_at, n.created_at, n.goal_id,
+    a.full_name AS actor_name,
+    a.email AS actor_email,
+    c.title AS campaign_title,
+    cc.body AS comment_body,
+    g.metric AS goal_metric,
+    g.direction AS goal_direction,
+    g.target AS goal_target
+FROM notifications n
+LEFT JOIN users a ON a.id = n.actor_id
+LEFT JOIN campaigns c ON c.id = n.campaign_id
+LEFT JOIN campaign_comments cc ON cc.id = n.comment_id
+LEFT JOIN campaign_goals g ON g.id = n.goal_id
+WHERE n.user_id = $1
+  AND (NOT $2::boolean OR n.read_at IS NULL)
+  AND ($3::uuid IS NULL OR (n.created_at, n.id) < ($4::timestamptz, $3::uuid))
+ORDER BY n.created_at DESC, n.id DESC
+LIMIT $5
+`
+
+type ListNotificationsParams struct {
+	UserID     uuid.UUID          `json:"user_id"`
+	UnreadOnly bool               `json:"unread_only"`
+	CursorID   pgtype.UUID        `json:"cursor_id"`
+	CursorTime pgtype.Timestamptz `json:"cursor_time"`
+	RowLimit   int32              `json:"row_limit"`
+}
+
+type ListNotificationsRow struct {
+	ID            uuid.UUID          `json:"id"`
+	UserID        uuid.UUID          `json:"user_id"`
+	Type          string             `json:"type"`
+	ActorID       pgtype.UUID        `json:"actor_id"`
+	CampaignID    pgtype.UUID        `json:"campaign_id"`
+	CommentID     pgtype.UUID        `json:"comment_id"`
+	ReadAt        pgtype.Timestamptz `json:"read_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	GoalID        pgtype.UUID        `json:"goal_id"`
+	ActorName     *string            `json:"actor_name"`
+	ActorEmail    *string            `json:"actor_email"`
+	CampaignTitle *string            `json:"campaign_title"`
+	CommentBody   *string            `json:"comment_body"`
+	GoalMetric    *string            `json:"goal_metric"`
+	GoalDirection *string            `json:"goal_direction"`
+	GoalTarget    *float64           `json:"goal_target"`
+}
+
+func (q *Queries) ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]ListNotificationsRow, error) {
+	rows, err := q.db.Query(ctx, listNotifications,
+		arg.UserID,
+		arg.UnreadOnly,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListNotificationsRow
+	for rows.Next() {
+		var i ListNotificationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Type,
+			&i.ActorID,
+			&i.CampaignID,
+			&i.CommentID,
+			&i.ReadAt,
+			&i.CreatedAt,
+			&i.GoalID,
+			&i.ActorName,
+			&i.ActorEmail,
+			&i.CampaignTitle,
+			&i.CommentBody,
+			&i.GoalMetric,
+			&i.GoalDirection,
+			&i.GoalTarget,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markAllNotificationsRead = `-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = $1 AND read_at IS NULL
+`
+
+func (q *Queries) MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, markAllNotificationsRead, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markNotificationRead = `-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = COALESCE(read_at, NOW())
+WHERE id = $1 AND user_id = $2
+`
+
+type MarkNotificationReadParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markNotificationRead, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
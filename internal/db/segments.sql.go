@@ -0,0 +1,397 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: segments.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addCampaignSegments = `-- name: AddCampaignSegments :execrows
+INSERT INTO campaign_segments (campaign_id, segment_id)
+SELECT $1::uuid, unnest($2::uuid[])
+ON CONFLICT DO NOTHING
+`
+
+type AddCampaignSegmentsParams struct {
+	CampaignID uuid.UUID   `json:"campaign_id"`
+	SegmentIds []uuid.UUID `json:"segment_ids"`
+}
+
+func (q *Queries) AddCampaignSegments(ctx context.Context, arg AddCampaignSegmentsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, addCampaignSegments, arg.CampaignID, arg.SegmentIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const countExistingSegments = `-- name: CountExistingSegments :one
+SELECT COUNT(*)::bigint FROM audience_segments
+WHERE id = ANY($1::uuid[])
+`
+
+func (q *Queries) CountExistingSegments(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countExistingSegments, ids)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const countSegmentCampaigns = `-- name: CountSegmentCampaigns :one
+SELECT COUNT(*)::bigint
+FROM campaign_segments cs
+JOIN campaigns c ON c.id = cs.campaign_id
+WHERE cs.segment_id = $1
+  AND c.deleted_at IS NULL
+  AND ($2::boolean OR c.user_id = $3)
+`
+
+type CountSegmentCampaignsParams struct {
+	SegmentID uuid.UUID `json:"segment_id"`
+	AllOwners bool      `json:"all_owners"`
+	UserID    uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) CountSegmentCampaigns(ctx context.Context, arg CountSegmentCampaignsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSegmentCampaigns, arg.SegmentID, arg.AllOwners, arg.UserID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const createSegment = `-- name: CreateSegment :one
+INSERT INTO audience_segments (name, description, rules, summary, created_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, name, description, rules, summary, created_by, created_at, updated_at
+`
+
+type CreateSegmentParams struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Rules       []byte      `json:"rules"`
+	Summary     string      `json:"summary"`
+	CreatedBy   pgtype.UUID `json:"created_by"`
+}
+
+func (q *Queries) CreateSegment(ctx context.Context, arg CreateSegmentParams) (AudienceSegment, error) {
+	row := q.db.QueryRow(ctx, createSegment,
+		arg.Name,
+		arg.Description,
+		arg.Rules,
+		arg.Summary,
+		arg.CreatedBy,
+	)
+	var i AudienceSegment
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Rules,
+		&i.Summary,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteSegment = `-- name: DeleteSegment :execrows
+DELETE FROM audience_segments
+WHERE id = $1
+`
+
+func (q *Queries) DeleteSegment(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSegment, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getSegment = `-- name: GetSegment :one
+SELECT id, name, description, rules, summary, created_by, created_at, updated_at FROM audience_segments
+WHERE id = $1
+`
+
+func (q *Queries) GetSegment(ctx context.Context, id uuid.UUID) (AudienceSegment, error) {
+	row := q.db.QueryRow(ctx, getSegment, id)
+	var i AudienceSegment
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Rules,
+		&i.Summary,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getSegmentForUpdate = `-- name: GetSegmentForUpdate :one
+SELECT id, name, description, rules, summary, created_by, created_at, updated_at FROM audience_segments
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetSegmentForUpdate(ctx context.Context, id uuid.UUID) (AudienceSegment, error) {
+	row := q.db.QueryRow(ctx, getSegmentForUpdate, id)
+	var i AudienceSegment
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Rules,
+		&i.Summary,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listCampaignSegments = `-- name: ListCampaignSegments :many
+SELECT cs.campaign_id, s.id, s.name, s.summary
+FROM campaign_segments cs
+JOIN audience_segments s ON s.id = cs.segment_id
+WHERE cs.campaign_id = ANY($1::uuid[])
+ORDER BY cs.campaign_id, lower(s.name)
+`
+
+type ListCampaignSegmentsRow struct {
+	CampaignID uuid.UUID `json:"campaign_id"`
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Summary    string    `json:"summary"`
+}
+
+func (q *Queries) ListCampaignSegments(ctx context.Context, campaignIds []uuid.UUID) ([]ListCampaignSegmentsRow, error) {
+	rows, err := q.db.Query(ctx, listCampaignSegments, campaignIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCampaignSegmentsRow
+	for rows.Next() {
+		var i ListCampaignSegmentsRow
+		if err := rows.Scan(
+			&i.CampaignID,
+			&i.ID,
+			&i.Name,
+			&i.Summary,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSegmentCampaigns = `-- name: ListSegmentCampaigns :many
+SELECT c.id, c.user_id, c.title, c.description, c.status, c.start_date, c.end_date, c.budget, c.created_at, c.updated_at, c.deleted_at, c.version
+FROM campaign_segments cs
+JOIN campaigns c ON c.id = cs.campaign_id
+WHERE cs.segment_id = $1
+  AND c.deleted_at IS NULL
+  AND ($2::boolean OR c.user_id = $3)
+  AND ($4::uuid IS NULL OR (c.created_at, c.id) < ($5::timestamptz, $4::uuid))
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT $6
+`
+
+type ListSegmentCampaignsParams struct {
+	SegmentID  uuid.UUID          `json:"segment_id"`
+	AllOwners  bool               `json:"all_owners"`
+	UserID     uuid.UUID          `json:"user_id"`
+	CursorID   pgtype.UUID        `json:"cursor_id"`
+	CursorTime pgtype.Timestamptz `json:"cursor_time"`
+	RowLimit   int32              `json:"row_limit"`
+}
+
+// Newest campaigns first. Without @all_owners only the caller's campaigns
+// are listed.
+func (q *Queries) ListSegmentCampaigns(ctx context.Context, arg ListSegmentCampaignsParams) ([]Campaign, error) {
+	rows, err := q.db.Query(ctx, listSegmentCampaigns,
+		arg.SegmentID,
+		arg.AllOwners,
+		arg.UserID,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Campaign
+	for rows.Next() {
+		var i Campaign
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Budget,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSegments = `-- name: ListSegments :many
+SELECT
+    s.id, s.name, s.description, s.rules, s.summary, s.created_by, s.created_at, s.updated_at,
+    (SELECT COUNT(*)
+     FROM campaign_segments cs
+     JOIN campaigns c ON c.id = cs.campaign_id
+     WHERE cs.segment_id = s.id
+       AND c.deleted_at IS NULL)::bigint AS campaign_count
+FROM audience_segments s
+WHERE $1::text IS NULL OR s.name ILIKE $1 OR s.description ILIKE $1
+ORDER BY lower(s.name)
+`
+
+type ListSegmentsRow struct {
+	ID            uuid.UUID          `json:"id"`
+	Name          string             `json:"name"`
+	Description   string             `json:"description"`
+	Rules         []byte             `json:"rules"`
+	Summary       string             `json:"summary"`
+	CreatedBy     pgtype.UUID        `json:"created_by"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	CampaignCount int64              `json:"campaign_count"`
+}
+
+// campaign_count counts every live campaign using the segment.
+func (q *Queries) ListSegments(ctx context.Context, search *string) ([]ListSegmentsRow, error) {
+	rows, err := q.db.Query(ctx, listSegments, search)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSegmentsRow
+	for rows.Next() {
+		var i ListSegmentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Rules,
+			&i.Summary,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CampaignCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeCampaignSegments = `-- name: RemoveCampaignSegments :execrows
+DELETE FROM campaign_segments
+WHERE campaign_id = $1
+  AND segment_id = ANY($2::uuid[])
+`
+
+type RemoveCampaignSegmentsParams struct {
+	CampaignID uuid.UUID   `json:"campaign_id"`
+	SegmentIds []uuid.UUID `json:"segment_ids"`
+}
+
+func (q *Queries) RemoveCampaignSegments(ctx context.Context, arg RemoveCampaignSegmentsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, removeCampaignSegments, arg.CampaignID, arg.SegmentIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const removeOtherCampaignSegments = `-- name: RemoveOtherCampaignSegments :execrows
+DELETE FROM campaign_segments
+WHERE campaign_id = $1
+  AND NOT (segment_id = ANY($2::uuid[]))
+`
+
+type RemoveOtherCampaignSegmentsParams struct {
+	CampaignID     uuid.UUID   `json:"campaign_id"`
+	KeepSegmentIds []uuid.UUID `json:"keep_segment_ids"`
+}
+
+func (q *Queries) RemoveOtherCampaignSegments(ctx context.Context, arg RemoveOtherCampaignSegmentsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, removeOtherCampaignSegments, arg.CampaignID, arg.KeepSegmentIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateSegment = `-- name: UpdateSegment :one
+UPDATE audience_segments
+SET
+    name = COALESCE($1, name),
+    description = COALESCE($2, description),
+    rules = COALESCE($3, rules),
+    summary = COALESCE($4, summary),
+    updated_at = NOW()
+WHERE id = $5
+RETURNING id, name, description, rules, summary, created_by, created_at, updated_at
+`
+
+type UpdateSegmentParams struct {
+	Name        *string   `json:"name"`
+	Description *string   `json:"description"`
+	Rules       []byte    `json:"rules"`
+	Summary     *string   `json:"summary"`
+	ID          uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateSegment(ctx context.Context, arg UpdateSegmentParams) (AudienceSegment, error) {
+	row := q.db.QueryRow(ctx, updateSegment,
+		arg.Name,
+		arg.Description,
+		arg.Rules,
+		arg.Summary,
+		arg.ID,
+	)
+	var i AudienceSegment
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Rules,
+		&i.Summary,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
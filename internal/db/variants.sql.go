@@ -0,0 +1,219 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: variants.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const clearCampaignVariantControl = `-- name: ClearCampaignVariantControl :exec
+UPDATE campaign_variants
+SET is_control = FALSE
+WHERE campaign_id = $1 AND is_control
+`
+
+// Runs before the upserts of a replacement so moving the control from one
+// variant to another never has two controls at once.
+func (q *Queries) ClearCampaignVariantControl(ctx context.Context, campaignID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, clearCampaignVariantControl, campaignID)
+	return err
+}
+
+const getCampaignVariantTotals = `-- name: GetCampaignVariantTotals :many
+SELECT
+    variant_id::uuid AS variant_id,
+    COALESCE(SUM(impressions), 0)::bigint AS impressions,
+    COALESCE(SUM(clicks), 0)::bigint AS clicks,
+    COALESCE(SUM(conversions), 0)::bigint AS conversions,
+    COALESCE(SUM(spend), 0)::float8 AS spend,
+    COALESCE(SUM(revenue), 0)::float8 AS revenue
+FROM campaign_metrics
+WHERE campaign_id = $1
+  AND variant_id IS NOT NULL
+  AND ($2::timestamptz IS NULL OR recorded_at >= $2)
+  AND ($3::timestamptz IS NULL OR recorded_at < $3)
+GROUP BY variant_id
+`
+
+type GetCampaignVariantTotalsParams struct {
+	CampaignID uuid.UUID          `json:"campaign_id"`
+	FromTime   pgtype.Timestamptz `json:"from_time"`
+	ToTime     pgtype.Timestamptz `json:"to_time"`
+}
+
+type GetCampaignVariantTotalsRow struct {
+	VariantID   uuid.UUID `json:"variant_id"`
+	Impressions int64     `json:"impressions"`
+	Clicks      int64     `json:"clicks"`
+	Conversions int64     `json:"conversions"`
+	Spend       float64   `json:"spend"`
+	Revenue     float64   `json:"revenue"`
+}
+
+// Totals per variant, optionally limited to a window. Metrics without a
+// variant are left out.
+func (q *Queries) GetCampaignVariantTotals(ctx context.Context, arg GetCampaignVariantTotalsParams) ([]GetCampaignVariantTotalsRow, error) {
+	rows, err := q.db.Query(ctx, getCampaignVariantTotals, arg.CampaignID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCampaignVariantTotalsRow
+	for rows.Next() {
+		var i GetCampaignVariantTotalsRow
+		if err := rows.Scan(
+			&i.VariantID,
+			&i.Impressions,
+			&i.Clicks,
+			&i.Conversions,
+			&i.Spend,
+			&i.Revenue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaignVariants = `-- name: ListCampaignVariants :many
+SELECT id, campaign_id, name, description, traffic_split, is_control, created_at, updated_at FROM campaign_variants
+WHERE campaign_id = $1
+ORDER BY is_control DESC, lower(name)
+`
+
+func (q *Queries) ListCampaignVariants(ctx context.Context, campaignID uuid.UUID) ([]CampaignVariant, error) {
+	rows, err := q.db.Query(ctx, listCampaignVariants, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CampaignVariant
+	for rows.Next() {
+		var i CampaignVariant
+		if err := rows.Scan(
+			&i.ID,
+			&i.CampaignID,
+			&i.Name,
+			&i.Description,
+			&i.TrafficSplit,
+			&i.IsControl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRemovedVariantsWithMetrics = `-- name: ListRemovedVariantsWithMetrics :many
+SELECT v.name
+FROM campaign_variants v
+WHERE v.campaign_id = $1
+  AND NOT (lower(v.name) = ANY($2::text[]))
+  AND EXISTS (SELECT 1 FROM campaign_metrics m WHERE m.variant_id = v.id)
+ORDER BY lower(v.name)
+`
+
+type ListRemovedVariantsWithMetricsParams struct {
+	CampaignID uuid.UUID `json:"campaign_id"`
+	KeepNames  []string  `json:"keep_names"`
+}
+
+// Variants that replacing the set with keep_names (lower-cased) would remove
+// but that already have metrics recorded against them.
+func (q *Queries) ListRemovedVariantsWithMetrics(ctx context.Context, arg ListRemovedVariantsWithMetricsParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listRemovedVariantsWithMetrics, arg.CampaignID, arg.KeepNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeOtherCampaignVariants = `-- name: RemoveOtherCampaignVariants :execrows
+DELETE FROM campaign_variants
+WHERE campaign_id = $1
+  AND NOT (lower(name) = ANY($2::text[]))
+`
+
+type RemoveOtherCampaignVariantsParams struct {
+	CampaignID uuid.UUID `json:"campaign_id"`
+	KeepNames  []string  `json:"keep_names"`
+}
+
+func (q *Queries) RemoveOtherCampaignVariants(ctx context.Context, arg RemoveOtherCampaignVariantsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, removeOtherCampaignVariants, arg.CampaignID, arg.KeepNames)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertCampaignVariant = `-- name: UpsertCampaignVariant :one
+INSERT INTO campaign_variants (campaign_id, name, description, traffic_split, is_control)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (campaign_id, lower(name)) DO UPDATE
+SET
+    name = EXCLUDED.name,
+    description = EXCLUDED.description,
+    traffic_split = EXCLUDED.traffic_split,
+    is_control = EXCLUDED.is_control,
+    updated_at = NOW()
+RETURNING id, campaign_id, name, description, traffic_split, is_control, created_at, updated_at
+`
+
+type UpsertCampaignVariantParams struct {
+	CampaignID   uuid.UUID `json:"campaign_id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	TrafficSplit float64   `json:"traffic_split"`
+	IsControl    bool      `json:"is_control"`
+}
+
+func (q *Queries) UpsertCampaignVariant(ctx context.Context, arg UpsertCampaignVariantParams) (CampaignVariant, error) {
+	row := q.db.QueryRow(ctx, upsertCampaignVariant,
+		arg.CampaignID,
+		arg.Name,
+		arg.Description,
+		arg.TrafficSplit,
+		arg.IsControl,
+	)
+	var i CampaignVariant
+	err := row.Scan(
+		&i.ID,
+		&i.CampaignID,
+		&i.Name,
+		&i.Description,
+		&i.TrafficSplit,
+		&i.IsControl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
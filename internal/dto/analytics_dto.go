@@ -0,0 +1,96 @@
+package dto
+
+import "time"
+
+type AnalyticsRequest struct {
+	From     string `form:"from"`
+	To       string `form:"to"`
+	Interval string `form:"interval" binding:"omitempty,oneof=day week month"`
+	Timezone string `form:"timezone"`
+	Channel  string `form:"channel" binding:"omitempty,oneof=search social email display video affiliate other"`
+}
+
+type RecordMetricsRequest struct {
+	Metrics []MetricEntryRequest `json:"metrics" binding:"required,min=1,max=1000,dive"`
+}
+
+// MetricEntryRequest records metrics for a point in time. With Channel set
+// they count towards that channel of the campaign, otherwise they are
+// unattributed. Variant names the experiment variant they belong to.
+type MetricEntryRequest struct {
+	RecordedAt  time.Time `json:"recorded_at" binding:"required"`
+	Channel     string    `json:"channel" binding:"omitempty,oneof=search social email display video affiliate other"`
+	Variant     string    `json:"variant" binding:"max=50"`
+	Impressions int64     `json:"impressions" binding:"gte=0"`
+	Clicks      int64     `json:"clicks" binding:"gte=0"`
+	Conversions int64     `json:"conversions" binding:"gte=0"`
+	Spend       float64   `json:"spend" binding:"gte=0"`
+	Revenue     float64   `json:"revenue" binding:"gte=0"`
+}
+
+type MetricEntryResponse struct {
+	RecordedAt  time.Time `json:"recorded_at"`
+	Channel     *string   `json:"channel"`
+	Variant     *string   `json:"variant"`
+	Impressions int64     `json:"impressions"`
+	Clicks      int64     `json:"clicks"`
+	Conversions int64     `json:"conversions"`
+	Spend       float64   `json:"spend"`
+	Revenue     float64   `json:"revenue"`
+}
+
+// KPIResponse holds raw totals plus the derived KPIs. A derived KPI is null
+// when its denominator is zero (e.g. CPC without any clicks).
+type KPIResponse struct {
+	Impressions    int64    `json:"impressions"`
+	Clicks         int64    `json:"clicks"`
+	Conversions    int64    `json:"conversions"`
+	Spend          float64  `json:"spend"`
+	Revenue        float64  `json:"revenue"`
+	CTR            *float64 `json:"ctr"`
+	CPC            *float64 `json:"cpc"`
+	CPA            *float64 `json:"cpa"`
+	CPM            *float64 `json:"cpm"`
+	ConversionRate *float64 `json:"conversion_rate"`
+	ROAS           *float64 `json:"roas"`
+}
+
+// KPIChangeResponse holds the relative change (0.25 = +25%) of every KPI
+// against the previous equivalent period.
+type KPIChangeResponse struct {
+	Impressions    *float64 `json:"impressions"`
+	Clicks         *float64 `json:"clicks"`
+	Conversions    *float64 `json:"conversions"`
+	Spend          *float64 `json:"spend"`
+	Revenue        *float64 `json:"revenue"`
+	CTR            *float64 `json:"ctr"`
+	CPC            *float64 `json:"cpc"`
+	CPA            *float64 `json:"cpa"`
+	CPM            *float64 `json:"cpm"`
+	ConversionRate *float64 `json:"conversion_rate"`
+	ROAS           *float64 `json:"roas"`
+}
+
+type AnalyticsBucketResponse struct {
+	Start time.Time   `json:"start"`
+	KPIs  KPIResponse `json:"kpis"`
+}
+
+type AnalyticsComparisonResponse struct {
+	From   time.Time         `json:"from"`
+	To     time.Time         `json:"to"`
+	KPIs   KPIResponse       `json:"kpis"`
+	Change KPIChangeResponse `json:"change"`
+}
+
+type AnalyticsResponse struct {
+	CampaignID string                      `json:"campaign_id,omitempty"`
+	Channel    string                      `json:"channel,omitempty"`
+	From       time.Time                   `json:"from"`
+	To         time.Time                   `json:"to"`
+	Interval   string                      `json:"interval"`
+	Timezone   string                      `json:"timezone"`
+	Totals     KPIResponse                 `json:"totals"`
+	Previous   AnalyticsComparisonResponse `json:"previous"`
+	Series     []AnalyticsBucketResponse   `json:"series"`
+}
@@ -0,0 +1,65 @@
+package dto
+
+import "time"
+
+// ApprovalPolicyRequest creates or replaces an approval policy. Campaigns
+// with a budget of at least MinBudget must be approved by each role in
+// ApproverRoles, in order, before they can go live. AppliesToRoles limits
+// the policy to campaigns owned by users with those roles; empty means all.
+type ApprovalPolicyRequest struct {
+	Name           string   `json:"name" binding:"required,max=255"`
+	MinBudget      *float64 `json:"min_budget" binding:"required,min=0"`
+	ApproverRoles  []string `json:"approver_roles" binding:"required,min=1,max=10"`
+	AppliesToRoles []string `json:"applies_to_roles"`
+}
+
+type ApprovalPolicyResponse struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	MinBudget      float64   `json:"min_budget"`
+	ApproverRoles  []string  `json:"approver_roles"`
+	AppliesToRoles []string  `json:"applies_to_roles"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// SubmitApprovalRequest asks for approval of a campaign at its current
+// budget.
+type SubmitApprovalRequest struct {
+	Comment string `json:"comment" binding:"max=2000"`
+}
+
+// ApprovalDecisionRequest approves or rejects the current step. Rejections
+// must say why.
+type ApprovalDecisionRequest struct {
+	Comment string `json:"comment" binding:"max=2000"`
+}
+
+type ApprovalDecisionResponse struct {
+	Step          int       `json:"step"`
+	ApproverID    *string   `json:"approver_id"`
+	ApproverEmail *string   `json:"approver_email"`
+	Decision      string    `json:"decision"`
+	Comment       *string   `json:"comment"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ApprovalResponse is an approval request with its decisions so far.
+// NextApproverRole is the role that must decide the current step and is null
+// once the request is closed.
+type ApprovalResponse struct {
+	ID               string                     `json:"id"`
+	CampaignID       string                     `json:"campaign_id"`
+	CampaignTitle    string                     `json:"campaign_title,omitempty"`
+	RequestedBy      *string                    `json:"requested_by"`
+	PolicyID         *string                    `json:"policy_id"`
+	Status           string                     `json:"status"`
+	Budget           float64                    `json:"budget"`
+	ApproverRoles    []string                   `json:"approver_roles"`
+	CurrentStep      int                        `json:"current_step"`
+	NextApproverRole *string                    `json:"next_approver_role"`
+	Comment          *string                    `json:"comment"`
+	Decisions        []ApprovalDecisionResponse `json:"decisions"`
+	CreatedAt        time.Time                  `json:"created_at"`
+	DecidedAt        *time.Time                 `json:"decided_at"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+type ListAssetsRequest struct {
+	Kind string `form:"kind" binding:"omitempty,oneof=image video document"`
+}
+
+// AssetResponse is a file attached to a campaign. DownloadURL and
+// ThumbnailURL are signed and stop working at ExpiresAt; fetch the asset
+// again for fresh ones. Deduplicated is set on upload when the campaign
+// already had the exact same file.
+type AssetResponse struct {
+	ID           string    `json:"id"`
+	CampaignID   string    `json:"campaign_id"`
+	Filename     string    `json:"filename"`
+	Kind         string    `json:"kind"`
+	MimeType     string    `json:"mime_type"`
+	SizeBytes    int64     `json:"size_bytes"`
+	SHA256       string    `json:"sha256"`
+	Width        *int32    `json:"width"`
+	Height       *int32    `json:"height"`
+	UploadedBy   *string   `json:"uploaded_by"`
+	DownloadURL  string    `json:"download_url"`
+	ThumbnailURL *string   `json:"thumbnail_url"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	Deduplicated bool      `json:"deduplicated,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
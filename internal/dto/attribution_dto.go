@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+// AttributionRequest picks the model and the conversions to credit: those
+// in the from/to window. Only clicks within lookback_days before a
+// conversion count as its touches. half_life_days tunes time_decay.
+type AttributionRequest struct {
+	Model        string  `form:"model" binding:"omitempty,oneof=last_click first_click linear time_decay position_based"`
+	LookbackDays int     `form:"lookback_days" binding:"omitempty,min=1,max=90"`
+	HalfLifeDays float64 `form:"half_life_days" binding:"omitempty,min=0.1,max=90"`
+	From         string  `form:"from"`
+	To           string  `form:"to"`
+	Timezone     string  `form:"timezone"`
+}
+
+// AttributedCampaignResponse is the credit one campaign earned. Touches
+// counts its clicks on the paths to the conversions; Conversions and
+// Revenue are its attributed shares.
+type AttributedCampaignResponse struct {
+	CampaignID  string  `json:"campaign_id"`
+	Title       string  `json:"title"`
+	Touches     int64   `json:"touches"`
+	Conversions float64 `json:"conversions"`
+	Revenue     float64 `json:"revenue"`
+}
+
+// AttributionResponse credits my campaigns with the conversions their
+// clicks led to. Conversions and Revenue total every conversion with at
+// least one of my campaigns on its path; the part credited to other
+// campaigns on those paths is the difference to the attributed totals.
+// Revenue only covers conversions in the reporting currency.
+type AttributionResponse struct {
+	Model                 string                       `json:"model"`
+	LookbackDays          int                          `json:"lookback_days"`
+	HalfLifeDays          *float64                     `json:"half_life_days,omitempty"`
+	From                  time.Time                    `json:"from"`
+	To                    time.Time                    `json:"to"`
+	Timezone              string                       `json:"timezone"`
+	Currency              string                       `json:"currency"`
+	Conversions           int64                        `json:"conversions"`
+	Revenue               float64                      `json:"revenue"`
+	AttributedConversions float64                      `json:"attributed_conversions"`
+	AttributedRevenue     float64                      `json:"attributed_revenue"`
+	Campaigns             []AttributedCampaignResponse `json:"campaigns"`
+}
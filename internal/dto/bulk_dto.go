@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+// BulkCampaignRequest targets campaigns either by IDs or by Filter (not both)
+// and applies one action to each of them.
+type BulkCampaignRequest struct {
+	IDs    []string            `json:"ids" binding:"omitempty,max=500,dive,uuid"`
+	Filter *BulkCampaignFilter `json:"filter"`
+	Action string              `json:"action" binding:"required,oneof=update_status set_fields delete add_tag remove_tag"`
+	Status string              `json:"status"`
+	Fields *BulkCampaignFields `json:"fields"`
+	// TagIDs are added or removed by the add_tag and remove_tag actions.
+	TagIDs []string `json:"tag_ids" binding:"omitempty,max=50,dive,uuid"`
+	// DryRun validates every item and reports the outcome without saving.
+	DryRun bool `json:"dry_run"`
+	// Atomic saves nothing unless every item succeeds.
+	Atomic bool `json:"atomic"`
+}
+
+// BulkCampaignFilter has the same meaning as the list endpoint's filters.
+type BulkCampaignFilter struct {
+	Status    []string `json:"status"`
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	MinBudget *float64 `json:"min_budget" binding:"omitempty,gte=0"`
+	MaxBudget *float64 `json:"max_budget" binding:"omitempty,gte=0"`
+	Query     string   `json:"q" binding:"max=100"`
+	Tags      []string `json:"tag"`
+	TagMatch  string   `json:"tag_match" binding:"omitempty,oneof=any all"`
+}
+
+// BulkCampaignFields are set on every targeted campaign; omitted fields are
+// left unchanged.
+type BulkCampaignFields struct {
+	Description *string    `json:"description"`
+	StartDate   *time.Time `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+	Budget      *float64   `json:"budget" binding:"omitempty,gte=0"`
+}
+
+type BulkItemResult struct {
+	ID      string                 `json:"id"`
+	Result  string                 `json:"result"`
+	Error   string                 `json:"error,omitempty"`
+	Changes map[string]FieldChange `json:"changes,omitempty"`
+}
+
+type BulkCampaignResponse struct {
+	Action    string           `json:"action"`
+	DryRun    bool             `json:"dry_run"`
+	Committed bool             `json:"committed"`
+	Matched   int              `json:"matched"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Items     []BulkItemResult `json:"items"`
+}
@@ -10,23 +10,49 @@ type CreateCampaignRequest struct {
 	StartDate   time.Time `json:"start_date" binding:"required"`
 	EndDate     time.Time `json:"end_date" binding:"required,gtfield=StartDate"`
 	Budget      float64   `json:"budget" binding:"required,gte=0"`
+	TagIDs      []string  `json:"tag_ids" binding:"omitempty,max=50,dive,uuid"`
 }
 
 type CampaignResponse struct {
-	ID          string    `json:"id"`
-	UserID      string    `json:"user_id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	StartDate   time.Time `json:"start_date"`
-	EndDate     time.Time `json:"end_date"`
-	Budget      float64   `json:"budget"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID           string           `json:"id"`
+	UserID       string           `json:"user_id"`
+	Title        string           `json:"title"`
+	Description  string           `json:"description"`
+	Status       string           `json:"status"`
+	StartDate    time.Time        `json:"start_date"`
+	EndDate      time.Time        `json:"end_date"`
+	Budget       float64          `json:"budget"`
+	Version      int32            `json:"version"`
+	Tags         []TagSummary     `json:"tags"`
+	Segments     []SegmentSummary `json:"segments"`
+	CommentCount int64            `json:"comment_count"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+// TrashedCampaignResponse is a campaign in the trash together with the time
+// the retention job will purge it.
+type TrashedCampaignResponse struct {
+	CampaignResponse
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
 }
 
-type PaginationRequest struct {
-	Page  int `form:"page" binding:"min=1"`
-	Limit int `form:"limit" binding:"min=1,max=100"`
+// ListCampaignsRequest holds the list filters. Status and tag may be repeated
+// (?status=draft&status=active) or comma separated; from/to select campaigns
+// whose schedule overlaps the range. Tags match any of the given tag IDs
+// unless tag_match=all.
+type ListCampaignsRequest struct {
+	PageRequest
+	Status    []string `form:"status"`
+	From      string   `form:"from"`
+	To        string   `form:"to"`
+	MinBudget *float64 `form:"min_budget" binding:"omitempty,gte=0"`
+	MaxBudget *float64 `form:"max_budget" binding:"omitempty,gte=0"`
+	Query     string   `form:"q" binding:"max=100"`
+	Tags      []string `form:"tag"`
+	TagMatch  string   `form:"tag_match" binding:"omitempty,oneof=any all"`
+	SortBy    string   `form:"sort_by" binding:"omitempty,oneof=created_at start_date end_date budget title status"`
+	SortOrder string   `form:"sort_order" binding:"omitempty,oneof=asc desc"`
 }
 
 type UpdateCampaignRequest struct {
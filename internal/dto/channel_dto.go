@@ -0,0 +1,76 @@
+package dto
+
+import "time"
+
+type CampaignChannelRequest struct {
+	Channel         string  `json:"channel" binding:"required,oneof=search social email display video affiliate other"`
+	AllocatedBudget float64 `json:"allocated_budget" binding:"gte=0"`
+	Status          string  `json:"status" binding:"omitempty,oneof=planned active paused completed"`
+}
+
+// ReplaceChannelsRequest sets the complete channel breakdown of a campaign.
+// The allocations must add up to the campaign budget; Budget changes the
+// campaign budget in the same step so both can move together. An empty
+// list removes the breakdown.
+type ReplaceChannelsRequest struct {
+	Budget   *float64                 `json:"budget" binding:"omitempty,gte=0"`
+	Channels []CampaignChannelRequest `json:"channels" binding:"required,max=7,dive"`
+}
+
+type UpdateChannelRequest struct {
+	Status string `json:"status" binding:"required,oneof=planned active paused completed"`
+}
+
+// ChannelResponse is one channel of a campaign with its lifetime metrics.
+// Share is the part of the campaign budget allocated to the channel and
+// Pacing the part of the allocation already spent; either is null when its
+// denominator is zero.
+type ChannelResponse struct {
+	ID              string      `json:"id"`
+	Channel         string      `json:"channel"`
+	Status          string      `json:"status"`
+	AllocatedBudget float64     `json:"allocated_budget"`
+	Share           *float64    `json:"share"`
+	Pacing          *float64    `json:"pacing"`
+	KPIs            KPIResponse `json:"kpis"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+// CampaignChannelsResponse is the channel breakdown of a campaign.
+// Unattributed holds metrics recorded without a channel; Totals covers all
+// metrics of the campaign.
+type CampaignChannelsResponse struct {
+	CampaignID   string            `json:"campaign_id"`
+	Version      int32             `json:"version"`
+	Budget       float64           `json:"budget"`
+	Allocated    float64           `json:"allocated"`
+	Channels     []ChannelResponse `json:"channels"`
+	Unattributed KPIResponse       `json:"unattributed"`
+	Totals       KPIResponse       `json:"totals"`
+}
+
+type ChannelAnalyticsRequest struct {
+	From     string `form:"from"`
+	To       string `form:"to"`
+	Timezone string `form:"timezone"`
+}
+
+// ChannelSpendResponse aggregates one channel across all my campaigns.
+// Allocated sums the current allocations; the KPIs cover the requested
+// window and SpendShare is the channel's part of the total spend.
+type ChannelSpendResponse struct {
+	Channel    string      `json:"channel"`
+	Campaigns  int64       `json:"campaigns"`
+	Allocated  float64     `json:"allocated"`
+	SpendShare *float64    `json:"spend_share"`
+	KPIs       KPIResponse `json:"kpis"`
+}
+
+type ChannelAnalyticsResponse struct {
+	From         time.Time              `json:"from"`
+	To           time.Time              `json:"to"`
+	Timezone     string                 `json:"timezone"`
+	Channels     []ChannelSpendResponse `json:"channels"`
+	Unattributed KPIResponse            `json:"unattributed"`
+	Totals       KPIResponse            `json:"totals"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+// CohortRequest groups visitors by the campaign and week of their first
+// tracked link click, for first clicks in the from/to window. weeks is how
+// many weeks to follow each cohort for.
+type CohortRequest struct {
+	CampaignID string `form:"campaign_id" binding:"omitempty,uuid"`
+	Weeks      int    `form:"weeks" binding:"omitempty,min=1,max=26"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+	Timezone   string `form:"timezone"`
+}
+
+// CohortResponse is one row of the cohort matrices: the visitors whose
+// first click was on the campaign in the week starting at Week. Index k of
+// each array is the k-th week since, up to the current week. Active counts
+// the visitors who clicked or converted that week and Retention is their
+// share of the cohort. Converted and Repeat are the shares who had
+// converted at least once, and at least twice, by the end of that week.
+type CohortResponse struct {
+	CampaignID string    `json:"campaign_id"`
+	Title      string    `json:"title"`
+	Week       time.Time `json:"week"`
+	Visitors   int64     `json:"visitors"`
+	Active     []int64   `json:"active"`
+	Retention  []float64 `json:"retention"`
+	Converted  []float64 `json:"converted"`
+	Repeat     []float64 `json:"repeat"`
+}
+
+// CohortAnalysisResponse lists the cohorts by campaign title and week.
+// Weeks start on Monday in the requested timezone.
+type CohortAnalysisResponse struct {
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"`
+	Timezone string           `json:"timezone"`
+	Weeks    int              `json:"weeks"`
+	Cohorts  []CohortResponse `json:"cohorts"`
+}
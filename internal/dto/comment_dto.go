@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// CreateCommentRequest adds a Markdown comment to a campaign, or a reply
+// when ParentID is set. Members are mentioned as @email.
+type CreateCommentRequest struct {
+	Body     string `json:"body" binding:"required,max=10000"`
+	ParentID string `json:"parent_id" binding:"omitempty,uuid"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=10000"`
+}
+
+// CommentResponse is a comment with the members it mentions. Top-level
+// comments carry their whole thread in Replies, oldest first; ParentID tells
+// which comment a reply answers. Deleted comments keep their place in the
+// thread with an empty body and no author.
+type CommentResponse struct {
+	ID         string            `json:"id"`
+	CampaignID string            `json:"campaign_id"`
+	ParentID   *string           `json:"parent_id"`
+	Author     *UserSummary      `json:"author"`
+	Body       string            `json:"body"`
+	Mentions   []UserSummary     `json:"mentions"`
+	Replies    []CommentResponse `json:"replies,omitempty"`
+	Deleted    bool              `json:"deleted"`
+	CreatedAt  time.Time         `json:"created_at"`
+	EditedAt   *time.Time        `json:"edited_at"`
+}
@@ -5,3 +5,22 @@ type APIResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
 }
+
+// PageRequest is embedded by every cursor-paginated list request.
+type PageRequest struct {
+	Cursor       string `form:"cursor"`
+	Limit        int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	IncludeTotal bool   `form:"include_total"`
+}
+
+type PageInfo struct {
+	Limit      int     `json:"limit"`
+	NextCursor *string `json:"next_cursor"`
+	PrevCursor *string `json:"prev_cursor"`
+	Total      *int64  `json:"total,omitempty"`
+}
+
+type PageResponse struct {
+	Items interface{} `json:"items"`
+	Page  PageInfo    `json:"page"`
+}
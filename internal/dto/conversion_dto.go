@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+// PixelRequest is the query of the conversion pixel. click_id comes from
+// the landing URL of a tracked link; without it the conversion goes to the
+// visitor's last click. txn deduplicates repeated loads of the same page.
+// event names what happened and defaults to conversion.
+type PixelRequest struct {
+	ClickID       string  `form:"click_id" binding:"omitempty,uuid"`
+	Event         string  `form:"event" binding:"max=50"`
+	TransactionID string  `form:"txn" binding:"max=100"`
+	Value         float64 `form:"value" binding:"gte=0,lt=10000000000000"`
+	Currency      string  `form:"currency" binding:"omitempty,len=3,alpha"`
+}
+
+// PostbackRequest reports a conversion from the advertiser's server.
+// Event defaults to conversion and ConvertedAt to the time of the request.
+type PostbackRequest struct {
+	ClickID       string     `json:"click_id" binding:"required,uuid"`
+	Event         string     `json:"event" binding:"max=50"`
+	TransactionID string     `json:"transaction_id" binding:"required,max=100"`
+	Value         float64    `json:"value" binding:"gte=0,lt=10000000000000"`
+	Currency      string     `json:"currency" binding:"omitempty,len=3,alpha"`
+	ConvertedAt   *time.Time `json:"converted_at"`
+}
+
+// PostbackResponse acknowledges a postback. Duplicate is set when the
+// transaction was already recorded for the same campaign, by the pixel or a
+// postback, in which case it is not counted again.
+type PostbackResponse struct {
+	ID         string  `json:"id"`
+	CampaignID *string `json:"campaign_id"`
+	Duplicate  bool    `json:"duplicate"`
+}
+
+type ListConversionsRequest struct {
+	PageRequest
+}
+
+// ConversionResponse is a recorded conversion with the tracked link, and
+// its channel and variant, that led to it.
+type ConversionResponse struct {
+	ID            string    `json:"id"`
+	Event         string    `json:"event"`
+	Source        string    `json:"source"`
+	TransactionID *string   `json:"transaction_id"`
+	Value         float64   `json:"value"`
+	Currency      string    `json:"currency"`
+	ClickID       *string   `json:"click_id"`
+	Link          *string   `json:"link"`
+	Channel       *string   `json:"channel"`
+	Variant       *string   `json:"variant"`
+	ConvertedAt   time.Time `json:"converted_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
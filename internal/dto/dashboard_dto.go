@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+type DashboardSummaryRequest struct {
+	UpcomingDays int `form:"upcoming_days" binding:"omitempty,min=1,max=90"`
+	Limit        int `form:"limit" binding:"omitempty,min=1,max=20"`
+}
+
+type BudgetSummaryResponse struct {
+	TotalCampaigns  int64   `json:"total_campaigns"`
+	TotalBudget     float64 `json:"total_budget"`
+	TotalSpend      float64 `json:"total_spend"`
+	RemainingBudget float64 `json:"remaining_budget"`
+}
+
+type CampaignScheduleResponse struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	Budget    float64   `json:"budget"`
+}
+
+type TopCampaignResponse struct {
+	ID     string      `json:"id"`
+	Title  string      `json:"title"`
+	Status string      `json:"status"`
+	KPIs   KPIResponse `json:"kpis"`
+}
+
+type ActivityResponse struct {
+	CampaignID string    `json:"campaign_id"`
+	Title      string    `json:"title"`
+	Action     string    `json:"action"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type DashboardSummaryResponse struct {
+	CampaignsByStatus map[string]int64           `json:"campaigns_by_status"`
+	Budget            BudgetSummaryResponse      `json:"budget"`
+	StartingSoon      []CampaignScheduleResponse `json:"starting_soon"`
+	EndingSoon        []CampaignScheduleResponse `json:"ending_soon"`
+	TopCampaigns      []TopCampaignResponse      `json:"top_campaigns"`
+	RecentActivity    []ActivityResponse         `json:"recent_activity"`
+	GeneratedAt       time.Time                  `json:"generated_at"`
+}
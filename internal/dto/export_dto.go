@@ -0,0 +1,38 @@
+package dto
+
+// ExportOptions select the output of an export. Columns may be repeated
+// (?columns=id&columns=title) or comma separated; empty exports every column
+// in its default order.
+type ExportOptions struct {
+	Format  string   `form:"format" binding:"omitempty,oneof=csv xlsx ndjson"`
+	Columns []string `form:"columns"`
+	// Locale (e.g. de-DE) sets the CSV separator and number/date formats and
+	// the XLSX date formats.
+	Locale string `form:"locale"`
+}
+
+// ExportCampaignsRequest has the same filters and sort as the list endpoint,
+// without pagination.
+type ExportCampaignsRequest struct {
+	ExportOptions
+	Status    []string `form:"status"`
+	From      string   `form:"from"`
+	To        string   `form:"to"`
+	MinBudget *float64 `form:"min_budget" binding:"omitempty,gte=0"`
+	MaxBudget *float64 `form:"max_budget" binding:"omitempty,gte=0"`
+	Query     string   `form:"q" binding:"max=100"`
+	Tags      []string `form:"tag"`
+	TagMatch  string   `form:"tag_match" binding:"omitempty,oneof=any all"`
+	SortBy    string   `form:"sort_by" binding:"omitempty,oneof=created_at start_date end_date budget title status"`
+	SortOrder string   `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+	// Timezone (IANA name) in which dates are written; defaults to UTC.
+	Timezone string `form:"timezone"`
+}
+
+// ExportAnalyticsRequest exports one row per campaign and interval over the
+// analytics window, optionally for a single campaign.
+type ExportAnalyticsRequest struct {
+	AnalyticsRequest
+	ExportOptions
+	CampaignID string `form:"campaign_id" binding:"omitempty,uuid"`
+}
@@ -0,0 +1,75 @@
+package dto
+
+import "time"
+
+// CreateFunnelRequest defines a funnel. Steps are event names in order:
+// click for a tracked link click, or a conversion event such as signup or
+// purchase. Visitors have window_days from the first step to go through
+// the rest.
+type CreateFunnelRequest struct {
+	Name        string   `json:"name" binding:"required,max=100"`
+	Description string   `json:"description"`
+	Steps       []string `json:"steps" binding:"required,min=2,max=10,dive,required,max=50"`
+	WindowDays  int32    `json:"window_days" binding:"omitempty,min=1,max=90"`
+}
+
+type UpdateFunnelRequest struct {
+	Name        *string  `json:"name" binding:"omitempty,max=100"`
+	Description *string  `json:"description"`
+	Steps       []string `json:"steps" binding:"omitempty,min=2,max=10,dive,required,max=50"`
+	WindowDays  *int32   `json:"window_days" binding:"omitempty,min=1,max=90"`
+}
+
+type FunnelResponse struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Steps       []string  `json:"steps"`
+	WindowDays  int32     `json:"window_days"`
+	CreatedBy   *string   `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// FunnelReportRequest picks the visitors entering the funnel: those whose
+// first step happened in the from/to window, optionally on one campaign.
+type FunnelReportRequest struct {
+	CampaignID string `form:"campaign_id" binding:"omitempty,uuid"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+	Timezone   string `form:"timezone"`
+}
+
+// FunnelStepResponse is how many visitors reached a step. FromPrevious and
+// FromStart are the shares of the visitors of the previous and the first
+// step, null when those are zero. DropOff is how many of the previous
+// step's visitors did not get here; MedianSeconds is their median time from
+// the previous step, null for the first step or when nobody got here.
+type FunnelStepResponse struct {
+	Step          int      `json:"step"`
+	Event         string   `json:"event"`
+	Visitors      int64    `json:"visitors"`
+	FromPrevious  *float64 `json:"from_previous"`
+	FromStart     *float64 `json:"from_start"`
+	DropOff       int64    `json:"drop_off"`
+	MedianSeconds *float64 `json:"median_seconds"`
+}
+
+// CampaignFunnelResponse is the funnel for the visitors who entered it
+// through one campaign.
+type CampaignFunnelResponse struct {
+	CampaignID string               `json:"campaign_id"`
+	Title      string               `json:"title"`
+	Steps      []FunnelStepResponse `json:"steps"`
+}
+
+// FunnelReportResponse is the funnel over all the caller's campaigns
+// (Steps) and per campaign, by the campaign of each visitor's first step.
+type FunnelReportResponse struct {
+	Funnel    FunnelResponse           `json:"funnel"`
+	From      time.Time                `json:"from"`
+	To        time.Time                `json:"to"`
+	Timezone  string                   `json:"timezone"`
+	Steps     []FunnelStepResponse     `json:"steps"`
+	Campaigns []CampaignFunnelResponse `json:"campaigns"`
+}
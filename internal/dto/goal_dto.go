@@ -0,0 +1,54 @@
+package dto
+
+import "time"
+
+// CreateGoalRequest sets a target for one campaign metric. Direction
+// defaults to at_least for counts, revenue, CTR, conversion rate and ROAS,
+// and to at_most for spend and costs. Deadline defaults to the campaign end
+// date.
+type CreateGoalRequest struct {
+	Metric    string     `json:"metric" binding:"required,oneof=impressions clicks conversions spend revenue ctr cpc cpa cpm conversion_rate roas"`
+	Direction string     `json:"direction" binding:"omitempty,oneof=at_least at_most"`
+	Target    float64    `json:"target" binding:"gt=0"`
+	Deadline  *time.Time `json:"deadline"`
+}
+
+// UpdateGoalRequest changes a goal. A changed goal is open again, even if it
+// was achieved or missed before.
+type UpdateGoalRequest struct {
+	Direction *string    `json:"direction" binding:"omitempty,oneof=at_least at_most"`
+	Target    *float64   `json:"target" binding:"omitempty,gt=0"`
+	Deadline  *time.Time `json:"deadline"`
+}
+
+type ListGoalsRequest struct {
+	Status []string `form:"status"`
+}
+
+// GoalResponse is a goal with its progress. Current is the metric over the
+// campaign's metrics recorded before the deadline, null while a ratio has
+// no denominator; Progress is Current divided by Target. Expected is where
+// a cumulative at_least goal should be by now on a straight line from the
+// campaign start to the deadline.
+//
+// Status is on_track, at_risk or off_track while the goal is open, then
+// achieved or missed. Cumulative at_least goals are achieved as soon as they
+// reach the target and at_most spend goals are missed as soon as they
+// exceed it; every other goal is decided at its deadline.
+type GoalResponse struct {
+	ID            string     `json:"id"`
+	CampaignID    string     `json:"campaign_id"`
+	CampaignTitle string     `json:"campaign_title"`
+	Metric        string     `json:"metric"`
+	Direction     string     `json:"direction"`
+	Target        float64    `json:"target"`
+	Deadline      time.Time  `json:"deadline"`
+	Current       *float64   `json:"current"`
+	Progress      *float64   `json:"progress"`
+	Expected      *float64   `json:"expected"`
+	Status        string     `json:"status"`
+	AchievedAt    *time.Time `json:"achieved_at"`
+	MissedAt      *time.Time `json:"missed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
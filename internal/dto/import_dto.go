@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// ImportCampaignsRequest holds the non-file fields of an import upload.
+// Mapping is a JSON object from spreadsheet header to campaign field, e.g.
+// {"Campaign Name": "title", "Spend Cap": "budget"}; unmapped headers are
+// matched by name.
+type ImportCampaignsRequest struct {
+	Mode    string `form:"mode" binding:"omitempty,oneof=all_or_nothing partial"`
+	Mapping string `form:"mapping"`
+}
+
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ImportResponse struct {
+	ID           string           `json:"id"`
+	Filename     string           `json:"filename"`
+	Format       string           `json:"format"`
+	Mode         string           `json:"mode"`
+	Status       string           `json:"status"`
+	TotalRows    int32            `json:"total_rows"`
+	ImportedRows int32            `json:"imported_rows"`
+	FailedRows   int32            `json:"failed_rows"`
+	RowErrors    []ImportRowError `json:"row_errors"`
+	Error        *string          `json:"error,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+	CompletedAt  *time.Time       `json:"completed_at"`
+}
@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+// CreateLinkRequest tags a destination URL for a campaign. utm_medium
+// defaults to the channel and utm_campaign to the campaign title; every UTM
+// value is normalised to lower case with underscores. Clicks count towards
+// the channel and variant, when given.
+type CreateLinkRequest struct {
+	DestinationURL string `json:"destination_url" binding:"required,url,max=2048"`
+	Channel        string `json:"channel" binding:"omitempty,oneof=search social email display video affiliate other"`
+	Variant        string `json:"variant" binding:"omitempty,max=50"`
+	UTMSource      string `json:"utm_source" binding:"required,max=100"`
+	UTMMedium      string `json:"utm_medium" binding:"max=100"`
+	UTMCampaign    string `json:"utm_campaign" binding:"max=100"`
+	UTMTerm        string `json:"utm_term" binding:"max=100"`
+	UTMContent     string `json:"utm_content" binding:"max=100"`
+}
+
+type UTMResponse struct {
+	Source   string `json:"source"`
+	Medium   string `json:"medium"`
+	Campaign string `json:"campaign"`
+	Term     string `json:"term,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+// LinkResponse is a tracked link. ShortURL is the address to share; it
+// redirects to TaggedURL, the destination with the UTM parameters applied.
+// Clicks and Visitors leave out bots, which BotClicks counts.
+type LinkResponse struct {
+	ID             string      `json:"id"`
+	CampaignID     string      `json:"campaign_id"`
+	Code           string      `json:"code"`
+	ShortURL       string      `json:"short_url"`
+	DestinationURL string      `json:"destination_url"`
+	TaggedURL      string      `json:"tagged_url"`
+	Channel        *string     `json:"channel"`
+	Variant        *string     `json:"variant"`
+	UTM            UTMResponse `json:"utm"`
+	Clicks         int64       `json:"clicks"`
+	Visitors       int64       `json:"visitors"`
+	BotClicks      int64       `json:"bot_clicks"`
+	LastClickedAt  *time.Time  `json:"last_clicked_at"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+type ListNotificationsRequest struct {
+	PageRequest
+	UnreadOnly bool `form:"unread"`
+}
+
+// NotificationResponse tells a member that someone mentioned them, replied to
+// them or commented on their campaign, or that a campaign goal was achieved.
+// Excerpt is the start of the comment or a description of the goal.
+type NotificationResponse struct {
+	ID            string       `json:"id"`
+	Type          string       `json:"type"`
+	Actor         *UserSummary `json:"actor"`
+	CampaignID    *string      `json:"campaign_id"`
+	CampaignTitle *string      `json:"campaign_title"`
+	CommentID     *string      `json:"comment_id"`
+	GoalID        *string      `json:"goal_id"`
+	Excerpt       *string      `json:"excerpt"`
+	ReadAt        *time.Time   `json:"read_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+type UnreadNotificationsResponse struct {
+	Unread int64 `json:"unread"`
+}
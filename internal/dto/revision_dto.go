@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+// CampaignSnapshot is the editable state of a campaign as stored with each
+// revision.
+type CampaignSnapshot struct {
+	Title       string     `json:"title"`
+	Description *string    `json:"description"`
+	Status      string     `json:"status"`
+	StartDate   *time.Time `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+	Budget      float64    `json:"budget"`
+}
+
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// CampaignRevisionResponse is one entry of a campaign's history. Changes is
+// keyed by field name and only lists fields whose value changed.
+type CampaignRevisionResponse struct {
+	ID             string                 `json:"id"`
+	CampaignID     string                 `json:"campaign_id"`
+	Revision       int32                  `json:"revision"`
+	Action         string                 `json:"action"`
+	ActorID        *string                `json:"actor_id"`
+	ActorEmail     *string                `json:"actor_email"`
+	Changes        map[string]FieldChange `json:"changes"`
+	Snapshot       CampaignSnapshot       `json:"snapshot"`
+	SourceRevision *int32                 `json:"source_revision,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+type RevertCampaignRequest struct {
+	Revision int32 `json:"revision" binding:"required,min=1"`
+}
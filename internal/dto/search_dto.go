@@ -0,0 +1,15 @@
+package dto
+
+type SearchRequest struct {
+	PageRequest
+	Query string `form:"q" binding:"required,max=200"`
+}
+
+// SearchResultResponse carries highlighted fragments in which matches are
+// wrapped in <mark> tags; everything else is HTML-escaped.
+type SearchResultResponse struct {
+	Campaign       CampaignResponse `json:"campaign"`
+	Rank           float64          `json:"rank"`
+	TitleHighlight string           `json:"title_highlight"`
+	Snippet        string           `json:"snippet"`
+}
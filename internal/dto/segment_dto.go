@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CreateSegmentRequest creates a workspace audience segment. Rules is a rule
+// tree such as {"all": [{"field": "age", "op": "between", "value": [18, 34]}]};
+// see GET /segments/fields for the fields and operators.
+type CreateSegmentRequest struct {
+	Name        string          `json:"name" binding:"required,max=100"`
+	Description string          `json:"description" binding:"max=1000"`
+	Rules       json.RawMessage `json:"rules" binding:"required" swaggertype:"object"`
+}
+
+type UpdateSegmentRequest struct {
+	Name        *string         `json:"name" binding:"omitempty,max=100"`
+	Description *string         `json:"description" binding:"omitempty,max=1000"`
+	Rules       json.RawMessage `json:"rules" swaggertype:"object"`
+}
+
+type ValidateSegmentRequest struct {
+	Rules json.RawMessage `json:"rules" binding:"required" swaggertype:"object"`
+}
+
+type ListSegmentsRequest struct {
+	Query string `form:"q" binding:"max=100"`
+}
+
+// SegmentRuleError points at one problem in a rule tree, e.g.
+// {"path": "all[1].value", "message": "must be a two-letter code"}.
+type SegmentRuleError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// SegmentValidationResponse is the outcome of checking a rule tree. A valid
+// tree comes back in canonical form with its summary; an invalid one lists
+// every problem.
+type SegmentValidationResponse struct {
+	Valid   bool               `json:"valid"`
+	Rules   json.RawMessage    `json:"rules,omitempty" swaggertype:"object"`
+	Summary string             `json:"summary,omitempty"`
+	Errors  []SegmentRuleError `json:"errors,omitempty"`
+}
+
+// SegmentFieldResponse describes a field segment rules can test.
+type SegmentFieldResponse struct {
+	Name     string   `json:"name"`
+	Category string   `json:"category"`
+	Label    string   `json:"label"`
+	Ops      []string `json:"ops"`
+	Values   []string `json:"values,omitempty"`
+}
+
+// SegmentSummary is a segment as embedded in a campaign.
+type SegmentSummary struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Summary string `json:"summary"`
+}
+
+// SegmentResponse is a workspace segment. CampaignCount counts every live
+// campaign using it, whoever owns the campaign.
+type SegmentResponse struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	Rules         json.RawMessage `json:"rules" swaggertype:"object"`
+	Summary       string          `json:"summary"`
+	CreatedBy     *string         `json:"created_by"`
+	CampaignCount int64           `json:"campaign_count"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// CampaignSegmentsRequest lists the segments to set, add or remove on a
+// campaign.
+type CampaignSegmentsRequest struct {
+	SegmentIDs []string `json:"segment_ids" binding:"required,max=20,dive,uuid"`
+}
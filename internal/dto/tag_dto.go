@@ -0,0 +1,62 @@
+package dto
+
+import "time"
+
+// CreateTagRequest creates a workspace tag. Color is a hex RGB value such as
+// #1E88E5 and defaults to gray.
+type CreateTagRequest struct {
+	Name  string `json:"name" binding:"required,max=50"`
+	Color string `json:"color"`
+}
+
+type UpdateTagRequest struct {
+	Name  *string `json:"name" binding:"omitempty,max=50"`
+	Color *string `json:"color"`
+}
+
+type ListTagsRequest struct {
+	Query string `form:"q" binding:"max=50"`
+}
+
+// TagSummary is a tag as embedded in a campaign.
+type TagSummary struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// TagResponse is a workspace tag; CampaignCount only counts the caller's
+// campaigns.
+type TagResponse struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Color         string    `json:"color"`
+	CreatedBy     *string   `json:"created_by"`
+	CampaignCount int64     `json:"campaign_count"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// CampaignTagsRequest lists the tags to set, add or remove on a campaign.
+type CampaignTagsRequest struct {
+	TagIDs []string `json:"tag_ids" binding:"required,max=50,dive,uuid"`
+}
+
+// TagRollupRequest filters the campaigns and the metric window of a rollup.
+// Without from/to metrics cover the whole campaign lifetime.
+type TagRollupRequest struct {
+	From     string   `form:"from"`
+	To       string   `form:"to"`
+	Status   []string `form:"status"`
+	Tags     []string `form:"tag"`
+	Timezone string   `form:"timezone"`
+}
+
+// TagRollupResponse sums budget and metrics over the caller's campaigns with
+// a tag. A campaign with several tags counts towards each of them.
+type TagRollupResponse struct {
+	Tag           TagSummary  `json:"tag"`
+	CampaignCount int64       `json:"campaign_count"`
+	Budget        float64     `json:"budget"`
+	KPIs          KPIResponse `json:"kpis"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import "time"
+
+type CreateTemplateRequest struct {
+	Name                string   `json:"name" binding:"required,max=255"`
+	Scope               string   `json:"scope" binding:"omitempty,oneof=user workspace"`
+	TitleTemplate       string   `json:"title_template" binding:"required,max=255"`
+	DescriptionTemplate *string  `json:"description_template"`
+	DefaultBudget       *float64 `json:"default_budget" binding:"omitempty,gte=0"`
+	DefaultDurationDays *int32   `json:"default_duration_days" binding:"omitempty,min=1"`
+}
+
+type UpdateTemplateRequest struct {
+	Name                *string  `json:"name" binding:"omitempty,max=255"`
+	TitleTemplate       *string  `json:"title_template" binding:"omitempty,max=255"`
+	DescriptionTemplate *string  `json:"description_template"`
+	DefaultBudget       *float64 `json:"default_budget" binding:"omitempty,gte=0"`
+	DefaultDurationDays *int32   `json:"default_duration_days" binding:"omitempty,min=1"`
+}
+
+type ListTemplatesRequest struct {
+	PageRequest
+	Scope string `form:"scope" binding:"omitempty,oneof=user workspace"`
+}
+
+// TemplateResponse lists the variables referenced by the title and
+// description templates that callers have to supply, excluding built-ins.
+type TemplateResponse struct {
+	ID                  string    `json:"id"`
+	OwnerID             string    `json:"owner_id"`
+	Scope               string    `json:"scope"`
+	Name                string    `json:"name"`
+	TitleTemplate       string    `json:"title_template"`
+	DescriptionTemplate *string   `json:"description_template"`
+	DefaultBudget       *float64  `json:"default_budget"`
+	DefaultDurationDays *int32    `json:"default_duration_days"`
+	Variables           []string  `json:"variables"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// InstantiateTemplateRequest creates a campaign from a template. EndDate
+// defaults to StartDate plus the template's default duration and Budget to
+// its default budget.
+type InstantiateTemplateRequest struct {
+	Variables map[string]string `json:"variables"`
+	StartDate time.Time         `json:"start_date" binding:"required"`
+	EndDate   *time.Time        `json:"end_date"`
+	Budget    *float64          `json:"budget" binding:"omitempty,gte=0"`
+}
+
+// CloneCampaignRequest copies a campaign. Dates move either by ShiftDays or so
+// that the copy starts at StartDate; the duration is kept. The copy is reset
+// to draft unless ResetStatus is false.
+type CloneCampaignRequest struct {
+	Title       *string    `json:"title" binding:"omitempty,max=255"`
+	ShiftDays   *int       `json:"shift_days"`
+	StartDate   *time.Time `json:"start_date"`
+	ResetStatus *bool      `json:"reset_status"`
+}
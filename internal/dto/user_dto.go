@@ -15,6 +15,14 @@ type UserResponse struct {
 	Role     string    `json:"role"`
 }
 
+// UserSummary is a workspace member as shown next to content they wrote or
+// were mentioned in.
+type UserSummary struct {
+	ID       string `json:"id"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+}
+
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
@@ -0,0 +1,96 @@
+package dto
+
+import "time"
+
+type CampaignVariantRequest struct {
+	Name         string  `json:"name" binding:"required,max=50"`
+	Description  string  `json:"description" binding:"max=1000"`
+	TrafficSplit float64 `json:"traffic_split" binding:"gt=0,lte=100"`
+	IsControl    bool    `json:"is_control"`
+}
+
+// ReplaceVariantsRequest sets the complete variant set of a campaign. A
+// campaign runs 2 to 10 variants whose traffic splits add up to 100; the
+// first variant is the control unless another one is marked. Variants are
+// matched by name, case-insensitively. An empty list ends the experiment.
+type ReplaceVariantsRequest struct {
+	Variants []CampaignVariantRequest `json:"variants" binding:"required,max=10,dive"`
+}
+
+// VariantResponse is one variant of a campaign with its lifetime metrics.
+type VariantResponse struct {
+	ID           string      `json:"id"`
+	Name         string      `json:"name"`
+	Description  string      `json:"description"`
+	TrafficSplit float64     `json:"traffic_split"`
+	IsControl    bool        `json:"is_control"`
+	KPIs         KPIResponse `json:"kpis"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+type CampaignVariantsResponse struct {
+	CampaignID string            `json:"campaign_id"`
+	Version    int32             `json:"version"`
+	Variants   []VariantResponse `json:"variants"`
+}
+
+// ExperimentRequest selects what the results compare. Metric is
+// conversion_rate (conversions per click, the default) or ctr (clicks per
+// impression). Without from/to the whole experiment is covered.
+type ExperimentRequest struct {
+	From       string   `form:"from"`
+	To         string   `form:"to"`
+	Timezone   string   `form:"timezone"`
+	Metric     string   `form:"metric" binding:"omitempty,oneof=conversion_rate ctr"`
+	Confidence *float64 `form:"confidence" binding:"omitempty,gte=0.8,lte=0.999"`
+	MinSample  *int64   `form:"min_sample" binding:"omitempty,gte=1"`
+}
+
+// IntervalResponse is a two-sided confidence interval.
+type IntervalResponse struct {
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+}
+
+// VariantResultResponse is one variant of an experiment. Trials and
+// Successes are the denominator and numerator of the metric. For every
+// variant but the control, Lift is the relative change of its rate against
+// the control's, with PValue from a two-proportion z-test and
+// ProbabilityToBeatControl from Beta posteriors; statistics are null when
+// either side has no trials.
+type VariantResultResponse struct {
+	ID                       string            `json:"id"`
+	Name                     string            `json:"name"`
+	IsControl                bool              `json:"is_control"`
+	TrafficSplit             float64           `json:"traffic_split"`
+	Trials                   int64             `json:"trials"`
+	Successes                int64             `json:"successes"`
+	Rate                     *float64          `json:"rate"`
+	RateInterval             *IntervalResponse `json:"rate_interval"`
+	Lift                     *float64          `json:"lift"`
+	LiftInterval             *IntervalResponse `json:"lift_interval"`
+	ZScore                   *float64          `json:"z_score"`
+	PValue                   *float64          `json:"p_value"`
+	ProbabilityToBeatControl *float64          `json:"probability_to_beat_control"`
+	Significant              bool              `json:"significant"`
+	KPIs                     KPIResponse       `json:"kpis"`
+}
+
+// ExperimentResponse holds the results of a campaign experiment. Status is
+// insufficient_data until every variant reaches MinSample trials, then
+// winner or inconclusive. A challenger wins when it beats the control both
+// by the z-test (at the Bonferroni-corrected Alpha) and by its probability
+// to beat the control (at Confidence); the control wins when every
+// challenger is significantly worse. Winner is the ID of the winning variant.
+type ExperimentResponse struct {
+	CampaignID string                  `json:"campaign_id"`
+	Metric     string                  `json:"metric"`
+	Confidence float64                 `json:"confidence"`
+	Alpha      float64                 `json:"alpha"`
+	MinSample  int64                   `json:"min_sample"`
+	From       *time.Time              `json:"from"`
+	To         *time.Time              `json:"to"`
+	Status     string                  `json:"status"`
+	Winner     *string                 `json:"winner"`
+	Variants   []VariantResultResponse `json:"variants"`
+}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+// GoalChecker periodically decides campaign goals whose deadline has passed,
+// so goals are achieved or missed even when no new metrics come in.
+type GoalChecker struct {
+	goalService *service.GoalService
+	interval    time.Duration
+}
+
+func NewGoalChecker(goalService *service.GoalService, interval time.Duration) *GoalChecker {
+	return &GoalChecker{
+		goalService: goalService,
+		interval:    interval,
+	}
+}
+
+// Start runs a check immediately and then once per interval until ctx is
+// cancelled. It returns without blocking.
+func (g *GoalChecker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(g.interval)
+		defer ticker.Stop()
+
+		for {
+			g.check(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (g *GoalChecker) check(ctx context.Context) {
+	decided, err := g.goalService.DecideDueGoals(ctx, time.Now())
+	if err != nil {
+		zap.L().Error("Goal check failed", zap.Error(err))
+		return
+	}
+	if decided > 0 {
+		zap.L().Info("Decided campaign goals", zap.Int("count", decided))
+	}
+}
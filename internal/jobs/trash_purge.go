@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/service"
+)
+
+// TrashPurger periodically deletes campaigns whose trash retention has
+// expired, along with stored assets no campaign uses any more.
+type TrashPurger struct {
+	campaignService *service.CampaignService
+	assetService    *service.AssetService
+	interval        time.Duration
+}
+
+func NewTrashPurger(campaignService *service.CampaignService, assetService *service.AssetService, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		campaignService: campaignService,
+		assetService:    assetService,
+		interval:        interval,
+	}
+}
+
+// Start runs a purge immediately and then once per interval until ctx is
+// cancelled. It returns without blocking.
+func (p *TrashPurger) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			p.purge(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (p *TrashPurger) purge(ctx context.Context) {
+	purged, err := p.campaignService.PurgeTrash(ctx, time.Now())
+	if err != nil {
+		zap.L().Error("Trash purge failed", zap.Error(err))
+		return
+	}
+	if purged > 0 {
+		zap.L().Info("Purged trashed campaigns", zap.Int64("count", purged))
+	}
+
+	orphans, err := p.assetService.PurgeOrphans(ctx, time.Now())
+	if err != nil {
+		zap.L().Error("Asset purge failed", zap.Error(err))
+		return
+	}
+	if orphans > 0 {
+		zap.L().Info("Purged unused assets", zap.Int64("count", orphans))
+	}
+}
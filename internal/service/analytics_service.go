@@ -0,0 +1,457 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+)
+
+var (
+	ErrInvalidAnalyticsRange = errors.New("invalid analytics date range")
+	ErrInvalidTimezone       = errors.New("invalid timezone")
+)
+
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+
+	defaultAnalyticsDays = 30
+	maxAnalyticsBuckets  = 400
+)
+
+type AnalyticsService struct {
+	dbPool      *pgxpool.Pool
+	queries     *db.Queries
+	goalService *GoalService
+}
+
+func NewAnalyticsService(dbPool *pgxpool.Pool, queries *db.Queries, goalService *GoalService) *AnalyticsService {
+	return &AnalyticsService{
+		dbPool:      dbPool,
+		queries:     queries,
+		goalService: goalService,
+	}
+}
+
+// analyticsWindow is a resolved AnalyticsRequest: a half-open [from, to)
+// range in the requested location plus the previous range of equal length.
+type analyticsWindow struct {
+	from     time.Time
+	to       time.Time
+	prevFrom time.Time
+	prevTo   time.Time
+	interval string
+	loc      *time.Location
+	channel  *string
+}
+
+// RecordMetrics upserts metric entries in one transaction. Entries naming a
+// channel are recorded against that channel, which the campaign must have.
+func (s *AnalyticsService) RecordMetrics(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.RecordMetricsRequest) ([]dto.MetricEntryResponse, error) {
+	if err := s.ensureCampaignOwner(ctx, userID, campaignID); err != nil {
+		return nil, err
+	}
+
+	channels, err := s.queries.ListCampaignChannels(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	channelIDs := make(map[string]uuid.UUID, len(channels))
+	for _, ch := range channels {
+		channelIDs[ch.Channel] = ch.ID
+	}
+	variants, err := s.queries.ListCampaignVariants(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	variantsByName := make(map[string]db.CampaignVariant, len(variants))
+	for _, v := range variants {
+		variantsByName[strings.ToLower(v.Name)] = v
+	}
+
+	// Every entry is resolved before anything is written, so an unknown
+	// channel or variant rejects the whole batch.
+	args := make([]db.UpsertCampaignMetricParams, len(req.Metrics))
+	responses := make([]dto.MetricEntryResponse, len(req.Metrics))
+	for i, m := range req.Metrics {
+		args[i] = db.UpsertCampaignMetricParams{
+			CampaignID:  campaignID,
+			RecordedAt:  pgtype.Timestamptz{Time: m.RecordedAt, Valid: true},
+			Impressions: m.Impressions,
+			Clicks:      m.Clicks,
+			Conversions: m.Conversions,
+			Spend:       m.Spend,
+			Revenue:     m.Revenue,
+		}
+		if m.Channel != "" {
+			id, ok := channelIDs[m.Channel]
+			if !ok {
+				return nil, fmt.Errorf("%w: campaign has no %s channel", ErrChannelNotFound, m.Channel)
+			}
+			args[i].ChannelID = pgtype.UUID{Bytes: id, Valid: true}
+			responses[i].Channel = &m.Channel
+		}
+		if name := strings.TrimSpace(m.Variant); name != "" {
+			v, ok := variantsByName[strings.ToLower(name)]
+			if !ok {
+				return nil, fmt.Errorf("%w: campaign has no variant %q", ErrVariantNotFound, name)
+			}
+			args[i].VariantID = pgtype.UUID{Bytes: v.ID, Valid: true}
+			responses[i].Variant = &v.Name
+		}
+	}
+
+	err = execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		for i, arg := range args {
+			metric, err := q.UpsertCampaignMetric(ctx, arg)
+			if err != nil {
+				return err
+			}
+			responses[i].RecordedAt = metric.RecordedAt.Time
+			responses[i].Impressions = metric.Impressions
+			responses[i].Clicks = metric.Clicks
+			responses[i].Conversions = metric.Conversions
+			responses[i].Spend = metric.Spend
+			responses[i].Revenue = metric.Revenue
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// New metrics can achieve, or overrun, the campaign's goals. They are
+	// stored either way, so a failed evaluation is left to the goal checker.
+	if _, err := s.goalService.EvaluateCampaign(ctx, campaignID, time.Now()); err != nil {
+		zap.L().Error("Evaluating campaign goals failed", zap.String("campaign_id", campaignID.String()), zap.Error(err))
+	}
+
+	return responses, nil
+}
+
+func (s *AnalyticsService) CampaignAnalytics(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.AnalyticsRequest) (*dto.AnalyticsResponse, error) {
+	window, err := resolveAnalyticsWindow(req, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureCampaignOwner(ctx, userID, campaignID); err != nil {
+		return nil, err
+	}
+
+	totals, err := s.queries.GetCampaignMetricTotals(ctx, db.GetCampaignMetricTotalsParams{
+		CampaignID: campaignID,
+		FromTime:   pgtype.Timestamptz{Time: window.from, Valid: true},
+		ToTime:     pgtype.Timestamptz{Time: window.to, Valid: true},
+		Channel:    window.channel,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.queries.GetCampaignMetricTotals(ctx, db.GetCampaignMetricTotalsParams{
+		CampaignID: campaignID,
+		FromTime:   pgtype.Timestamptz{Time: window.prevFrom, Valid: true},
+		ToTime:     pgtype.Timestamptz{Time: window.prevTo, Valid: true},
+		Channel:    window.channel,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := s.queries.GetCampaignMetricSeries(ctx, db.GetCampaignMetricSeriesParams{
+		Bucket:     window.interval,
+		TimeZone:   window.loc.String(),
+		CampaignID: campaignID,
+		FromTime:   pgtype.Timestamptz{Time: window.from, Valid: true},
+		ToTime:     pgtype.Timestamptz{Time: window.to, Valid: true},
+		Channel:    window.channel,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := buildAnalyticsResponse(window, totals, previous, series)
+	res.CampaignID = campaignID.String()
+	return res, nil
+}
+
+func (s *AnalyticsService) PortfolioAnalytics(ctx context.Context, userID uuid.UUID, req dto.AnalyticsRequest) (*dto.AnalyticsResponse, error) {
+	window, err := resolveAnalyticsWindow(req, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.queries.GetPortfolioMetricTotals(ctx, db.GetPortfolioMetricTotalsParams{
+		UserID:   userID,
+		FromTime: pgtype.Timestamptz{Time: window.from, Valid: true},
+		ToTime:   pgtype.Timestamptz{Time: window.to, Valid: true},
+		Channel:  window.channel,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.queries.GetPortfolioMetricTotals(ctx, db.GetPortfolioMetricTotalsParams{
+		UserID:   userID,
+		FromTime: pgtype.Timestamptz{Time: window.prevFrom, Valid: true},
+		ToTime:   pgtype.Timestamptz{Time: window.prevTo, Valid: true},
+		Channel:  window.channel,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := s.queries.GetPortfolioMetricSeries(ctx, db.GetPortfolioMetricSeriesParams{
+		Bucket:   window.interval,
+		TimeZone: window.loc.String(),
+		UserID:   userID,
+		FromTime: pgtype.Timestamptz{Time: window.from, Valid: true},
+		ToTime:   pgtype.Timestamptz{Time: window.to, Valid: true},
+		Channel:  window.channel,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	campaignSeries := make([]db.GetCampaignMetricSeriesRow, 0, len(series))
+	for _, row := range series {
+		campaignSeries = append(campaignSeries, db.GetCampaignMetricSeriesRow(row))
+	}
+
+	return buildAnalyticsResponse(
+		window,
+		db.GetCampaignMetricTotalsRow(totals),
+		db.GetCampaignMetricTotalsRow(previous),
+		campaignSeries,
+	), nil
+}
+
+func (s *AnalyticsService) ensureCampaignOwner(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID) error {
+	_, err := s.queries.GetCampaign(ctx, db.GetCampaignParams{
+		ID:     campaignID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCampaignNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func resolveAnalyticsWindow(req dto.AnalyticsRequest, now time.Time) (analyticsWindow, error) {
+	window := analyticsWindow{interval: req.Interval, loc: time.UTC}
+	if window.interval == "" {
+		window.interval = IntervalDay
+	}
+	if req.Channel != "" {
+		window.channel = &req.Channel
+	}
+
+	if req.Timezone != "" {
+		// The name is passed on to Postgres, which has no "Local": that is
+		// the server's own zone to LoadLocation.
+		loc, err := time.LoadLocation(req.Timezone)
+		if err != nil || req.Timezone == "Local" {
+			return window, ErrInvalidTimezone
+		}
+		window.loc = loc
+	}
+
+	today := truncateToBucket(now.In(window.loc), IntervalDay)
+	window.to = today.AddDate(0, 0, 1)
+	if req.To != "" {
+		to, dateOnly, err := parseTimeParam(req.To, window.loc)
+		if err != nil {
+			return window, ErrInvalidAnalyticsRange
+		}
+		// A plain date as upper bound means "up to and including that day".
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		window.to = to
+	}
+
+	window.from = window.to.AddDate(0, 0, -defaultAnalyticsDays)
+	if req.From != "" {
+		from, _, err := parseTimeParam(req.From, window.loc)
+		if err != nil {
+			return window, ErrInvalidAnalyticsRange
+		}
+		window.from = from
+	}
+
+	if !window.from.Before(window.to) {
+		return window, ErrInvalidAnalyticsRange
+	}
+	if countBuckets(window.from, window.to, window.interval) > maxAnalyticsBuckets {
+		return window, ErrInvalidAnalyticsRange
+	}
+
+	length := window.to.Sub(window.from)
+	window.prevTo = window.from
+	window.prevFrom = window.from.Add(-length)
+
+	return window, nil
+}
+
+func parseTimeParam(value string, loc *time.Location) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(loc), false, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, loc)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, true, nil
+}
+
+// truncateToBucket mirrors Postgres date_trunc in t's location; weeks start
+// on Monday as they do in Postgres.
+func truncateToBucket(t time.Time, interval string) time.Time {
+	year, month, day := t.Date()
+	switch interval {
+	case IntervalWeek:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+	case IntervalMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func nextBucket(t time.Time, interval string) time.Time {
+	switch interval {
+	case IntervalWeek:
+		return t.AddDate(0, 0, 7)
+	case IntervalMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+func countBuckets(from, to time.Time, interval string) int {
+	count := 0
+	for b := truncateToBucket(from, interval); b.Before(to); b = nextBucket(b, interval) {
+		count++
+		if count > maxAnalyticsBuckets {
+			break
+		}
+	}
+	return count
+}
+
+func buildAnalyticsResponse(window analyticsWindow, totals, previous db.GetCampaignMetricTotalsRow, series []db.GetCampaignMetricSeriesRow) *dto.AnalyticsResponse {
+	current := buildKPIs(totals.Impressions, totals.Clicks, totals.Conversions, totals.Spend, totals.Revenue)
+	prev := buildKPIs(previous.Impressions, previous.Clicks, previous.Conversions, previous.Spend, previous.Revenue)
+
+	byBucket := make(map[int64]db.GetCampaignMetricSeriesRow, len(series))
+	for _, row := range series {
+		byBucket[row.BucketStart.Time.Unix()] = row
+	}
+
+	// Empty buckets are filled with zeroes so charts get a continuous axis.
+	var buckets []dto.AnalyticsBucketResponse
+	for b := truncateToBucket(window.from.In(window.loc), window.interval); b.Before(window.to); b = nextBucket(b, window.interval) {
+		row := byBucket[b.Unix()]
+		buckets = append(buckets, dto.AnalyticsBucketResponse{
+			Start: b,
+			KPIs:  buildKPIs(row.Impressions, row.Clicks, row.Conversions, row.Spend, row.Revenue),
+		})
+	}
+
+	var channel string
+	if window.channel != nil {
+		channel = *window.channel
+	}
+	return &dto.AnalyticsResponse{
+		Channel:  channel,
+		From:     window.from,
+		To:       window.to,
+		Interval: window.interval,
+		Timezone: window.loc.String(),
+		Totals:   current,
+		Previous: dto.AnalyticsComparisonResponse{
+			From:   window.prevFrom,
+			To:     window.prevTo,
+			KPIs:   prev,
+			Change: compareKPIs(current, prev),
+		},
+		Series: buckets,
+	}
+}
+
+func buildKPIs(impressions, clicks, conversions int64, spend, revenue float64) dto.KPIResponse {
+	kpis := dto.KPIResponse{
+		Impressions:    impressions,
+		Clicks:         clicks,
+		Conversions:    conversions,
+		Spend:          spend,
+		Revenue:        revenue,
+		CTR:            ratio(float64(clicks), float64(impressions)),
+		CPC:            ratio(spend, float64(clicks)),
+		CPA:            ratio(spend, float64(conversions)),
+		ConversionRate: ratio(float64(conversions), float64(clicks)),
+		ROAS:           ratio(revenue, spend),
+	}
+	if cpm := ratio(spend, float64(impressions)); cpm != nil {
+		*cpm *= 1000
+		kpis.CPM = cpm
+	}
+	return kpis
+}
+
+func compareKPIs(current, previous dto.KPIResponse) dto.KPIChangeResponse {
+	return dto.KPIChangeResponse{
+		Impressions:    relativeChange(float64(current.Impressions), float64(previous.Impressions)),
+		Clicks:         relativeChange(float64(current.Clicks), float64(previous.Clicks)),
+		Conversions:    relativeChange(float64(current.Conversions), float64(previous.Conversions)),
+		Spend:          relativeChange(current.Spend, previous.Spend),
+		Revenue:        relativeChange(current.Revenue, previous.Revenue),
+		CTR:            relativeChangePtr(current.CTR, previous.CTR),
+		CPC:            relativeChangePtr(current.CPC, previous.CPC),
+		CPA:            relativeChangePtr(current.CPA, previous.CPA),
+		CPM:            relativeChangePtr(current.CPM, previous.CPM),
+		ConversionRate: relativeChangePtr(current.ConversionRate, previous.ConversionRate),
+		ROAS:           relativeChangePtr(current.ROAS, previous.ROAS),
+	}
+}
+
+func ratio(num, den float64) *float64 {
+	if den == 0 {
+		return nil
+	}
+	v := num / den
+	return &v
+}
+
+func relativeChange(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	v := (current - previous) / previous
+	return &v
+}
+
+func relativeChangePtr(current, previous *float64) *float64 {
+	if current == nil || previous == nil {
+		return nil
+	}
+	return relativeChange(*current, *previous)
+}
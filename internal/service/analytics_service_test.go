@@ -0,0 +1,33 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/valenrio66/be-project/internal/dto"
+)
+
+func TestResolveAnalyticsWindowTimezone(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		timezone string
+		wantErr  error
+	}{
+		{timezone: ""},
+		{timezone: "UTC"},
+		{timezone: "Asia/Jakarta"},
+		{timezone: "Local", wantErr: ErrInvalidTimezone},
+		{timezone: "Mars/Olympus", wantErr: ErrInvalidTimezone},
+	}
+	for _, tt := range tests {
+		window, err := resolveAnalyticsWindow(dto.AnalyticsRequest{Timezone: tt.timezone}, now)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("timezone %q: err = %v, want %v", tt.timezone, err, tt.wantErr)
+			continue
+		}
+		if err == nil && tt.timezone != "" && window.loc.String() != tt.timezone {
+			t.Errorf("timezone %q resolved to %q", tt.timezone, window.loc)
+		}
+	}
+}
@@ -0,0 +1,597 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/pagination"
+	"github.com/valenrio66/be-project/pkg/utils"
+)
+
+var (
+	ErrApprovalPolicyNotFound = errors.New("approval policy not found")
+	ErrInvalidApprovalPolicy  = errors.New("invalid approval policy")
+	ErrApprovalNotFound       = errors.New("approval not found")
+	ErrApprovalNotRequired    = errors.New("campaign does not need approval")
+	ErrApprovalPending        = errors.New("campaign already has a pending approval")
+	ErrApprovalClosed         = errors.New("approval is no longer pending")
+	ErrApprovalForbidden      = errors.New("not allowed to decide this approval")
+	ErrInvalidApproval        = errors.New("invalid approval decision")
+	ErrApprovalRequired       = errors.New("campaign needs an approval before it can go live")
+)
+
+// Approval request states.
+const (
+	ApprovalStatusPending   = "pending"
+	ApprovalStatusApproved  = "approved"
+	ApprovalStatusRejected  = "rejected"
+	ApprovalStatusCancelled = "cancelled"
+)
+
+const approvalQueueSortBy = "created_at"
+
+// approverRoles are the roles that may sit in an approver chain.
+var approverRoles = []string{utils.RoleManager, utils.RoleAdmin}
+
+type ApprovalService struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewApprovalService(dbPool *pgxpool.Pool, queries *db.Queries) *ApprovalService {
+	return &ApprovalService{
+		dbPool:  dbPool,
+		queries: queries,
+	}
+}
+
+func (s *ApprovalService) CreatePolicy(ctx context.Context, req dto.ApprovalPolicyRequest) (*dto.ApprovalPolicyResponse, error) {
+	arg, err := approvalPolicyParams(req)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.queries.CreateApprovalPolicy(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	res := toApprovalPolicyResponse(policy)
+	return &res, nil
+}
+
+func (s *ApprovalService) ListPolicies(ctx context.Context) ([]dto.ApprovalPolicyResponse, error) {
+	policies, err := s.queries.ListApprovalPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]dto.ApprovalPolicyResponse, 0, len(policies))
+	for _, p := range policies {
+		responses = append(responses, toApprovalPolicyResponse(p))
+	}
+	return responses, nil
+}
+
+// UpdatePolicy replaces a policy. Requests already submitted keep the chain
+// they were created with.
+func (s *ApprovalService) UpdatePolicy(ctx context.Context, policyID uuid.UUID, req dto.ApprovalPolicyRequest) (*dto.ApprovalPolicyResponse, error) {
+	arg, err := approvalPolicyParams(req)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.queries.UpdateApprovalPolicy(ctx, db.UpdateApprovalPolicyParams{
+		ID:             policyID,
+		Name:           arg.Name,
+		MinBudget:      arg.MinBudget,
+		ApproverRoles:  arg.ApproverRoles,
+		AppliesToRoles: arg.AppliesToRoles,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrApprovalPolicyNotFound
+		}
+		return nil, err
+	}
+	res := toApprovalPolicyResponse(policy)
+	return &res, nil
+}
+
+func (s *ApprovalService) DeletePolicy(ctx context.Context, policyID uuid.UUID) error {
+	rows, err := s.queries.DeleteApprovalPolicy(ctx, policyID)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrApprovalPolicyNotFound
+	}
+	return nil
+}
+
+// Submit asks for approval of a campaign at its current budget. It fails with
+// ErrApprovalNotRequired when no policy covers the budget or an earlier
+// approval already does.
+func (s *ApprovalService) Submit(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.SubmitApprovalRequest) (*dto.ApprovalResponse, error) {
+	var approval db.CampaignApproval
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		campaign, err := q.GetCampaignForUpdate(ctx, db.GetCampaignForUpdateParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrCampaignNotFound
+			}
+			return err
+		}
+
+		policy, err := applicablePolicy(ctx, q, campaign.UserID, campaign.Budget)
+		if err != nil {
+			return err
+		}
+		if policy == nil {
+			return fmt.Errorf("%w: no approval policy covers a budget of %.2f", ErrApprovalNotRequired, campaign.Budget)
+		}
+		approved, err := q.GetLatestApprovedBudget(ctx, campaignID)
+		if err != nil {
+			return err
+		}
+		if approved >= campaign.Budget {
+			return fmt.Errorf("%w: a budget of %.2f is already approved", ErrApprovalNotRequired, approved)
+		}
+
+		if _, err := q.GetPendingCampaignApproval(ctx, campaignID); err == nil {
+			return ErrApprovalPending
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		approval, err = q.CreateCampaignApproval(ctx, db.CreateCampaignApprovalParams{
+			CampaignID:    campaignID,
+			RequestedBy:   pgtype.UUID{Bytes: userID, Valid: true},
+			PolicyID:      pgtype.UUID{Bytes: policy.ID, Valid: true},
+			Budget:        campaign.Budget,
+			ApproverRoles: policy.ApproverRoles,
+			Comment:       optionalComment(req.Comment),
+		})
+		if isUniqueViolation(err) {
+			return ErrApprovalPending
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := toApprovalResponse(approval)
+	res.Decisions = []dto.ApprovalDecisionResponse{}
+	return &res, nil
+}
+
+// ListCampaignApprovals returns every approval request of a campaign the
+// user owns, newest first.
+func (s *ApprovalService) ListCampaignApprovals(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID) ([]dto.ApprovalResponse, error) {
+	if _, err := s.queries.GetCampaign(ctx, db.GetCampaignParams{
+		ID:     campaignID,
+		UserID: userID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	approvals, err := s.queries.ListCampaignApprovals(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]dto.ApprovalResponse, 0, len(approvals))
+	for _, a := range approvals {
+		responses = append(responses, toApprovalResponse(a))
+	}
+	if err := s.attachDecisions(ctx, responses); err != nil {
+		return nil, err
+	}
+	return responses, nil
+}
+
+// Queue lists the pending requests the user can decide next, oldest first.
+// Admins may decide any step, managers only the manager steps.
+func (s *ApprovalService) Queue(ctx context.Context, userID uuid.UUID, role string, req dto.PageRequest) (*dto.PageResponse, error) {
+	cur, err := decodeCursor(req.Cursor, approvalQueueSortBy, pagination.OrderAsc)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil && cur.Backward {
+		return nil, ErrInvalidCursor
+	}
+	limit := pagination.Limit(req.Limit)
+
+	roles := decidableRoles(role)
+	arg := db.ListPendingApprovalsParams{
+		Roles:    roles,
+		UserID:   userID,
+		RowLimit: int32(limit + 1),
+	}
+	if cur != nil {
+		arg.CursorID = pgtype.UUID{Bytes: cur.ID, Valid: true}
+		arg.CursorTime = pgtype.Timestamptz{Time: cur.Time, Valid: true}
+	}
+
+	rows, err := s.queries.ListPendingApprovals(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	rows, hasNext, _ := pagination.Trim(rows, limit, cur)
+
+	responses := make([]dto.ApprovalResponse, 0, len(rows))
+	for _, r := range rows {
+		res := toApprovalResponse(db.CampaignApproval{
+			ID:            r.ID,
+			CampaignID:    r.CampaignID,
+			RequestedBy:   r.RequestedBy,
+			PolicyID:      r.PolicyID,
+			Status:        r.Status,
+			Budget:        r.Budget,
+			ApproverRoles: r.ApproverRoles,
+			CurrentStep:   r.CurrentStep,
+			Comment:       r.Comment,
+			CreatedAt:     r.CreatedAt,
+			DecidedAt:     r.DecidedAt,
+		})
+		res.CampaignTitle = r.CampaignTitle
+		responses = append(responses, res)
+	}
+	if err := s.attachDecisions(ctx, responses); err != nil {
+		return nil, err
+	}
+
+	var next *pagination.Cursor
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		next = &pagination.Cursor{
+			SortBy:    approvalQueueSortBy,
+			SortOrder: pagination.OrderAsc,
+			Time:      last.CreatedAt.Time,
+			ID:        last.ID,
+		}
+	}
+	page := newPageInfo(limit, next, nil)
+
+	if req.IncludeTotal {
+		total, err := s.queries.CountPendingApprovals(ctx, db.CountPendingApprovalsParams{
+			Roles:  roles,
+			UserID: userID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return &dto.PageResponse{
+		Items: responses,
+		Page:  page,
+	}, nil
+}
+
+// GetApproval returns one request to its requester, the campaign owner or
+// any manager or admin.
+func (s *ApprovalService) GetApproval(ctx context.Context, userID uuid.UUID, role string, approvalID uuid.UUID) (*dto.ApprovalResponse, error) {
+	approval, err := s.queries.GetCampaignApproval(ctx, approvalID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrApprovalNotFound
+		}
+		return nil, err
+	}
+	if !slices.Contains(approverRoles, role) && !(approval.RequestedBy.Valid && approval.RequestedBy.Bytes == userID) {
+		if _, err := s.queries.GetCampaign(ctx, db.GetCampaignParams{
+			ID:     approval.CampaignID,
+			UserID: userID,
+		}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrApprovalNotFound
+			}
+			return nil, err
+		}
+	}
+
+	responses := []dto.ApprovalResponse{toApprovalResponse(approval)}
+	if err := s.attachDecisions(ctx, responses); err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// Approve signs off the current step. The request is approved once the last
+// step in its chain is signed off.
+func (s *ApprovalService) Approve(ctx context.Context, userID uuid.UUID, role string, approvalID uuid.UUID, req dto.ApprovalDecisionRequest) (*dto.ApprovalResponse, error) {
+	return s.decide(ctx, userID, role, approvalID, ApprovalStatusApproved, req.Comment)
+}
+
+// Reject closes the request at the current step; the comment is required.
+func (s *ApprovalService) Reject(ctx context.Context, userID uuid.UUID, role string, approvalID uuid.UUID, req dto.ApprovalDecisionRequest) (*dto.ApprovalResponse, error) {
+	if strings.TrimSpace(req.Comment) == "" {
+		return nil, fmt.Errorf("%w: a comment is required to reject", ErrInvalidApproval)
+	}
+	return s.decide(ctx, userID, role, approvalID, ApprovalStatusRejected, req.Comment)
+}
+
+// decide records one decision. Approvers must hold the role of the current
+// step (admins may stand in for any step), may not decide requests they made
+// or whose campaign they own, and may sign only one step of a chain.
+func (s *ApprovalService) decide(ctx context.Context, userID uuid.UUID, role string, approvalID uuid.UUID, decision string, comment string) (*dto.ApprovalResponse, error) {
+	var approval db.CampaignApproval
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		current, err := q.GetCampaignApprovalForUpdate(ctx, approvalID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrApprovalNotFound
+			}
+			return err
+		}
+		if current.Status != ApprovalStatusPending {
+			return ErrApprovalClosed
+		}
+		if current.CampaignOwnerID == userID || (current.RequestedBy.Valid && current.RequestedBy.Bytes == userID) {
+			return fmt.Errorf("%w: requests for your own campaigns must be decided by someone else", ErrApprovalForbidden)
+		}
+		stepRole := current.ApproverRoles[current.CurrentStep]
+		if role != utils.RoleAdmin && role != stepRole {
+			return fmt.Errorf("%w: step %d needs the %s role", ErrApprovalForbidden, current.CurrentStep+1, stepRole)
+		}
+		approver := pgtype.UUID{Bytes: userID, Valid: true}
+		signed, err := q.HasApprovalDecisionBy(ctx, db.HasApprovalDecisionByParams{
+			ApprovalID: approvalID,
+			ApproverID: approver,
+		})
+		if err != nil {
+			return err
+		}
+		if signed {
+			return fmt.Errorf("%w: you already signed an earlier step", ErrApprovalForbidden)
+		}
+
+		if _, err := q.CreateApprovalDecision(ctx, db.CreateApprovalDecisionParams{
+			ApprovalID: approvalID,
+			Step:       current.CurrentStep,
+			ApproverID: approver,
+			Decision:   decision,
+			Comment:    optionalComment(comment),
+		}); err != nil {
+			return err
+		}
+
+		if decision == ApprovalStatusApproved {
+			approval, err = q.AdvanceCampaignApproval(ctx, approvalID)
+		} else {
+			approval, err = q.CloseCampaignApproval(ctx, db.CloseCampaignApprovalParams{
+				ID:     approvalID,
+				Status: ApprovalStatusRejected,
+			})
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []dto.ApprovalResponse{toApprovalResponse(approval)}
+	if err := s.attachDecisions(ctx, responses); err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// Cancel withdraws a pending request. Only the requester or the campaign
+// owner may cancel it.
+func (s *ApprovalService) Cancel(ctx context.Context, userID uuid.UUID, approvalID uuid.UUID) (*dto.ApprovalResponse, error) {
+	var approval db.CampaignApproval
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		current, err := q.GetCampaignApprovalForUpdate(ctx, approvalID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrApprovalNotFound
+			}
+			return err
+		}
+		if current.CampaignOwnerID != userID && !(current.RequestedBy.Valid && current.RequestedBy.Bytes == userID) {
+			return ErrApprovalNotFound
+		}
+		if current.Status != ApprovalStatusPending {
+			return ErrApprovalClosed
+		}
+
+		approval, err = q.CloseCampaignApproval(ctx, db.CloseCampaignApprovalParams{
+			ID:     approvalID,
+			Status: ApprovalStatusCancelled,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []dto.ApprovalResponse{toApprovalResponse(approval)}
+	if err := s.attachDecisions(ctx, responses); err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// attachDecisions loads the decisions of the given requests with one query.
+func (s *ApprovalService) attachDecisions(ctx context.Context, responses []dto.ApprovalResponse) error {
+	ids := make([]uuid.UUID, 0, len(responses))
+	byID := make(map[uuid.UUID]*dto.ApprovalResponse, len(responses))
+	for i := range responses {
+		responses[i].Decisions = []dto.ApprovalDecisionResponse{}
+		id, err := uuid.Parse(responses[i].ID)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+		byID[id] = &responses[i]
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := s.queries.ListApprovalDecisions(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		res := byID[row.ApprovalID]
+		res.Decisions = append(res.Decisions, dto.ApprovalDecisionResponse{
+			Step:          int(row.Step) + 1,
+			ApproverID:    uuidString(row.ApproverID),
+			ApproverEmail: row.ApproverEmail,
+			Decision:      row.Decision,
+			Comment:       row.Comment,
+			CreatedAt:     row.CreatedAt.Time,
+		})
+	}
+	return nil
+}
+
+// checkLaunch enforces the approval policies on a status or budget change.
+// A campaign needs an approval covering its budget when it goes live, and
+// again when a live campaign's budget grows. before is nil for campaigns
+// being created, which cannot have an approval yet.
+func checkLaunch(ctx context.Context, q *db.Queries, ownerID uuid.UUID, before *db.Campaign, target dto.CampaignSnapshot) error {
+	if target.Status != utils.CampaignStatusActive {
+		return nil
+	}
+	if before != nil && before.Status == utils.CampaignStatusActive && target.Budget <= before.Budget {
+		return nil
+	}
+
+	policy, err := applicablePolicy(ctx, q, ownerID, target.Budget)
+	if err != nil || policy == nil {
+		return err
+	}
+	if before != nil {
+		approved, err := q.GetLatestApprovedBudget(ctx, before.ID)
+		if err != nil {
+			return err
+		}
+		if approved >= target.Budget {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: a budget of %.2f needs sign-off under the %q policy", ErrApprovalRequired, target.Budget, policy.Name)
+}
+
+// applicablePolicy returns the policy with the highest threshold covering
+// budget for a campaign owned by ownerID, or nil when none does.
+func applicablePolicy(ctx context.Context, q *db.Queries, ownerID uuid.UUID, budget float64) (*db.ApprovalPolicy, error) {
+	role, err := q.GetUserRole(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := q.GetApplicableApprovalPolicy(ctx, db.GetApplicableApprovalPolicyParams{
+		Budget:    budget,
+		OwnerRole: role,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// decidableRoles lists the chain roles a user with role may sign.
+func decidableRoles(role string) []string {
+	if role == utils.RoleAdmin {
+		return approverRoles
+	}
+	return []string{role}
+}
+
+func approvalPolicyParams(req dto.ApprovalPolicyRequest) (db.CreateApprovalPolicyParams, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return db.CreateApprovalPolicyParams{}, fmt.Errorf("%w: name is required", ErrInvalidApprovalPolicy)
+	}
+	for _, r := range req.ApproverRoles {
+		if !slices.Contains(approverRoles, r) {
+			return db.CreateApprovalPolicyParams{}, fmt.Errorf("%w: approver roles must be one of %s", ErrInvalidApprovalPolicy, strings.Join(approverRoles, ", "))
+		}
+	}
+	appliesTo := []string{}
+	for _, r := range req.AppliesToRoles {
+		if !slices.Contains(utils.Roles, r) {
+			return db.CreateApprovalPolicyParams{}, fmt.Errorf("%w: applies_to_roles must be one of %s", ErrInvalidApprovalPolicy, strings.Join(utils.Roles, ", "))
+		}
+		if !slices.Contains(appliesTo, r) {
+			appliesTo = append(appliesTo, r)
+		}
+	}
+	return db.CreateApprovalPolicyParams{
+		Name:           name,
+		MinBudget:      *req.MinBudget,
+		ApproverRoles:  req.ApproverRoles,
+		AppliesToRoles: appliesTo,
+	}, nil
+}
+
+func optionalComment(comment string) *string {
+	if comment = strings.TrimSpace(comment); comment == "" {
+		return nil
+	}
+	return &comment
+}
+
+func uuidString(id pgtype.UUID) *string {
+	if !id.Valid {
+		return nil
+	}
+	s := uuid.UUID(id.Bytes).String()
+	return &s
+}
+
+func toApprovalPolicyResponse(p db.ApprovalPolicy) dto.ApprovalPolicyResponse {
+	return dto.ApprovalPolicyResponse{
+		ID:             p.ID.String(),
+		Name:           p.Name,
+		MinBudget:      p.MinBudget,
+		ApproverRoles:  p.ApproverRoles,
+		AppliesToRoles: p.AppliesToRoles,
+		CreatedAt:      p.CreatedAt.Time,
+		UpdatedAt:      p.UpdatedAt.Time,
+	}
+}
+
+func toApprovalResponse(a db.CampaignApproval) dto.ApprovalResponse {
+	res := dto.ApprovalResponse{
+		ID:            a.ID.String(),
+		CampaignID:    a.CampaignID.String(),
+		RequestedBy:   uuidString(a.RequestedBy),
+		PolicyID:      uuidString(a.PolicyID),
+		Status:        a.Status,
+		Budget:        a.Budget,
+		ApproverRoles: a.ApproverRoles,
+		CurrentStep:   min(int(a.CurrentStep)+1, len(a.ApproverRoles)),
+		Comment:       a.Comment,
+		CreatedAt:     a.CreatedAt.Time,
+	}
+	if a.Status == ApprovalStatusPending && int(a.CurrentStep) < len(a.ApproverRoles) {
+		next := a.ApproverRoles[a.CurrentStep]
+		res.NextApproverRole = &next
+	}
+	if a.DecidedAt.Valid {
+		decided := a.DecidedAt.Time
+		res.DecidedAt = &decided
+	}
+	return res
+}
@@ -0,0 +1,453 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/storage"
+	"github.com/valenrio66/be-project/pkg/thumbnail"
+)
+
+var (
+	ErrAssetNotFound    = errors.New("asset not found")
+	ErrInvalidAsset     = errors.New("invalid asset")
+	ErrUnsupportedAsset = errors.New("unsupported file type")
+	ErrAssetTooLarge    = errors.New("file too large")
+)
+
+// Asset kinds.
+const (
+	AssetImage    = "image"
+	AssetVideo    = "video"
+	AssetDocument = "document"
+)
+
+const (
+	// MaxAssetBytes is the largest upload of any kind.
+	MaxAssetBytes = 200 << 20
+
+	maxAssetFilenameLength = 255
+	// orphanAssetGrace keeps the sweep away from blobs that an upload has
+	// stored but not attached yet.
+	orphanAssetGrace = time.Hour
+)
+
+var assetSizeLimits = map[string]int64{
+	AssetImage:    10 << 20,
+	AssetVideo:    MaxAssetBytes,
+	AssetDocument: 25 << 20,
+}
+
+// assetTypes lists the accepted types as sniffed from the file content.
+var assetTypes = map[string]string{
+	"image/jpeg":      AssetImage,
+	"image/png":       AssetImage,
+	"image/gif":       AssetImage,
+	"image/webp":      AssetImage,
+	"video/mp4":       AssetVideo,
+	"video/webm":      AssetVideo,
+	"application/pdf": AssetDocument,
+	"text/plain":      AssetDocument,
+}
+
+// officeTypes are the Office Open XML formats, which sniff as plain zip
+// archives and are told apart by their extension.
+var officeTypes = map[string]string{
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+}
+
+type AssetService struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	storage storage.Storage
+	urlTTL  time.Duration
+}
+
+func NewAssetService(dbPool *pgxpool.Pool, queries *db.Queries, store storage.Storage, urlTTL time.Duration) *AssetService {
+	return &AssetService{
+		dbPool:  dbPool,
+		queries: queries,
+		storage: store,
+		urlTTL:  urlTTL,
+	}
+}
+
+// Upload attaches a file to a campaign. The content decides the type, not
+// the filename or the declared Content-Type. Identical content is stored
+// once however many campaigns use it; uploading a file the campaign already
+// has returns the existing attachment with created false.
+func (s *AssetService) Upload(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, filename string, r io.Reader) (res *dto.AssetResponse, created bool, err error) {
+	if err := s.ownCampaign(ctx, userID, campaignID); err != nil {
+		return nil, false, err
+	}
+	filename = cleanAssetFilename(filename)
+
+	tmp, err := os.CreateTemp("", "asset-*")
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(r, MaxAssetBytes+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if size == 0 {
+		return nil, false, fmt.Errorf("%w: file is empty", ErrInvalidAsset)
+	}
+	if size > MaxAssetBytes {
+		return nil, false, fmt.Errorf("%w: files must not exceed %d MB", ErrAssetTooLarge, MaxAssetBytes>>20)
+	}
+
+	head := make([]byte, 512)
+	n, err := tmp.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, false, err
+	}
+	mimeType, kind, err := detectAssetType(head[:n], filename)
+	if err != nil {
+		return nil, false, err
+	}
+	if limit := assetSizeLimits[kind]; size > limit {
+		return nil, false, fmt.Errorf("%w: %s files must not exceed %d MB", ErrAssetTooLarge, kind, limit>>20)
+	}
+
+	blob, thumb, err := prepareAsset(tmp, hex.EncodeToString(hash.Sum(nil)), size, mimeType, kind)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Skip the upload when the content is already stored. The upsert below
+	// still runs, as the stored copy may be swept in between.
+	stored := false
+	if _, err := s.queries.GetAssetBySHA256(ctx, blob.Sha256); errors.Is(err, pgx.ErrNoRows) {
+		if err := s.putAsset(ctx, tmp, blob, thumb); err != nil {
+			return nil, false, err
+		}
+		stored = true
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	var row db.GetCampaignAssetRow
+	var assetID uuid.UUID
+	err = execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		asset, err := q.UpsertAsset(ctx, blob)
+		if err != nil {
+			return err
+		}
+		assetID = asset.ID
+		if asset.ID == blob.ID && !stored {
+			if err := s.putAsset(ctx, tmp, blob, thumb); err != nil {
+				return err
+			}
+			stored = true
+		}
+
+		_, err = q.AttachCampaignAsset(ctx, db.AttachCampaignAssetParams{
+			CampaignID: campaignID,
+			AssetID:    asset.ID,
+			Filename:   filename,
+			UploadedBy: pgtype.UUID{Bytes: userID, Valid: true},
+		})
+		switch {
+		case err == nil:
+			created = true
+		case !errors.Is(err, pgx.ErrNoRows):
+			return err
+		}
+
+		attached, err := q.GetCampaignAssetByAsset(ctx, db.GetCampaignAssetByAssetParams{
+			CampaignID: campaignID,
+			AssetID:    asset.ID,
+		})
+		row = db.GetCampaignAssetRow(attached)
+		return err
+	})
+	// Drop our copy when the transaction failed or another upload of the
+	// same content won the race.
+	if stored && (err != nil || assetID != blob.ID) {
+		s.removeAsset(context.WithoutCancel(ctx), blob.StorageKey, blob.ThumbnailKey)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	res, err = s.toAssetResponse(row)
+	if err != nil {
+		return nil, false, err
+	}
+	res.Deduplicated = !created
+	return res, created, nil
+}
+
+func (s *AssetService) ListAssets(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.ListAssetsRequest) ([]dto.AssetResponse, error) {
+	if err := s.ownCampaign(ctx, userID, campaignID); err != nil {
+		return nil, err
+	}
+
+	arg := db.ListCampaignAssetsParams{CampaignID: campaignID}
+	if req.Kind != "" {
+		arg.Kind = &req.Kind
+	}
+	rows, err := s.queries.ListCampaignAssets(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.AssetResponse, 0, len(rows))
+	for _, r := range rows {
+		res, err := s.toAssetResponse(db.GetCampaignAssetRow(r))
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *res)
+	}
+	return responses, nil
+}
+
+func (s *AssetService) GetAsset(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, assetID uuid.UUID) (*dto.AssetResponse, error) {
+	if err := s.ownCampaign(ctx, userID, campaignID); err != nil {
+		return nil, err
+	}
+
+	row, err := s.queries.GetCampaignAsset(ctx, db.GetCampaignAssetParams{
+		ID:         assetID,
+		CampaignID: campaignID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAssetNotFound
+		}
+		return nil, err
+	}
+	return s.toAssetResponse(row)
+}
+
+// DeleteAsset detaches a file from a campaign. The stored content is
+// removed once no campaign uses it any more.
+func (s *AssetService) DeleteAsset(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, assetID uuid.UUID) error {
+	if err := s.ownCampaign(ctx, userID, campaignID); err != nil {
+		return err
+	}
+
+	var orphan *db.Asset
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		blobID, err := q.DeleteCampaignAsset(ctx, db.DeleteCampaignAssetParams{
+			ID:         assetID,
+			CampaignID: campaignID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrAssetNotFound
+			}
+			return err
+		}
+
+		asset, err := q.DeleteAssetIfOrphaned(ctx, blobID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+		orphan = &asset
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if orphan != nil {
+		s.removeAsset(ctx, orphan.StorageKey, orphan.ThumbnailKey)
+	}
+	return nil
+}
+
+// PurgeOrphans deletes stored files that no campaign uses any more, which
+// happens when campaigns are purged from the trash, and reports how many
+// were removed.
+func (s *AssetService) PurgeOrphans(ctx context.Context, now time.Time) (int64, error) {
+	assets, err := s.queries.DeleteOrphanedAssets(ctx, pgtype.Timestamptz{Time: now.Add(-orphanAssetGrace), Valid: true})
+	if err != nil {
+		return 0, err
+	}
+	for _, a := range assets {
+		s.removeAsset(ctx, a.StorageKey, a.ThumbnailKey)
+	}
+	return int64(len(assets)), nil
+}
+
+func (s *AssetService) ownCampaign(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID) error {
+	_, err := s.queries.GetCampaign(ctx, db.GetCampaignParams{
+		ID:     campaignID,
+		UserID: userID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCampaignNotFound
+	}
+	return err
+}
+
+func (s *AssetService) putAsset(ctx context.Context, f *os.File, blob db.UpsertAssetParams, thumb *thumbnail.Thumbnail) error {
+	if err := s.storage.Put(ctx, blob.StorageKey, io.NewSectionReader(f, 0, blob.SizeBytes), blob.SizeBytes, blob.MimeType); err != nil {
+		return err
+	}
+	if thumb != nil {
+		if err := s.storage.Put(ctx, *blob.ThumbnailKey, bytes.NewReader(thumb.Data), int64(len(thumb.Data)), thumb.ContentType); err != nil {
+			s.removeAsset(context.WithoutCancel(ctx), blob.StorageKey, nil)
+			return err
+		}
+	}
+	return nil
+}
+
+// removeAsset deletes stored files on a best-effort basis; the database no
+// longer points at them, so a failure only leaves garbage behind.
+func (s *AssetService) removeAsset(ctx context.Context, storageKey string, thumbnailKey *string) {
+	keys := []string{storageKey}
+	if thumbnailKey != nil {
+		keys = append(keys, *thumbnailKey)
+	}
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			zap.L().Warn("Deleting stored asset failed", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+func (s *AssetService) toAssetResponse(a db.GetCampaignAssetRow) (*dto.AssetResponse, error) {
+	expiresAt := time.Now().Add(s.urlTTL)
+	downloadURL, err := s.storage.SignedURL(a.StorageKey, s.urlTTL, a.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.AssetResponse{
+		ID:          a.ID.String(),
+		CampaignID:  a.CampaignID.String(),
+		Filename:    a.Filename,
+		Kind:        a.Kind,
+		MimeType:    a.MimeType,
+		SizeBytes:   a.SizeBytes,
+		SHA256:      a.Sha256,
+		Width:       a.Width,
+		Height:      a.Height,
+		UploadedBy:  uuidString(a.UploadedBy),
+		DownloadURL: downloadURL,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   a.CreatedAt.Time,
+	}
+	if a.ThumbnailKey != nil {
+		thumbnailURL, err := s.storage.SignedURL(*a.ThumbnailKey, s.urlTTL, "")
+		if err != nil {
+			return nil, err
+		}
+		res.ThumbnailURL = &thumbnailURL
+	}
+	return res, nil
+}
+
+// prepareAsset assigns storage keys to new content and, for images the
+// standard library can decode, reads the dimensions and renders a
+// thumbnail.
+func prepareAsset(f *os.File, sum string, size int64, mimeType, kind string) (db.UpsertAssetParams, *thumbnail.Thumbnail, error) {
+	id := uuid.New()
+	blob := db.UpsertAssetParams{
+		ID:         id,
+		Sha256:     sum,
+		SizeBytes:  size,
+		MimeType:   mimeType,
+		Kind:       kind,
+		StorageKey: "assets/" + id.String() + "/original",
+	}
+	if kind != AssetImage || mimeType == "image/webp" {
+		return blob, nil, nil
+	}
+
+	src := io.NewSectionReader(f, 0, size)
+	width, height, err := thumbnail.Config(src)
+	if err != nil {
+		return blob, nil, fmt.Errorf("%w: image cannot be decoded", ErrInvalidAsset)
+	}
+	w, h := int32(width), int32(height)
+	blob.Width, blob.Height = &w, &h
+
+	thumb, err := thumbnail.Generate(io.NewSectionReader(f, 0, size))
+	if err != nil {
+		if errors.Is(err, thumbnail.ErrTooLarge) {
+			return blob, nil, fmt.Errorf("%w: image must not exceed %d megapixels", ErrInvalidAsset, thumbnail.MaxPixels/1_000_000)
+		}
+		return blob, nil, fmt.Errorf("%w: image cannot be decoded", ErrInvalidAsset)
+	}
+	thumbnailKey := "assets/" + id.String() + "/thumbnail"
+	blob.ThumbnailKey = &thumbnailKey
+	return blob, thumb, nil
+}
+
+// detectAssetType sniffs the content type and maps it to an asset kind.
+func detectAssetType(head []byte, filename string) (mimeType, kind string, err error) {
+	mimeType, _, err = mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "", "", ErrUnsupportedAsset
+	}
+	if mimeType == "application/zip" {
+		if office, ok := officeTypes[strings.ToLower(filepath.Ext(filename))]; ok {
+			return office, AssetDocument, nil
+		}
+	}
+	kind, ok := assetTypes[mimeType]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", ErrUnsupportedAsset, mimeType)
+	}
+	return mimeType, kind, nil
+}
+
+// cleanAssetFilename keeps the base name of an uploaded file without
+// control characters.
+func cleanAssetFilename(filename string) string {
+	filename = filepath.Base(strings.ReplaceAll(filename, "\\", "/"))
+	filename = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, filename))
+	if filename == "" || filename == "." || filename == "/" {
+		return "file"
+	}
+	if runes := []rune(filename); len(runes) > maxAssetFilenameLength {
+		ext := []rune(filepath.Ext(filename))
+		if len(ext) > 16 {
+			ext = nil
+		}
+		filename = string(runes[:maxAssetFilenameLength-len(ext)]) + string(ext)
+	}
+	return filename
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/attribution"
+)
+
+const (
+	defaultAttributionLookbackDays = 30
+	defaultAttributionHalfLifeDays = 7
+)
+
+type AttributionService struct {
+	queries  *db.Queries
+	currency string
+}
+
+// NewAttributionService creates the attribution service. Revenue is only
+// attributed from conversions in currency, the reporting currency.
+func NewAttributionService(queries *db.Queries, currency string) *AttributionService {
+	return &AttributionService{
+		queries:  queries,
+		currency: strings.ToUpper(currency),
+	}
+}
+
+// Attribute credits the user's campaigns with the conversions in the window
+// using the requested model, spreading each conversion over the clicks of
+// its visitor within the lookback.
+func (s *AttributionService) Attribute(ctx context.Context, userID uuid.UUID, req dto.AttributionRequest) (*dto.AttributionResponse, error) {
+	window, err := resolveAnalyticsWindow(dto.AnalyticsRequest{
+		From:     req.From,
+		To:       req.To,
+		Timezone: req.Timezone,
+	}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	model := req.Model
+	if model == "" {
+		model = attribution.LastClick
+	}
+	lookbackDays := req.LookbackDays
+	if lookbackDays == 0 {
+		lookbackDays = defaultAttributionLookbackDays
+	}
+	lookback := time.Duration(lookbackDays) * 24 * time.Hour
+	var opts attribution.Options
+	var halfLifeDays *float64
+	if model == attribution.TimeDecay {
+		days := req.HalfLifeDays
+		if days == 0 {
+			days = defaultAttributionHalfLifeDays
+		}
+		halfLifeDays = &days
+		opts.HalfLife = time.Duration(days * float64(24*time.Hour))
+	}
+
+	rows, err := s.queries.ListAttributionTouches(ctx, db.ListAttributionTouchesParams{
+		UserID:          userID,
+		LookbackSeconds: lookback.Seconds(),
+		FromTime:        pgtype.Timestamptz{Time: window.from, Valid: true},
+		ToTime:          pgtype.Timestamptz{Time: window.to, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.AttributionResponse{
+		Model:        model,
+		LookbackDays: lookbackDays,
+		HalfLifeDays: halfLifeDays,
+		From:         window.from,
+		To:           window.to,
+		Timezone:     window.loc.String(),
+		Currency:     s.currency,
+		Campaigns:    []dto.AttributedCampaignResponse{},
+	}
+	campaigns := make(map[uuid.UUID]*dto.AttributedCampaignResponse)
+
+	// Rows come grouped by conversion, touches in time order.
+	for start := 0; start < len(rows); {
+		end := start + 1
+		for end < len(rows) && rows[end].ConversionID == rows[start].ConversionID {
+			end++
+		}
+		path := rows[start:end]
+		start = end
+
+		conv := path[0]
+		revenue := 0.0
+		if conv.Currency == s.currency {
+			revenue = conv.Value
+		}
+		res.Conversions++
+		res.Revenue += revenue
+
+		touches := make([]attribution.Touch, len(path))
+		for i, t := range path {
+			touches[i] = attribution.Touch{Key: t.CampaignID.String(), At: t.ClickedAt.Time}
+		}
+		credit, err := attribution.Credit(model, touches, conv.ConvertedAt.Time, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, t := range path {
+			if !t.Owned {
+				continue
+			}
+			c := campaigns[t.CampaignID]
+			if c == nil {
+				c = &dto.AttributedCampaignResponse{CampaignID: t.CampaignID.String(), Title: t.CampaignTitle}
+				campaigns[t.CampaignID] = c
+			}
+			c.Touches++
+			c.Conversions += credit[i]
+			c.Revenue += credit[i] * revenue
+			res.AttributedConversions += credit[i]
+			res.AttributedRevenue += credit[i] * revenue
+		}
+	}
+
+	for _, c := range campaigns {
+		res.Campaigns = append(res.Campaigns, *c)
+	}
+	slices.SortFunc(res.Campaigns, func(a, b dto.AttributedCampaignResponse) int {
+		return cmp.Or(
+			cmp.Compare(b.Conversions, a.Conversions),
+			strings.Compare(a.Title, b.Title),
+			strings.Compare(a.CampaignID, b.CampaignID),
+		)
+	})
+	return res, nil
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/utils"
+)
+
+var (
+	ErrInvalidBulkRequest = errors.New("invalid bulk request")
+	ErrBulkForbidden      = errors.New("not allowed to perform this bulk action")
+)
+
+const (
+	BulkActionUpdateStatus = "update_status"
+	BulkActionSetFields    = "set_fields"
+	BulkActionDelete       = "delete"
+	BulkActionAddTag       = "add_tag"
+	BulkActionRemoveTag    = "remove_tag"
+
+	maxBulkItems = 500
+)
+
+// Per-item outcomes reported by BulkCampaigns.
+const (
+	BulkResultUpdated   = "updated"
+	BulkResultUnchanged = "unchanged"
+	BulkResultDeleted   = "deleted"
+	BulkResultNotFound  = "not_found"
+	BulkResultInvalid   = "invalid"
+	// BulkResultApprovalRequired marks campaigns that cannot go live, or
+	// raise a live budget, without an approval.
+	BulkResultApprovalRequired = "approval_required"
+)
+
+// errRollback ends a transaction without reporting a failure to the caller.
+var errRollback = errors.New("rollback")
+
+// BulkCampaigns applies one action to many campaigns in a single transaction.
+// Every item is checked on its own: campaigns the user does not own (or that
+// are already deleted) are reported as not_found and invalid results do not
+// stop the others unless req.Atomic is set. Items are locked in ID order so
+// concurrent bulk requests cannot deadlock.
+func (s *CampaignService) BulkCampaigns(ctx context.Context, userID uuid.UUID, role string, req dto.BulkCampaignRequest) (*dto.BulkCampaignResponse, error) {
+	if err := validateBulkRequest(req, role); err != nil {
+		return nil, err
+	}
+
+	tagIDs, err := parseTagIDs(req.TagIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBulkRequest, err)
+	}
+	if err := ensureTagsExist(ctx, s.queries, tagIDs); err != nil {
+		return nil, err
+	}
+
+	ids, err := s.resolveBulkTargets(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.BulkCampaignResponse{
+		Action:  req.Action,
+		DryRun:  req.DryRun,
+		Matched: len(ids),
+		Items:   make([]dto.BulkItemResult, 0, len(ids)),
+	}
+
+	err = execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		for _, id := range ids {
+			item, err := s.bulkApply(ctx, q, userID, id, req, tagIDs)
+			if err != nil {
+				return err
+			}
+			if item.Result == BulkResultNotFound || item.Result == BulkResultInvalid || item.Result == BulkResultApprovalRequired {
+				res.Failed++
+			} else {
+				res.Succeeded++
+			}
+			res.Items = append(res.Items, item)
+		}
+
+		if req.DryRun || (req.Atomic && res.Failed > 0) {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return nil, err
+	}
+	res.Committed = err == nil
+
+	return res, nil
+}
+
+func validateBulkRequest(req dto.BulkCampaignRequest, role string) error {
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		return fmt.Errorf("%w: provide either ids or filter", ErrInvalidBulkRequest)
+	}
+
+	switch req.Action {
+	case BulkActionUpdateStatus:
+		if !slices.Contains(utils.CampaignStatuses, req.Status) {
+			return fmt.Errorf("%w: status must be one of %s", ErrInvalidBulkRequest, strings.Join(utils.CampaignStatuses, ", "))
+		}
+	case BulkActionSetFields:
+		f := req.Fields
+		if f == nil || (f.Description == nil && f.StartDate == nil && f.EndDate == nil && f.Budget == nil) {
+			return fmt.Errorf("%w: fields must set at least one field", ErrInvalidBulkRequest)
+		}
+	case BulkActionDelete:
+		// Matches DELETE /campaigns/:id, which is admin only.
+		if role != utils.RoleAdmin {
+			return ErrBulkForbidden
+		}
+	case BulkActionAddTag, BulkActionRemoveTag:
+		if len(req.TagIDs) == 0 {
+			return fmt.Errorf("%w: tag_ids must list at least one tag", ErrInvalidBulkRequest)
+		}
+	}
+	return nil
+}
+
+// resolveBulkTargets returns the distinct campaign IDs to act on in lock
+// order. Filters only match the user's own live campaigns; explicit IDs are
+// checked per item later.
+func (s *CampaignService) resolveBulkTargets(ctx context.Context, userID uuid.UUID, req dto.BulkCampaignRequest) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if req.Filter != nil {
+		filter, err := buildCampaignFilter(dto.ListCampaignsRequest{
+			Status:    req.Filter.Status,
+			From:      req.Filter.From,
+			To:        req.Filter.To,
+			MinBudget: req.Filter.MinBudget,
+			MaxBudget: req.Filter.MaxBudget,
+			Query:     req.Filter.Query,
+			Tags:      req.Filter.Tags,
+			TagMatch:  req.Filter.TagMatch,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		ids, err = s.queries.ListCampaignIDs(ctx, db.ListCampaignIDsParams{
+			UserID:      userID,
+			Statuses:    filter.statuses,
+			ActiveTo:    filter.activeTo,
+			ActiveFrom:  filter.activeFrom,
+			MinBudget:   filter.minBudget,
+			MaxBudget:   filter.maxBudget,
+			Search:      filter.search,
+			TagIds:      filter.tagIDs,
+			TagMatchAll: filter.tagMatchAll,
+			RowLimit:    maxBulkItems + 1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) > maxBulkItems {
+			return nil, fmt.Errorf("%w: filter matches more than %d campaigns", ErrInvalidBulkRequest, maxBulkItems)
+		}
+		return ids, nil
+	}
+
+	for _, raw := range req.IDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid id %q", ErrInvalidBulkRequest, raw)
+		}
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+	return slices.Compact(ids), nil
+}
+
+// bulkApply handles one campaign. Item-level problems become the item's
+// result; only unexpected database errors are returned.
+func (s *CampaignService) bulkApply(ctx context.Context, q *db.Queries, userID uuid.UUID, id uuid.UUID, req dto.BulkCampaignRequest, tagIDs []uuid.UUID) (dto.BulkItemResult, error) {
+	item := dto.BulkItemResult{ID: id.String()}
+
+	before, err := q.GetCampaignForUpdate(ctx, db.GetCampaignForUpdateParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			item.Result = BulkResultNotFound
+			return item, nil
+		}
+		return item, err
+	}
+
+	if req.Action == BulkActionDelete {
+		item.Result = BulkResultDeleted
+		if req.DryRun {
+			return item, nil
+		}
+		deleted, err := q.DeleteCampaign(ctx, db.DeleteCampaignParams{
+			ID:     id,
+			UserID: userID,
+		})
+		if err != nil {
+			return item, err
+		}
+		return item, recordRevision(ctx, q, userID, RevisionDeleted, deleted, nil, nil)
+	}
+
+	if req.Action == BulkActionAddTag || req.Action == BulkActionRemoveTag {
+		return bulkApplyTags(ctx, q, userID, id, req, tagIDs, item)
+	}
+
+	target := snapshotOf(before)
+	if req.Action == BulkActionUpdateStatus {
+		target.Status = req.Status
+	} else {
+		if req.Fields.Description != nil {
+			target.Description = req.Fields.Description
+		}
+		if req.Fields.StartDate != nil {
+			target.StartDate = req.Fields.StartDate
+		}
+		if req.Fields.EndDate != nil {
+			target.EndDate = req.Fields.EndDate
+		}
+		if req.Fields.Budget != nil {
+			target.Budget = *req.Fields.Budget
+		}
+	}
+	if err := validateSnapshot(target); err != nil {
+		item.Result = BulkResultInvalid
+		item.Error = err.Error()
+		return item, nil
+	}
+	if err := checkChannelBudget(ctx, q, id, target.Budget); err != nil {
+		if !errors.Is(err, ErrInvalidCampaign) {
+			return item, err
+		}
+		item.Result = BulkResultInvalid
+		item.Error = err.Error()
+		return item, nil
+	}
+	if err := checkLaunch(ctx, q, userID, &before, target); err != nil {
+		if !errors.Is(err, ErrApprovalRequired) {
+			return item, err
+		}
+		item.Result = BulkResultApprovalRequired
+		item.Error = err.Error()
+		return item, nil
+	}
+
+	item.Changes = diffSnapshots(snapshotOf(before), target)
+	if len(item.Changes) == 0 {
+		item.Result = BulkResultUnchanged
+		return item, nil
+	}
+	item.Result = BulkResultUpdated
+	if req.DryRun {
+		return item, nil
+	}
+
+	campaign, err := q.SetCampaignFields(ctx, db.SetCampaignFieldsParams{
+		ID:          id,
+		UserID:      userID,
+		Title:       target.Title,
+		Description: target.Description,
+		Status:      target.Status,
+		StartDate:   utils.ToPgTimestamp(target.StartDate),
+		EndDate:     utils.ToPgTimestamp(target.EndDate),
+		Budget:      target.Budget,
+	})
+	if err != nil {
+		return item, err
+	}
+	return item, recordRevision(ctx, q, userID, RevisionUpdated, campaign, item.Changes, nil)
+}
+
+// bulkApplyTags adds or removes tags on one campaign. Like a field update it
+// moves the campaign version only when the tag set actually changes.
+func bulkApplyTags(ctx context.Context, q *db.Queries, userID uuid.UUID, id uuid.UUID, req dto.BulkCampaignRequest, tagIDs []uuid.UUID, item dto.BulkItemResult) (dto.BulkItemResult, error) {
+	present, err := q.CountCampaignTagsIn(ctx, db.CountCampaignTagsInParams{
+		CampaignID: id,
+		TagIds:     tagIDs,
+	})
+	if err != nil {
+		return item, err
+	}
+	changed := present > 0
+	if req.Action == BulkActionAddTag {
+		changed = present < int64(len(tagIDs))
+	}
+	if !changed {
+		item.Result = BulkResultUnchanged
+		return item, nil
+	}
+	item.Result = BulkResultUpdated
+	if req.DryRun {
+		return item, nil
+	}
+
+	if req.Action == BulkActionAddTag {
+		_, err = q.AddCampaignTags(ctx, db.AddCampaignTagsParams{
+			CampaignID: id,
+			TagIds:     tagIDs,
+		})
+	} else {
+		_, err = q.RemoveCampaignTags(ctx, db.RemoveCampaignTagsParams{
+			CampaignID: id,
+			TagIds:     tagIDs,
+		})
+	}
+	if err != nil {
+		return item, err
+	}
+	_, err = q.TouchCampaign(ctx, db.TouchCampaignParams{
+		ID:     id,
+		UserID: userID,
+	})
+	return item, err
+}
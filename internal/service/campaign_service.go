@@ -1,30 +1,81 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/valenrio66/be-project/pkg/jsonpatch"
+	"github.com/valenrio66/be-project/pkg/pagination"
 	"github.com/valenrio66/be-project/pkg/utils"
 
+	"github.com/valenrio66/be-project/config"
 	"github.com/valenrio66/be-project/internal/db"
 	"github.com/valenrio66/be-project/internal/dto"
 )
 
 var (
-	ErrCampaignNotFound = errors.New("campaign not found")
+	ErrCampaignNotFound      = errors.New("campaign not found")
+	ErrInvalidCampaignFilter = errors.New("invalid campaign filter")
+	ErrRevisionNotFound      = errors.New("revision not found")
+	ErrVersionConflict       = errors.New("campaign was modified by another request")
+	ErrInvalidCampaign       = errors.New("invalid campaign")
+	ErrInvalidPatch          = errors.New("invalid patch")
+	ErrPatchTestFailed       = errors.New("patch test operation failed")
+)
+
+// Patch document formats accepted by PatchCampaign.
+const (
+	PatchTypeMerge = "merge" // RFC 7396, application/merge-patch+json
+	PatchTypeJSON  = "json"  // RFC 6902, application/json-patch+json
+)
+
+const maxCampaignTitleLength = 255
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// campaignFilter is a validated ListCampaignsRequest in query parameter form.
+type campaignFilter struct {
+	statuses   []string
+	activeFrom pgtype.Timestamptz
+	activeTo   pgtype.Timestamptz
+	minBudget  *float64
+	maxBudget  *float64
+	search     *string
+	tagIDs     []uuid.UUID
+	// tagMatchAll requires every tag in tagIDs instead of any of them.
+	tagMatchAll bool
+	sortBy      string
+	sortOrder   string
+}
+
+const (
+	trashSortBy   = "deleted_at"
+	historySortBy = "revision"
 )
 
 type CampaignService struct {
-	queries *db.Queries
+	dbPool         *pgxpool.Pool
+	queries        *db.Queries
+	trashRetention time.Duration
 }
 
-func NewCampaignService(queries *db.Queries) *CampaignService {
+func NewCampaignService(dbPool *pgxpool.Pool, queries *db.Queries, cfg config.Config) *CampaignService {
 	return &CampaignService{
-		queries: queries,
+		dbPool:         dbPool,
+		queries:        queries,
+		trashRetention: time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour,
 	}
 }
 
@@ -33,43 +84,183 @@ func (s *CampaignService) CreateCampaign(ctx context.Context, userID uuid.UUID,
 		UserID:      userID,
 		Title:       req.Title,
 		Description: utils.StringToPtr(req.Description),
-		Status:      "draft",
+		Status:      utils.CampaignStatusDraft,
 		StartDate:   pgtype.Timestamptz{Time: req.StartDate, Valid: true},
 		EndDate:     pgtype.Timestamptz{Time: req.EndDate, Valid: true},
 		Budget:      req.Budget,
 	}
+	tagIDs, err := parseTagIDs(req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.createCampaign(ctx, userID, arg, tagIDs, nil)
+}
 
-	campaign, err := s.queries.CreateCampaign(ctx, arg)
+// createCampaign inserts a campaign with its tags and segments together with
+// its first revision.
+func (s *CampaignService) createCampaign(ctx context.Context, userID uuid.UUID, arg db.CreateCampaignParams, tagIDs []uuid.UUID, segmentIDs []uuid.UUID) (*dto.CampaignResponse, error) {
+	var campaign db.Campaign
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		if err := ensureTagsExist(ctx, q, tagIDs); err != nil {
+			return err
+		}
+		var err error
+		campaign, err = q.CreateCampaign(ctx, arg)
+		if err != nil {
+			return err
+		}
+		if len(tagIDs) > 0 {
+			if _, err := q.AddCampaignTags(ctx, db.AddCampaignTagsParams{
+				CampaignID: campaign.ID,
+				TagIds:     tagIDs,
+			}); err != nil {
+				return err
+			}
+		}
+		if len(segmentIDs) > 0 {
+			if _, err := q.AddCampaignSegments(ctx, db.AddCampaignSegmentsParams{
+				CampaignID: campaign.ID,
+				SegmentIds: segmentIDs,
+			}); err != nil {
+				return err
+			}
+		}
+		return recordRevision(ctx, q, userID, RevisionCreated, campaign, nil, nil)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &dto.CampaignResponse{
-		ID:          campaign.ID.String(),
-		UserID:      campaign.UserID.String(),
-		Title:       campaign.Title,
-		Description: utils.PtrToString(campaign.Description),
-		Status:      campaign.Status,
-		Budget:      campaign.Budget,
-		CreatedAt:   campaign.CreatedAt.Time,
-	}, nil
+	return s.campaignResponse(ctx, campaign)
 }
 
-func (s *CampaignService) ListCampaigns(ctx context.Context, userID uuid.UUID, page, limit int) ([]dto.CampaignResponse, error) {
-	offset := (page - 1) * limit
+// CloneCampaign creates a copy of a campaign, optionally moved in time. The
+// copy keeps the original duration, budget, description, tags and segments.
+func (s *CampaignService) CloneCampaign(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.CloneCampaignRequest) (*dto.CampaignResponse, error) {
+	if req.ShiftDays != nil && req.StartDate != nil {
+		return nil, fmt.Errorf("%w: use either shift_days or start_date", ErrInvalidCampaign)
+	}
 
-	arg := db.ListCampaignsParams{
+	source, err := s.queries.GetCampaign(ctx, db.GetCampaignParams{
+		ID:     campaignID,
 		UserID: userID,
-		Limit:  int32(limit),
-		Offset: int32(offset),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	clone := snapshotOf(source)
+	clone.Title = source.Title + " (copy)"
+	if req.Title != nil {
+		clone.Title = *req.Title
+	}
+	if req.ResetStatus == nil || *req.ResetStatus {
+		clone.Status = utils.CampaignStatusDraft
+	}
+
+	shift := func(t time.Time) time.Time { return t }
+	switch {
+	case req.ShiftDays != nil:
+		days := *req.ShiftDays
+		shift = func(t time.Time) time.Time { return t.AddDate(0, 0, days) }
+	case req.StartDate != nil:
+		if clone.StartDate == nil {
+			return nil, fmt.Errorf("%w: campaign has no start_date to shift from", ErrInvalidCampaign)
+		}
+		delta := req.StartDate.Sub(*clone.StartDate)
+		shift = func(t time.Time) time.Time { return t.Add(delta) }
+	}
+	if clone.StartDate != nil {
+		start := shift(*clone.StartDate)
+		clone.StartDate = &start
+	}
+	if clone.EndDate != nil {
+		end := shift(*clone.EndDate)
+		clone.EndDate = &end
+	}
+
+	if err := validateSnapshot(clone); err != nil {
+		return nil, err
+	}
+	// Approvals belong to the source campaign, so a clone that keeps an
+	// active status needs its own when a policy covers its budget.
+	if err := checkLaunch(ctx, s.queries, userID, nil, clone); err != nil {
+		return nil, err
+	}
+
+	sourceTags, err := s.queries.ListCampaignTags(ctx, []uuid.UUID{source.ID})
+	if err != nil {
+		return nil, err
+	}
+	tagIDs := make([]uuid.UUID, 0, len(sourceTags))
+	for _, t := range sourceTags {
+		tagIDs = append(tagIDs, t.ID)
+	}
+	sourceSegments, err := s.queries.ListCampaignSegments(ctx, []uuid.UUID{source.ID})
+	if err != nil {
+		return nil, err
+	}
+	segmentIDs := make([]uuid.UUID, 0, len(sourceSegments))
+	for _, seg := range sourceSegments {
+		segmentIDs = append(segmentIDs, seg.ID)
+	}
+
+	return s.createCampaign(ctx, userID, db.CreateCampaignParams{
+		UserID:      userID,
+		Title:       clone.Title,
+		Description: clone.Description,
+		Status:      clone.Status,
+		StartDate:   utils.ToPgTimestamp(clone.StartDate),
+		EndDate:     utils.ToPgTimestamp(clone.EndDate),
+		Budget:      clone.Budget,
+	}, tagIDs, segmentIDs)
+}
+
+func (s *CampaignService) ListCampaigns(ctx context.Context, userID uuid.UUID, req dto.ListCampaignsRequest) (*dto.PageResponse, error) {
+	filter, err := buildCampaignFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := decodeCursor(req.Cursor, filter.sortBy, filter.sortOrder)
+	if err != nil {
+		return nil, err
+	}
+	limit := pagination.Limit(req.Limit)
+
+	arg := db.ListCampaignsParams{
+		UserID:      userID,
+		Statuses:    filter.statuses,
+		ActiveTo:    filter.activeTo,
+		ActiveFrom:  filter.activeFrom,
+		MinBudget:   filter.minBudget,
+		MaxBudget:   filter.maxBudget,
+		Search:      filter.search,
+		TagIds:      filter.tagIDs,
+		TagMatchAll: filter.tagMatchAll,
+		SortBy:      filter.sortBy,
+		SortOrder:   filter.sortOrder,
+		ScanOrder:   pagination.ScanOrder(filter.sortOrder, cur),
+		RowLimit:    int32(limit + 1),
+	}
+	if cur != nil {
+		arg.CursorID = pgtype.UUID{Bytes: cur.ID, Valid: true}
+		arg.CursorTime = pgtype.Timestamptz{Time: cur.Time, Valid: true}
+		arg.CursorNumber = cur.Number
+		arg.CursorText = cur.Text
 	}
 
 	campaigns, err := s.queries.ListCampaigns(ctx, arg)
 	if err != nil {
 		return nil, err
 	}
+	campaigns, hasNext, hasPrev := pagination.Trim(campaigns, limit, cur)
 
-	var responses []dto.CampaignResponse
+	responses := make([]dto.CampaignResponse, 0, len(campaigns))
 	for _, c := range campaigns {
 		responses = append(responses, dto.CampaignResponse{
 			ID:          c.ID.String(),
@@ -80,11 +271,63 @@ func (s *CampaignService) ListCampaigns(ctx context.Context, userID uuid.UUID, p
 			StartDate:   c.StartDate.Time,
 			EndDate:     c.EndDate.Time,
 			Budget:      c.Budget,
+			Version:     c.Version,
 			CreatedAt:   c.CreatedAt.Time,
 		})
 	}
+	tagged := make([]*dto.CampaignResponse, len(responses))
+	for i := range responses {
+		tagged[i] = &responses[i]
+	}
+	if err := attachCampaignDetails(ctx, s.queries, tagged...); err != nil {
+		return nil, err
+	}
+
+	var next, prev *pagination.Cursor
+	if len(campaigns) > 0 {
+		if hasNext {
+			next = campaignCursor(filter, campaigns[len(campaigns)-1], false)
+		}
+		if hasPrev {
+			prev = campaignCursor(filter, campaigns[0], true)
+		}
+	}
+	page := newPageInfo(limit, next, prev)
 
-	return responses, nil
+	if req.IncludeTotal {
+		total, err := s.queries.CountCampaigns(ctx, db.CountCampaignsParams{
+			UserID:      userID,
+			Statuses:    filter.statuses,
+			ActiveTo:    filter.activeTo,
+			ActiveFrom:  filter.activeFrom,
+			MinBudget:   filter.minBudget,
+			MaxBudget:   filter.maxBudget,
+			Search:      filter.search,
+			TagIds:      filter.tagIDs,
+			TagMatchAll: filter.tagMatchAll,
+		})
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return &dto.PageResponse{
+		Items: responses,
+		Page:  page,
+	}, nil
+}
+
+func campaignCursor(filter campaignFilter, row db.ListCampaignsRow, backward bool) *pagination.Cursor {
+	return &pagination.Cursor{
+		SortBy:    filter.sortBy,
+		SortOrder: filter.sortOrder,
+		Backward:  backward,
+		Time:      row.SortTime.Time,
+		Number:    row.SortNumber,
+		Text:      row.SortText,
+		ID:        row.ID,
+	}
 }
 
 func (s *CampaignService) GetCampaign(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID) (*dto.CampaignResponse, error) {
@@ -99,35 +342,12 @@ func (s *CampaignService) GetCampaign(ctx context.Context, userID uuid.UUID, cam
 		return nil, err
 	}
 
-	var startDate, endDate time.Time
-	if campaign.StartDate.Valid {
-		startDate = campaign.StartDate.Time
-	}
-	if campaign.EndDate.Valid {
-		endDate = campaign.EndDate.Time
-	}
-
-	return &dto.CampaignResponse{
-		ID:          campaign.ID.String(),
-		UserID:      campaign.UserID.String(),
-		Title:       campaign.Title,
-		Description: utils.PtrToString(campaign.Description),
-		Status:      campaign.Status,
-		Budget:      campaign.Budget,
-		StartDate:   startDate,
-		EndDate:     endDate,
-		CreatedAt:   campaign.CreatedAt.Time,
-	}, nil
+	return s.campaignResponse(ctx, campaign)
 }
 
-func (s *CampaignService) UpdateCampaign(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.UpdateCampaignRequest) (*dto.CampaignResponse, error) {
-	var budget pgtype.Numeric
-	if req.Budget != nil {
-		if err := budget.Scan(*req.Budget); err != nil {
-			return nil, err
-		}
-	}
-
+// UpdateCampaign applies req when the stored version is one of ifMatch; a nil
+// ifMatch ("If-Match: *") accepts any version.
+func (s *CampaignService) UpdateCampaign(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, ifMatch []int32, req dto.UpdateCampaignRequest) (*dto.CampaignResponse, error) {
 	arg := db.UpdateCampaignParams{
 		ID:          campaignID,
 		UserID:      userID,
@@ -136,10 +356,121 @@ func (s *CampaignService) UpdateCampaign(ctx context.Context, userID uuid.UUID,
 		Status:      req.Status,
 		StartDate:   utils.ToPgTimestamp(req.StartDate),
 		EndDate:     utils.ToPgTimestamp(req.EndDate),
-		Budget:      budget,
+		Budget:      req.Budget,
+	}
+
+	var campaign db.Campaign
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		before, err := q.GetCampaignForUpdate(ctx, db.GetCampaignForUpdateParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		if !versionMatches(before.Version, ifMatch) {
+			return ErrVersionConflict
+		}
+
+		campaign, err = q.UpdateCampaign(ctx, arg)
+		if err != nil {
+			return err
+		}
+		if err := validateSnapshot(snapshotOf(campaign)); err != nil {
+			return err
+		}
+		if err := checkChannelBudget(ctx, q, campaignID, campaign.Budget); err != nil {
+			return err
+		}
+		if err := checkLaunch(ctx, q, userID, &before, snapshotOf(campaign)); err != nil {
+			return err
+		}
+
+		changes := diffSnapshots(snapshotOf(before), snapshotOf(campaign))
+		if len(changes) == 0 {
+			return nil
+		}
+		return recordRevision(ctx, q, userID, RevisionUpdated, campaign, changes, nil)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
 	}
 
-	campaign, err := s.queries.UpdateCampaign(ctx, arg)
+	return s.campaignResponse(ctx, campaign)
+}
+
+// PatchCampaign applies a merge patch or JSON Patch to the editable fields of
+// a campaign. The patched document is validated as a whole, so cross-field
+// rules hold for the result and not just for the fields in the patch.
+func (s *CampaignService) PatchCampaign(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, ifMatch []int32, patchType string, patch []byte) (*dto.CampaignResponse, error) {
+	var campaign db.Campaign
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		before, err := q.GetCampaignForUpdate(ctx, db.GetCampaignForUpdateParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		if !versionMatches(before.Version, ifMatch) {
+			return ErrVersionConflict
+		}
+
+		doc, err := json.Marshal(snapshotOf(before))
+		if err != nil {
+			return err
+		}
+
+		var patched []byte
+		if patchType == PatchTypeJSON {
+			patched, err = jsonpatch.Apply(doc, patch)
+		} else {
+			patched, err = jsonpatch.MergePatch(doc, patch)
+		}
+		if err != nil {
+			if errors.Is(err, jsonpatch.ErrTestFailed) {
+				return fmt.Errorf("%w: %v", ErrPatchTestFailed, err)
+			}
+			return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+
+		target, err := decodeSnapshot(patched)
+		if err != nil {
+			return err
+		}
+		if err := validateSnapshot(target); err != nil {
+			return err
+		}
+		if err := checkChannelBudget(ctx, q, campaignID, target.Budget); err != nil {
+			return err
+		}
+		if err := checkLaunch(ctx, q, userID, &before, target); err != nil {
+			return err
+		}
+
+		campaign, err = q.SetCampaignFields(ctx, db.SetCampaignFieldsParams{
+			ID:          campaignID,
+			UserID:      userID,
+			Title:       target.Title,
+			Description: target.Description,
+			Status:      target.Status,
+			StartDate:   utils.ToPgTimestamp(target.StartDate),
+			EndDate:     utils.ToPgTimestamp(target.EndDate),
+			Budget:      target.Budget,
+		})
+		if err != nil {
+			return err
+		}
+
+		changes := diffSnapshots(snapshotOf(before), snapshotOf(campaign))
+		if len(changes) == 0 {
+			return nil
+		}
+		return recordRevision(ctx, q, userID, RevisionUpdated, campaign, changes, nil)
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrCampaignNotFound
@@ -147,32 +478,435 @@ func (s *CampaignService) UpdateCampaign(ctx context.Context, userID uuid.UUID,
 		return nil, err
 	}
 
+	return s.campaignResponse(ctx, campaign)
+}
+
+// decodeSnapshot reads a patched campaign document, rejecting fields that do
+// not exist or cannot be edited. The budget cannot be removed or nulled: it
+// would otherwise decode as zero and silently clear the budget.
+func decodeSnapshot(raw []byte) (dto.CampaignSnapshot, error) {
+	var snap dto.CampaignSnapshot
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&snap); err != nil {
+		return snap, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return snap, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	if budget, ok := fields["budget"]; !ok || string(bytes.TrimSpace(budget)) == "null" {
+		return snap, fmt.Errorf("%w: budget cannot be removed or null", ErrInvalidPatch)
+	}
+	return snap, nil
+}
+
+func validateSnapshot(snap dto.CampaignSnapshot) error {
+	if strings.TrimSpace(snap.Title) == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidCampaign)
+	}
+	if utf8.RuneCountInString(snap.Title) > maxCampaignTitleLength {
+		return fmt.Errorf("%w: title must be at most %d characters", ErrInvalidCampaign, maxCampaignTitleLength)
+	}
+	if !slices.Contains(utils.CampaignStatuses, snap.Status) {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidCampaign, snap.Status)
+	}
+	if math.IsNaN(snap.Budget) || math.IsInf(snap.Budget, 0) {
+		return fmt.Errorf("%w: budget must be a finite number", ErrInvalidCampaign)
+	}
+	if snap.Budget < 0 {
+		return fmt.Errorf("%w: budget must not be negative", ErrInvalidCampaign)
+	}
+	if snap.StartDate != nil && snap.EndDate != nil && !snap.EndDate.After(*snap.StartDate) {
+		return fmt.Errorf("%w: end_date must be after start_date", ErrInvalidCampaign)
+	}
+	return nil
+}
+
+// DeleteCampaign moves a campaign to the trash. It stays restorable until the
+// purge job removes it after the retention period.
+func (s *CampaignService) DeleteCampaign(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, ifMatch []int32) error {
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		current, err := q.GetCampaignForUpdate(ctx, db.GetCampaignForUpdateParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		if !versionMatches(current.Version, ifMatch) {
+			return ErrVersionConflict
+		}
+
+		campaign, err := q.DeleteCampaign(ctx, db.DeleteCampaignParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		return recordRevision(ctx, q, userID, RevisionDeleted, campaign, nil, nil)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCampaignNotFound
+	}
+	return err
+}
+
+func (s *CampaignService) ListTrash(ctx context.Context, userID uuid.UUID, req dto.PageRequest) (*dto.PageResponse, error) {
+	cur, err := decodeCursor(req.Cursor, trashSortBy, pagination.OrderDesc)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil && cur.Backward {
+		return nil, ErrInvalidCursor
+	}
+	limit := pagination.Limit(req.Limit)
+
+	arg := db.ListDeletedCampaignsParams{
+		UserID:   userID,
+		RowLimit: int32(limit + 1),
+	}
+	if cur != nil {
+		arg.CursorID = pgtype.UUID{Bytes: cur.ID, Valid: true}
+		arg.CursorTime = pgtype.Timestamptz{Time: cur.Time, Valid: true}
+	}
+
+	campaigns, err := s.queries.ListDeletedCampaigns(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	campaigns, hasNext, _ := pagination.Trim(campaigns, limit, cur)
+
+	responses := make([]dto.TrashedCampaignResponse, 0, len(campaigns))
+	for _, c := range campaigns {
+		responses = append(responses, dto.TrashedCampaignResponse{
+			CampaignResponse: toCampaignResponse(c),
+			DeletedAt:        c.DeletedAt.Time,
+			PurgeAt:          c.DeletedAt.Time.Add(s.trashRetention),
+		})
+	}
+	tagged := make([]*dto.CampaignResponse, len(responses))
+	for i := range responses {
+		tagged[i] = &responses[i].CampaignResponse
+	}
+	if err := attachCampaignDetails(ctx, s.queries, tagged...); err != nil {
+		return nil, err
+	}
+
+	// The trash only pages forward, newest deletions first.
+	var next *pagination.Cursor
+	if hasNext && len(campaigns) > 0 {
+		last := campaigns[len(campaigns)-1]
+		next = &pagination.Cursor{
+			SortBy:    trashSortBy,
+			SortOrder: pagination.OrderDesc,
+			Time:      last.DeletedAt.Time,
+			ID:        last.ID,
+		}
+	}
+	page := newPageInfo(limit, next, nil)
+
+	if req.IncludeTotal {
+		total, err := s.queries.CountDeletedCampaigns(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return &dto.PageResponse{
+		Items: responses,
+		Page:  page,
+	}, nil
+}
+
+func (s *CampaignService) RestoreCampaign(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID) (*dto.CampaignResponse, error) {
+	var campaign db.Campaign
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		var err error
+		campaign, err = q.RestoreCampaign(ctx, db.RestoreCampaignParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		return recordRevision(ctx, q, userID, RevisionRestored, campaign, nil, nil)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	return s.campaignResponse(ctx, campaign)
+}
+
+func (s *CampaignService) ListHistory(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.PageRequest) (*dto.PageResponse, error) {
+	if _, err := s.GetCampaign(ctx, userID, campaignID); err != nil {
+		return nil, err
+	}
+
+	cur, err := decodeCursor(req.Cursor, historySortBy, pagination.OrderDesc)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil && cur.Backward {
+		return nil, ErrInvalidCursor
+	}
+	limit := pagination.Limit(req.Limit)
+
+	arg := db.ListCampaignRevisionsParams{
+		CampaignID: campaignID,
+		RowLimit:   int32(limit + 1),
+	}
+	if cur != nil {
+		arg.CursorID = pgtype.UUID{Bytes: cur.ID, Valid: true}
+		arg.CursorRevision = int32(cur.Number)
+	}
+
+	revisions, err := s.queries.ListCampaignRevisions(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	revisions, hasNext, _ := pagination.Trim(revisions, limit, cur)
+
+	responses := make([]dto.CampaignRevisionResponse, 0, len(revisions))
+	for _, r := range revisions {
+		res, err := toRevisionResponse(r)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, res)
+	}
+
+	var next *pagination.Cursor
+	if hasNext && len(revisions) > 0 {
+		last := revisions[len(revisions)-1]
+		next = &pagination.Cursor{
+			SortBy:    historySortBy,
+			SortOrder: pagination.OrderDesc,
+			Number:    float64(last.Revision),
+			ID:        last.ID,
+		}
+	}
+	page := newPageInfo(limit, next, nil)
+
+	if req.IncludeTotal {
+		total, err := s.queries.CountCampaignRevisions(ctx, campaignID)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return &dto.PageResponse{
+		Items: responses,
+		Page:  page,
+	}, nil
+}
+
+// RevertCampaign restores the editable fields stored with an earlier revision.
+// The revert is itself recorded as a new revision, so it can be undone.
+func (s *CampaignService) RevertCampaign(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.RevertCampaignRequest) (*dto.CampaignResponse, error) {
+	var campaign db.Campaign
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		before, err := q.GetCampaignForUpdate(ctx, db.GetCampaignForUpdateParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrCampaignNotFound
+			}
+			return err
+		}
+
+		revision, err := q.GetCampaignRevision(ctx, db.GetCampaignRevisionParams{
+			CampaignID: campaignID,
+			Revision:   req.Revision,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrRevisionNotFound
+			}
+			return err
+		}
+
+		var target dto.CampaignSnapshot
+		if err := json.Unmarshal(revision.Snapshot, &target); err != nil {
+			return err
+		}
+		// Old revisions predate some of today's rules.
+		if err := validateSnapshot(target); err != nil {
+			return err
+		}
+		if err := checkChannelBudget(ctx, q, campaignID, target.Budget); err != nil {
+			return err
+		}
+		if err := checkLaunch(ctx, q, userID, &before, target); err != nil {
+			return err
+		}
+
+		campaign, err = q.SetCampaignFields(ctx, db.SetCampaignFieldsParams{
+			ID:          campaignID,
+			UserID:      userID,
+			Title:       target.Title,
+			Description: target.Description,
+			Status:      target.Status,
+			StartDate:   utils.ToPgTimestamp(target.StartDate),
+			EndDate:     utils.ToPgTimestamp(target.EndDate),
+			Budget:      target.Budget,
+		})
+		if err != nil {
+			return err
+		}
+
+		changes := diffSnapshots(snapshotOf(before), snapshotOf(campaign))
+		return recordRevision(ctx, q, userID, RevisionReverted, campaign, changes, &revision.Revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.campaignResponse(ctx, campaign)
+}
+
+// PurgeTrash permanently deletes campaigns that have been in the trash for
+// longer than the retention period and reports how many were removed.
+func (s *CampaignService) PurgeTrash(ctx context.Context, now time.Time) (int64, error) {
+	cutoff := pgtype.Timestamptz{Time: now.Add(-s.trashRetention), Valid: true}
+	return s.queries.PurgeDeletedCampaigns(ctx, cutoff)
+}
+
+func versionMatches(version int32, ifMatch []int32) bool {
+	return ifMatch == nil || slices.Contains(ifMatch, version)
+}
+
+// campaignResponse converts a campaign and loads its tags.
+func (s *CampaignService) campaignResponse(ctx context.Context, c db.Campaign) (*dto.CampaignResponse, error) {
+	res := toCampaignResponse(c)
+	if err := attachCampaignDetails(ctx, s.queries, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// attachCampaignDetails fills in the tags and comment counts of campaign
+// responses, with one query each for the whole batch.
+func attachCampaignDetails(ctx context.Context, q *db.Queries, responses ...*dto.CampaignResponse) error {
+	if err := attachCampaignTags(ctx, q, responses...); err != nil {
+		return err
+	}
+	if err := attachCampaignSegments(ctx, q, responses...); err != nil {
+		return err
+	}
+	return attachCommentCounts(ctx, q, responses...)
+}
+
+func toCampaignResponse(c db.Campaign) dto.CampaignResponse {
 	var startDate, endDate time.Time
-	if campaign.StartDate.Valid {
-		startDate = campaign.StartDate.Time
+	if c.StartDate.Valid {
+		startDate = c.StartDate.Time
 	}
-	if campaign.EndDate.Valid {
-		endDate = campaign.EndDate.Time
+	if c.EndDate.Valid {
+		endDate = c.EndDate.Time
 	}
 
-	return &dto.CampaignResponse{
-		ID:          campaign.ID.String(),
-		UserID:      campaign.UserID.String(),
-		Title:       campaign.Title,
-		Description: utils.PtrToString(campaign.Description),
-		Status:      campaign.Status,
-		Budget:      campaign.Budget,
+	return dto.CampaignResponse{
+		ID:          c.ID.String(),
+		UserID:      c.UserID.String(),
+		Title:       c.Title,
+		Description: utils.PtrToString(c.Description),
+		Status:      c.Status,
+		Budget:      c.Budget,
 		StartDate:   startDate,
 		EndDate:     endDate,
-		CreatedAt:   campaign.CreatedAt.Time,
-	}, nil
+		Version:     c.Version,
+		Tags:        []dto.TagSummary{},
+		Segments:    []dto.SegmentSummary{},
+		CreatedAt:   c.CreatedAt.Time,
+	}
 }
 
-func (s *CampaignService) DeleteCampaign(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID) error {
-	err := s.queries.DeleteCampaign(ctx, db.DeleteCampaignParams{
-		ID:     campaignID,
-		UserID: userID,
-	})
+func buildCampaignFilter(req dto.ListCampaignsRequest) (campaignFilter, error) {
+	filter := campaignFilter{
+		statuses:  []string{},
+		minBudget: req.MinBudget,
+		maxBudget: req.MaxBudget,
+		sortBy:    req.SortBy,
+		sortOrder: req.SortOrder,
+	}
+	if filter.sortBy == "" {
+		filter.sortBy = "created_at"
+	}
+	if filter.sortOrder == "" {
+		filter.sortOrder = pagination.OrderDesc
+	}
 
-	return err
+	for _, value := range req.Status {
+		for _, status := range strings.Split(value, ",") {
+			status = strings.TrimSpace(status)
+			if status == "" {
+				continue
+			}
+			if !slices.Contains(utils.CampaignStatuses, status) {
+				return filter, fmt.Errorf("%w: unknown status %q", ErrInvalidCampaignFilter, status)
+			}
+			filter.statuses = append(filter.statuses, status)
+		}
+	}
+
+	if req.From != "" {
+		from, _, err := parseTimeParam(req.From, time.UTC)
+		if err != nil {
+			return filter, fmt.Errorf("%w: from must be YYYY-MM-DD or RFC3339", ErrInvalidCampaignFilter)
+		}
+		filter.activeFrom = pgtype.Timestamptz{Time: from, Valid: true}
+	}
+	if req.To != "" {
+		to, dateOnly, err := parseTimeParam(req.To, time.UTC)
+		if err != nil {
+			return filter, fmt.Errorf("%w: to must be YYYY-MM-DD or RFC3339", ErrInvalidCampaignFilter)
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		filter.activeTo = pgtype.Timestamptz{Time: to, Valid: true}
+	}
+	if filter.activeFrom.Valid && filter.activeTo.Valid && filter.activeTo.Time.Before(filter.activeFrom.Time) {
+		return filter, fmt.Errorf("%w: to must not be before from", ErrInvalidCampaignFilter)
+	}
+
+	if req.MinBudget != nil && req.MaxBudget != nil && *req.MinBudget > *req.MaxBudget {
+		return filter, fmt.Errorf("%w: min_budget must not exceed max_budget", ErrInvalidCampaignFilter)
+	}
+
+	if q := strings.TrimSpace(req.Query); q != "" {
+		pattern := "%" + likeEscaper.Replace(q) + "%"
+		filter.search = &pattern
+	}
+
+	filter.tagIDs = []uuid.UUID{}
+	for _, value := range req.Tags {
+		for _, raw := range strings.Split(value, ",") {
+			raw = strings.TrimSpace(raw)
+			if raw == "" {
+				continue
+			}
+			tagID, err := uuid.Parse(raw)
+			if err != nil {
+				return filter, fmt.Errorf("%w: tag must be a tag ID, got %q", ErrInvalidCampaignFilter, raw)
+			}
+			if !slices.Contains(filter.tagIDs, tagID) {
+				filter.tagIDs = append(filter.tagIDs, tagID)
+			}
+		}
+	}
+	filter.tagMatchAll = req.TagMatch == TagMatchAll
+
+	return filter, nil
 }
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+)
+
+var (
+	ErrChannelNotFound = errors.New("channel not found")
+	ErrInvalidChannels = errors.New("invalid channel allocation")
+	// ErrChannelHasMetrics is returned when a replacement would drop a
+	// channel that metrics were already recorded against.
+	ErrChannelHasMetrics = errors.New("channels with recorded metrics cannot be removed")
+)
+
+const ChannelPlanned = "planned"
+
+type ChannelService struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewChannelService(dbPool *pgxpool.Pool, queries *db.Queries) *ChannelService {
+	return &ChannelService{
+		dbPool:  dbPool,
+		queries: queries,
+	}
+}
+
+func (s *ChannelService) ListChannels(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID) (*dto.CampaignChannelsResponse, error) {
+	campaign, err := s.queries.GetCampaign(ctx, db.GetCampaignParams{
+		ID:     campaignID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+	return channelsResponse(ctx, s.queries, campaign)
+}
+
+// ReplaceChannels makes req.Channels the exact channel breakdown of a
+// campaign. Channels left out are removed unless they have metrics; a
+// channel without a status keeps its current one, or starts as planned.
+// The campaign version moves when anything changed, and a budget change is
+// recorded in the history like any other edit.
+func (s *ChannelService) ReplaceChannels(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, ifMatch []int32, req dto.ReplaceChannelsRequest) (*dto.CampaignChannelsResponse, error) {
+	names := make([]string, 0, len(req.Channels))
+	var allocated int64
+	for _, ch := range req.Channels {
+		if slices.Contains(names, ch.Channel) {
+			return nil, fmt.Errorf("%w: %s is listed more than once", ErrInvalidChannels, ch.Channel)
+		}
+		names = append(names, ch.Channel)
+		allocated += toCents(ch.AllocatedBudget)
+	}
+
+	var campaign db.Campaign
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		before, err := q.GetCampaignForUpdate(ctx, db.GetCampaignForUpdateParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		if !versionMatches(before.Version, ifMatch) {
+			return ErrVersionConflict
+		}
+
+		budget := before.Budget
+		if req.Budget != nil {
+			budget = *req.Budget
+		}
+		if len(req.Channels) > 0 && allocated != toCents(budget) {
+			return fmt.Errorf("%w: allocations add up to %.2f but the campaign budget is %.2f", ErrInvalidChannels, float64(allocated)/100, budget)
+		}
+
+		blocked, err := q.ListRemovedChannelsWithMetrics(ctx, db.ListRemovedChannelsWithMetricsParams{
+			CampaignID:   campaignID,
+			KeepChannels: names,
+		})
+		if err != nil {
+			return err
+		}
+		if len(blocked) > 0 {
+			return fmt.Errorf("%w: %s", ErrChannelHasMetrics, strings.Join(blocked, ", "))
+		}
+
+		existing, err := q.ListCampaignChannels(ctx, campaignID)
+		if err != nil {
+			return err
+		}
+		statuses := make(map[string]string, len(existing))
+		for _, ch := range existing {
+			statuses[ch.Channel] = ch.Status
+		}
+
+		removed, err := q.RemoveOtherCampaignChannels(ctx, db.RemoveOtherCampaignChannelsParams{
+			CampaignID:   campaignID,
+			KeepChannels: names,
+		})
+		if err != nil {
+			return err
+		}
+		changed := removed > 0
+
+		for _, ch := range req.Channels {
+			status := ch.Status
+			if status == "" {
+				status = cmp.Or(statuses[ch.Channel], ChannelPlanned)
+			}
+			_, err := q.UpsertCampaignChannel(ctx, db.UpsertCampaignChannelParams{
+				CampaignID:      campaignID,
+				Channel:         ch.Channel,
+				AllocatedBudget: ch.AllocatedBudget,
+				Status:          status,
+			})
+			switch {
+			case err == nil:
+				changed = true
+			case !errors.Is(err, pgx.ErrNoRows):
+				return err
+			}
+		}
+
+		campaign = before
+		if toCents(budget) != toCents(before.Budget) {
+			target := snapshotOf(before)
+			target.Budget = budget
+			if err := validateSnapshot(target); err != nil {
+				return err
+			}
+			if err := checkLaunch(ctx, q, userID, &before, target); err != nil {
+				return err
+			}
+			campaign, err = q.SetCampaignFields(ctx, db.SetCampaignFieldsParams{
+				ID:          campaignID,
+				UserID:      userID,
+				Title:       before.Title,
+				Description: before.Description,
+				Status:      before.Status,
+				StartDate:   before.StartDate,
+				EndDate:     before.EndDate,
+				Budget:      budget,
+			})
+			if err != nil {
+				return err
+			}
+			return recordRevision(ctx, q, userID, RevisionUpdated, campaign, diffSnapshots(snapshotOf(before), snapshotOf(campaign)), nil)
+		}
+		if changed {
+			campaign, err = q.TouchCampaign(ctx, db.TouchCampaignParams{
+				ID:     campaignID,
+				UserID: userID,
+			})
+		}
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	return channelsResponse(ctx, s.queries, campaign)
+}
+
+// UpdateChannel changes the status of one channel. Allocations only change
+// through ReplaceChannels, which keeps them adding up to the budget.
+func (s *ChannelService) UpdateChannel(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, channel string, req dto.UpdateChannelRequest) (*dto.CampaignChannelsResponse, error) {
+	var campaign db.Campaign
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		var err error
+		campaign, err = q.GetCampaignForUpdate(ctx, db.GetCampaignForUpdateParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrCampaignNotFound
+			}
+			return err
+		}
+
+		channels, err := q.ListCampaignChannels(ctx, campaignID)
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(channels, func(ch db.CampaignChannel) bool { return ch.Channel == channel })
+		if i < 0 {
+			return ErrChannelNotFound
+		}
+		if channels[i].Status == req.Status {
+			return nil
+		}
+
+		if _, err := q.UpdateCampaignChannelStatus(ctx, db.UpdateCampaignChannelStatusParams{
+			Status:     req.Status,
+			CampaignID: campaignID,
+			Channel:    channel,
+		}); err != nil {
+			return err
+		}
+		campaign, err = q.TouchCampaign(ctx, db.TouchCampaignParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return channelsResponse(ctx, s.queries, campaign)
+}
+
+// PortfolioChannels breaks down my current allocations and the metrics of
+// the requested window by channel, across all my campaigns.
+func (s *ChannelService) PortfolioChannels(ctx context.Context, userID uuid.UUID, req dto.ChannelAnalyticsRequest) (*dto.ChannelAnalyticsResponse, error) {
+	window, err := resolveAnalyticsWindow(dto.AnalyticsRequest{
+		From:     req.From,
+		To:       req.To,
+		Timezone: req.Timezone,
+	}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	allocations, err := s.queries.GetPortfolioChannelAllocations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.queries.GetPortfolioChannelTotals(ctx, db.GetPortfolioChannelTotalsParams{
+		UserID:   userID,
+		FromTime: pgtype.Timestamptz{Time: window.from, Valid: true},
+		ToTime:   pgtype.Timestamptz{Time: window.to, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byChannel := make(map[string]*dto.ChannelSpendResponse)
+	channel := func(name string) *dto.ChannelSpendResponse {
+		if byChannel[name] == nil {
+			byChannel[name] = &dto.ChannelSpendResponse{Channel: name, KPIs: buildKPIs(0, 0, 0, 0, 0)}
+		}
+		return byChannel[name]
+	}
+	for _, a := range allocations {
+		ch := channel(a.Channel)
+		ch.Campaigns = a.Campaigns
+		ch.Allocated = a.Allocated
+	}
+
+	var sum db.GetPortfolioChannelTotalsRow
+	res := &dto.ChannelAnalyticsResponse{
+		From:         window.from,
+		To:           window.to,
+		Timezone:     window.loc.String(),
+		Channels:     []dto.ChannelSpendResponse{},
+		Unattributed: buildKPIs(0, 0, 0, 0, 0),
+	}
+	for _, t := range totals {
+		kpis := buildKPIs(t.Impressions, t.Clicks, t.Conversions, t.Spend, t.Revenue)
+		if t.Channel == nil {
+			res.Unattributed = kpis
+		} else {
+			channel(*t.Channel).KPIs = kpis
+		}
+		sum.Impressions += t.Impressions
+		sum.Clicks += t.Clicks
+		sum.Conversions += t.Conversions
+		sum.Spend += t.Spend
+		sum.Revenue += t.Revenue
+	}
+	res.Totals = buildKPIs(sum.Impressions, sum.Clicks, sum.Conversions, sum.Spend, sum.Revenue)
+
+	for _, ch := range byChannel {
+		ch.SpendShare = ratio(ch.KPIs.Spend, sum.Spend)
+		res.Channels = append(res.Channels, *ch)
+	}
+	slices.SortFunc(res.Channels, func(a, b dto.ChannelSpendResponse) int {
+		return cmp.Or(cmp.Compare(b.KPIs.Spend, a.KPIs.Spend), cmp.Compare(b.Allocated, a.Allocated), cmp.Compare(a.Channel, b.Channel))
+	})
+	return res, nil
+}
+
+// checkChannelBudget keeps a campaign budget equal to its channel
+// allocations. Campaigns without channels may have any budget.
+func checkChannelBudget(ctx context.Context, q *db.Queries, campaignID uuid.UUID, budget float64) error {
+	alloc, err := q.GetCampaignChannelAllocation(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+	if alloc.Channels == 0 || toCents(alloc.Allocated) == toCents(budget) {
+		return nil
+	}
+	return fmt.Errorf("%w: budget must equal the %.2f allocated across channels; change both together through the channels endpoint", ErrInvalidCampaign, alloc.Allocated)
+}
+
+// toCents compares money amounts without floating point noise.
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func channelsResponse(ctx context.Context, q *db.Queries, campaign db.Campaign) (*dto.CampaignChannelsResponse, error) {
+	channels, err := q.ListCampaignChannels(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := q.GetCampaignChannelTotals(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	byChannel := make(map[uuid.UUID]db.GetCampaignChannelTotalsRow, len(totals))
+	var unattributed, sum db.GetCampaignChannelTotalsRow
+	for _, t := range totals {
+		if t.ChannelID.Valid {
+			byChannel[t.ChannelID.Bytes] = t
+		} else {
+			unattributed = t
+		}
+		sum.Impressions += t.Impressions
+		sum.Clicks += t.Clicks
+		sum.Conversions += t.Conversions
+		sum.Spend += t.Spend
+		sum.Revenue += t.Revenue
+	}
+
+	res := &dto.CampaignChannelsResponse{
+		CampaignID:   campaign.ID.String(),
+		Version:      campaign.Version,
+		Budget:       campaign.Budget,
+		Channels:     make([]dto.ChannelResponse, 0, len(channels)),
+		Unattributed: buildKPIs(unattributed.Impressions, unattributed.Clicks, unattributed.Conversions, unattributed.Spend, unattributed.Revenue),
+		Totals:       buildKPIs(sum.Impressions, sum.Clicks, sum.Conversions, sum.Spend, sum.Revenue),
+	}
+	for _, ch := range channels {
+		t := byChannel[ch.ID]
+		res.Allocated += ch.AllocatedBudget
+		res.Channels = append(res.Channels, dto.ChannelResponse{
+			ID:              ch.ID.String(),
+			Channel:         ch.Channel,
+			Status:          ch.Status,
+			AllocatedBudget: ch.AllocatedBudget,
+			Share:           ratio(ch.AllocatedBudget, campaign.Budget),
+			Pacing:          ratio(t.Spend, ch.AllocatedBudget),
+			KPIs:            buildKPIs(t.Impressions, t.Clicks, t.Conversions, t.Spend, t.Revenue),
+			UpdatedAt:       ch.UpdatedAt.Time,
+		})
+	}
+	return res, nil
+}
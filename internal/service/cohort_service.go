@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+)
+
+const defaultCohortWeeks = 8
+
+const cohortWeek = 7 * 24 * time.Hour
+
+type CohortService struct {
+	queries *db.Queries
+}
+
+func NewCohortService(queries *db.Queries) *CohortService {
+	return &CohortService{
+		queries: queries,
+	}
+}
+
+// cohort accumulates the visitors of one campaign and first-click week.
+type cohort struct {
+	campaignID uuid.UUID
+	title      string
+	week       time.Time
+	visitors   int64
+	active     []int64
+	converted  []int64
+	repeat     []int64
+}
+
+// Cohorts groups the visitors whose first click on one of the user's
+// campaigns was in the window by campaign and week, and follows each group
+// for the requested number of weeks.
+func (s *CohortService) Cohorts(ctx context.Context, userID uuid.UUID, req dto.CohortRequest) (*dto.CohortAnalysisResponse, error) {
+	now := time.Now()
+	window, err := resolveAnalyticsWindow(dto.AnalyticsRequest{
+		From:     req.From,
+		To:       req.To,
+		Timezone: req.Timezone,
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	weeks := req.Weeks
+	if weeks == 0 {
+		weeks = defaultCohortWeeks
+	}
+
+	arg := db.ListCohortEventsParams{
+		UntilTime: pgtype.Timestamptz{Time: window.to.Add(time.Duration(weeks) * cohortWeek), Valid: true},
+		FromTime:  pgtype.Timestamptz{Time: window.from, Valid: true},
+		ToTime:    pgtype.Timestamptz{Time: window.to, Valid: true},
+		UserID:    userID,
+	}
+	if req.CampaignID != "" {
+		campaignID, err := uuid.Parse(req.CampaignID)
+		if err != nil {
+			return nil, err
+		}
+		arg.CampaignID = pgtype.UUID{Bytes: campaignID, Valid: true}
+	}
+	rows, err := s.queries.ListCohortEvents(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	type cohortKey struct {
+		campaignID uuid.UUID
+		week       time.Time
+	}
+	cohorts := make(map[cohortKey]*cohort)
+
+	// Rows come grouped by visitor, events in time order.
+	for start := 0; start < len(rows); {
+		end := start + 1
+		for end < len(rows) && rows[end].VisitorID == rows[start].VisitorID {
+			end++
+		}
+		events := rows[start:end]
+		start = end
+
+		first := events[0]
+		weekStart := startOfWeek(first.FirstTouchAt.Time, window.loc)
+		key := cohortKey{campaignID: first.CampaignID, week: weekStart}
+		c := cohorts[key]
+		if c == nil {
+			c = &cohort{
+				campaignID: first.CampaignID,
+				title:      first.CampaignTitle,
+				week:       weekStart,
+				active:     make([]int64, elapsedWeeks(weekStart, weeks, now)),
+			}
+			c.converted = make([]int64, len(c.active))
+			c.repeat = make([]int64, len(c.active))
+			cohorts[key] = c
+		}
+		c.visitors++
+
+		// activeIn marks the weeks the visitor did something in;
+		// conversionsBy counts their conversions up to the end of each week.
+		activeIn := make([]bool, len(c.active))
+		conversionsBy := make([]int, len(c.active))
+		for _, e := range events {
+			k := weekIndex(weekStart, e.OccurredAt.Time, window.loc)
+			if k < 0 || k >= len(c.active) {
+				continue
+			}
+			activeIn[k] = true
+			if e.Event == EventConversion {
+				conversionsBy[k]++
+			}
+		}
+		total := 0
+		for k := range c.active {
+			total += conversionsBy[k]
+			if activeIn[k] {
+				c.active[k]++
+			}
+			if total >= 1 {
+				c.converted[k]++
+			}
+			if total >= 2 {
+				c.repeat[k]++
+			}
+		}
+	}
+
+	res := &dto.CohortAnalysisResponse{
+		From:     window.from,
+		To:       window.to,
+		Timezone: window.loc.String(),
+		Weeks:    weeks,
+		Cohorts:  make([]dto.CohortResponse, 0, len(cohorts)),
+	}
+	for _, c := range cohorts {
+		res.Cohorts = append(res.Cohorts, dto.CohortResponse{
+			CampaignID: c.campaignID.String(),
+			Title:      c.title,
+			Week:       c.week,
+			Visitors:   c.visitors,
+			Active:     c.active,
+			Retention:  shares(c.active, c.visitors),
+			Converted:  shares(c.converted, c.visitors),
+			Repeat:     shares(c.repeat, c.visitors),
+		})
+	}
+	slices.SortFunc(res.Cohorts, func(a, b dto.CohortResponse) int {
+		if c := strings.Compare(a.Title, b.Title); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.CampaignID, b.CampaignID); c != 0 {
+			return c
+		}
+		return a.Week.Compare(b.Week)
+	})
+	return res, nil
+}
+
+// startOfWeek is midnight of the Monday on or before t in loc.
+func startOfWeek(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, loc)
+}
+
+// weekIndex is the number of weeks between the week starting at weekStart
+// and the week of t, counted in calendar days so that DST changes in loc do
+// not shift events between weeks.
+func weekIndex(weekStart, t time.Time, loc *time.Location) int {
+	t = t.In(loc)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	base := time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(day.Sub(base).Hours() / 24)
+	if days < 0 {
+		return -1
+	}
+	return days / 7
+}
+
+// elapsedWeeks is how many of the first weeks weeks since weekStart have
+// started by now.
+func elapsedWeeks(weekStart time.Time, weeks int, now time.Time) int {
+	n := weekIndex(weekStart, now, weekStart.Location()) + 1
+	return max(0, min(n, weeks))
+}
+
+func shares(counts []int64, total int64) []float64 {
+	out := make([]float64, len(counts))
+	for i, n := range counts {
+		if total > 0 {
+			out[i] = float64(n) / float64(total)
+		}
+	}
+	return out
+}
@@ -0,0 +1,432 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/mention"
+	"github.com/valenrio66/be-project/pkg/pagination"
+)
+
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrInvalidComment   = errors.New("invalid comment")
+	ErrCommentForbidden = errors.New("only the author can change a comment")
+)
+
+const commentSortBy = "created_at"
+
+type CommentService struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewCommentService(dbPool *pgxpool.Pool, queries *db.Queries) *CommentService {
+	return &CommentService{
+		dbPool:  dbPool,
+		queries: queries,
+	}
+}
+
+// CreateComment adds a comment or reply and notifies the campaign owner, the
+// author of the comment replied to and every mentioned member.
+func (s *CommentService) CreateComment(ctx context.Context, userID uuid.UUID, role string, campaignID uuid.UUID, req dto.CreateCommentRequest) (*dto.CommentResponse, error) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, fmt.Errorf("%w: body is required", ErrInvalidComment)
+	}
+
+	var comment db.CampaignComment
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		campaign, err := discussableCampaign(ctx, q, userID, role, campaignID)
+		if err != nil {
+			return err
+		}
+
+		arg := db.CreateCommentParams{
+			CampaignID: campaignID,
+			AuthorID:   pgtype.UUID{Bytes: userID, Valid: true},
+			Body:       body,
+		}
+		recipients := map[uuid.UUID]string{campaign.UserID: NotificationComment}
+		if req.ParentID != "" {
+			parentID, err := uuid.Parse(req.ParentID)
+			if err != nil {
+				return fmt.Errorf("%w: invalid parent_id", ErrInvalidComment)
+			}
+			parent, err := q.GetComment(ctx, db.GetCommentParams{
+				ID:         parentID,
+				CampaignID: campaignID,
+			})
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return fmt.Errorf("%w: parent comment not found", ErrInvalidComment)
+				}
+				return err
+			}
+			if parent.DeletedAt.Valid {
+				return fmt.Errorf("%w: cannot reply to a deleted comment", ErrInvalidComment)
+			}
+			arg.ParentID = pgtype.UUID{Bytes: parent.ID, Valid: true}
+			arg.RootID = parent.RootID
+			if !parent.RootID.Valid {
+				arg.RootID = arg.ParentID
+			}
+			if parent.AuthorID.Valid {
+				recipients[parent.AuthorID.Bytes] = NotificationReply
+			}
+		}
+
+		comment, err = q.CreateComment(ctx, arg)
+		if err != nil {
+			return err
+		}
+		mentioned, err := saveMentions(ctx, q, comment.ID, body)
+		if err != nil {
+			return err
+		}
+		for _, id := range mentioned {
+			recipients[id] = NotificationMention
+		}
+
+		return notify(ctx, q, db.CreateNotificationsParams{
+			ActorID:    arg.AuthorID,
+			CampaignID: pgtype.UUID{Bytes: campaignID, Valid: true},
+			CommentID:  pgtype.UUID{Bytes: comment.ID, Valid: true},
+		}, recipients)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.commentResponse(ctx, comment.ID)
+}
+
+// ListComments pages through the top-level comments of a campaign, oldest
+// first, each with its whole thread of replies.
+func (s *CommentService) ListComments(ctx context.Context, userID uuid.UUID, role string, campaignID uuid.UUID, req dto.PageRequest) (*dto.PageResponse, error) {
+	cur, err := decodeCursor(req.Cursor, commentSortBy, pagination.OrderAsc)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil && cur.Backward {
+		return nil, ErrInvalidCursor
+	}
+	limit := pagination.Limit(req.Limit)
+
+	if _, err := discussableCampaign(ctx, s.queries, userID, role, campaignID); err != nil {
+		return nil, err
+	}
+
+	arg := db.ListRootCommentsParams{
+		CampaignID: campaignID,
+		RowLimit:   int32(limit + 1),
+	}
+	if cur != nil {
+		arg.CursorID = pgtype.UUID{Bytes: cur.ID, Valid: true}
+		arg.CursorTime = pgtype.Timestamptz{Time: cur.Time, Valid: true}
+	}
+	roots, err := s.queries.ListRootComments(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	roots, hasNext, _ := pagination.Trim(roots, limit, cur)
+
+	rootIDs := make([]uuid.UUID, 0, len(roots))
+	responses := make([]dto.CommentResponse, 0, len(roots))
+	byRoot := make(map[uuid.UUID]int, len(roots))
+	for i, r := range roots {
+		rootIDs = append(rootIDs, r.ID)
+		byRoot[r.ID] = i
+		responses = append(responses, toCommentResponse(db.GetCommentWithAuthorRow(r)))
+	}
+
+	var replies []db.ListCommentRepliesRow
+	if len(rootIDs) > 0 {
+		replies, err = s.queries.ListCommentReplies(ctx, rootIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range replies {
+		root := &responses[byRoot[r.RootID.Bytes]]
+		root.Replies = append(root.Replies, toCommentResponse(db.GetCommentWithAuthorRow(r)))
+	}
+
+	all := make([]*dto.CommentResponse, 0, len(roots)+len(replies))
+	for i := range responses {
+		all = append(all, &responses[i])
+		for j := range responses[i].Replies {
+			all = append(all, &responses[i].Replies[j])
+		}
+	}
+	if err := attachMentions(ctx, s.queries, all...); err != nil {
+		return nil, err
+	}
+
+	var next *pagination.Cursor
+	if hasNext && len(roots) > 0 {
+		last := roots[len(roots)-1]
+		next = &pagination.Cursor{
+			SortBy:    commentSortBy,
+			SortOrder: pagination.OrderAsc,
+			Time:      last.CreatedAt.Time,
+			ID:        last.ID,
+		}
+	}
+	page := newPageInfo(limit, next, nil)
+
+	if req.IncludeTotal {
+		total, err := s.queries.CountRootComments(ctx, campaignID)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return &dto.PageResponse{
+		Items: responses,
+		Page:  page,
+	}, nil
+}
+
+// UpdateComment replaces the body of the user's own comment. Members
+// mentioned for the first time are notified; members no longer mentioned
+// are dropped from the comment.
+func (s *CommentService) UpdateComment(ctx context.Context, userID uuid.UUID, role string, campaignID uuid.UUID, commentID uuid.UUID, req dto.UpdateCommentRequest) (*dto.CommentResponse, error) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, fmt.Errorf("%w: body is required", ErrInvalidComment)
+	}
+
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		comment, err := s.ownComment(ctx, q, userID, role, campaignID, commentID)
+		if err != nil {
+			return err
+		}
+		if comment.Body == body {
+			return nil
+		}
+
+		if _, err := q.UpdateCommentBody(ctx, db.UpdateCommentBodyParams{
+			ID:   commentID,
+			Body: body,
+		}); err != nil {
+			return err
+		}
+		mentioned, err := saveMentions(ctx, q, commentID, body)
+		if err != nil {
+			return err
+		}
+		recipients := make(map[uuid.UUID]string, len(mentioned))
+		for _, id := range mentioned {
+			recipients[id] = NotificationMention
+		}
+		return notify(ctx, q, db.CreateNotificationsParams{
+			ActorID:    comment.AuthorID,
+			CampaignID: pgtype.UUID{Bytes: campaignID, Valid: true},
+			CommentID:  pgtype.UUID{Bytes: commentID, Valid: true},
+		}, recipients)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.commentResponse(ctx, commentID)
+}
+
+// DeleteComment removes the user's own comment. Its replies stay, so the
+// comment keeps its place in the thread without a body; its mentions and
+// notifications are removed.
+func (s *CommentService) DeleteComment(ctx context.Context, userID uuid.UUID, role string, campaignID uuid.UUID, commentID uuid.UUID) error {
+	return execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		if _, err := s.ownComment(ctx, q, userID, role, campaignID, commentID); err != nil {
+			return err
+		}
+		if _, err := q.SoftDeleteComment(ctx, commentID); err != nil {
+			return err
+		}
+		if err := q.RemoveOtherCommentMentions(ctx, db.RemoveOtherCommentMentionsParams{
+			CommentID:   commentID,
+			KeepUserIds: []uuid.UUID{},
+		}); err != nil {
+			return err
+		}
+		return q.DeleteCommentNotifications(ctx, pgtype.UUID{Bytes: commentID, Valid: true})
+	})
+}
+
+// ownComment locks a live comment on a campaign the user can discuss and
+// checks that the user wrote it.
+func (s *CommentService) ownComment(ctx context.Context, q *db.Queries, userID uuid.UUID, role string, campaignID uuid.UUID, commentID uuid.UUID) (db.CampaignComment, error) {
+	if _, err := discussableCampaign(ctx, q, userID, role, campaignID); err != nil {
+		return db.CampaignComment{}, err
+	}
+	comment, err := q.GetCommentForUpdate(ctx, db.GetCommentForUpdateParams{
+		ID:         commentID,
+		CampaignID: campaignID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return comment, ErrCommentNotFound
+		}
+		return comment, err
+	}
+	if comment.DeletedAt.Valid {
+		return comment, ErrCommentNotFound
+	}
+	if !comment.AuthorID.Valid || comment.AuthorID.Bytes != userID {
+		return comment, ErrCommentForbidden
+	}
+	return comment, nil
+}
+
+func (s *CommentService) commentResponse(ctx context.Context, commentID uuid.UUID) (*dto.CommentResponse, error) {
+	row, err := s.queries.GetCommentWithAuthor(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	res := toCommentResponse(row)
+	if err := attachMentions(ctx, s.queries, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// discussableCampaign returns the campaign when the user may read and write
+// its comments: owners, managers, admins and members mentioned in it. Others
+// get ErrCampaignNotFound.
+func discussableCampaign(ctx context.Context, q *db.Queries, userID uuid.UUID, role string, campaignID uuid.UUID) (db.GetDiscussableCampaignRow, error) {
+	campaign, err := q.GetDiscussableCampaign(ctx, db.GetDiscussableCampaignParams{
+		CampaignID: campaignID,
+		UserID:     userID,
+		CanViewAll: slices.Contains(approverRoles, role),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return campaign, ErrCampaignNotFound
+	}
+	return campaign, err
+}
+
+// saveMentions makes the members mentioned in body the mentions of a comment
+// and returns the ones that were not mentioned before. Unknown addresses are
+// ignored.
+func saveMentions(ctx context.Context, q *db.Queries, commentID uuid.UUID, body string) ([]uuid.UUID, error) {
+	userIDs := []uuid.UUID{}
+	if emails := mention.Extract(body); len(emails) > 0 {
+		users, err := q.GetUsersByEmails(ctx, emails)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			userIDs = append(userIDs, u.ID)
+		}
+	}
+
+	if err := q.RemoveOtherCommentMentions(ctx, db.RemoveOtherCommentMentionsParams{
+		CommentID:   commentID,
+		KeepUserIds: userIDs,
+	}); err != nil {
+		return nil, err
+	}
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	return q.AddCommentMentions(ctx, db.AddCommentMentionsParams{
+		CommentID: commentID,
+		UserIds:   userIDs,
+	})
+}
+
+// attachMentions loads the mentioned members of the given comments with one
+// query.
+func attachMentions(ctx context.Context, q *db.Queries, responses ...*dto.CommentResponse) error {
+	ids := make([]uuid.UUID, 0, len(responses))
+	byID := make(map[uuid.UUID]*dto.CommentResponse, len(responses))
+	for _, res := range responses {
+		res.Mentions = []dto.UserSummary{}
+		id, err := uuid.Parse(res.ID)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+		byID[id] = res
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := q.ListCommentMentions(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		res := byID[row.CommentID]
+		res.Mentions = append(res.Mentions, dto.UserSummary{
+			ID:       row.ID.String(),
+			FullName: row.FullName,
+			Email:    row.Email,
+		})
+	}
+	return nil
+}
+
+// attachCommentCounts sets the number of live comments on campaign
+// responses with one query.
+func attachCommentCounts(ctx context.Context, q *db.Queries, responses ...*dto.CampaignResponse) error {
+	ids := make([]uuid.UUID, 0, len(responses))
+	byID := make(map[uuid.UUID]*dto.CampaignResponse, len(responses))
+	for _, res := range responses {
+		id, err := uuid.Parse(res.ID)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+		byID[id] = res
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := q.CountCampaignComments(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		byID[row.CampaignID].CommentCount = row.CommentCount
+	}
+	return nil
+}
+
+func toCommentResponse(c db.GetCommentWithAuthorRow) dto.CommentResponse {
+	res := dto.CommentResponse{
+		ID:         c.ID.String(),
+		CampaignID: c.CampaignID.String(),
+		ParentID:   uuidString(c.ParentID),
+		Body:       c.Body,
+		Deleted:    c.DeletedAt.Valid,
+		CreatedAt:  c.CreatedAt.Time,
+	}
+	if !res.Deleted && c.AuthorID.Valid && c.AuthorName != nil && c.AuthorEmail != nil {
+		res.Author = &dto.UserSummary{
+			ID:       uuid.UUID(c.AuthorID.Bytes).String(),
+			FullName: *c.AuthorName,
+			Email:    *c.AuthorEmail,
+		}
+	}
+	if c.EditedAt.Valid {
+		edited := c.EditedAt.Time
+		res.EditedAt = &edited
+	}
+	return res
+}
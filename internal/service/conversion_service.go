@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/pagination"
+)
+
+var (
+	ErrClickNotFound     = errors.New("click not found")
+	ErrInvalidConversion = errors.New("invalid conversion")
+	ErrInvalidSignature  = errors.New("invalid postback signature")
+)
+
+var eventNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+const (
+	ConversionPixel    = "pixel"
+	ConversionPostback = "postback"
+
+	// EventConversion is the default event, the only one counted in campaign
+	// metrics and attribution. EventClick is the event of a tracked link
+	// click in funnels.
+	EventConversion = "conversion"
+	EventClick      = "click"
+
+	conversionSortBy = "converted_at"
+	// conversionLookback is how long after a click a pixel conversion
+	// without a click ID is still credited to the visitor's last click.
+	conversionLookback = 30 * 24 * time.Hour
+	// postbackTolerance bounds the age of a signed postback, so a captured
+	// request cannot be replayed later with a new transaction.
+	postbackTolerance = 5 * time.Minute
+)
+
+// ConversionEvent is a conversion as reported by the pixel or a postback.
+// ClickID, when known, ties it to a tracked link click; otherwise a pixel
+// conversion goes to the visitor's last click.
+type ConversionEvent struct {
+	Source        string
+	Event         string
+	ClickID       *uuid.UUID
+	VisitorID     *uuid.UUID
+	TransactionID string
+	Value         float64
+	Currency      string
+	At            time.Time
+}
+
+type ConversionService struct {
+	dbPool         *pgxpool.Pool
+	queries        *db.Queries
+	goalService    *GoalService
+	currency       string
+	postbackSecret []byte
+}
+
+// NewConversionService creates the conversion service. currency is the one
+// campaign revenue is reported in: only conversion values in it are added
+// to the revenue metric. Postbacks are rejected while postbackSecret is
+// empty.
+func NewConversionService(dbPool *pgxpool.Pool, queries *db.Queries, goalService *GoalService, currency string, postbackSecret []byte) *ConversionService {
+	return &ConversionService{
+		dbPool:         dbPool,
+		queries:        queries,
+		goalService:    goalService,
+		currency:       strings.ToUpper(currency),
+		postbackSecret: postbackSecret,
+	}
+}
+
+// VerifyPostback checks the signature of a postback: the hex HMAC-SHA256,
+// keyed with the postback secret, of the Unix timestamp, a dot and the raw
+// body.
+func (s *ConversionService) VerifyPostback(timestamp, signature string, body []byte, now time.Time) error {
+	if len(s.postbackSecret) == 0 {
+		return ErrInvalidSignature
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > postbackTolerance || age < -postbackTolerance {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, s.postbackSecret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	want := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(want), []byte(strings.ToLower(strings.TrimPrefix(signature, "sha256=")))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Record stores a conversion event. Conversions proper are also added to
+// the campaign metrics of the click's link, under its channel and variant.
+// A transaction recorded before for the same campaign, by the pixel or a
+// postback, is returned as a duplicate and counted only once; a postback
+// adds its click to a duplicate that has none.
+func (s *ConversionService) Record(ctx context.Context, event ConversionEvent) (*dto.PostbackResponse, error) {
+	currency := strings.ToUpper(strings.TrimSpace(event.Currency))
+	if currency == "" {
+		currency = s.currency
+	}
+	if len(currency) != 3 {
+		return nil, fmt.Errorf("%w: currency must be a three-letter ISO 4217 code", ErrInvalidConversion)
+	}
+	name := strings.ToLower(strings.TrimSpace(event.Event))
+	if name == "" {
+		name = EventConversion
+	}
+	if !eventNamePattern.MatchString(name) || name == EventClick {
+		return nil, fmt.Errorf("%w: event must be lower case letters, digits and underscores, and not click", ErrInvalidConversion)
+	}
+	if event.At.After(time.Now().Add(postbackTolerance)) {
+		return nil, fmt.Errorf("%w: converted_at is in the future", ErrInvalidConversion)
+	}
+
+	click, err := s.findClick(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.CreateConversionParams{
+		Event:       name,
+		Value:       event.Value,
+		Currency:    currency,
+		Source:      event.Source,
+		ConvertedAt: pgtype.Timestamptz{Time: event.At, Valid: true},
+	}
+	if event.VisitorID != nil {
+		arg.VisitorID = pgtype.UUID{Bytes: *event.VisitorID, Valid: true}
+	}
+	if txn := strings.TrimSpace(event.TransactionID); txn != "" {
+		arg.TransactionID = &txn
+	}
+	if click != nil {
+		arg.CampaignID = pgtype.UUID{Bytes: click.CampaignID, Valid: true}
+		arg.LinkID = pgtype.UUID{Bytes: click.LinkID, Valid: true}
+		arg.ClickID = pgtype.UUID{Bytes: click.ID, Valid: true}
+		if !arg.VisitorID.Valid {
+			arg.VisitorID = pgtype.UUID{Bytes: click.VisitorID, Valid: true}
+		}
+	}
+
+	var conversion db.Conversion
+	created := true
+	err = execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		conversion, err = q.CreateConversion(ctx, arg)
+		if errors.Is(err, pgx.ErrNoRows) {
+			created = false
+			conversion, err = q.GetConversionByTransaction(ctx, db.GetConversionByTransactionParams{
+				CampaignID:    arg.CampaignID,
+				TransactionID: arg.TransactionID,
+			})
+			if err != nil || click == nil || event.Source != ConversionPostback || conversion.ClickID.Valid {
+				return err
+			}
+			// The first report is kept; a signed postback only adds the
+			// click it is missing.
+			attached, err := q.AttachConversionClick(ctx, db.AttachConversionClickParams{
+				ID:        conversion.ID,
+				LinkID:    arg.LinkID,
+				ClickID:   arg.ClickID,
+				VisitorID: arg.VisitorID,
+			})
+			if err == nil {
+				conversion = attached
+			} else if !errors.Is(err, pgx.ErrNoRows) {
+				return err
+			}
+			return nil
+		}
+		if err != nil || click == nil || name != EventConversion {
+			return err
+		}
+
+		metric := db.AddCampaignMetricEventsParams{
+			CampaignID:  click.CampaignID,
+			ChannelID:   click.ChannelID,
+			VariantID:   click.VariantID,
+			RecordedAt:  pgtype.Timestamptz{Time: event.At.UTC().Truncate(time.Hour), Valid: true},
+			Conversions: 1,
+		}
+		if currency == s.currency {
+			metric.Revenue = event.Value
+		}
+		return q.AddCampaignMetricEvents(ctx, metric)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The conversion can achieve the campaign's conversion or revenue goals.
+	if created && click != nil && name == EventConversion {
+		if _, err := s.goalService.EvaluateCampaign(ctx, click.CampaignID, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	return &dto.PostbackResponse{
+		ID:         conversion.ID.String(),
+		CampaignID: uuidString(conversion.CampaignID),
+		Duplicate:  !created,
+	}, nil
+}
+
+// ListConversions pages through a campaign's conversions, newest first.
+func (s *ConversionService) ListConversions(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.ListConversionsRequest) (*dto.PageResponse, error) {
+	cur, err := decodeCursor(req.Cursor, conversionSortBy, pagination.OrderDesc)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil && cur.Backward {
+		return nil, ErrInvalidCursor
+	}
+	limit := pagination.Limit(req.Limit)
+
+	if _, err := s.queries.GetCampaign(ctx, db.GetCampaignParams{
+		ID:     campaignID,
+		UserID: userID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	arg := db.ListCampaignConversionsParams{
+		CampaignID: pgtype.UUID{Bytes: campaignID, Valid: true},
+		RowLimit:   int32(limit + 1),
+	}
+	if cur != nil {
+		arg.CursorID = pgtype.UUID{Bytes: cur.ID, Valid: true}
+		arg.CursorTime = pgtype.Timestamptz{Time: cur.Time, Valid: true}
+	}
+
+	rows, err := s.queries.ListCampaignConversions(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	rows, hasNext, _ := pagination.Trim(rows, limit, cur)
+
+	responses := make([]dto.ConversionResponse, 0, len(rows))
+	for _, r := range rows {
+		responses = append(responses, toConversionResponse(r))
+	}
+
+	var next *pagination.Cursor
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		next = &pagination.Cursor{
+			SortBy:    conversionSortBy,
+			SortOrder: pagination.OrderDesc,
+			Time:      last.ConvertedAt.Time,
+			ID:        last.ID,
+		}
+	}
+	page := newPageInfo(limit, next, nil)
+
+	if req.IncludeTotal {
+		total, err := s.queries.CountCampaignConversions(ctx, pgtype.UUID{Bytes: campaignID, Valid: true})
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return &dto.PageResponse{
+		Items: responses,
+		Page:  page,
+	}, nil
+}
+
+// findClick returns the click a conversion is credited to, or nil when
+// there is none. Postbacks must name a click that exists; the pixel falls
+// back to the visitor's last click within the lookback window.
+func (s *ConversionService) findClick(ctx context.Context, event ConversionEvent) (*db.GetLinkClickRow, error) {
+	if event.ClickID != nil {
+		click, err := s.queries.GetLinkClick(ctx, *event.ClickID)
+		if err == nil {
+			return &click, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		if event.Source == ConversionPostback {
+			return nil, ErrClickNotFound
+		}
+	}
+	if event.VisitorID == nil {
+		return nil, nil
+	}
+
+	click, err := s.queries.GetLatestVisitorClick(ctx, db.GetLatestVisitorClickParams{
+		VisitorID: *event.VisitorID,
+		Since:     pgtype.Timestamptz{Time: event.At.Add(-conversionLookback), Valid: true},
+		Until:     pgtype.Timestamptz{Time: event.At, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	row := db.GetLinkClickRow(click)
+	return &row, nil
+}
+
+func toConversionResponse(c db.ListCampaignConversionsRow) dto.ConversionResponse {
+	return dto.ConversionResponse{
+		ID:            c.ID.String(),
+		Event:         c.Event,
+		Source:        c.Source,
+		TransactionID: c.TransactionID,
+		Value:         c.Value,
+		Currency:      c.Currency,
+		ClickID:       uuidString(c.ClickID),
+		Link:          c.LinkCode,
+		Channel:       c.Channel,
+		Variant:       c.VariantName,
+		ConvertedAt:   c.ConvertedAt.Time,
+		CreatedAt:     c.CreatedAt.Time,
+	}
+}
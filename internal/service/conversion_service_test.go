@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestRecordDeduplicatesTransactionsAcrossSources(t *testing.T) {
+	pool, q := testDB(t)
+	ctx := context.Background()
+	s := NewConversionService(pool, q, NewGoalService(pool, q), "USD", []byte("secret"))
+
+	userID := createTestUser(t, pool, q)
+	campaign := createTestCampaign(t, q, userID)
+	other := createTestCampaign(t, q, userID)
+	visitorID := uuid.New()
+	now := time.Now().Truncate(time.Second)
+	click := createTestClick(t, q, campaign.ID, visitorID, now.Add(-time.Hour))
+	otherClick := createTestClick(t, q, other.ID, visitorID, now.Add(-time.Hour))
+
+	record := func(source string, clickID uuid.UUID) (id string, duplicate bool) {
+		t.Helper()
+		res, err := s.Record(ctx, ConversionEvent{
+			Source:        source,
+			ClickID:       &clickID,
+			TransactionID: "order-1001",
+			Value:         50,
+			Currency:      "USD",
+			At:            now,
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", source, err)
+		}
+		return res.ID, res.Duplicate
+	}
+
+	pixelID, duplicate := record(ConversionPixel, click.ID)
+	if duplicate {
+		t.Fatal("first pixel hit reported as a duplicate")
+	}
+	postbackID, duplicate := record(ConversionPostback, click.ID)
+	if !duplicate || postbackID != pixelID {
+		t.Errorf("postback = %s duplicate %v, want duplicate of pixel conversion %s", postbackID, duplicate, pixelID)
+	}
+	if _, duplicate := record(ConversionPixel, click.ID); !duplicate {
+		t.Error("repeated pixel hit not reported as a duplicate")
+	}
+	otherID, duplicate := record(ConversionPostback, otherClick.ID)
+	if duplicate || otherID == pixelID {
+		t.Errorf("the same transaction on another campaign was a duplicate of %s", otherID)
+	}
+
+	conversions, revenue := campaignConversionTotals(t, pool, campaign.ID)
+	if conversions != 1 || revenue != 50 {
+		t.Errorf("campaign metrics = %d conversions, %v revenue, want 1 and 50", conversions, revenue)
+	}
+}
+
+func TestRecordPostbackAddsMissingClick(t *testing.T) {
+	pool, q := testDB(t)
+	ctx := context.Background()
+	s := NewConversionService(pool, q, NewGoalService(pool, q), "USD", []byte("secret"))
+
+	userID := createTestUser(t, pool, q)
+	campaign := createTestCampaign(t, q, userID)
+	visitorID := uuid.New()
+	now := time.Now().Truncate(time.Second)
+	first := createTestClick(t, q, campaign.ID, visitorID, now.Add(-2*time.Hour))
+
+	pixel, err := s.Record(ctx, ConversionEvent{
+		Source:        ConversionPixel,
+		ClickID:       &first.ID,
+		TransactionID: "order-2002",
+		Currency:      "USD",
+		At:            now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Deleting the link leaves the pixel conversion without a click.
+	if _, err := pool.Exec(ctx, "DELETE FROM tracked_links WHERE id = $1", first.LinkID); err != nil {
+		t.Fatal(err)
+	}
+
+	second := createTestClick(t, q, campaign.ID, visitorID, now.Add(-time.Hour))
+	postback, err := s.Record(ctx, ConversionEvent{
+		Source:        ConversionPostback,
+		ClickID:       &second.ID,
+		TransactionID: "order-2002",
+		Currency:      "USD",
+		At:            now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !postback.Duplicate || postback.ID != pixel.ID {
+		t.Fatalf("postback = %+v, want a duplicate of %s", postback, pixel.ID)
+	}
+
+	var clickID uuid.UUID
+	if err := pool.QueryRow(ctx, "SELECT click_id FROM conversions WHERE id = $1", pixel.ID).Scan(&clickID); err != nil {
+		t.Fatal(err)
+	}
+	if clickID != second.ID {
+		t.Errorf("click_id = %s, want the postback's click %s", clickID, second.ID)
+	}
+}
+
+func campaignConversionTotals(t *testing.T, pool *pgxpool.Pool, campaignID uuid.UUID) (int64, float64) {
+	t.Helper()
+	var conversions int64
+	var revenue float64
+	err := pool.QueryRow(context.Background(),
+		"SELECT COALESCE(SUM(conversions), 0)::bigint, COALESCE(SUM(revenue), 0)::float8 FROM campaign_metrics WHERE campaign_id = $1",
+		campaignID,
+	).Scan(&conversions, &revenue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conversions, revenue
+}
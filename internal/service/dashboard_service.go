@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+)
+
+const (
+	defaultUpcomingDays      = 7
+	defaultDashboardLimit    = 5
+	topCampaignsLookbackDays = 30
+)
+
+type DashboardService struct {
+	queries *db.Queries
+}
+
+func NewDashboardService(queries *db.Queries) *DashboardService {
+	return &DashboardService{
+		queries: queries,
+	}
+}
+
+func (s *DashboardService) GetSummary(ctx context.Context, userID uuid.UUID, req dto.DashboardSummaryRequest) (*dto.DashboardSummaryResponse, error) {
+	upcomingDays := req.UpcomingDays
+	if upcomingDays == 0 {
+		upcomingDays = defaultUpcomingDays
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultDashboardLimit
+	}
+
+	now := time.Now()
+	from := pgtype.Timestamptz{Time: now, Valid: true}
+	to := pgtype.Timestamptz{Time: now.AddDate(0, 0, upcomingDays), Valid: true}
+
+	statusRows, err := s.queries.CountCampaignsByStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byStatus := make(map[string]int64, len(statusRows))
+	for _, row := range statusRows {
+		byStatus[row.Status] = row.Total
+	}
+
+	budget, err := s.queries.GetBudgetSummary(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	starting, err := s.queries.ListCampaignsStartingBetween(ctx, db.ListCampaignsStartingBetweenParams{
+		UserID:   userID,
+		FromTime: from,
+		ToTime:   to,
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ending, err := s.queries.ListCampaignsEndingBetween(ctx, db.ListCampaignsEndingBetweenParams{
+		UserID:   userID,
+		FromTime: from,
+		ToTime:   to,
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	top, err := s.queries.ListTopCampaigns(ctx, db.ListTopCampaignsParams{
+		UserID:   userID,
+		Since:    pgtype.Timestamptz{Time: now.AddDate(0, 0, -topCampaignsLookbackDays), Valid: true},
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recent, err := s.queries.ListRecentCampaignActivity(ctx, db.ListRecentCampaignActivityParams{
+		UserID:   userID,
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.DashboardSummaryResponse{
+		CampaignsByStatus: byStatus,
+		Budget: dto.BudgetSummaryResponse{
+			TotalCampaigns:  budget.TotalCampaigns,
+			TotalBudget:     budget.TotalBudget,
+			TotalSpend:      budget.TotalSpend,
+			RemainingBudget: budget.RemainingBudget,
+		},
+		StartingSoon:   make([]dto.CampaignScheduleResponse, 0, len(starting)),
+		EndingSoon:     make([]dto.CampaignScheduleResponse, 0, len(ending)),
+		TopCampaigns:   make([]dto.TopCampaignResponse, 0, len(top)),
+		RecentActivity: make([]dto.ActivityResponse, 0, len(recent)),
+		GeneratedAt:    now,
+	}
+
+	for _, c := range starting {
+		res.StartingSoon = append(res.StartingSoon, toCampaignSchedule(db.ListCampaignsEndingBetweenRow(c)))
+	}
+	for _, c := range ending {
+		res.EndingSoon = append(res.EndingSoon, toCampaignSchedule(c))
+	}
+	for _, c := range top {
+		res.TopCampaigns = append(res.TopCampaigns, dto.TopCampaignResponse{
+			ID:     c.ID.String(),
+			Title:  c.Title,
+			Status: c.Status,
+			KPIs:   buildKPIs(c.Impressions, c.Clicks, c.Conversions, c.Spend, c.Revenue),
+		})
+	}
+	for _, c := range recent {
+		action := "updated"
+		if c.UpdatedAt.Time.Equal(c.CreatedAt.Time) {
+			action = "created"
+		}
+		res.RecentActivity = append(res.RecentActivity, dto.ActivityResponse{
+			CampaignID: c.ID.String(),
+			Title:      c.Title,
+			Action:     action,
+			OccurredAt: c.UpdatedAt.Time,
+		})
+	}
+
+	return res, nil
+}
+
+func toCampaignSchedule(c db.ListCampaignsEndingBetweenRow) dto.CampaignScheduleResponse {
+	return dto.CampaignScheduleResponse{
+		ID:        c.ID.String(),
+		Title:     c.Title,
+		Status:    c.Status,
+		StartDate: c.StartDate.Time,
+		EndDate:   c.EndDate.Time,
+		Budget:    c.Budget,
+	}
+}
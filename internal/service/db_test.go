@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/pkg/utils"
+)
+
+// testDB connects to the migrated database named by TEST_DATABASE_URL. Tests
+// that need one are skipped when it is not set.
+func testDB(t *testing.T) (*pgxpool.Pool, *db.Queries) {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	pool, err := pgxpool.New(context.Background(), url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return pool, db.New(pool)
+}
+
+// createTestUser creates a user that is deleted, along with everything it
+// owns, when the test ends.
+func createTestUser(t *testing.T, pool *pgxpool.Pool, q *db.Queries) uuid.UUID {
+	t.Helper()
+	user, err := q.CreateUser(context.Background(), db.CreateUserParams{
+		FullName: "Test User",
+		Email:    "test-" + uuid.NewString() + "@example.com",
+		Password: "not a hash",
+		Role:     "user",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if _, err := pool.Exec(context.Background(), "DELETE FROM users WHERE id = $1", user.ID); err != nil {
+			t.Errorf("deleting test user: %v", err)
+		}
+	})
+	return user.ID
+}
+
+func createTestCampaign(t *testing.T, q *db.Queries, userID uuid.UUID) db.Campaign {
+	t.Helper()
+	campaign, err := q.CreateCampaign(context.Background(), db.CreateCampaignParams{
+		UserID: userID,
+		Title:  "Test campaign",
+		Status: utils.CampaignStatusDraft,
+		Budget: 1000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return campaign
+}
+
+// createTestClick records a click by visitorID on a new tracked link of the
+// campaign.
+func createTestClick(t *testing.T, q *db.Queries, campaignID, visitorID uuid.UUID, at time.Time) db.GetLinkClickRow {
+	t.Helper()
+	ctx := context.Background()
+	link, err := q.CreateTrackedLink(ctx, db.CreateTrackedLinkParams{
+		CampaignID:     campaignID,
+		Code:           uuid.NewString()[:12],
+		DestinationUrl: "https://example.com/landing",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clickID, err := q.CreateLinkClick(ctx, db.CreateLinkClickParams{
+		LinkID:    link.ID,
+		ClickedAt: pgtype.Timestamptz{Time: at, Valid: true},
+		VisitorID: visitorID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	click, err := q.GetLinkClick(ctx, clickID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return click
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/spreadsheet"
+	"github.com/valenrio66/be-project/pkg/utils"
+)
+
+var ErrInvalidExport = errors.New("invalid export request")
+
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatXLSX   = "xlsx"
+	ExportFormatNDJSON = "ndjson"
+)
+
+type ExportService struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewExportService(dbPool *pgxpool.Pool, queries *db.Queries) *ExportService {
+	return &ExportService{
+		dbPool:  dbPool,
+		queries: queries,
+	}
+}
+
+// Export is a validated export. Nothing is read from the database until
+// WriteTo, so request errors can still be reported before any output.
+type Export struct {
+	Filename    string
+	ContentType string
+	write       func(ctx context.Context, w io.Writer) error
+}
+
+// WriteTo streams the export to w.
+func (e *Export) WriteTo(ctx context.Context, w io.Writer) error {
+	return e.write(ctx, w)
+}
+
+// exportColumn renders one output column of a row type T.
+type exportColumn[T any] struct {
+	key   string
+	value func(row T) spreadsheet.Cell
+}
+
+func (s *ExportService) ExportCampaigns(userID uuid.UUID, req dto.ExportCampaignsRequest) (*Export, error) {
+	filter, err := buildCampaignFilter(dto.ListCampaignsRequest{
+		Status:    req.Status,
+		From:      req.From,
+		To:        req.To,
+		MinBudget: req.MinBudget,
+		MaxBudget: req.MaxBudget,
+		Query:     req.Query,
+		Tags:      req.Tags,
+		TagMatch:  req.TagMatch,
+		SortBy:    req.SortBy,
+		SortOrder: req.SortOrder,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	loc := time.UTC
+	if req.Timezone != "" {
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			return nil, ErrInvalidTimezone
+		}
+	}
+
+	columns, err := selectExportColumns(campaignExportColumns(loc), req.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.ExportCampaignsParams{
+		UserID:      userID,
+		Statuses:    filter.statuses,
+		ActiveTo:    filter.activeTo,
+		ActiveFrom:  filter.activeFrom,
+		MinBudget:   filter.minBudget,
+		MaxBudget:   filter.maxBudget,
+		Search:      filter.search,
+		TagIds:      filter.tagIDs,
+		TagMatchAll: filter.tagMatchAll,
+		SortOrder:   filter.sortOrder,
+		SortBy:      filter.sortBy,
+	}
+	return newExport(s, "campaigns", req.ExportOptions, columns, func(ctx context.Context, q *db.Queries, fn func(db.ExportCampaignsRow) error) error {
+		return q.StreamExportCampaigns(ctx, arg, fn)
+	})
+}
+
+func (s *ExportService) ExportAnalytics(userID uuid.UUID, req dto.ExportAnalyticsRequest) (*Export, error) {
+	window, err := resolveAnalyticsWindow(req.AnalyticsRequest, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := selectExportColumns(metricSeriesExportColumns(window.loc), req.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.ExportCampaignMetricSeriesParams{
+		TimeZone: window.loc.String(),
+		Bucket:   window.interval,
+		UserID:   userID,
+		Channel:  window.channel,
+		FromTime: pgtype.Timestamptz{Time: window.from, Valid: true},
+		ToTime:   pgtype.Timestamptz{Time: window.to, Valid: true},
+	}
+	if req.CampaignID != "" {
+		campaignID, err := uuid.Parse(req.CampaignID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: campaign_id must be a UUID", ErrInvalidExport)
+		}
+		arg.CampaignID = pgtype.UUID{Bytes: campaignID, Valid: true}
+	}
+	return newExport(s, "analytics", req.ExportOptions, columns, func(ctx context.Context, q *db.Queries, fn func(db.ExportCampaignMetricSeriesRow) error) error {
+		return q.StreamExportCampaignMetricSeries(ctx, arg, fn)
+	})
+}
+
+// newExport validates the output options and returns an Export that streams
+// the rows produced by stream. The cursor behind stream lives in a
+// transaction for the duration of the export.
+func newExport[T any](s *ExportService, name string, opts dto.ExportOptions, columns []exportColumn[T], stream func(context.Context, *db.Queries, func(T) error) error) (*Export, error) {
+	locale, ok := spreadsheet.LookupLocale(opts.Locale)
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported locale %q, use one of %s", ErrInvalidExport, opts.Locale, strings.Join(spreadsheet.LocaleTags(), ", "))
+	}
+	format := opts.Format
+	if format == "" {
+		format = ExportFormatCSV
+	}
+
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.key
+	}
+
+	export := &Export{
+		Filename: fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("20060102-150405"), format),
+	}
+	var newWriter func(io.Writer) (spreadsheet.Writer, error)
+	switch format {
+	case ExportFormatXLSX:
+		export.ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		newWriter = func(w io.Writer) (spreadsheet.Writer, error) {
+			return spreadsheet.NewXLSXWriter(w, name, header, locale)
+		}
+	case ExportFormatNDJSON:
+		export.ContentType = "application/x-ndjson"
+		newWriter = func(w io.Writer) (spreadsheet.Writer, error) {
+			return spreadsheet.NewNDJSONWriter(w, header)
+		}
+	default:
+		export.ContentType = "text/csv; charset=utf-8"
+		newWriter = func(w io.Writer) (spreadsheet.Writer, error) {
+			return spreadsheet.NewCSVWriter(w, header, locale)
+		}
+	}
+
+	export.write = func(ctx context.Context, w io.Writer) error {
+		out, err := newWriter(w)
+		if err != nil {
+			return err
+		}
+		cells := make([]spreadsheet.Cell, len(columns))
+		err = execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+			return stream(ctx, q, func(row T) error {
+				for i, col := range columns {
+					cells[i] = col.value(row)
+				}
+				return out.WriteRow(cells)
+			})
+		})
+		if err != nil {
+			return err
+		}
+		return out.Close()
+	}
+	return export, nil
+}
+
+// selectExportColumns picks the requested columns in the requested order, or
+// all of them when none are given.
+func selectExportColumns[T any](all []exportColumn[T], requested []string) ([]exportColumn[T], error) {
+	var keys []string
+	for _, value := range requested {
+		for _, key := range strings.Split(value, ",") {
+			if key = strings.TrimSpace(key); key != "" && !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return all, nil
+	}
+
+	selected := make([]exportColumn[T], 0, len(keys))
+	for _, key := range keys {
+		idx := slices.IndexFunc(all, func(col exportColumn[T]) bool { return col.key == key })
+		if idx < 0 {
+			available := make([]string, len(all))
+			for i, col := range all {
+				available[i] = col.key
+			}
+			return nil, fmt.Errorf("%w: unknown column %q, use any of %s", ErrInvalidExport, key, strings.Join(available, ", "))
+		}
+		selected = append(selected, all[idx])
+	}
+	return selected, nil
+}
+
+func campaignExportColumns(loc *time.Location) []exportColumn[db.ExportCampaignsRow] {
+	type row = db.ExportCampaignsRow
+	kpis := func(r row) dto.KPIResponse {
+		return buildKPIs(r.Impressions, r.Clicks, r.Conversions, r.Spend, r.Revenue)
+	}
+	return []exportColumn[row]{
+		{"id", func(r row) spreadsheet.Cell { return spreadsheet.Text(r.ID.String()) }},
+		{"title", func(r row) spreadsheet.Cell { return spreadsheet.Text(r.Title) }},
+		{"description", func(r row) spreadsheet.Cell { return spreadsheet.Text(utils.PtrToString(r.Description)) }},
+		{"status", func(r row) spreadsheet.Cell { return spreadsheet.Text(r.Status) }},
+		{"tags", func(r row) spreadsheet.Cell { return spreadsheet.Text(r.Tags) }},
+		{"start_date", func(r row) spreadsheet.Cell { return timeCell(r.StartDate, loc) }},
+		{"end_date", func(r row) spreadsheet.Cell { return timeCell(r.EndDate, loc) }},
+		{"budget", func(r row) spreadsheet.Cell { return spreadsheet.Float(r.Budget, 2) }},
+		{"impressions", func(r row) spreadsheet.Cell { return spreadsheet.Int(r.Impressions) }},
+		{"clicks", func(r row) spreadsheet.Cell { return spreadsheet.Int(r.Clicks) }},
+		{"conversions", func(r row) spreadsheet.Cell { return spreadsheet.Int(r.Conversions) }},
+		{"spend", func(r row) spreadsheet.Cell { return spreadsheet.Float(r.Spend, 2) }},
+		{"revenue", func(r row) spreadsheet.Cell { return spreadsheet.Float(r.Revenue, 2) }},
+		{"ctr", func(r row) spreadsheet.Cell { return ratioCell(kpis(r).CTR) }},
+		{"cpc", func(r row) spreadsheet.Cell { return ratioCell(kpis(r).CPC) }},
+		{"roas", func(r row) spreadsheet.Cell { return ratioCell(kpis(r).ROAS) }},
+		{"version", func(r row) spreadsheet.Cell { return spreadsheet.Int(int64(r.Version)) }},
+		{"created_at", func(r row) spreadsheet.Cell { return timeCell(r.CreatedAt, loc) }},
+		{"updated_at", func(r row) spreadsheet.Cell { return timeCell(r.UpdatedAt, loc) }},
+	}
+}
+
+func metricSeriesExportColumns(loc *time.Location) []exportColumn[db.ExportCampaignMetricSeriesRow] {
+	type row = db.ExportCampaignMetricSeriesRow
+	kpis := func(r row) dto.KPIResponse {
+		return buildKPIs(r.Impressions, r.Clicks, r.Conversions, r.Spend, r.Revenue)
+	}
+	return []exportColumn[row]{
+		{"period_start", func(r row) spreadsheet.Cell { return spreadsheet.Date(r.BucketStart.Time.In(loc)) }},
+		{"campaign_id", func(r row) spreadsheet.Cell { return spreadsheet.Text(r.CampaignID.String()) }},
+		{"campaign_title", func(r row) spreadsheet.Cell { return spreadsheet.Text(r.CampaignTitle) }},
+		{"impressions", func(r row) spreadsheet.Cell { return spreadsheet.Int(r.Impressions) }},
+		{"clicks", func(r row) spreadsheet.Cell { return spreadsheet.Int(r.Clicks) }},
+		{"conversions", func(r row) spreadsheet.Cell { return spreadsheet.Int(r.Conversions) }},
+		{"spend", func(r row) spreadsheet.Cell { return spreadsheet.Float(r.Spend, 2) }},
+		{"revenue", func(r row) spreadsheet.Cell { return spreadsheet.Float(r.Revenue, 2) }},
+		{"ctr", func(r row) spreadsheet.Cell { return ratioCell(kpis(r).CTR) }},
+		{"cpc", func(r row) spreadsheet.Cell { return ratioCell(kpis(r).CPC) }},
+		{"cpa", func(r row) spreadsheet.Cell { return ratioCell(kpis(r).CPA) }},
+		{"cpm", func(r row) spreadsheet.Cell { return ratioCell(kpis(r).CPM) }},
+		{"conversion_rate", func(r row) spreadsheet.Cell { return ratioCell(kpis(r).ConversionRate) }},
+		{"roas", func(r row) spreadsheet.Cell { return ratioCell(kpis(r).ROAS) }},
+	}
+}
+
+func timeCell(t pgtype.Timestamptz, loc *time.Location) spreadsheet.Cell {
+	if !t.Valid {
+		return spreadsheet.Empty()
+	}
+	return spreadsheet.DateTime(t.Time.In(loc))
+}
+
+// ratioCell leaves undefined KPIs (zero denominator) blank.
+func ratioCell(v *float64) spreadsheet.Cell {
+	if v == nil {
+		return spreadsheet.Empty()
+	}
+	return spreadsheet.Float(*v, 4)
+}
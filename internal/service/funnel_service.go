@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/utils"
+)
+
+var (
+	ErrFunnelNotFound  = errors.New("funnel not found")
+	ErrFunnelExists    = errors.New("a funnel with this name already exists")
+	ErrFunnelForbidden = errors.New("not allowed to manage this funnel")
+	ErrInvalidFunnel   = errors.New("invalid funnel")
+)
+
+const (
+	maxFunnelNameLength     = 100
+	defaultFunnelWindowDays = 30
+)
+
+type FunnelService struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewFunnelService(dbPool *pgxpool.Pool, queries *db.Queries) *FunnelService {
+	return &FunnelService{
+		dbPool:  dbPool,
+		queries: queries,
+	}
+}
+
+func (s *FunnelService) CreateFunnel(ctx context.Context, userID uuid.UUID, req dto.CreateFunnelRequest) (*dto.FunnelResponse, error) {
+	name, err := normalizeFunnelName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := normalizeFunnelSteps(req.Steps)
+	if err != nil {
+		return nil, err
+	}
+	windowDays := req.WindowDays
+	if windowDays == 0 {
+		windowDays = defaultFunnelWindowDays
+	}
+
+	funnel, err := s.queries.CreateFunnel(ctx, db.CreateFunnelParams{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		Steps:       steps,
+		WindowDays:  windowDays,
+		CreatedBy:   pgtype.UUID{Bytes: userID, Valid: true},
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrFunnelExists
+		}
+		return nil, err
+	}
+
+	res := toFunnelResponse(funnel)
+	return &res, nil
+}
+
+// ListFunnels returns every funnel of the workspace by name.
+func (s *FunnelService) ListFunnels(ctx context.Context) ([]dto.FunnelResponse, error) {
+	funnels, err := s.queries.ListFunnels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.FunnelResponse, 0, len(funnels))
+	for _, f := range funnels {
+		responses = append(responses, toFunnelResponse(f))
+	}
+	return responses, nil
+}
+
+func (s *FunnelService) GetFunnel(ctx context.Context, funnelID uuid.UUID) (*dto.FunnelResponse, error) {
+	funnel, err := s.queries.GetFunnel(ctx, funnelID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrFunnelNotFound
+		}
+		return nil, err
+	}
+
+	res := toFunnelResponse(funnel)
+	return &res, nil
+}
+
+// UpdateFunnel changes a funnel. Only its creator or an admin may.
+func (s *FunnelService) UpdateFunnel(ctx context.Context, userID uuid.UUID, role string, funnelID uuid.UUID, req dto.UpdateFunnelRequest) (*dto.FunnelResponse, error) {
+	arg := db.UpdateFunnelParams{
+		ID:         funnelID,
+		WindowDays: req.WindowDays,
+	}
+	if req.Name != nil {
+		name, err := normalizeFunnelName(*req.Name)
+		if err != nil {
+			return nil, err
+		}
+		arg.Name = &name
+	}
+	if req.Description != nil {
+		description := strings.TrimSpace(*req.Description)
+		arg.Description = &description
+	}
+	if req.Steps != nil {
+		steps, err := normalizeFunnelSteps(req.Steps)
+		if err != nil {
+			return nil, err
+		}
+		arg.Steps = steps
+	}
+
+	var funnel db.Funnel
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		current, err := q.GetFunnelForUpdate(ctx, funnelID)
+		if err != nil {
+			return err
+		}
+		if !canManageFunnel(current, userID, role) {
+			return ErrFunnelForbidden
+		}
+		funnel, err = q.UpdateFunnel(ctx, arg)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrFunnelNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, ErrFunnelExists
+		}
+		return nil, err
+	}
+
+	res := toFunnelResponse(funnel)
+	return &res, nil
+}
+
+// DeleteFunnel removes a funnel. Only its creator or an admin may.
+func (s *FunnelService) DeleteFunnel(ctx context.Context, userID uuid.UUID, role string, funnelID uuid.UUID) error {
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		current, err := q.GetFunnelForUpdate(ctx, funnelID)
+		if err != nil {
+			return err
+		}
+		if !canManageFunnel(current, userID, role) {
+			return ErrFunnelForbidden
+		}
+		_, err = q.DeleteFunnel(ctx, funnelID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrFunnelNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Report runs a funnel over the visitors whose first step happened in the
+// window on one of the user's campaigns. Each visitor counts towards the
+// campaign of that first step and reaches the steps they went through in
+// order within the funnel's window.
+func (s *FunnelService) Report(ctx context.Context, userID uuid.UUID, funnelID uuid.UUID, req dto.FunnelReportRequest) (*dto.FunnelReportResponse, error) {
+	window, err := resolveAnalyticsWindow(dto.AnalyticsRequest{
+		From:     req.From,
+		To:       req.To,
+		Timezone: req.Timezone,
+	}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	funnel, err := s.queries.GetFunnel(ctx, funnelID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrFunnelNotFound
+		}
+		return nil, err
+	}
+
+	arg := db.ListFunnelEventsParams{
+		WindowDays: funnel.WindowDays,
+		Steps:      funnel.Steps,
+		FirstStep:  funnel.Steps[0],
+		FromTime:   pgtype.Timestamptz{Time: window.from, Valid: true},
+		ToTime:     pgtype.Timestamptz{Time: window.to, Valid: true},
+		UserID:     userID,
+	}
+	if req.CampaignID != "" {
+		campaignID, err := uuid.Parse(req.CampaignID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid campaign ID", ErrInvalidFunnel)
+		}
+		arg.CampaignID = pgtype.UUID{Bytes: campaignID, Valid: true}
+	}
+	rows, err := s.queries.ListFunnelEvents(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	total := newFunnelTally(len(funnel.Steps))
+	campaigns := make(map[uuid.UUID]*funnelTally)
+	titles := make(map[uuid.UUID]string)
+	// Rows come grouped by visitor, the entering event first.
+	for start := 0; start < len(rows); {
+		end := start + 1
+		for end < len(rows) && rows[end].VisitorID == rows[start].VisitorID {
+			end++
+		}
+		journey := rows[start:end]
+		start = end
+
+		campaignID := journey[0].CampaignID
+		if campaigns[campaignID] == nil {
+			campaigns[campaignID] = newFunnelTally(len(funnel.Steps))
+			titles[campaignID] = journey[0].CampaignTitle
+		}
+
+		next := 0
+		var last time.Time
+		for _, e := range journey {
+			if next == len(funnel.Steps) {
+				break
+			}
+			if e.Event != funnel.Steps[next] {
+				continue
+			}
+			var elapsed *time.Duration
+			if next > 0 {
+				d := e.OccurredAt.Time.Sub(last)
+				elapsed = &d
+			}
+			total.reach(next, elapsed)
+			campaigns[campaignID].reach(next, elapsed)
+			last = e.OccurredAt.Time
+			next++
+		}
+	}
+
+	res := &dto.FunnelReportResponse{
+		Funnel:    toFunnelResponse(funnel),
+		From:      window.from,
+		To:        window.to,
+		Timezone:  window.loc.String(),
+		Steps:     total.steps(funnel.Steps),
+		Campaigns: make([]dto.CampaignFunnelResponse, 0, len(campaigns)),
+	}
+	for id, tally := range campaigns {
+		res.Campaigns = append(res.Campaigns, dto.CampaignFunnelResponse{
+			CampaignID: id.String(),
+			Title:      titles[id],
+			Steps:      tally.steps(funnel.Steps),
+		})
+	}
+	slices.SortFunc(res.Campaigns, func(a, b dto.CampaignFunnelResponse) int {
+		if c := strings.Compare(a.Title, b.Title); c != 0 {
+			return c
+		}
+		return strings.Compare(a.CampaignID, b.CampaignID)
+	})
+	return res, nil
+}
+
+// funnelTally counts the visitors reaching each step and the time they took
+// from the step before.
+type funnelTally struct {
+	reached []int64
+	seconds [][]float64
+}
+
+func newFunnelTally(steps int) *funnelTally {
+	return &funnelTally{
+		reached: make([]int64, steps),
+		seconds: make([][]float64, steps),
+	}
+}
+
+func (t *funnelTally) reach(step int, elapsed *time.Duration) {
+	t.reached[step]++
+	if elapsed != nil {
+		t.seconds[step] = append(t.seconds[step], elapsed.Seconds())
+	}
+}
+
+func (t *funnelTally) steps(events []string) []dto.FunnelStepResponse {
+	steps := make([]dto.FunnelStepResponse, len(events))
+	for i, event := range events {
+		step := dto.FunnelStepResponse{
+			Step:          i + 1,
+			Event:         event,
+			Visitors:      t.reached[i],
+			FromStart:     ratio(float64(t.reached[i]), float64(t.reached[0])),
+			MedianSeconds: median(t.seconds[i]),
+		}
+		if i > 0 {
+			step.FromPrevious = ratio(float64(t.reached[i]), float64(t.reached[i-1]))
+			step.DropOff = t.reached[i-1] - t.reached[i]
+		}
+		steps[i] = step
+	}
+	return steps
+}
+
+func median(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	m := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		m = (sorted[len(sorted)/2-1] + m) / 2
+	}
+	return &m
+}
+
+func normalizeFunnelName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalidFunnel)
+	}
+	if len([]rune(name)) > maxFunnelNameLength {
+		return "", fmt.Errorf("%w: name must be at most %d characters", ErrInvalidFunnel, maxFunnelNameLength)
+	}
+	return name, nil
+}
+
+// normalizeFunnelSteps lower-cases the step events and checks they are
+// click or valid conversion event names.
+func normalizeFunnelSteps(steps []string) ([]string, error) {
+	normalized := make([]string, len(steps))
+	for i, step := range steps {
+		step = strings.ToLower(strings.TrimSpace(step))
+		if !eventNamePattern.MatchString(step) {
+			return nil, fmt.Errorf("%w: step %d must be click or an event name of lower case letters, digits and underscores", ErrInvalidFunnel, i+1)
+		}
+		normalized[i] = step
+	}
+	return normalized, nil
+}
+
+// canManageFunnel reports whether a user may edit or delete a funnel: its
+// creator and admins may.
+func canManageFunnel(f db.Funnel, userID uuid.UUID, role string) bool {
+	if role == utils.RoleAdmin {
+		return true
+	}
+	return f.CreatedBy.Valid && f.CreatedBy.Bytes == userID
+}
+
+func toFunnelResponse(f db.Funnel) dto.FunnelResponse {
+	return dto.FunnelResponse{
+		ID:          f.ID.String(),
+		Name:        f.Name,
+		Description: f.Description,
+		Steps:       f.Steps,
+		WindowDays:  f.WindowDays,
+		CreatedBy:   uuidString(f.CreatedBy),
+		CreatedAt:   f.CreatedAt.Time,
+		UpdatedAt:   f.UpdatedAt.Time,
+	}
+}
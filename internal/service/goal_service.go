@@ -0,0 +1,516 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+)
+
+var (
+	ErrGoalNotFound = errors.New("goal not found")
+	ErrGoalExists   = errors.New("the campaign already has a goal for this metric")
+	ErrInvalidGoal  = errors.New("invalid goal")
+)
+
+const (
+	GoalAtLeast = "at_least"
+	GoalAtMost  = "at_most"
+
+	GoalOnTrack  = "on_track"
+	GoalAtRisk   = "at_risk"
+	GoalOffTrack = "off_track"
+	GoalAchieved = "achieved"
+	GoalMissed   = "missed"
+
+	// goalAtRiskPace is the share of the expected progress below which a
+	// cumulative at_least goal is off track rather than at risk.
+	goalAtRiskPace = 0.8
+	// goalAtRiskMargin is how far past its target a ratio, or the
+	// projection of an at_most total, may be while still only at risk.
+	goalAtRiskMargin = 0.1
+)
+
+var (
+	goalStatuses = []string{GoalOnTrack, GoalAtRisk, GoalOffTrack, GoalAchieved, GoalMissed}
+
+	// cumulativeGoalMetrics only grow as metrics come in; the others are
+	// ratios that can move either way until the deadline.
+	cumulativeGoalMetrics = []string{"impressions", "clicks", "conversions", "spend", "revenue"}
+	// costGoalMetrics aim for at most their target by default.
+	costGoalMetrics = []string{"spend", "cpc", "cpa", "cpm"}
+	// rateGoalMetrics are fractions and cannot exceed one.
+	rateGoalMetrics = []string{"ctr", "conversion_rate"}
+)
+
+type GoalService struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewGoalService(dbPool *pgxpool.Pool, queries *db.Queries) *GoalService {
+	return &GoalService{
+		dbPool:  dbPool,
+		queries: queries,
+	}
+}
+
+func (s *GoalService) CreateGoal(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.CreateGoalRequest) (*dto.GoalResponse, error) {
+	campaign, err := s.queries.GetCampaign(ctx, db.GetCampaignParams{
+		ID:     campaignID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	direction := req.Direction
+	if direction == "" {
+		direction = GoalAtLeast
+		if slices.Contains(costGoalMetrics, req.Metric) {
+			direction = GoalAtMost
+		}
+	}
+	deadline := campaign.EndDate.Time
+	if req.Deadline != nil {
+		deadline = *req.Deadline
+	} else if !campaign.EndDate.Valid {
+		return nil, fmt.Errorf("%w: deadline is required when the campaign has no end date", ErrInvalidGoal)
+	}
+	if err := validateGoal(req.Metric, req.Target, deadline, time.Now()); err != nil {
+		return nil, err
+	}
+
+	goal, err := s.queries.CreateCampaignGoal(ctx, db.CreateCampaignGoalParams{
+		CampaignID: campaignID,
+		Metric:     req.Metric,
+		Direction:  direction,
+		Target:     req.Target,
+		Deadline:   pgtype.Timestamptz{Time: deadline, Valid: true},
+		CreatedBy:  pgtype.UUID{Bytes: userID, Valid: true},
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrGoalExists
+		}
+		return nil, err
+	}
+
+	// The campaign may already meet the target.
+	if _, err := s.EvaluateCampaign(ctx, campaignID, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.getGoal(ctx, userID, campaignID, goal.ID)
+}
+
+// ListCampaignGoals returns the goals of a campaign by deadline.
+func (s *GoalService) ListCampaignGoals(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID) ([]dto.GoalResponse, error) {
+	if _, err := s.queries.GetCampaign(ctx, db.GetCampaignParams{
+		ID:     campaignID,
+		UserID: userID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := s.queries.ListGoalProgress(ctx, db.ListGoalProgressParams{
+		UserID:      userID,
+		CampaignIds: []uuid.UUID{campaignID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toGoalResponses(rows, time.Now(), nil), nil
+}
+
+// ListGoals returns the goals of all my campaigns by deadline, optionally
+// only those with the given statuses.
+func (s *GoalService) ListGoals(ctx context.Context, userID uuid.UUID, req dto.ListGoalsRequest) ([]dto.GoalResponse, error) {
+	var statuses []string
+	for _, value := range req.Status {
+		for _, status := range strings.Split(value, ",") {
+			if status = strings.TrimSpace(status); status == "" {
+				continue
+			}
+			if !slices.Contains(goalStatuses, status) {
+				return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidGoal, status)
+			}
+			statuses = append(statuses, status)
+		}
+	}
+
+	rows, err := s.queries.ListGoalProgress(ctx, db.ListGoalProgressParams{
+		UserID:      userID,
+		CampaignIds: []uuid.UUID{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toGoalResponses(rows, time.Now(), statuses), nil
+}
+
+func (s *GoalService) UpdateGoal(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, goalID uuid.UUID, req dto.UpdateGoalRequest) (*dto.GoalResponse, error) {
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		if _, err := q.GetCampaignForUpdate(ctx, db.GetCampaignForUpdateParams{
+			ID:     campaignID,
+			UserID: userID,
+		}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrCampaignNotFound
+			}
+			return err
+		}
+		goal, err := q.GetCampaignGoalForUpdate(ctx, db.GetCampaignGoalForUpdateParams{
+			ID:         goalID,
+			CampaignID: campaignID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrGoalNotFound
+			}
+			return err
+		}
+
+		arg := db.UpdateCampaignGoalParams{
+			ID:        goalID,
+			Direction: goal.Direction,
+			Target:    goal.Target,
+			Deadline:  goal.Deadline,
+		}
+		if req.Direction != nil {
+			arg.Direction = *req.Direction
+		}
+		if req.Target != nil {
+			arg.Target = *req.Target
+		}
+		if req.Deadline != nil {
+			arg.Deadline = pgtype.Timestamptz{Time: *req.Deadline, Valid: true}
+		}
+		if arg.Direction == goal.Direction && arg.Target == goal.Target && arg.Deadline.Time.Equal(goal.Deadline.Time) {
+			return nil
+		}
+		if err := validateGoal(goal.Metric, arg.Target, arg.Deadline.Time, time.Now()); err != nil {
+			return err
+		}
+		_, err = q.UpdateCampaignGoal(ctx, arg)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.EvaluateCampaign(ctx, campaignID, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.getGoal(ctx, userID, campaignID, goalID)
+}
+
+func (s *GoalService) DeleteGoal(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, goalID uuid.UUID) error {
+	if _, err := s.queries.GetCampaign(ctx, db.GetCampaignParams{
+		ID:     campaignID,
+		UserID: userID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCampaignNotFound
+		}
+		return err
+	}
+
+	rows, err := s.queries.DeleteCampaignGoal(ctx, db.DeleteCampaignGoalParams{
+		ID:         goalID,
+		CampaignID: campaignID,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrGoalNotFound
+	}
+	return nil
+}
+
+// EvaluateCampaign decides the open goals of a campaign that its metrics
+// settle as of now, notifying the owner of every goal achieved. It returns
+// the number of goals decided.
+func (s *GoalService) EvaluateCampaign(ctx context.Context, campaignID uuid.UUID, now time.Time) (int, error) {
+	return s.evaluate(ctx, db.ListOpenGoalProgressParams{
+		CampaignIds: []uuid.UUID{campaignID},
+		Now:         pgtype.Timestamptz{Time: now, Valid: true},
+	}, now)
+}
+
+// DecideDueGoals decides every open goal whose deadline has passed.
+func (s *GoalService) DecideDueGoals(ctx context.Context, now time.Time) (int, error) {
+	return s.evaluate(ctx, db.ListOpenGoalProgressParams{
+		CampaignIds: []uuid.UUID{},
+		OnlyDue:     true,
+		Now:         pgtype.Timestamptz{Time: now, Valid: true},
+	}, now)
+}
+
+func (s *GoalService) evaluate(ctx context.Context, arg db.ListOpenGoalProgressParams, now time.Time) (int, error) {
+	rows, err := s.queries.ListOpenGoalProgress(ctx, arg)
+	if err != nil {
+		return 0, err
+	}
+
+	decided := 0
+	for _, row := range rows {
+		progress := evaluateGoal(db.ListGoalProgressRow(row), now)
+		if progress.status != GoalAchieved && progress.status != GoalMissed {
+			continue
+		}
+
+		err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+			mark := db.MarkGoalMissedParams{
+				ID:        row.ID,
+				DecidedAt: pgtype.Timestamptz{Time: now, Valid: true},
+			}
+			if progress.status == GoalMissed {
+				_, err := q.MarkGoalMissed(ctx, mark)
+				return err
+			}
+
+			// Only the evaluation that actually decides the goal notifies,
+			// so concurrent metric uploads send one notification.
+			updated, err := q.MarkGoalAchieved(ctx, db.MarkGoalAchievedParams(mark))
+			if err != nil || updated == 0 {
+				return err
+			}
+			return notify(ctx, q, db.CreateNotificationsParams{
+				CampaignID: pgtype.UUID{Bytes: row.CampaignID, Valid: true},
+				GoalID:     pgtype.UUID{Bytes: row.ID, Valid: true},
+			}, map[uuid.UUID]string{row.UserID: NotificationGoalAchieved})
+		})
+		if err != nil {
+			return decided, err
+		}
+		decided++
+	}
+	return decided, nil
+}
+
+func (s *GoalService) getGoal(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, goalID uuid.UUID) (*dto.GoalResponse, error) {
+	rows, err := s.queries.ListGoalProgress(ctx, db.ListGoalProgressParams{
+		UserID:      userID,
+		CampaignIds: []uuid.UUID{campaignID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(rows, func(r db.ListGoalProgressRow) bool { return r.ID == goalID })
+	if i < 0 {
+		return nil, ErrGoalNotFound
+	}
+	res := toGoalResponse(rows[i], evaluateGoal(rows[i], time.Now()))
+	return &res, nil
+}
+
+type goalProgress struct {
+	status   string
+	current  *float64
+	expected *float64
+}
+
+// evaluateGoal works out where a goal stands at now. A goal decided before
+// keeps its outcome.
+func evaluateGoal(g db.ListGoalProgressRow, now time.Time) goalProgress {
+	kpis := buildKPIs(g.Impressions, g.Clicks, g.Conversions, g.Spend, g.Revenue)
+	p := goalProgress{current: goalMetricValue(g.Metric, kpis)}
+	switch {
+	case g.AchievedAt.Valid:
+		p.status = GoalAchieved
+		return p
+	case g.MissedAt.Valid:
+		p.status = GoalMissed
+		return p
+	}
+
+	met := p.current != nil && goalMet(g.Direction, *p.current, g.Target)
+	if !now.Before(g.Deadline.Time) {
+		p.status = GoalMissed
+		if met {
+			p.status = GoalAchieved
+		}
+		return p
+	}
+
+	// The share of the goal's time already elapsed, measured from the
+	// campaign start or, for campaigns without one, from when the goal was
+	// set.
+	start := g.CreatedAt.Time
+	if g.CampaignStart.Valid && g.CampaignStart.Time.Before(g.Deadline.Time) {
+		start = g.CampaignStart.Time
+	}
+	elapsed := 0.0
+	if total := g.Deadline.Time.Sub(start); total > 0 {
+		elapsed = min(max(float64(now.Sub(start))/float64(total), 0), 1)
+	}
+
+	if !slices.Contains(cumulativeGoalMetrics, g.Metric) {
+		switch {
+		case met:
+			p.status = GoalOnTrack
+		case p.current != nil && goalMet(g.Direction, *p.current, withMargin(g.Direction, g.Target)):
+			p.status = GoalAtRisk
+		case p.current == nil:
+			p.status = GoalAtRisk
+		default:
+			p.status = GoalOffTrack
+		}
+		return p
+	}
+
+	current := *p.current
+	if g.Direction == GoalAtMost {
+		if !met {
+			p.status = GoalMissed
+			return p
+		}
+		p.status = GoalOnTrack
+		if elapsed > 0 {
+			projected := current / elapsed
+			switch {
+			case projected <= g.Target:
+			case projected <= withMargin(GoalAtMost, g.Target):
+				p.status = GoalAtRisk
+			default:
+				p.status = GoalOffTrack
+			}
+		}
+		return p
+	}
+
+	if met {
+		p.status = GoalAchieved
+		return p
+	}
+	expected := g.Target * elapsed
+	p.expected = &expected
+	switch {
+	case expected == 0 || current >= expected:
+		p.status = GoalOnTrack
+	case current >= expected*goalAtRiskPace:
+		p.status = GoalAtRisk
+	default:
+		p.status = GoalOffTrack
+	}
+	return p
+}
+
+func goalMet(direction string, current, target float64) bool {
+	if direction == GoalAtMost {
+		return current <= target
+	}
+	return current >= target
+}
+
+// withMargin moves a target by goalAtRiskMargin in the direction that makes
+// it easier to meet.
+func withMargin(direction string, target float64) float64 {
+	if direction == GoalAtMost {
+		return target * (1 + goalAtRiskMargin)
+	}
+	return target * (1 - goalAtRiskMargin)
+}
+
+func goalMetricValue(metric string, k dto.KPIResponse) *float64 {
+	value := func(v float64) *float64 { return &v }
+	switch metric {
+	case "impressions":
+		return value(float64(k.Impressions))
+	case "clicks":
+		return value(float64(k.Clicks))
+	case "conversions":
+		return value(float64(k.Conversions))
+	case "spend":
+		return value(k.Spend)
+	case "revenue":
+		return value(k.Revenue)
+	case "ctr":
+		return k.CTR
+	case "cpc":
+		return k.CPC
+	case "cpa":
+		return k.CPA
+	case "cpm":
+		return k.CPM
+	case "conversion_rate":
+		return k.ConversionRate
+	case "roas":
+		return k.ROAS
+	}
+	return nil
+}
+
+func validateGoal(metric string, target float64, deadline time.Time, now time.Time) error {
+	if slices.Contains(rateGoalMetrics, metric) && target > 1 {
+		return fmt.Errorf("%w: %s is a fraction, so the target must be at most 1", ErrInvalidGoal, metric)
+	}
+	if !deadline.After(now) {
+		return fmt.Errorf("%w: deadline must be in the future", ErrInvalidGoal)
+	}
+	return nil
+}
+
+// goalLabel describes a goal, e.g. "conversions at least 10000".
+func goalLabel(metric, direction string, target float64) string {
+	return fmt.Sprintf("%s %s %s", metric, strings.ReplaceAll(direction, "_", " "), strconv.FormatFloat(target, 'f', -1, 64))
+}
+
+func toGoalResponses(rows []db.ListGoalProgressRow, now time.Time, statuses []string) []dto.GoalResponse {
+	responses := make([]dto.GoalResponse, 0, len(rows))
+	for _, row := range rows {
+		progress := evaluateGoal(row, now)
+		if len(statuses) > 0 && !slices.Contains(statuses, progress.status) {
+			continue
+		}
+		responses = append(responses, toGoalResponse(row, progress))
+	}
+	return responses
+}
+
+func toGoalResponse(g db.ListGoalProgressRow, p goalProgress) dto.GoalResponse {
+	res := dto.GoalResponse{
+		ID:            g.ID.String(),
+		CampaignID:    g.CampaignID.String(),
+		CampaignTitle: g.CampaignTitle,
+		Metric:        g.Metric,
+		Direction:     g.Direction,
+		Target:        g.Target,
+		Deadline:      g.Deadline.Time,
+		Current:       p.current,
+		Expected:      p.expected,
+		Status:        p.status,
+		CreatedAt:     g.CreatedAt.Time,
+		UpdatedAt:     g.UpdatedAt.Time,
+	}
+	if p.current != nil {
+		res.Progress = ratio(*p.current, g.Target)
+	}
+	if g.AchievedAt.Valid {
+		t := g.AchievedAt.Time
+		res.AchievedAt = &t
+	}
+	if g.MissedAt.Valid {
+		t := g.MissedAt.Time
+		res.MissedAt = &t
+	}
+	return res
+}
@@ -0,0 +1,502 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/spreadsheet"
+	"github.com/valenrio66/be-project/pkg/utils"
+)
+
+var (
+	ErrInvalidImport  = errors.New("invalid import")
+	ErrImportNotFound = errors.New("import not found")
+)
+
+const (
+	ImportModeAllOrNothing = "all_or_nothing"
+	ImportModePartial      = "partial"
+
+	ImportStatusPending    = "pending"
+	ImportStatusProcessing = "processing"
+	ImportStatusCompleted  = "completed"
+	ImportStatusFailed     = "failed"
+
+	maxImportRows = 10000
+	// maxImportSheetRows bounds the rows read from a file, leaving room for
+	// the header and blank rows on top of maxImportRows.
+	maxImportSheetRows = 2 * maxImportRows
+	// Files up to this many rows are imported within the request; larger
+	// ones are accepted and processed in the background.
+	syncImportRows       = 200
+	maxReportedRowErrors = 1000
+	// An import still unfinished this long after it was uploaded was cut
+	// short, by a restart for instance, and is reported as failed.
+	staleImportAge = 30 * time.Minute
+)
+
+var (
+	importFields         = []string{"title", "description", "start_date", "end_date", "budget"}
+	requiredImportFields = []string{"title", "start_date", "end_date", "budget"}
+
+	// importHeaderAliases maps normalised header names to campaign fields.
+	importHeaderAliases = map[string]string{
+		"title":       "title",
+		"name":        "title",
+		"campaign":    "title",
+		"description": "description",
+		"start_date":  "start_date",
+		"start":       "start_date",
+		"end_date":    "end_date",
+		"end":         "end_date",
+		"budget":      "budget",
+	}
+	headerNormalizer = strings.NewReplacer(" ", "_", "-", "_")
+)
+
+type ImportService struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewImportService(dbPool *pgxpool.Pool, queries *db.Queries) *ImportService {
+	return &ImportService{
+		dbPool:  dbPool,
+		queries: queries,
+	}
+}
+
+// importJob is a parsed upload ready to be validated and saved.
+type importJob struct {
+	importID uuid.UUID
+	userID   uuid.UUID
+	mode     string
+	columns  map[string]int
+	rows     []importRow
+	date1904 bool
+	// decimalComma is set for semicolon separated files, which write
+	// numbers as 1.234,56.
+	decimalComma bool
+}
+
+type importRow struct {
+	number int // spreadsheet row number, header is row 1
+	cells  []string
+}
+
+// ImportCampaigns parses an uploaded CSV or XLSX file and creates one draft
+// campaign per row. The header row is mapped to campaign fields first, so a
+// file with unusable columns is rejected before anything is recorded. Small
+// files are imported before returning; for larger ones the returned import is
+// still pending and async is true.
+func (s *ImportService) ImportCampaigns(ctx context.Context, userID uuid.UUID, req dto.ImportCampaignsRequest, filename string, data []byte) (res *dto.ImportResponse, async bool, err error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = ImportModeAllOrNothing
+	}
+
+	var format string
+	var sheet *spreadsheet.Sheet
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		format = "csv"
+		sheet, err = spreadsheet.ReadCSV(bytes.NewReader(data), maxImportSheetRows)
+	case ".xlsx":
+		format = "xlsx"
+		sheet, err = spreadsheet.ReadXLSX(bytes.NewReader(data), int64(len(data)), maxImportSheetRows)
+	default:
+		return nil, false, fmt.Errorf("%w: file must be .csv or .xlsx", ErrInvalidImport)
+	}
+	if errors.Is(err, spreadsheet.ErrRowLimit) {
+		return nil, false, fmt.Errorf("%w: file has more than %d rows", ErrInvalidImport, maxImportRows)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	if len(sheet.Rows) == 0 {
+		return nil, false, fmt.Errorf("%w: file is empty", ErrInvalidImport)
+	}
+
+	columns, err := mapImportColumns(sheet.Rows[0], req.Mapping)
+	if err != nil {
+		return nil, false, err
+	}
+
+	job := &importJob{
+		userID:       userID,
+		mode:         mode,
+		columns:      columns,
+		date1904:     sheet.Date1904,
+		decimalComma: sheet.Comma == ';',
+	}
+	for i, cells := range sheet.Rows[1:] {
+		if isBlankRow(cells) {
+			continue
+		}
+		job.rows = append(job.rows, importRow{number: i + 2, cells: cells})
+	}
+	if len(job.rows) == 0 {
+		return nil, false, fmt.Errorf("%w: file has no data rows", ErrInvalidImport)
+	}
+	if len(job.rows) > maxImportRows {
+		return nil, false, fmt.Errorf("%w: file has more than %d rows", ErrInvalidImport, maxImportRows)
+	}
+
+	record, err := s.queries.CreateCampaignImport(ctx, db.CreateCampaignImportParams{
+		UserID:    userID,
+		Filename:  filepath.Base(filename),
+		Format:    format,
+		Mode:      mode,
+		TotalRows: int32(len(job.rows)),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	job.importID = record.ID
+
+	if len(job.rows) > syncImportRows {
+		// The request context ends with the response, so the background run
+		// gets its own. An import interrupted by a restart is failed by
+		// GetImport once it is stale.
+		go func() {
+			if _, err := s.process(context.Background(), job); err != nil {
+				zap.L().Error("Campaign import failed", zap.String("import_id", job.importID.String()), zap.Error(err))
+			}
+		}()
+		res, err := toImportResponse(record)
+		return res, true, err
+	}
+
+	record, err = s.process(ctx, job)
+	if err != nil {
+		return nil, false, err
+	}
+	res, err = toImportResponse(record)
+	return res, false, err
+}
+
+func (s *ImportService) GetImport(ctx context.Context, userID uuid.UUID, importID uuid.UUID) (*dto.ImportResponse, error) {
+	record, err := s.queries.GetCampaignImport(ctx, db.GetCampaignImportParams{
+		ID:     importID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrImportNotFound
+		}
+		return nil, err
+	}
+	if (record.Status == ImportStatusPending || record.Status == ImportStatusProcessing) &&
+		time.Since(record.CreatedAt.Time) > staleImportAge {
+		failed, err := s.queries.FailCampaignImport(ctx, db.FailCampaignImportParams{
+			ID:    record.ID,
+			Error: utils.StringToPtr("the import was interrupted, nothing more will be imported"),
+		})
+		if err == nil {
+			record = failed
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+	}
+	return toImportResponse(record)
+}
+
+// process runs an import and records it as failed when it stops on an
+// error, so nobody polls an import stuck in processing.
+func (s *ImportService) process(ctx context.Context, job *importJob) (db.CampaignImport, error) {
+	record, err := s.run(ctx, job)
+	if err != nil {
+		// The request may have ended, which is often why the import failed.
+		failCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+		_, failErr := s.queries.FailCampaignImport(failCtx, db.FailCampaignImportParams{
+			ID:    job.importID,
+			Error: utils.StringToPtr("the import could not be completed"),
+		})
+		if failErr != nil && !errors.Is(failErr, pgx.ErrNoRows) {
+			zap.L().Error("Recording failed campaign import failed", zap.String("import_id", job.importID.String()), zap.Error(failErr))
+		}
+		return db.CampaignImport{}, err
+	}
+	return record, nil
+}
+
+// run validates every row and saves the valid ones. In all_or_nothing
+// mode a single invalid row means nothing is saved.
+func (s *ImportService) run(ctx context.Context, job *importJob) (db.CampaignImport, error) {
+	if err := s.queries.MarkCampaignImportProcessing(ctx, job.importID); err != nil {
+		return db.CampaignImport{}, err
+	}
+
+	var valid []dto.CampaignSnapshot
+	var rowErrors []dto.ImportRowError
+	failedRows := 0
+	for _, row := range job.rows {
+		snap, errs := parseImportRow(row, job.columns, job.date1904, job.decimalComma)
+		if len(errs) > 0 {
+			failedRows++
+			rowErrors = append(rowErrors, errs...)
+			continue
+		}
+		if err := checkLaunch(ctx, s.queries, job.userID, nil, snap); err != nil {
+			if !errors.Is(err, ErrApprovalRequired) {
+				return db.CampaignImport{}, err
+			}
+			failedRows++
+			rowErrors = append(rowErrors, dto.ImportRowError{Row: row.number, Field: "status", Message: err.Error()})
+			continue
+		}
+		valid = append(valid, snap)
+	}
+	if len(rowErrors) > maxReportedRowErrors {
+		rowErrors = rowErrors[:maxReportedRowErrors]
+	}
+
+	finish := db.FinishCampaignImportParams{
+		ID:         job.importID,
+		Status:     ImportStatusCompleted,
+		FailedRows: int32(failedRows),
+	}
+
+	if job.mode == ImportModeAllOrNothing && failedRows > 0 {
+		finish.Status = ImportStatusFailed
+		finish.Error = utils.StringToPtr(fmt.Sprintf("%d of %d rows failed validation, nothing was imported", failedRows, len(job.rows)))
+	} else if len(valid) > 0 {
+		err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+			for _, snap := range valid {
+				campaign, err := q.CreateCampaign(ctx, db.CreateCampaignParams{
+					UserID:      job.userID,
+					Title:       snap.Title,
+					Description: snap.Description,
+					Status:      snap.Status,
+					StartDate:   utils.ToPgTimestamp(snap.StartDate),
+					EndDate:     utils.ToPgTimestamp(snap.EndDate),
+					Budget:      snap.Budget,
+				})
+				if err != nil {
+					return err
+				}
+				if err := recordRevision(ctx, q, job.userID, RevisionCreated, campaign, nil, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			zap.L().Error("Saving imported campaigns failed", zap.String("import_id", job.importID.String()), zap.Error(err))
+			finish.Status = ImportStatusFailed
+			finish.Error = utils.StringToPtr("saving campaigns failed, nothing was imported")
+		} else {
+			finish.ImportedRows = int32(len(valid))
+		}
+	}
+
+	if rowErrors == nil {
+		rowErrors = []dto.ImportRowError{}
+	}
+	raw, err := json.Marshal(rowErrors)
+	if err != nil {
+		return db.CampaignImport{}, err
+	}
+	finish.RowErrors = raw
+
+	return s.queries.FinishCampaignImport(ctx, finish)
+}
+
+// mapImportColumns resolves each campaign field to a column index using the
+// caller's mapping first and the known header aliases second.
+func mapImportColumns(header []string, rawMapping string) (map[string]int, error) {
+	mapping := map[string]string{}
+	if strings.TrimSpace(rawMapping) != "" {
+		if err := json.Unmarshal([]byte(rawMapping), &mapping); err != nil {
+			return nil, fmt.Errorf("%w: mapping must be a JSON object of header to field", ErrInvalidImport)
+		}
+	}
+	normalized := make(map[string]string, len(mapping))
+	for h, field := range mapping {
+		if !slices.Contains(importFields, field) {
+			return nil, fmt.Errorf("%w: unknown field %q in mapping, expected one of %s", ErrInvalidImport, field, strings.Join(importFields, ", "))
+		}
+		normalized[normalizeHeader(h)] = field
+	}
+
+	columns := map[string]int{}
+	for i, h := range header {
+		key := normalizeHeader(h)
+		field, ok := normalized[key]
+		if !ok {
+			field, ok = importHeaderAliases[key]
+		}
+		if !ok {
+			continue
+		}
+		if _, dup := columns[field]; dup {
+			return nil, fmt.Errorf("%w: more than one column maps to %s", ErrInvalidImport, field)
+		}
+		columns[field] = i
+	}
+
+	for _, field := range requiredImportFields {
+		if _, ok := columns[field]; !ok {
+			return nil, fmt.Errorf("%w: no column for required field %s", ErrInvalidImport, field)
+		}
+	}
+	return columns, nil
+}
+
+func parseImportRow(row importRow, columns map[string]int, date1904, decimalComma bool) (dto.CampaignSnapshot, []dto.ImportRowError) {
+	cell := func(field string) string {
+		idx, ok := columns[field]
+		if !ok || idx >= len(row.cells) {
+			return ""
+		}
+		return strings.TrimSpace(row.cells[idx])
+	}
+
+	var errs []dto.ImportRowError
+	fail := func(field, message string) {
+		errs = append(errs, dto.ImportRowError{Row: row.number, Field: field, Message: message})
+	}
+
+	snap := dto.CampaignSnapshot{
+		Title:       cell("title"),
+		Description: utils.StringToPtr(cell("description")),
+		Status:      utils.CampaignStatusDraft,
+	}
+	if snap.Title == "" {
+		fail("title", "title is required")
+	}
+
+	for _, field := range []string{"start_date", "end_date"} {
+		value := cell(field)
+		if value == "" {
+			fail(field, field+" is required")
+			continue
+		}
+		t, err := parseImportDate(value, date1904)
+		if err != nil {
+			fail(field, field+" must be YYYY-MM-DD, RFC3339 or a spreadsheet date")
+			continue
+		}
+		if field == "start_date" {
+			snap.StartDate = &t
+		} else {
+			snap.EndDate = &t
+		}
+	}
+
+	if value := cell("budget"); value == "" {
+		fail("budget", "budget is required")
+	} else if budget, err := parseImportNumber(value, decimalComma); err != nil {
+		fail("budget", "budget must be a number")
+	} else {
+		snap.Budget = budget
+	}
+
+	if len(errs) == 0 {
+		if err := validateSnapshot(snap); err != nil {
+			fail("", strings.TrimPrefix(err.Error(), ErrInvalidCampaign.Error()+": "))
+		}
+	}
+	return snap, errs
+}
+
+// parseImportDate accepts the same formats as the list filters plus the
+// serial numbers spreadsheets store dates as.
+func parseImportDate(value string, date1904 bool) (time.Time, error) {
+	if t, _, err := parseTimeParam(value, time.UTC); err == nil {
+		return t, nil
+	}
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil || serial <= 0 {
+		return time.Time{}, errors.New("unrecognised date")
+	}
+	return spreadsheet.ExcelSerialTime(serial, date1904), nil
+}
+
+// parseImportNumber reads a number written with an optional thousands
+// separator. The decimal separator is a comma when decimalComma is set and a
+// point otherwise; the other one may only group thousands, so "5000,50" in a
+// comma separated file is rejected instead of read as 500050.
+func parseImportNumber(value string, decimalComma bool) (float64, error) {
+	decimal, group := ".", ","
+	if decimalComma {
+		decimal, group = ",", "."
+	}
+	whole, frac, hasFrac := strings.Cut(value, decimal)
+	if strings.Contains(frac, group) {
+		return 0, errors.New("misplaced thousands separator")
+	}
+	if strings.Contains(whole, group) {
+		digits := strings.TrimLeft(whole, "+-")
+		groups := strings.Split(digits, group)
+		for i, g := range groups {
+			if (i == 0 && (len(g) == 0 || len(g) > 3)) || (i > 0 && len(g) != 3) {
+				return 0, errors.New("misplaced thousands separator")
+			}
+		}
+		whole = whole[:len(whole)-len(digits)] + strings.Join(groups, "")
+	}
+	if hasFrac {
+		whole += "." + frac
+	}
+	n, err := strconv.ParseFloat(whole, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, errors.New("not a finite number")
+	}
+	return n, nil
+}
+
+func normalizeHeader(h string) string {
+	return headerNormalizer.Replace(strings.ToLower(strings.TrimSpace(h)))
+}
+
+func isBlankRow(cells []string) bool {
+	for _, c := range cells {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func toImportResponse(r db.CampaignImport) (*dto.ImportResponse, error) {
+	res := &dto.ImportResponse{
+		ID:           r.ID.String(),
+		Filename:     r.Filename,
+		Format:       r.Format,
+		Mode:         r.Mode,
+		Status:       r.Status,
+		TotalRows:    r.TotalRows,
+		ImportedRows: r.ImportedRows,
+		FailedRows:   r.FailedRows,
+		Error:        r.Error,
+		CreatedAt:    r.CreatedAt.Time,
+	}
+	if err := json.Unmarshal(r.RowErrors, &res.RowErrors); err != nil {
+		return nil, err
+	}
+	if r.CompletedAt.Valid {
+		res.CompletedAt = &r.CompletedAt.Time
+	}
+	return res, nil
+}
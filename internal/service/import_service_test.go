@@ -0,0 +1,70 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/utils"
+)
+
+func TestParseImportRowBudget(t *testing.T) {
+	columns := map[string]int{"title": 0, "start_date": 1, "end_date": 2, "budget": 3}
+	tests := []struct {
+		name         string
+		budget       string
+		decimalComma bool
+		want         float64
+		wantErr      bool
+	}{
+		{name: "plain", budget: "5000", want: 5000},
+		{name: "decimal point", budget: "5000.50", want: 5000.5},
+		{name: "grouped thousands", budget: "1,234.56", want: 1234.56},
+		{name: "decimal comma in a comma file", budget: "5000,50", wantErr: true},
+		{name: "decimal comma in a semicolon file", budget: "5000,50", decimalComma: true, want: 5000.5},
+		{name: "grouped thousands in a semicolon file", budget: "1.234,56", decimalComma: true, want: 1234.56},
+		{name: "point grouping in a comma file", budget: "1.234,56", wantErr: true},
+		{name: "misplaced group", budget: "12,34,567", wantErr: true},
+		{name: "NaN", budget: "NaN", wantErr: true},
+		{name: "Inf", budget: "Inf", wantErr: true},
+		{name: "negative infinity", budget: "-Infinity", decimalComma: true, wantErr: true},
+		{name: "negative", budget: "-1,000", wantErr: true},
+		{name: "text", budget: "lots", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := importRow{number: 2, cells: []string{"Sale", "2026-01-01", "2026-02-01", tt.budget}}
+			snap, errs := parseImportRow(row, columns, false, tt.decimalComma)
+			if tt.wantErr {
+				if len(errs) == 0 {
+					t.Fatalf("budget %q parsed as %v, want a row error", tt.budget, snap.Budget)
+				}
+				return
+			}
+			if len(errs) > 0 {
+				t.Fatalf("budget %q: %+v", tt.budget, errs)
+			}
+			if snap.Budget != tt.want {
+				t.Errorf("budget %q = %v, want %v", tt.budget, snap.Budget, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSnapshotRejectsNonFiniteBudget(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	for _, budget := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		snap := dto.CampaignSnapshot{
+			Title:     "Sale",
+			Status:    utils.CampaignStatusDraft,
+			StartDate: &start,
+			EndDate:   &end,
+			Budget:    budget,
+		}
+		if err := validateSnapshot(snap); err == nil {
+			t.Errorf("budget %v passed validation", budget)
+		}
+	}
+}
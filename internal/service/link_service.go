@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/tracking"
+)
+
+var (
+	ErrLinkNotFound = errors.New("link not found")
+	ErrInvalidLink  = errors.New("invalid link")
+)
+
+const (
+	// linkCodeAttempts bounds the retries when a generated code is taken.
+	linkCodeAttempts = 5
+	// maxClickHeaderLength caps the referrer and user agent stored per click.
+	maxClickHeaderLength = 1024
+	// clickGoalDelay batches the goal checks clicks trigger: a campaign's
+	// goals are evaluated this long after the first click since the last
+	// evaluation, off the redirect's path.
+	clickGoalDelay = 5 * time.Second
+)
+
+// LinkClick is one request to a tracked link.
+type LinkClick struct {
+	VisitorID uuid.UUID
+	IP        string
+	Referrer  string
+	UserAgent string
+	At        time.Time
+}
+
+type LinkService struct {
+	dbPool      *pgxpool.Pool
+	queries     *db.Queries
+	goalService *GoalService
+	baseURL     string
+	secret      []byte
+
+	// goalChecks holds the campaigns with a goal evaluation scheduled.
+	goalChecks sync.Map
+}
+
+// NewLinkService creates the tracked link service. baseURL is the public
+// origin serving /r/{code}; secret keys the hash of click IPs.
+func NewLinkService(dbPool *pgxpool.Pool, queries *db.Queries, goalService *GoalService, baseURL string, secret []byte) *LinkService {
+	return &LinkService{
+		dbPool:      dbPool,
+		queries:     queries,
+		goalService: goalService,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		secret:      secret,
+	}
+}
+
+func (s *LinkService) CreateLink(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.CreateLinkRequest) (*dto.LinkResponse, error) {
+	campaign, err := s.queries.GetCampaign(ctx, db.GetCampaignParams{
+		ID:     campaignID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	arg := db.CreateTrackedLinkParams{
+		CampaignID:     campaignID,
+		DestinationUrl: strings.TrimSpace(req.DestinationURL),
+		CreatedBy:      pgtype.UUID{Bytes: userID, Valid: true},
+	}
+	if _, err := tracking.ValidateURL(arg.DestinationUrl); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLink, err)
+	}
+
+	if req.Channel != "" {
+		channels, err := s.queries.ListCampaignChannels(ctx, campaignID)
+		if err != nil {
+			return nil, err
+		}
+		for _, ch := range channels {
+			if ch.Channel == req.Channel {
+				arg.ChannelID = pgtype.UUID{Bytes: ch.ID, Valid: true}
+			}
+		}
+		if !arg.ChannelID.Valid {
+			return nil, fmt.Errorf("%w: campaign has no %s channel", ErrChannelNotFound, req.Channel)
+		}
+	}
+	if name := strings.TrimSpace(req.Variant); name != "" {
+		variants, err := s.queries.ListCampaignVariants(ctx, campaignID)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range variants {
+			if strings.EqualFold(v.Name, name) {
+				arg.VariantID = pgtype.UUID{Bytes: v.ID, Valid: true}
+			}
+		}
+		if !arg.VariantID.Valid {
+			return nil, fmt.Errorf("%w: campaign has no variant %q", ErrVariantNotFound, name)
+		}
+	}
+
+	utm := tracking.UTM{
+		Source:   req.UTMSource,
+		Medium:   req.UTMMedium,
+		Campaign: req.UTMCampaign,
+		Term:     req.UTMTerm,
+		Content:  req.UTMContent,
+	}
+	if strings.TrimSpace(utm.Medium) == "" {
+		utm.Medium = req.Channel
+	}
+	if strings.TrimSpace(utm.Campaign) == "" {
+		utm.Campaign = campaign.Title
+	}
+	utm = utm.Normalized()
+	switch {
+	case utm.Source == "":
+		return nil, fmt.Errorf("%w: utm_source must contain a letter or digit", ErrInvalidLink)
+	case utm.Medium == "":
+		return nil, fmt.Errorf("%w: utm_medium is required for links without a channel", ErrInvalidLink)
+	case utm.Campaign == "":
+		return nil, fmt.Errorf("%w: utm_campaign must contain a letter or digit", ErrInvalidLink)
+	}
+	arg.UtmSource = utm.Source
+	arg.UtmMedium = utm.Medium
+	arg.UtmCampaign = utm.Campaign
+	arg.UtmTerm = utm.Term
+	arg.UtmContent = utm.Content
+
+	var link db.TrackedLink
+	for attempt := 1; ; attempt++ {
+		arg.Code = tracking.NewCode()
+		link, err = s.queries.CreateTrackedLink(ctx, arg)
+		if err == nil {
+			break
+		}
+		if !isUniqueViolation(err) || attempt == linkCodeAttempts {
+			return nil, err
+		}
+	}
+
+	return s.GetLink(ctx, userID, campaignID, link.ID)
+}
+
+// ListLinks returns the tracked links of a campaign, newest first.
+func (s *LinkService) ListLinks(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID) ([]dto.LinkResponse, error) {
+	if err := s.ensureCampaignOwner(ctx, userID, campaignID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.queries.ListTrackedLinks(ctx, db.ListTrackedLinksParams{CampaignID: campaignID})
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]dto.LinkResponse, 0, len(rows))
+	for _, row := range rows {
+		responses = append(responses, s.toLinkResponse(row))
+	}
+	return responses, nil
+}
+
+func (s *LinkService) GetLink(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, linkID uuid.UUID) (*dto.LinkResponse, error) {
+	if err := s.ensureCampaignOwner(ctx, userID, campaignID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.queries.ListTrackedLinks(ctx, db.ListTrackedLinksParams{
+		CampaignID: campaignID,
+		LinkID:     pgtype.UUID{Bytes: linkID, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrLinkNotFound
+	}
+	res := s.toLinkResponse(rows[0])
+	return &res, nil
+}
+
+// DeleteLink removes a link and its click log. Clicks already counted in
+// the campaign metrics stay there.
+func (s *LinkService) DeleteLink(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, linkID uuid.UUID) error {
+	if err := s.ensureCampaignOwner(ctx, userID, campaignID); err != nil {
+		return err
+	}
+
+	rows, err := s.queries.DeleteTrackedLink(ctx, db.DeleteTrackedLinkParams{
+		ID:         linkID,
+		CampaignID: campaignID,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrLinkNotFound
+	}
+	return nil
+}
+
+// Resolve logs a click on the link with the given code and returns the URL
+// to redirect to, carrying the click ID for conversion tracking. Clicks
+// that do not look like bots are added to the campaign's clicks for the
+// hour, under the link's channel and variant, and shortly after checked
+// against its goals.
+func (s *LinkService) Resolve(ctx context.Context, code string, click LinkClick) (string, error) {
+	link, err := s.queries.GetTrackedLinkByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrLinkNotFound
+		}
+		return "", err
+	}
+
+	target, err := tracking.Apply(link.DestinationUrl, linkUTM(link.UtmSource, link.UtmMedium, link.UtmCampaign, link.UtmTerm, link.UtmContent))
+	if err != nil {
+		return "", err
+	}
+
+	isBot := tracking.IsBot(click.UserAgent)
+	var clickID uuid.UUID
+	err = execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		id, err := q.CreateLinkClick(ctx, db.CreateLinkClickParams{
+			LinkID:    link.ID,
+			ClickedAt: pgtype.Timestamptz{Time: click.At, Valid: true},
+			VisitorID: click.VisitorID,
+			IpHash:    s.hashIP(click.IP),
+			Referrer:  truncate(click.Referrer, maxClickHeaderLength),
+			UserAgent: truncate(click.UserAgent, maxClickHeaderLength),
+			IsBot:     isBot,
+		})
+		if err != nil {
+			return err
+		}
+		clickID = id
+		if isBot {
+			return nil
+		}
+		return q.AddCampaignMetricEvents(ctx, db.AddCampaignMetricEventsParams{
+			CampaignID: link.CampaignID,
+			ChannelID:  link.ChannelID,
+			VariantID:  link.VariantID,
+			RecordedAt: pgtype.Timestamptz{Time: click.At.UTC().Truncate(time.Hour), Valid: true},
+			Clicks:     1,
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// The click can achieve the campaign's click goals.
+	if !isBot {
+		s.scheduleGoalCheck(link.CampaignID)
+	}
+	return tracking.WithClickID(target, clickID.String())
+}
+
+// scheduleGoalCheck evaluates the campaign's goals after clickGoalDelay,
+// unless an evaluation is already scheduled; that one will see the click.
+func (s *LinkService) scheduleGoalCheck(campaignID uuid.UUID) {
+	if _, scheduled := s.goalChecks.LoadOrStore(campaignID, struct{}{}); scheduled {
+		return
+	}
+	time.AfterFunc(clickGoalDelay, func() {
+		s.goalChecks.Delete(campaignID)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if _, err := s.goalService.EvaluateCampaign(ctx, campaignID, time.Now()); err != nil {
+			zap.L().Error("Evaluating campaign goals failed", zap.String("campaign_id", campaignID.String()), zap.Error(err))
+		}
+	})
+}
+
+func (s *LinkService) ensureCampaignOwner(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID) error {
+	if _, err := s.queries.GetCampaign(ctx, db.GetCampaignParams{
+		ID:     campaignID,
+		UserID: userID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCampaignNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// hashIP keys the hash so stored hashes cannot be reversed by hashing the
+// whole IPv4 space.
+func (s *LinkService) hashIP(ip string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(ip))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *LinkService) toLinkResponse(l db.ListTrackedLinksRow) dto.LinkResponse {
+	res := dto.LinkResponse{
+		ID:             l.ID.String(),
+		CampaignID:     l.CampaignID.String(),
+		Code:           l.Code,
+		ShortURL:       s.baseURL + "/r/" + l.Code,
+		DestinationURL: l.DestinationUrl,
+		Channel:        l.Channel,
+		Variant:        l.VariantName,
+		UTM: dto.UTMResponse{
+			Source:   l.UtmSource,
+			Medium:   l.UtmMedium,
+			Campaign: l.UtmCampaign,
+			Term:     l.UtmTerm,
+			Content:  l.UtmContent,
+		},
+		Clicks:    l.Clicks,
+		Visitors:  l.Visitors,
+		BotClicks: l.BotClicks,
+		CreatedAt: l.CreatedAt.Time,
+	}
+	// Destinations are validated on creation, so this cannot fail.
+	res.TaggedURL, _ = tracking.Apply(l.DestinationUrl, linkUTM(l.UtmSource, l.UtmMedium, l.UtmCampaign, l.UtmTerm, l.UtmContent))
+	if l.LastClickedAt.Valid {
+		t := l.LastClickedAt.Time
+		res.LastClickedAt = &t
+	}
+	return res
+}
+
+func linkUTM(source, medium, campaign, term, content string) tracking.UTM {
+	return tracking.UTM{
+		Source:   source,
+		Medium:   medium,
+		Campaign: campaign,
+		Term:     term,
+		Content:  content,
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
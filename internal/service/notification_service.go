@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/pagination"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// Notification types, from most to least specific. A member who qualifies
+// for several on one comment only gets the most specific.
+const (
+	NotificationMention = "mention"
+	NotificationReply   = "reply"
+	NotificationComment = "comment"
+	// NotificationGoalAchieved goes to the campaign owner.
+	NotificationGoalAchieved = "goal_achieved"
+)
+
+const (
+	notificationSortBy     = "created_at"
+	notificationExcerptLen = 140
+)
+
+type NotificationService struct {
+	queries *db.Queries
+}
+
+func NewNotificationService(queries *db.Queries) *NotificationService {
+	return &NotificationService{
+		queries: queries,
+	}
+}
+
+// ListNotifications pages through the user's notifications, newest first.
+func (s *NotificationService) ListNotifications(ctx context.Context, userID uuid.UUID, req dto.ListNotificationsRequest) (*dto.PageResponse, error) {
+	cur, err := decodeCursor(req.Cursor, notificationSortBy, pagination.OrderDesc)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil && cur.Backward {
+		return nil, ErrInvalidCursor
+	}
+	limit := pagination.Limit(req.Limit)
+
+	arg := db.ListNotificationsParams{
+		UserID:     userID,
+		UnreadOnly: req.UnreadOnly,
+		RowLimit:   int32(limit + 1),
+	}
+	if cur != nil {
+		arg.CursorID = pgtype.UUID{Bytes: cur.ID, Valid: true}
+		arg.CursorTime = pgtype.Timestamptz{Time: cur.Time, Valid: true}
+	}
+
+	rows, err := s.queries.ListNotifications(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	rows, hasNext, _ := pagination.Trim(rows, limit, cur)
+
+	responses := make([]dto.NotificationResponse, 0, len(rows))
+	for _, r := range rows {
+		responses = append(responses, toNotificationResponse(r))
+	}
+
+	var next *pagination.Cursor
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		next = &pagination.Cursor{
+			SortBy:    notificationSortBy,
+			SortOrder: pagination.OrderDesc,
+			Time:      last.CreatedAt.Time,
+			ID:        last.ID,
+		}
+	}
+	page := newPageInfo(limit, next, nil)
+
+	if req.IncludeTotal {
+		total, err := s.queries.CountUnreadNotifications(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return &dto.PageResponse{
+		Items: responses,
+		Page:  page,
+	}, nil
+}
+
+func (s *NotificationService) UnreadCount(ctx context.Context, userID uuid.UUID) (*dto.UnreadNotificationsResponse, error) {
+	unread, err := s.queries.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UnreadNotificationsResponse{Unread: unread}, nil
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID) error {
+	rows, err := s.queries.MarkNotificationRead(ctx, db.MarkNotificationReadParams{
+		ID:     notificationID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification as read and reports how many
+// there were.
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.queries.MarkAllNotificationsRead(ctx, userID)
+}
+
+// notify creates one notification of the given type for each recipient in
+// the order of their IDs. The actor in arg is never notified of their own
+// action.
+func notify(ctx context.Context, q *db.Queries, arg db.CreateNotificationsParams, recipients map[uuid.UUID]string) error {
+	if arg.ActorID.Valid {
+		delete(recipients, uuid.UUID(arg.ActorID.Bytes))
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	arg.UserIds = make([]uuid.UUID, 0, len(recipients))
+	for id := range recipients {
+		arg.UserIds = append(arg.UserIds, id)
+	}
+	slices.SortFunc(arg.UserIds, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+	arg.Types = make([]string, 0, len(arg.UserIds))
+	for _, id := range arg.UserIds {
+		arg.Types = append(arg.Types, recipients[id])
+	}
+
+	_, err := q.CreateNotifications(ctx, arg)
+	return err
+}
+
+func toNotificationResponse(n db.ListNotificationsRow) dto.NotificationResponse {
+	res := dto.NotificationResponse{
+		ID:            n.ID.String(),
+		Type:          n.Type,
+		CampaignID:    uuidString(n.CampaignID),
+		CampaignTitle: n.CampaignTitle,
+		CommentID:     uuidString(n.CommentID),
+		GoalID:        uuidString(n.GoalID),
+		CreatedAt:     n.CreatedAt.Time,
+	}
+	if n.ActorID.Valid && n.ActorName != nil && n.ActorEmail != nil {
+		res.Actor = &dto.UserSummary{
+			ID:       uuid.UUID(n.ActorID.Bytes).String(),
+			FullName: *n.ActorName,
+			Email:    *n.ActorEmail,
+		}
+	}
+	if n.CommentBody != nil {
+		excerpt := *n.CommentBody
+		if runes := []rune(excerpt); len(runes) > notificationExcerptLen {
+			excerpt = string(runes[:notificationExcerptLen]) + "…"
+		}
+		res.Excerpt = &excerpt
+	}
+	if n.GoalMetric != nil && n.GoalDirection != nil && n.GoalTarget != nil {
+		excerpt := goalLabel(*n.GoalMetric, *n.GoalDirection, *n.GoalTarget)
+		res.Excerpt = &excerpt
+	}
+	if n.ReadAt.Valid {
+		read := n.ReadAt.Time
+		res.ReadAt = &read
+	}
+	return res
+}
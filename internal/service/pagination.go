@@ -0,0 +1,34 @@
+package service
+
+import (
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/pagination"
+)
+
+var ErrInvalidCursor = pagination.ErrInvalidCursor
+
+// decodeCursor parses a client cursor and rejects cursors that were issued
+// for a different sort, since their keys would not line up with the query.
+func decodeCursor(token, sortBy, sortOrder string) (*pagination.Cursor, error) {
+	cur, err := pagination.Decode(token)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil && (cur.SortBy != sortBy || cur.SortOrder != sortOrder) {
+		return nil, ErrInvalidCursor
+	}
+	return cur, nil
+}
+
+func newPageInfo(limit int, next, prev *pagination.Cursor) dto.PageInfo {
+	page := dto.PageInfo{Limit: limit}
+	if next != nil {
+		token := next.Encode()
+		page.NextCursor = &token
+	}
+	if prev != nil {
+		token := prev.Encode()
+		page.PrevCursor = &token
+	}
+	return page
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+)
+
+const (
+	RevisionCreated  = "created"
+	RevisionUpdated  = "updated"
+	RevisionDeleted  = "deleted"
+	RevisionRestored = "restored"
+	RevisionReverted = "reverted"
+)
+
+func snapshotOf(c db.Campaign) dto.CampaignSnapshot {
+	snap := dto.CampaignSnapshot{
+		Title:       c.Title,
+		Description: c.Description,
+		Status:      c.Status,
+		Budget:      c.Budget,
+	}
+	if c.StartDate.Valid {
+		snap.StartDate = &c.StartDate.Time
+	}
+	if c.EndDate.Valid {
+		snap.EndDate = &c.EndDate.Time
+	}
+	return snap
+}
+
+// diffSnapshots lists the fields that differ between two snapshots. Values are
+// compared in their JSON form, which is also how they are stored.
+func diffSnapshots(before, after dto.CampaignSnapshot) map[string]dto.FieldChange {
+	fields := []struct {
+		name          string
+		before, after interface{}
+	}{
+		{"title", before.Title, after.Title},
+		{"description", before.Description, after.Description},
+		{"status", before.Status, after.Status},
+		{"start_date", utcTime(before.StartDate), utcTime(after.StartDate)},
+		{"end_date", utcTime(before.EndDate), utcTime(after.EndDate)},
+		{"budget", before.Budget, after.Budget},
+	}
+
+	changes := make(map[string]dto.FieldChange)
+	for _, f := range fields {
+		b, _ := json.Marshal(f.before)
+		a, _ := json.Marshal(f.after)
+		if !bytes.Equal(a, b) {
+			changes[f.name] = dto.FieldChange{Before: f.before, After: f.after}
+		}
+	}
+	return changes
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// recordRevision appends the next revision to a campaign's history. It must
+// run in the same transaction as the change it describes.
+func recordRevision(ctx context.Context, q *db.Queries, actorID uuid.UUID, action string, campaign db.Campaign, changes map[string]dto.FieldChange, sourceRevision *int32) error {
+	if changes == nil {
+		changes = map[string]dto.FieldChange{}
+	}
+	rawChanges, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	rawSnapshot, err := json.Marshal(snapshotOf(campaign))
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateCampaignRevision(ctx, db.CreateCampaignRevisionParams{
+		CampaignID:     campaign.ID,
+		ActorID:        pgtype.UUID{Bytes: actorID, Valid: true},
+		Action:         action,
+		Changes:        rawChanges,
+		Snapshot:       rawSnapshot,
+		SourceRevision: sourceRevision,
+	})
+	return err
+}
+
+func toRevisionResponse(r db.ListCampaignRevisionsRow) (dto.CampaignRevisionResponse, error) {
+	res := dto.CampaignRevisionResponse{
+		ID:             r.ID.String(),
+		CampaignID:     r.CampaignID.String(),
+		Revision:       r.Revision,
+		Action:         r.Action,
+		ActorEmail:     r.ActorEmail,
+		SourceRevision: r.SourceRevision,
+		CreatedAt:      r.CreatedAt.Time,
+	}
+	if r.ActorID.Valid {
+		actorID := uuid.UUID(r.ActorID.Bytes).String()
+		res.ActorID = &actorID
+	}
+	if err := json.Unmarshal(r.Changes, &res.Changes); err != nil {
+		return res, err
+	}
+	if err := json.Unmarshal(r.Snapshot, &res.Snapshot); err != nil {
+		return res, err
+	}
+	return res, nil
+}
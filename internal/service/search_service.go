@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/pagination"
+	"github.com/valenrio66/be-project/pkg/utils"
+)
+
+var ErrEmptySearchQuery = errors.New("search query has no searchable terms")
+
+const (
+	searchSortBy   = "rank"
+	maxSearchTerms = 10
+
+	// Postgres marks matches with these control characters so the rest of the
+	// text can be HTML-escaped before the markers become <mark> tags.
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+var (
+	searchTermPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+	highlightOptions  = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `"`
+	highlightReplacer = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+)
+
+type SearchService struct {
+	queries *db.Queries
+}
+
+func NewSearchService(queries *db.Queries) *SearchService {
+	return &SearchService{
+		queries: queries,
+	}
+}
+
+func (s *SearchService) SearchCampaigns(ctx context.Context, userID uuid.UUID, req dto.SearchRequest) (*dto.PageResponse, error) {
+	query := buildPrefixQuery(req.Query)
+	if query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+
+	cur, err := decodeCursor(req.Cursor, searchSortBy, pagination.OrderDesc)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil && cur.Backward {
+		return nil, ErrInvalidCursor
+	}
+	limit := pagination.Limit(req.Limit)
+
+	arg := db.SearchCampaignsParams{
+		Query:            query,
+		HighlightOptions: highlightOptions,
+		UserID:           userID,
+		RowLimit:         int32(limit + 1),
+	}
+	if cur != nil {
+		arg.CursorID = pgtype.UUID{Bytes: cur.ID, Valid: true}
+		arg.CursorRank = cur.Number
+	}
+
+	rows, err := s.queries.SearchCampaigns(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	rows, hasNext, _ := pagination.Trim(rows, limit, cur)
+
+	results := make([]dto.SearchResultResponse, 0, len(rows))
+	for _, r := range rows {
+		results = append(results, dto.SearchResultResponse{
+			Campaign: dto.CampaignResponse{
+				ID:          r.ID.String(),
+				UserID:      r.UserID.String(),
+				Title:       r.Title,
+				Description: utils.PtrToString(r.Description),
+				Status:      r.Status,
+				StartDate:   r.StartDate.Time,
+				EndDate:     r.EndDate.Time,
+				Budget:      r.Budget,
+				Version:     r.Version,
+				CreatedAt:   r.CreatedAt.Time,
+			},
+			Rank:           r.Rank,
+			TitleHighlight: renderHighlight(r.TitleHighlight),
+			Snippet:        renderHighlight(r.Snippet),
+		})
+	}
+	tagged := make([]*dto.CampaignResponse, len(results))
+	for i := range results {
+		tagged[i] = &results[i].Campaign
+	}
+	if err := attachCampaignDetails(ctx, s.queries, tagged...); err != nil {
+		return nil, err
+	}
+
+	// Relevance results only page forward; a prev cursor is never issued.
+	var next *pagination.Cursor
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		next = &pagination.Cursor{
+			SortBy:    searchSortBy,
+			SortOrder: pagination.OrderDesc,
+			Number:    last.Rank,
+			ID:        last.ID,
+		}
+	}
+	page := newPageInfo(limit, next, nil)
+
+	if req.IncludeTotal {
+		total, err := s.queries.CountSearchCampaigns(ctx, db.CountSearchCampaignsParams{
+			UserID: userID,
+			Query:  query,
+		})
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return &dto.PageResponse{
+		Items: results,
+		Page:  page,
+	}, nil
+}
+
+// buildPrefixQuery turns free text into a to_tsquery expression in which
+// every term must match as a prefix, e.g. "spring promo sho" becomes
+// "spring:* & promo:* & sho:*". Only letters and digits survive, so the
+// result can never contain tsquery operators supplied by the user.
+func buildPrefixQuery(input string) string {
+	terms := searchTermPattern.FindAllString(strings.ToLower(input), maxSearchTerms)
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
+
+func renderHighlight(fragment string) string {
+	return highlightReplacer.Replace(html.EscapeString(fragment))
+}
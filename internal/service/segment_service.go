@@ -0,0 +1,533 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/audience"
+	"github.com/valenrio66/be-project/pkg/pagination"
+	"github.com/valenrio66/be-project/pkg/utils"
+)
+
+var (
+	ErrSegmentNotFound  = errors.New("segment not found")
+	ErrSegmentExists    = errors.New("a segment with this name already exists")
+	ErrInvalidSegment   = errors.New("invalid segment")
+	ErrSegmentForbidden = errors.New("not allowed to manage this segment")
+	// ErrSegmentInUse is returned when deleting a segment that campaigns,
+	// including campaigns in the trash, still use.
+	ErrSegmentInUse = errors.New("segment is used by campaigns")
+	// ErrUnknownSegments is returned when a request body references segments
+	// that do not exist.
+	ErrUnknownSegments = errors.New("unknown segments")
+)
+
+const (
+	maxSegmentNameLength = 100
+
+	segmentCampaignSortBy = "segment_campaigns"
+)
+
+type SegmentService struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewSegmentService(dbPool *pgxpool.Pool, queries *db.Queries) *SegmentService {
+	return &SegmentService{
+		dbPool:  dbPool,
+		queries: queries,
+	}
+}
+
+// Fields lists the fields segment rules can test.
+func (s *SegmentService) Fields() []dto.SegmentFieldResponse {
+	fields := audience.Fields()
+	responses := make([]dto.SegmentFieldResponse, 0, len(fields))
+	for _, f := range fields {
+		responses = append(responses, dto.SegmentFieldResponse{
+			Name:     f.Name,
+			Category: f.Category,
+			Label:    f.Label,
+			Ops:      f.Ops,
+			Values:   f.Values,
+		})
+	}
+	return responses
+}
+
+// ValidateRules checks a rule tree without saving anything. An invalid tree
+// is not an error here; the problems are part of the response.
+func (s *SegmentService) ValidateRules(req dto.ValidateSegmentRequest) (*dto.SegmentValidationResponse, error) {
+	rules, summary, err := parseSegmentRules(req.Rules)
+	if err != nil {
+		if errs := SegmentRuleErrors(err); errs != nil {
+			return &dto.SegmentValidationResponse{Errors: errs}, nil
+		}
+		return nil, err
+	}
+	return &dto.SegmentValidationResponse{
+		Valid:   true,
+		Rules:   rules,
+		Summary: summary,
+	}, nil
+}
+
+func (s *SegmentService) CreateSegment(ctx context.Context, userID uuid.UUID, req dto.CreateSegmentRequest) (*dto.SegmentResponse, error) {
+	name, err := normalizeSegmentName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	rules, summary, err := parseSegmentRules(req.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	segment, err := s.queries.CreateSegment(ctx, db.CreateSegmentParams{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		Rules:       rules,
+		Summary:     summary,
+		CreatedBy:   pgtype.UUID{Bytes: userID, Valid: true},
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrSegmentExists
+		}
+		return nil, err
+	}
+
+	res := toSegmentResponse(db.ListSegmentsRow{
+		ID:          segment.ID,
+		Name:        segment.Name,
+		Description: segment.Description,
+		Rules:       segment.Rules,
+		Summary:     segment.Summary,
+		CreatedBy:   segment.CreatedBy,
+		CreatedAt:   segment.CreatedAt,
+		UpdatedAt:   segment.UpdatedAt,
+	})
+	return &res, nil
+}
+
+// ListSegments returns every workspace segment by name, each with the number
+// of campaigns using it.
+func (s *SegmentService) ListSegments(ctx context.Context, req dto.ListSegmentsRequest) ([]dto.SegmentResponse, error) {
+	var search *string
+	if q := strings.TrimSpace(req.Query); q != "" {
+		pattern := "%" + likeEscaper.Replace(q) + "%"
+		search = &pattern
+	}
+
+	segments, err := s.queries.ListSegments(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.SegmentResponse, 0, len(segments))
+	for _, segment := range segments {
+		responses = append(responses, toSegmentResponse(segment))
+	}
+	return responses, nil
+}
+
+func (s *SegmentService) GetSegment(ctx context.Context, segmentID uuid.UUID) (*dto.SegmentResponse, error) {
+	segment, err := s.queries.GetSegment(ctx, segmentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSegmentNotFound
+		}
+		return nil, err
+	}
+	count, err := s.queries.CountSegmentCampaigns(ctx, db.CountSegmentCampaignsParams{
+		SegmentID: segmentID,
+		AllOwners: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := toSegmentResponse(db.ListSegmentsRow{
+		ID:            segment.ID,
+		Name:          segment.Name,
+		Description:   segment.Description,
+		Rules:         segment.Rules,
+		Summary:       segment.Summary,
+		CreatedBy:     segment.CreatedBy,
+		CreatedAt:     segment.CreatedAt,
+		UpdatedAt:     segment.UpdatedAt,
+		CampaignCount: count,
+	})
+	return &res, nil
+}
+
+// UpdateSegment changes a segment in place; campaigns using it target the
+// new definition from then on. Only its creator or an admin may edit it.
+func (s *SegmentService) UpdateSegment(ctx context.Context, userID uuid.UUID, role string, segmentID uuid.UUID, req dto.UpdateSegmentRequest) (*dto.SegmentResponse, error) {
+	arg := db.UpdateSegmentParams{ID: segmentID}
+	if req.Name != nil {
+		name, err := normalizeSegmentName(*req.Name)
+		if err != nil {
+			return nil, err
+		}
+		arg.Name = &name
+	}
+	if req.Description != nil {
+		description := strings.TrimSpace(*req.Description)
+		arg.Description = &description
+	}
+	if len(req.Rules) > 0 {
+		rules, summary, err := parseSegmentRules(req.Rules)
+		if err != nil {
+			return nil, err
+		}
+		arg.Rules = rules
+		arg.Summary = &summary
+	}
+
+	var segment db.AudienceSegment
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		current, err := q.GetSegmentForUpdate(ctx, segmentID)
+		if err != nil {
+			return err
+		}
+		if !canManageSegment(current, userID, role) {
+			return ErrSegmentForbidden
+		}
+		segment, err = q.UpdateSegment(ctx, arg)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSegmentNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, ErrSegmentExists
+		}
+		return nil, err
+	}
+	return s.GetSegment(ctx, segment.ID)
+}
+
+// DeleteSegment removes a segment no campaign uses. Only its creator or an
+// admin may delete it.
+func (s *SegmentService) DeleteSegment(ctx context.Context, userID uuid.UUID, role string, segmentID uuid.UUID) error {
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		current, err := q.GetSegmentForUpdate(ctx, segmentID)
+		if err != nil {
+			return err
+		}
+		if !canManageSegment(current, userID, role) {
+			return ErrSegmentForbidden
+		}
+		_, err = q.DeleteSegment(ctx, segmentID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrSegmentNotFound
+		}
+		if isForeignKeyViolation(err) {
+			return ErrSegmentInUse
+		}
+		return err
+	}
+	return nil
+}
+
+// ListSegmentCampaigns pages through the campaigns using a segment, newest
+// first. Managers and admins see every campaign, others only their own.
+func (s *SegmentService) ListSegmentCampaigns(ctx context.Context, userID uuid.UUID, role string, segmentID uuid.UUID, req dto.PageRequest) (*dto.PageResponse, error) {
+	cur, err := decodeCursor(req.Cursor, segmentCampaignSortBy, pagination.OrderDesc)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil && cur.Backward {
+		return nil, ErrInvalidCursor
+	}
+	limit := pagination.Limit(req.Limit)
+
+	if _, err := s.queries.GetSegment(ctx, segmentID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSegmentNotFound
+		}
+		return nil, err
+	}
+
+	allOwners := slices.Contains(approverRoles, role)
+	arg := db.ListSegmentCampaignsParams{
+		SegmentID: segmentID,
+		AllOwners: allOwners,
+		UserID:    userID,
+		RowLimit:  int32(limit + 1),
+	}
+	if cur != nil {
+		arg.CursorID = pgtype.UUID{Bytes: cur.ID, Valid: true}
+		arg.CursorTime = pgtype.Timestamptz{Time: cur.Time, Valid: true}
+	}
+	campaigns, err := s.queries.ListSegmentCampaigns(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	campaigns, hasNext, _ := pagination.Trim(campaigns, limit, cur)
+
+	responses := make([]dto.CampaignResponse, 0, len(campaigns))
+	for _, c := range campaigns {
+		responses = append(responses, toCampaignResponse(c))
+	}
+	ptrs := make([]*dto.CampaignResponse, 0, len(responses))
+	for i := range responses {
+		ptrs = append(ptrs, &responses[i])
+	}
+	if err := attachCampaignDetails(ctx, s.queries, ptrs...); err != nil {
+		return nil, err
+	}
+
+	var next *pagination.Cursor
+	if hasNext && len(campaigns) > 0 {
+		last := campaigns[len(campaigns)-1]
+		next = &pagination.Cursor{
+			SortBy:    segmentCampaignSortBy,
+			SortOrder: pagination.OrderDesc,
+			Time:      last.CreatedAt.Time,
+			ID:        last.ID,
+		}
+	}
+	page := newPageInfo(limit, next, nil)
+	if req.IncludeTotal {
+		total, err := s.queries.CountSegmentCampaigns(ctx, db.CountSegmentCampaignsParams{
+			SegmentID: segmentID,
+			AllOwners: allOwners,
+			UserID:    userID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return &dto.PageResponse{
+		Items: responses,
+		Page:  page,
+	}, nil
+}
+
+// ReplaceCampaignSegments makes segmentIDs the exact segment set of a
+// campaign.
+func (s *SegmentService) ReplaceCampaignSegments(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.CampaignSegmentsRequest) (*dto.CampaignResponse, error) {
+	segmentIDs, err := parseSegmentIDs(req.SegmentIDs)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateCampaignSegments(ctx, userID, campaignID, segmentIDs, func(q *db.Queries) (int64, error) {
+		removed, err := q.RemoveOtherCampaignSegments(ctx, db.RemoveOtherCampaignSegmentsParams{
+			CampaignID:     campaignID,
+			KeepSegmentIds: segmentIDs,
+		})
+		if err != nil {
+			return 0, err
+		}
+		added, err := q.AddCampaignSegments(ctx, db.AddCampaignSegmentsParams{
+			CampaignID: campaignID,
+			SegmentIds: segmentIDs,
+		})
+		return removed + added, err
+	})
+}
+
+func (s *SegmentService) AddCampaignSegments(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.CampaignSegmentsRequest) (*dto.CampaignResponse, error) {
+	segmentIDs, err := parseSegmentIDs(req.SegmentIDs)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateCampaignSegments(ctx, userID, campaignID, segmentIDs, func(q *db.Queries) (int64, error) {
+		return q.AddCampaignSegments(ctx, db.AddCampaignSegmentsParams{
+			CampaignID: campaignID,
+			SegmentIds: segmentIDs,
+		})
+	})
+}
+
+func (s *SegmentService) RemoveCampaignSegment(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, segmentID uuid.UUID) (*dto.CampaignResponse, error) {
+	return s.updateCampaignSegments(ctx, userID, campaignID, nil, func(q *db.Queries) (int64, error) {
+		return q.RemoveCampaignSegments(ctx, db.RemoveCampaignSegmentsParams{
+			CampaignID: campaignID,
+			SegmentIds: []uuid.UUID{segmentID},
+		})
+	})
+}
+
+// updateCampaignSegments locks the campaign, checks that segmentIDs exist
+// and runs apply. Like tags, segments move the campaign version only when
+// apply changed something.
+func (s *SegmentService) updateCampaignSegments(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, segmentIDs []uuid.UUID, apply func(q *db.Queries) (int64, error)) (*dto.CampaignResponse, error) {
+	var campaign db.Campaign
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		var err error
+		campaign, err = q.GetCampaignForUpdate(ctx, db.GetCampaignForUpdateParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		if len(segmentIDs) > 0 {
+			count, err := q.CountExistingSegments(ctx, segmentIDs)
+			if err != nil {
+				return err
+			}
+			if count != int64(len(segmentIDs)) {
+				return ErrUnknownSegments
+			}
+		}
+
+		changed, err := apply(q)
+		if err != nil || changed == 0 {
+			return err
+		}
+		campaign, err = q.TouchCampaign(ctx, db.TouchCampaignParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	res := toCampaignResponse(campaign)
+	if err := attachCampaignDetails(ctx, s.queries, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// attachCampaignSegments loads the segments of the given campaigns with one
+// query.
+func attachCampaignSegments(ctx context.Context, q *db.Queries, responses ...*dto.CampaignResponse) error {
+	ids := make([]uuid.UUID, 0, len(responses))
+	byID := make(map[uuid.UUID]*dto.CampaignResponse, len(responses))
+	for _, res := range responses {
+		res.Segments = []dto.SegmentSummary{}
+		id, err := uuid.Parse(res.ID)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+		byID[id] = res
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := q.ListCampaignSegments(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		res := byID[row.CampaignID]
+		res.Segments = append(res.Segments, dto.SegmentSummary{
+			ID:      row.ID.String(),
+			Name:    row.Name,
+			Summary: row.Summary,
+		})
+	}
+	return nil
+}
+
+// SegmentRuleErrors returns the rule problems carried by err, or nil when
+// err is not about the rule tree.
+func SegmentRuleErrors(err error) []dto.SegmentRuleError {
+	var ruleErrs audience.Errors
+	if !errors.As(err, &ruleErrs) {
+		return nil
+	}
+	errs := make([]dto.SegmentRuleError, 0, len(ruleErrs))
+	for _, e := range ruleErrs {
+		errs = append(errs, dto.SegmentRuleError{Path: e.Path, Message: e.Message})
+	}
+	return errs
+}
+
+// parseSegmentRules validates a rule tree and returns its canonical JSON and
+// summary.
+func parseSegmentRules(raw json.RawMessage) ([]byte, string, error) {
+	rule, err := audience.Parse(raw)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", ErrInvalidSegment, err)
+	}
+	rules, err := json.Marshal(rule)
+	if err != nil {
+		return nil, "", err
+	}
+	return rules, audience.Summary(rule), nil
+}
+
+// parseSegmentIDs parses and de-duplicates segment IDs.
+func parseSegmentIDs(raw []string) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	for _, value := range raw {
+		id, err := uuid.Parse(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a segment ID", ErrInvalidSegment, value)
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func normalizeSegmentName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalidSegment)
+	}
+	if len([]rune(name)) > maxSegmentNameLength {
+		return "", fmt.Errorf("%w: name must be at most %d characters", ErrInvalidSegment, maxSegmentNameLength)
+	}
+	return name, nil
+}
+
+// canManageSegment reports whether a user may edit or delete a segment:
+// its creator and admins may.
+func canManageSegment(s db.AudienceSegment, userID uuid.UUID, role string) bool {
+	if role == utils.RoleAdmin {
+		return true
+	}
+	return s.CreatedBy.Valid && s.CreatedBy.Bytes == userID
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
+
+func toSegmentResponse(s db.ListSegmentsRow) dto.SegmentResponse {
+	return dto.SegmentResponse{
+		ID:            s.ID.String(),
+		Name:          s.Name,
+		Description:   s.Description,
+		Rules:         s.Rules,
+		Summary:       s.Summary,
+		CreatedBy:     uuidString(s.CreatedBy),
+		CampaignCount: s.CampaignCount,
+		CreatedAt:     s.CreatedAt.Time,
+		UpdatedAt:     s.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,434 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/utils"
+)
+
+var (
+	ErrTagNotFound = errors.New("tag not found")
+	ErrTagExists   = errors.New("a tag with this name already exists")
+	ErrInvalidTag  = errors.New("invalid tag")
+	// ErrUnknownTags is returned when a request body references tags that do
+	// not exist.
+	ErrUnknownTags = errors.New("unknown tags")
+)
+
+const (
+	DefaultTagColor = "#6B7280"
+	// TagMatchAll makes a tag filter require every listed tag.
+	TagMatchAll = "all"
+
+	maxTagNameLength = 50
+)
+
+var tagColorPattern = regexp.MustCompile(`^#[0-9A-F]{6}$`)
+
+type TagService struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewTagService(dbPool *pgxpool.Pool, queries *db.Queries) *TagService {
+	return &TagService{
+		dbPool:  dbPool,
+		queries: queries,
+	}
+}
+
+func (s *TagService) CreateTag(ctx context.Context, userID uuid.UUID, req dto.CreateTagRequest) (*dto.TagResponse, error) {
+	name, err := normalizeTagName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	color := DefaultTagColor
+	if req.Color != "" {
+		if color, err = normalizeTagColor(req.Color); err != nil {
+			return nil, err
+		}
+	}
+
+	tag, err := s.queries.CreateTag(ctx, db.CreateTagParams{
+		Name:      name,
+		Color:     color,
+		CreatedBy: pgtype.UUID{Bytes: userID, Valid: true},
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrTagExists
+		}
+		return nil, err
+	}
+
+	res := toTagResponse(db.ListTagsRow{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		Color:     tag.Color,
+		CreatedBy: tag.CreatedBy,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	})
+	return &res, nil
+}
+
+// ListTags returns every workspace tag by name, each with the number of the
+// caller's campaigns that carry it.
+func (s *TagService) ListTags(ctx context.Context, userID uuid.UUID, req dto.ListTagsRequest) ([]dto.TagResponse, error) {
+	arg := db.ListTagsParams{UserID: userID}
+	if q := strings.TrimSpace(req.Query); q != "" {
+		pattern := "%" + likeEscaper.Replace(q) + "%"
+		arg.Search = &pattern
+	}
+
+	tags, err := s.queries.ListTags(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.TagResponse, 0, len(tags))
+	for _, t := range tags {
+		responses = append(responses, toTagResponse(t))
+	}
+	return responses, nil
+}
+
+func (s *TagService) UpdateTag(ctx context.Context, tagID uuid.UUID, req dto.UpdateTagRequest) (*dto.TagResponse, error) {
+	arg := db.UpdateTagParams{ID: tagID}
+	if req.Name != nil {
+		name, err := normalizeTagName(*req.Name)
+		if err != nil {
+			return nil, err
+		}
+		arg.Name = &name
+	}
+	if req.Color != nil {
+		color, err := normalizeTagColor(*req.Color)
+		if err != nil {
+			return nil, err
+		}
+		arg.Color = &color
+	}
+
+	tag, err := s.queries.UpdateTag(ctx, arg)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTagNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, ErrTagExists
+		}
+		return nil, err
+	}
+
+	res := toTagResponse(db.ListTagsRow{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		Color:     tag.Color,
+		CreatedBy: tag.CreatedBy,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	})
+	return &res, nil
+}
+
+// DeleteTag removes a tag from the workspace and from every campaign.
+func (s *TagService) DeleteTag(ctx context.Context, tagID uuid.UUID) error {
+	rows, err := s.queries.DeleteTag(ctx, tagID)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+// ReplaceCampaignTags makes tagIDs the exact tag set of a campaign.
+func (s *TagService) ReplaceCampaignTags(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.CampaignTagsRequest) (*dto.CampaignResponse, error) {
+	tagIDs, err := parseTagIDs(req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateCampaignTags(ctx, userID, campaignID, tagIDs, func(q *db.Queries) (int64, error) {
+		removed, err := q.RemoveOtherCampaignTags(ctx, db.RemoveOtherCampaignTagsParams{
+			CampaignID: campaignID,
+			KeepTagIds: tagIDs,
+		})
+		if err != nil {
+			return 0, err
+		}
+		added, err := q.AddCampaignTags(ctx, db.AddCampaignTagsParams{
+			CampaignID: campaignID,
+			TagIds:     tagIDs,
+		})
+		return removed + added, err
+	})
+}
+
+func (s *TagService) AddCampaignTags(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.CampaignTagsRequest) (*dto.CampaignResponse, error) {
+	tagIDs, err := parseTagIDs(req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateCampaignTags(ctx, userID, campaignID, tagIDs, func(q *db.Queries) (int64, error) {
+		return q.AddCampaignTags(ctx, db.AddCampaignTagsParams{
+			CampaignID: campaignID,
+			TagIds:     tagIDs,
+		})
+	})
+}
+
+func (s *TagService) RemoveCampaignTag(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, tagID uuid.UUID) (*dto.CampaignResponse, error) {
+	return s.updateCampaignTags(ctx, userID, campaignID, nil, func(q *db.Queries) (int64, error) {
+		return q.RemoveCampaignTags(ctx, db.RemoveCampaignTagsParams{
+			CampaignID: campaignID,
+			TagIds:     []uuid.UUID{tagID},
+		})
+	})
+}
+
+// updateCampaignTags locks the campaign, checks that tagIDs exist and runs
+// apply. The campaign version only moves when apply changed something.
+func (s *TagService) updateCampaignTags(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, tagIDs []uuid.UUID, apply func(q *db.Queries) (int64, error)) (*dto.CampaignResponse, error) {
+	var campaign db.Campaign
+	err := execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		var err error
+		campaign, err = q.GetCampaignForUpdate(ctx, db.GetCampaignForUpdateParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		if err := ensureTagsExist(ctx, q, tagIDs); err != nil {
+			return err
+		}
+
+		changed, err := apply(q)
+		if err != nil || changed == 0 {
+			return err
+		}
+		campaign, err = q.TouchCampaign(ctx, db.TouchCampaignParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	res := toCampaignResponse(campaign)
+	if err := attachCampaignDetails(ctx, s.queries, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Rollups sums budget and metrics per tag over the caller's campaigns.
+func (s *TagService) Rollups(ctx context.Context, userID uuid.UUID, req dto.TagRollupRequest) ([]dto.TagRollupResponse, error) {
+	loc := time.UTC
+	if req.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			return nil, ErrInvalidTimezone
+		}
+	}
+
+	arg := db.GetTagRollupsParams{
+		UserID:   userID,
+		Statuses: []string{},
+	}
+	if req.From != "" {
+		from, _, err := parseTimeParam(req.From, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: from must be YYYY-MM-DD or RFC3339", ErrInvalidCampaignFilter)
+		}
+		arg.FromTime = pgtype.Timestamptz{Time: from, Valid: true}
+	}
+	if req.To != "" {
+		to, dateOnly, err := parseTimeParam(req.To, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: to must be YYYY-MM-DD or RFC3339", ErrInvalidCampaignFilter)
+		}
+		// A date-only "to" includes that whole day.
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		arg.ToTime = pgtype.Timestamptz{Time: to, Valid: true}
+	}
+	if arg.FromTime.Valid && arg.ToTime.Valid && !arg.FromTime.Time.Before(arg.ToTime.Time) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidCampaignFilter)
+	}
+
+	for _, value := range req.Status {
+		for _, status := range strings.Split(value, ",") {
+			if status = strings.TrimSpace(status); status == "" {
+				continue
+			}
+			if !slices.Contains(utils.CampaignStatuses, status) {
+				return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidCampaignFilter, status)
+			}
+			arg.Statuses = append(arg.Statuses, status)
+		}
+	}
+
+	var rawTags []string
+	for _, value := range req.Tags {
+		rawTags = append(rawTags, strings.Split(value, ",")...)
+	}
+	tagIDs, err := parseTagIDs(rawTags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCampaignFilter, err)
+	}
+	arg.TagIds = tagIDs
+
+	rows, err := s.queries.GetTagRollups(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.TagRollupResponse, 0, len(rows))
+	for _, r := range rows {
+		responses = append(responses, dto.TagRollupResponse{
+			Tag: dto.TagSummary{
+				ID:    r.ID.String(),
+				Name:  r.Name,
+				Color: r.Color,
+			},
+			CampaignCount: r.CampaignCount,
+			Budget:        r.Budget,
+			KPIs:          buildKPIs(r.Impressions, r.Clicks, r.Conversions, r.Spend, r.Revenue),
+		})
+	}
+	return responses, nil
+}
+
+// attachCampaignTags loads the tags of the given campaigns with one query.
+func attachCampaignTags(ctx context.Context, q *db.Queries, responses ...*dto.CampaignResponse) error {
+	ids := make([]uuid.UUID, 0, len(responses))
+	byID := make(map[uuid.UUID]*dto.CampaignResponse, len(responses))
+	for _, res := range responses {
+		res.Tags = []dto.TagSummary{}
+		id, err := uuid.Parse(res.ID)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+		byID[id] = res
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := q.ListCampaignTags(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		res := byID[row.CampaignID]
+		res.Tags = append(res.Tags, dto.TagSummary{
+			ID:    row.ID.String(),
+			Name:  row.Name,
+			Color: row.Color,
+		})
+	}
+	return nil
+}
+
+// ensureTagsExist fails with ErrUnknownTags unless every ID is a tag.
+func ensureTagsExist(ctx context.Context, q *db.Queries, tagIDs []uuid.UUID) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	count, err := q.CountExistingTags(ctx, tagIDs)
+	if err != nil {
+		return err
+	}
+	if count != int64(len(tagIDs)) {
+		return ErrUnknownTags
+	}
+	return nil
+}
+
+// parseTagIDs parses and de-duplicates tag IDs, skipping blanks.
+func parseTagIDs(raw []string) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	for _, value := range raw {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a tag ID", ErrInvalidTag, value)
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func normalizeTagName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalidTag)
+	}
+	if len([]rune(name)) > maxTagNameLength {
+		return "", fmt.Errorf("%w: name must be at most %d characters", ErrInvalidTag, maxTagNameLength)
+	}
+	return name, nil
+}
+
+// normalizeTagColor accepts #RGB or #RRGGBB in any case and returns
+// upper-case #RRGGBB.
+func normalizeTagColor(color string) (string, error) {
+	color = strings.ToUpper(strings.TrimSpace(color))
+	if len(color) == 4 && color[0] == '#' {
+		color = string([]byte{'#', color[1], color[1], color[2], color[2], color[3], color[3]})
+	}
+	if !tagColorPattern.MatchString(color) {
+		return "", fmt.Errorf("%w: color must be a hex value like #1E88E5", ErrInvalidTag)
+	}
+	return color, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+func toTagResponse(t db.ListTagsRow) dto.TagResponse {
+	var createdBy *string
+	if t.CreatedBy.Valid {
+		id := uuid.UUID(t.CreatedBy.Bytes).String()
+		createdBy = &id
+	}
+	return dto.TagResponse{
+		ID:            t.ID.String(),
+		Name:          t.Name,
+		Color:         t.Color,
+		CreatedBy:     createdBy,
+		CampaignCount: t.CampaignCount,
+		CreatedAt:     t.CreatedAt.Time,
+		UpdatedAt:     t.UpdatedAt.Time,
+	}
+}
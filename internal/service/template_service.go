@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/pagination"
+	"github.com/valenrio66/be-project/pkg/utils"
+)
+
+var (
+	ErrTemplateNotFound         = errors.New("template not found")
+	ErrTemplateForbidden        = errors.New("not allowed to manage this template")
+	ErrMissingTemplateVariables = errors.New("missing template variables")
+)
+
+const (
+	TemplateScopeUser      = "user"
+	TemplateScopeWorkspace = "workspace"
+
+	templateSortBy = "created_at"
+)
+
+var templateVariablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// builtinTemplateVariables are filled from the campaign start date unless the
+// caller supplies them explicitly.
+var builtinTemplateVariables = []string{"year", "quarter", "month", "start_date"}
+
+type TemplateService struct {
+	queries         *db.Queries
+	campaignService *CampaignService
+}
+
+func NewTemplateService(queries *db.Queries, campaignService *CampaignService) *TemplateService {
+	return &TemplateService{
+		queries:         queries,
+		campaignService: campaignService,
+	}
+}
+
+func (s *TemplateService) CreateTemplate(ctx context.Context, userID uuid.UUID, role string, req dto.CreateTemplateRequest) (*dto.TemplateResponse, error) {
+	scope := req.Scope
+	if scope == "" {
+		scope = TemplateScopeUser
+	}
+	if scope == TemplateScopeWorkspace && role != utils.RoleAdmin {
+		return nil, ErrTemplateForbidden
+	}
+
+	template, err := s.queries.CreateCampaignTemplate(ctx, db.CreateCampaignTemplateParams{
+		OwnerID:             userID,
+		Scope:               scope,
+		Name:                req.Name,
+		TitleTemplate:       req.TitleTemplate,
+		DescriptionTemplate: req.DescriptionTemplate,
+		DefaultBudget:       req.DefaultBudget,
+		DefaultDurationDays: req.DefaultDurationDays,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := toTemplateResponse(template)
+	return &res, nil
+}
+
+func (s *TemplateService) ListTemplates(ctx context.Context, userID uuid.UUID, req dto.ListTemplatesRequest) (*dto.PageResponse, error) {
+	cur, err := decodeCursor(req.Cursor, templateSortBy, pagination.OrderDesc)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil && cur.Backward {
+		return nil, ErrInvalidCursor
+	}
+	limit := pagination.Limit(req.Limit)
+	scope := utils.StringToPtr(req.Scope)
+
+	arg := db.ListCampaignTemplatesParams{
+		UserID:   userID,
+		Scope:    scope,
+		RowLimit: int32(limit + 1),
+	}
+	if cur != nil {
+		arg.CursorID = pgtype.UUID{Bytes: cur.ID, Valid: true}
+		arg.CursorTime = pgtype.Timestamptz{Time: cur.Time, Valid: true}
+	}
+
+	templates, err := s.queries.ListCampaignTemplates(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	templates, hasNext, _ := pagination.Trim(templates, limit, cur)
+
+	responses := make([]dto.TemplateResponse, 0, len(templates))
+	for _, t := range templates {
+		responses = append(responses, toTemplateResponse(t))
+	}
+
+	var next *pagination.Cursor
+	if hasNext && len(templates) > 0 {
+		last := templates[len(templates)-1]
+		next = &pagination.Cursor{
+			SortBy:    templateSortBy,
+			SortOrder: pagination.OrderDesc,
+			Time:      last.CreatedAt.Time,
+			ID:        last.ID,
+		}
+	}
+	page := newPageInfo(limit, next, nil)
+
+	if req.IncludeTotal {
+		total, err := s.queries.CountCampaignTemplates(ctx, db.CountCampaignTemplatesParams{
+			UserID: userID,
+			Scope:  scope,
+		})
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return &dto.PageResponse{
+		Items: responses,
+		Page:  page,
+	}, nil
+}
+
+func (s *TemplateService) GetTemplate(ctx context.Context, userID uuid.UUID, templateID uuid.UUID) (*dto.TemplateResponse, error) {
+	template, err := s.getVisibleTemplate(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := toTemplateResponse(template)
+	return &res, nil
+}
+
+func (s *TemplateService) UpdateTemplate(ctx context.Context, userID uuid.UUID, role string, templateID uuid.UUID, req dto.UpdateTemplateRequest) (*dto.TemplateResponse, error) {
+	template, err := s.getVisibleTemplate(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageTemplate(template, userID, role) {
+		return nil, ErrTemplateForbidden
+	}
+
+	template, err = s.queries.UpdateCampaignTemplate(ctx, db.UpdateCampaignTemplateParams{
+		ID:                  templateID,
+		Name:                req.Name,
+		TitleTemplate:       req.TitleTemplate,
+		DescriptionTemplate: req.DescriptionTemplate,
+		DefaultBudget:       req.DefaultBudget,
+		DefaultDurationDays: req.DefaultDurationDays,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := toTemplateResponse(template)
+	return &res, nil
+}
+
+func (s *TemplateService) DeleteTemplate(ctx context.Context, userID uuid.UUID, role string, templateID uuid.UUID) error {
+	template, err := s.getVisibleTemplate(ctx, userID, templateID)
+	if err != nil {
+		return err
+	}
+	if !canManageTemplate(template, userID, role) {
+		return ErrTemplateForbidden
+	}
+
+	return s.queries.DeleteCampaignTemplate(ctx, templateID)
+}
+
+// Instantiate creates a draft campaign from a template, substituting
+// {{variable}} placeholders in the title and description.
+func (s *TemplateService) Instantiate(ctx context.Context, userID uuid.UUID, templateID uuid.UUID, req dto.InstantiateTemplateRequest) (*dto.CampaignResponse, error) {
+	template, err := s.getVisibleTemplate(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := map[string]string{
+		"year":       strconv.Itoa(req.StartDate.Year()),
+		"quarter":    fmt.Sprintf("Q%d", (int(req.StartDate.Month())-1)/3+1),
+		"month":      req.StartDate.Month().String(),
+		"start_date": req.StartDate.Format("2006-01-02"),
+	}
+	for name, value := range req.Variables {
+		vars[name] = value
+	}
+
+	var missing []string
+	title, missingInTitle := renderTemplate(template.TitleTemplate, vars)
+	missing = append(missing, missingInTitle...)
+	var description *string
+	if template.DescriptionTemplate != nil {
+		rendered, missingInDescription := renderTemplate(*template.DescriptionTemplate, vars)
+		missing = append(missing, missingInDescription...)
+		description = &rendered
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return nil, fmt.Errorf("%w: %s", ErrMissingTemplateVariables, strings.Join(slices.Compact(missing), ", "))
+	}
+
+	snap := dto.CampaignSnapshot{
+		Title:       title,
+		Description: description,
+		Status:      utils.CampaignStatusDraft,
+		StartDate:   &req.StartDate,
+		EndDate:     req.EndDate,
+	}
+	if snap.EndDate == nil && template.DefaultDurationDays != nil {
+		end := req.StartDate.AddDate(0, 0, int(*template.DefaultDurationDays))
+		snap.EndDate = &end
+	}
+	if snap.EndDate == nil {
+		return nil, fmt.Errorf("%w: end_date is required when the template has no default_duration_days", ErrInvalidCampaign)
+	}
+	switch {
+	case req.Budget != nil:
+		snap.Budget = *req.Budget
+	case template.DefaultBudget != nil:
+		snap.Budget = *template.DefaultBudget
+	default:
+		return nil, fmt.Errorf("%w: budget is required when the template has no default_budget", ErrInvalidCampaign)
+	}
+	if err := validateSnapshot(snap); err != nil {
+		return nil, err
+	}
+
+	return s.campaignService.createCampaign(ctx, userID, db.CreateCampaignParams{
+		UserID:      userID,
+		Title:       snap.Title,
+		Description: snap.Description,
+		Status:      snap.Status,
+		StartDate:   utils.ToPgTimestamp(snap.StartDate),
+		EndDate:     utils.ToPgTimestamp(snap.EndDate),
+		Budget:      snap.Budget,
+	}, nil, nil)
+}
+
+func (s *TemplateService) getVisibleTemplate(ctx context.Context, userID uuid.UUID, templateID uuid.UUID) (db.CampaignTemplate, error) {
+	template, err := s.queries.GetCampaignTemplate(ctx, db.GetCampaignTemplateParams{
+		ID:     templateID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return template, ErrTemplateNotFound
+		}
+		return template, err
+	}
+	return template, nil
+}
+
+// canManageTemplate reports whether a user may edit or delete a template:
+// personal templates belong to their owner, workspace templates to admins.
+func canManageTemplate(t db.CampaignTemplate, userID uuid.UUID, role string) bool {
+	if t.Scope == TemplateScopeWorkspace {
+		return role == utils.RoleAdmin
+	}
+	return t.OwnerID == userID
+}
+
+// renderTemplate replaces {{name}} placeholders and returns the names that
+// had no value.
+func renderTemplate(text string, vars map[string]string) (string, []string) {
+	var missing []string
+	rendered := templateVariablePattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := templateVariablePattern.FindStringSubmatch(match)[1]
+		value, ok := vars[name]
+		if !ok {
+			missing = append(missing, name)
+			return match
+		}
+		return value
+	})
+	return rendered, missing
+}
+
+// templateVariables lists the caller-supplied variables used by the texts.
+func templateVariables(texts ...string) []string {
+	vars := []string{}
+	for _, text := range texts {
+		for _, m := range templateVariablePattern.FindAllStringSubmatch(text, -1) {
+			name := m[1]
+			if !slices.Contains(builtinTemplateVariables, name) && !slices.Contains(vars, name) {
+				vars = append(vars, name)
+			}
+		}
+	}
+	slices.Sort(vars)
+	return vars
+}
+
+func toTemplateResponse(t db.CampaignTemplate) dto.TemplateResponse {
+	return dto.TemplateResponse{
+		ID:                  t.ID.String(),
+		OwnerID:             t.OwnerID.String(),
+		Scope:               t.Scope,
+		Name:                t.Name,
+		TitleTemplate:       t.TitleTemplate,
+		DescriptionTemplate: t.DescriptionTemplate,
+		DefaultBudget:       t.DefaultBudget,
+		DefaultDurationDays: t.DefaultDurationDays,
+		Variables:           templateVariables(t.TitleTemplate, utils.PtrToString(t.DescriptionTemplate)),
+		CreatedAt:           t.CreatedAt.Time,
+		UpdatedAt:           t.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,426 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/valenrio66/be-project/internal/db"
+	"github.com/valenrio66/be-project/internal/dto"
+	"github.com/valenrio66/be-project/pkg/stats"
+)
+
+var (
+	ErrVariantNotFound = errors.New("variant not found")
+	ErrInvalidVariants = errors.New("invalid variants")
+	// ErrVariantHasMetrics is returned when a replacement would drop a
+	// variant that metrics were already recorded against.
+	ErrVariantHasMetrics = errors.New("variants with recorded metrics cannot be removed")
+)
+
+const (
+	ExperimentMetricConversionRate = "conversion_rate"
+	ExperimentMetricCTR            = "ctr"
+
+	ExperimentInsufficientData = "insufficient_data"
+	ExperimentInconclusive     = "inconclusive"
+	ExperimentWinner           = "winner"
+
+	DefaultExperimentConfidence = 0.95
+	DefaultExperimentMinSample  = 100
+
+	minVariants = 2
+)
+
+type VariantService struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewVariantService(dbPool *pgxpool.Pool, queries *db.Queries) *VariantService {
+	return &VariantService{
+		dbPool:  dbPool,
+		queries: queries,
+	}
+}
+
+func (s *VariantService) ListVariants(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID) (*dto.CampaignVariantsResponse, error) {
+	campaign, err := s.queries.GetCampaign(ctx, db.GetCampaignParams{
+		ID:     campaignID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+	return variantsResponse(ctx, s.queries, campaign)
+}
+
+// ReplaceVariants makes req.Variants the exact variant set of a campaign.
+// Variants left out are removed unless they have metrics. The campaign
+// version moves when anything changed.
+func (s *VariantService) ReplaceVariants(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, ifMatch []int32, req dto.ReplaceVariantsRequest) (*dto.CampaignVariantsResponse, error) {
+	variants, err := normalizeVariants(req.Variants)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(variants))
+	for _, v := range variants {
+		keys = append(keys, strings.ToLower(v.Name))
+	}
+
+	var campaign db.Campaign
+	err = execTx(ctx, s.dbPool, s.queries, func(q *db.Queries) error {
+		var err error
+		campaign, err = q.GetCampaignForUpdate(ctx, db.GetCampaignForUpdateParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		if !versionMatches(campaign.Version, ifMatch) {
+			return ErrVersionConflict
+		}
+
+		existing, err := q.ListCampaignVariants(ctx, campaignID)
+		if err != nil {
+			return err
+		}
+		if sameVariants(existing, variants) {
+			return nil
+		}
+
+		blocked, err := q.ListRemovedVariantsWithMetrics(ctx, db.ListRemovedVariantsWithMetricsParams{
+			CampaignID: campaignID,
+			KeepNames:  keys,
+		})
+		if err != nil {
+			return err
+		}
+		if len(blocked) > 0 {
+			return fmt.Errorf("%w: %s", ErrVariantHasMetrics, strings.Join(blocked, ", "))
+		}
+
+		if _, err := q.RemoveOtherCampaignVariants(ctx, db.RemoveOtherCampaignVariantsParams{
+			CampaignID: campaignID,
+			KeepNames:  keys,
+		}); err != nil {
+			return err
+		}
+		if err := q.ClearCampaignVariantControl(ctx, campaignID); err != nil {
+			return err
+		}
+		for _, v := range variants {
+			if _, err := q.UpsertCampaignVariant(ctx, db.UpsertCampaignVariantParams{
+				CampaignID:   campaignID,
+				Name:         v.Name,
+				Description:  v.Description,
+				TrafficSplit: v.TrafficSplit,
+				IsControl:    v.IsControl,
+			}); err != nil {
+				return err
+			}
+		}
+
+		campaign, err = q.TouchCampaign(ctx, db.TouchCampaignParams{
+			ID:     campaignID,
+			UserID: userID,
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	return variantsResponse(ctx, s.queries, campaign)
+}
+
+// Experiment compares every variant of a campaign against its control.
+func (s *VariantService) Experiment(ctx context.Context, userID uuid.UUID, campaignID uuid.UUID, req dto.ExperimentRequest) (*dto.ExperimentResponse, error) {
+	res := &dto.ExperimentResponse{
+		CampaignID: campaignID.String(),
+		Metric:     cmp.Or(req.Metric, ExperimentMetricConversionRate),
+		Confidence: DefaultExperimentConfidence,
+		MinSample:  DefaultExperimentMinSample,
+		Status:     ExperimentInsufficientData,
+		Variants:   []dto.VariantResultResponse{},
+	}
+	if req.Confidence != nil {
+		res.Confidence = *req.Confidence
+	}
+	if req.MinSample != nil {
+		res.MinSample = *req.MinSample
+	}
+
+	loc := time.UTC
+	if req.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			return nil, ErrInvalidTimezone
+		}
+	}
+	arg := db.GetCampaignVariantTotalsParams{CampaignID: campaignID}
+	if req.From != "" {
+		from, _, err := parseTimeParam(req.From, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: from must be YYYY-MM-DD or RFC3339", ErrInvalidAnalyticsRange)
+		}
+		arg.FromTime = pgtype.Timestamptz{Time: from, Valid: true}
+		res.From = &from
+	}
+	if req.To != "" {
+		to, dateOnly, err := parseTimeParam(req.To, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: to must be YYYY-MM-DD or RFC3339", ErrInvalidAnalyticsRange)
+		}
+		// A date-only "to" includes that whole day.
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		arg.ToTime = pgtype.Timestamptz{Time: to, Valid: true}
+		res.To = &to
+	}
+	if res.From != nil && res.To != nil && !res.From.Before(*res.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidAnalyticsRange)
+	}
+
+	if _, err := s.queries.GetCampaign(ctx, db.GetCampaignParams{
+		ID:     campaignID,
+		UserID: userID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	variants, err := s.queries.ListCampaignVariants(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.queries.GetCampaignVariantTotals(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	byVariant := make(map[uuid.UUID]db.GetCampaignVariantTotalsRow, len(totals))
+	for _, t := range totals {
+		byVariant[t.VariantID] = t
+	}
+
+	comparisons := max(len(variants)-1, 1)
+	res.Alpha = (1 - res.Confidence) / float64(comparisons)
+
+	var control stats.Sample
+	samples := make([]stats.Sample, 0, len(variants))
+	for _, v := range variants {
+		t := byVariant[v.ID]
+		sample := stats.Sample{Successes: t.Conversions, Trials: t.Clicks}
+		if res.Metric == ExperimentMetricCTR {
+			sample = stats.Sample{Successes: t.Clicks, Trials: t.Impressions}
+		}
+		if v.IsControl {
+			control = sample
+		}
+		samples = append(samples, sample)
+
+		result := dto.VariantResultResponse{
+			ID:           v.ID.String(),
+			Name:         v.Name,
+			IsControl:    v.IsControl,
+			TrafficSplit: v.TrafficSplit,
+			Trials:       sample.Trials,
+			Successes:    sample.Successes,
+			KPIs:         buildKPIs(t.Impressions, t.Clicks, t.Conversions, t.Spend, t.Revenue),
+		}
+		if interval, err := stats.WilsonInterval(sample, res.Confidence); err == nil {
+			rate := sample.Rate()
+			result.Rate = &rate
+			result.RateInterval = toIntervalResponse(interval)
+		}
+		res.Variants = append(res.Variants, result)
+	}
+	if len(variants) == 0 {
+		return res, nil
+	}
+
+	enough := true
+	for _, sample := range samples {
+		enough = enough && sample.Trials >= res.MinSample
+	}
+
+	var winner *dto.VariantResultResponse
+	controlWins := len(variants) > 1
+	for i := range res.Variants {
+		result := &res.Variants[i]
+		if result.IsControl {
+			continue
+		}
+		better, worse := compareToControl(result, control, samples[i], res.Confidence, res.Alpha)
+		controlWins = controlWins && worse
+		if better && (winner == nil || *result.Rate > *winner.Rate) {
+			winner = result
+		}
+	}
+	if !enough {
+		return res, nil
+	}
+
+	res.Status = ExperimentInconclusive
+	switch {
+	case winner != nil:
+		res.Status = ExperimentWinner
+		res.Winner = &winner.ID
+	case controlWins:
+		// Variants are listed control first.
+		res.Status = ExperimentWinner
+		res.Winner = &res.Variants[0].ID
+	}
+	return res, nil
+}
+
+// compareToControl fills the statistics of a challenger and reports
+// whether it is significantly better or worse than the control.
+func compareToControl(result *dto.VariantResultResponse, control, sample stats.Sample, confidence, alpha float64) (better, worse bool) {
+	test, err := stats.TwoProportionZTest(control, sample)
+	if err != nil {
+		return false, false
+	}
+	result.ZScore = &test.Z
+	result.PValue = &test.PValue
+
+	if control.Successes > 0 {
+		lift := sample.Rate()/control.Rate() - 1
+		result.Lift = &lift
+		if interval, err := stats.LiftInterval(control, sample, confidence); err == nil {
+			result.LiftInterval = toIntervalResponse(interval)
+		}
+	}
+
+	beat, err := stats.ProbabilityToBeat(control, sample)
+	if err != nil {
+		return false, false
+	}
+	result.ProbabilityToBeatControl = &beat
+
+	result.Significant = test.PValue < alpha
+	better = result.Significant && test.Z > 0 && beat >= confidence
+	worse = result.Significant && test.Z < 0 && beat <= 1-confidence
+	return better, worse
+}
+
+func toIntervalResponse(i stats.Interval) *dto.IntervalResponse {
+	return &dto.IntervalResponse{Low: i.Low, High: i.High}
+}
+
+// normalizeVariants trims and checks a replacement set: unique names,
+// splits adding up to 100 and exactly one control, defaulting to the first
+// variant.
+func normalizeVariants(reqs []dto.CampaignVariantRequest) ([]dto.CampaignVariantRequest, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+	if len(reqs) < minVariants {
+		return nil, fmt.Errorf("%w: an experiment needs at least %d variants", ErrInvalidVariants, minVariants)
+	}
+
+	variants := make([]dto.CampaignVariantRequest, 0, len(reqs))
+	seen := make([]string, 0, len(reqs))
+	var split int64
+	controls := 0
+	for _, v := range reqs {
+		v.Name = strings.Join(strings.Fields(v.Name), " ")
+		v.Description = strings.TrimSpace(v.Description)
+		if v.Name == "" {
+			return nil, fmt.Errorf("%w: name is required", ErrInvalidVariants)
+		}
+		key := strings.ToLower(v.Name)
+		if slices.Contains(seen, key) {
+			return nil, fmt.Errorf("%w: %s is listed more than once", ErrInvalidVariants, v.Name)
+		}
+		seen = append(seen, key)
+		split += toCents(v.TrafficSplit)
+		if v.IsControl {
+			controls++
+		}
+		variants = append(variants, v)
+	}
+	if split != 100*100 {
+		return nil, fmt.Errorf("%w: traffic splits add up to %.2f, not 100", ErrInvalidVariants, float64(split)/100)
+	}
+	switch controls {
+	case 0:
+		variants[0].IsControl = true
+	case 1:
+	default:
+		return nil, fmt.Errorf("%w: only one variant can be the control", ErrInvalidVariants)
+	}
+	return variants, nil
+}
+
+// sameVariants reports whether a replacement would leave the stored set
+// exactly as it is.
+func sameVariants(existing []db.CampaignVariant, variants []dto.CampaignVariantRequest) bool {
+	if len(existing) != len(variants) {
+		return false
+	}
+	for _, v := range variants {
+		i := slices.IndexFunc(existing, func(e db.CampaignVariant) bool { return e.Name == v.Name })
+		if i < 0 {
+			return false
+		}
+		e := existing[i]
+		if e.Description != v.Description || toCents(e.TrafficSplit) != toCents(v.TrafficSplit) || e.IsControl != v.IsControl {
+			return false
+		}
+	}
+	return true
+}
+
+func variantsResponse(ctx context.Context, q *db.Queries, campaign db.Campaign) (*dto.CampaignVariantsResponse, error) {
+	variants, err := q.ListCampaignVariants(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := q.GetCampaignVariantTotals(ctx, db.GetCampaignVariantTotalsParams{CampaignID: campaign.ID})
+	if err != nil {
+		return nil, err
+	}
+	byVariant := make(map[uuid.UUID]db.GetCampaignVariantTotalsRow, len(totals))
+	for _, t := range totals {
+		byVariant[t.VariantID] = t
+	}
+
+	res := &dto.CampaignVariantsResponse{
+		CampaignID: campaign.ID.String(),
+		Version:    campaign.Version,
+		Variants:   make([]dto.VariantResponse, 0, len(variants)),
+	}
+	for _, v := range variants {
+		t := byVariant[v.ID]
+		res.Variants = append(res.Variants, dto.VariantResponse{
+			ID:           v.ID.String(),
+			Name:         v.Name,
+			Description:  v.Description,
+			TrafficSplit: v.TrafficSplit,
+			IsControl:    v.IsControl,
+			KPIs:         buildKPIs(t.Impressions, t.Clicks, t.Conversions, t.Spend, t.Revenue),
+			UpdatedAt:    v.UpdatedAt.Time,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,119 @@
+// Package attribution splits the credit for a conversion between the
+// touchpoints that led to it.
+//
+// Every model hands out a total credit of 1 across the touches of one
+// conversion:
+//
+//	last_click      all of it to the last touch
+//	first_click     all of it to the first touch
+//	linear          equal shares
+//	time_decay      shares halving every half-life before the conversion
+//	position_based  40% each to the first and last touch, the remaining
+//	                20% split evenly between the touches in between
+package attribution
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	LastClick     = "last_click"
+	FirstClick    = "first_click"
+	Linear        = "linear"
+	TimeDecay     = "time_decay"
+	PositionBased = "position_based"
+
+	// DefaultHalfLife is the time decay half-life when none is given.
+	DefaultHalfLife = 7 * 24 * time.Hour
+	// positionEndsShare is what position_based gives to the first and to
+	// the last touch each.
+	positionEndsShare = 0.4
+)
+
+// Models lists the supported models.
+var Models = []string{LastClick, FirstClick, Linear, TimeDecay, PositionBased}
+
+var ErrUnknownModel = errors.New("unknown attribution model")
+
+// Touch is one touchpoint before a conversion, such as a click on a
+// campaign's tracked link. Key identifies what gets the credit.
+type Touch struct {
+	Key string
+	At  time.Time
+}
+
+// Options tunes the models. A zero HalfLife means DefaultHalfLife.
+type Options struct {
+	HalfLife time.Duration
+}
+
+// Credit returns the share of a conversion at convertedAt that each touch
+// earns, in the order of touches. Touches are ranked by time, ties keeping
+// their given order. The shares add up to 1; without touches the result is
+// empty.
+func Credit(model string, touches []Touch, convertedAt time.Time, opts Options) ([]float64, error) {
+	if !slices.Contains(Models, model) {
+		return nil, ErrUnknownModel
+	}
+	credit := make([]float64, len(touches))
+	n := len(touches)
+	if n == 0 {
+		return credit, nil
+	}
+
+	// order holds the touch indexes from first to last.
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return touches[a].At.Compare(touches[b].At)
+	})
+	first, last := order[0], order[n-1]
+
+	switch model {
+	case LastClick:
+		credit[last] = 1
+	case FirstClick:
+		credit[first] = 1
+	case Linear:
+		for i := range credit {
+			credit[i] = 1 / float64(n)
+		}
+	case TimeDecay:
+		halfLife := opts.HalfLife
+		if halfLife <= 0 {
+			halfLife = DefaultHalfLife
+		}
+		// Weights are taken relative to the newest touch, which gets 1, so
+		// old touches or short half-lives cannot underflow every weight to
+		// zero.
+		newest := max(convertedAt.Sub(touches[last].At), 0)
+		total := 0.0
+		for i, t := range touches {
+			age := max(convertedAt.Sub(t.At), 0)
+			credit[i] = math.Exp2(-float64(age-newest) / float64(halfLife))
+			total += credit[i]
+		}
+		for i := range credit {
+			credit[i] /= total
+		}
+	case PositionBased:
+		switch n {
+		case 1:
+			credit[first] = 1
+		case 2:
+			credit[first], credit[last] = 0.5, 0.5
+		default:
+			credit[first], credit[last] = positionEndsShare, positionEndsShare
+			middle := (1 - 2*positionEndsShare) / float64(n-2)
+			for _, i := range order[1 : n-1] {
+				credit[i] = middle
+			}
+		}
+	}
+	return credit, nil
+}
@@ -0,0 +1,124 @@
+package attribution
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCredit(t *testing.T) {
+	conv := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	at := func(daysBefore float64) time.Time {
+		return conv.Add(-time.Duration(daysBefore * float64(day)))
+	}
+	// Touches are deliberately out of time order.
+	three := []Touch{
+		{Key: "middle", At: at(7)},
+		{Key: "last", At: at(0)},
+		{Key: "first", At: at(14)},
+	}
+	five := []Touch{
+		{Key: "a", At: at(4)},
+		{Key: "b", At: at(3)},
+		{Key: "c", At: at(2)},
+		{Key: "d", At: at(1)},
+		{Key: "e", At: at(0)},
+	}
+
+	tests := []struct {
+		name    string
+		model   string
+		touches []Touch
+		opts    Options
+		want    []float64
+		wantErr error
+	}{
+		{"last click", LastClick, three, Options{}, []float64{0, 1, 0}, nil},
+		{"first click", FirstClick, three, Options{}, []float64{0, 0, 1}, nil},
+		{"linear", Linear, three, Options{}, []float64{1.0 / 3, 1.0 / 3, 1.0 / 3}, nil},
+		// Weights 1/2, 1 and 1/4 for ages of one, zero and two half-lives.
+		{"time decay", TimeDecay, three, Options{}, []float64{2.0 / 7, 4.0 / 7, 1.0 / 7}, nil},
+		// Weights 1/√2, 1 and 1/2 with a half-life of two weeks.
+		{"time decay with half-life", TimeDecay, three, Options{HalfLife: 14 * day}, []float64{
+			math.Sqrt2 / 2 / (math.Sqrt2/2 + 1.5),
+			1 / (math.Sqrt2/2 + 1.5),
+			0.5 / (math.Sqrt2/2 + 1.5),
+		}, nil},
+		{"position based", PositionBased, five, Options{}, []float64{0.4, 0.2 / 3, 0.2 / 3, 0.2 / 3, 0.4}, nil},
+		{"position based pair", PositionBased, three[:2], Options{}, []float64{0.5, 0.5}, nil},
+		{"position based single", PositionBased, three[:1], Options{}, []float64{1}, nil},
+		{"single touch", TimeDecay, three[1:2], Options{}, []float64{1}, nil},
+		{"no touches", Linear, nil, Options{}, []float64{}, nil},
+		{"unknown model", "data_driven", three, Options{}, nil, ErrUnknownModel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Credit(tt.model, tt.touches, conv, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-12 {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCreditTies(t *testing.T) {
+	conv := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	touches := []Touch{
+		{Key: "a", At: conv.Add(-time.Hour)},
+		{Key: "b", At: conv.Add(-time.Hour)},
+	}
+	first, _ := Credit(FirstClick, touches, conv, Options{})
+	last, _ := Credit(LastClick, touches, conv, Options{})
+	if first[0] != 1 || last[1] != 1 {
+		t.Errorf("ties should keep their order: first=%v last=%v", first, last)
+	}
+}
+
+// Short half-lives make every weight underflow unless they are taken
+// relative to the newest touch.
+func TestCreditTimeDecayStaysFinite(t *testing.T) {
+	conv := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	touches := []Touch{
+		{Key: "old", At: conv.Add(-20 * day)},
+		{Key: "newer", At: conv.Add(-12 * day)},
+		{Key: "future", At: conv.Add(time.Hour)},
+	}
+	tests := []struct {
+		name     string
+		touches  []Touch
+		halfLife time.Duration
+	}{
+		{"old touches", touches[:2], 14 * time.Minute},
+		{"touch after the conversion", touches, time.Minute},
+		{"long lookback", touches[:2], time.Duration(0.1 * float64(day))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Credit(TimeDecay, tt.touches, conv, Options{HalfLife: tt.halfLife})
+			if err != nil {
+				t.Fatal(err)
+			}
+			total := 0.0
+			for _, c := range got {
+				if math.IsNaN(c) || math.IsInf(c, 0) {
+					t.Fatalf("got %v", got)
+				}
+				total += c
+			}
+			if math.Abs(total-1) > 1e-12 {
+				t.Errorf("credit adds up to %v, want 1", total)
+			}
+		})
+	}
+}
@@ -0,0 +1,430 @@
+// Package audience defines audience segments as JSON rule trees.
+//
+// A rule is either a group or a condition. Groups combine rules:
+//
+//	{"all": [rule, ...]}  every rule holds
+//	{"any": [rule, ...]}  at least one rule holds
+//	{"not": rule}         the rule does not hold
+//
+// A condition tests one field:
+//
+//	{"field": "age", "op": "between", "value": [18, 34]}
+//	{"field": "country", "op": "in", "value": ["ID", "SG"]}
+//	{"field": "interests", "op": "contains_any", "value": ["travel"]}
+//	{"field": "custom.loyalty_tier", "op": "eq", "value": "gold"}
+//
+// Parse validates a tree against the field catalog and returns it in
+// canonical form, so equal definitions are stored identically.
+package audience
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// MaxDepth caps how deeply groups can nest.
+	MaxDepth = 6
+	// MaxConditions caps the number of conditions in one tree.
+	MaxConditions = 100
+	// MaxValues caps the values of one list condition.
+	MaxValues = 200
+
+	// CustomPrefix starts the field name of a custom attribute.
+	CustomPrefix = "custom."
+)
+
+// Operators.
+const (
+	OpEq           = "eq"
+	OpNeq          = "neq"
+	OpIn           = "in"
+	OpNotIn        = "not_in"
+	OpGt           = "gt"
+	OpGte          = "gte"
+	OpLt           = "lt"
+	OpLte          = "lte"
+	OpBetween      = "between"
+	OpContainsAny  = "contains_any"
+	OpContainsAll  = "contains_all"
+	OpContainsNone = "contains_none"
+	OpExists       = "exists"
+	OpNotExists    = "not_exists"
+)
+
+// Field categories.
+const (
+	CategoryDemographics = "demographics"
+	CategoryGeography    = "geography"
+	CategoryInterests    = "interests"
+	CategoryCustom       = "custom"
+)
+
+type valueKind int
+
+const (
+	kindNumber valueKind = iota
+	kindEnum
+	kindUpperCode
+	kindLowerCode
+	kindText
+	kindList
+	kindScalar
+)
+
+var (
+	numberOps = []string{OpEq, OpNeq, OpGt, OpGte, OpLt, OpLte, OpBetween, OpIn, OpNotIn}
+	setOps    = []string{OpEq, OpNeq, OpIn, OpNotIn}
+	listOps   = []string{OpContainsAny, OpContainsAll, OpContainsNone}
+	customOps = []string{OpEq, OpNeq, OpIn, OpNotIn, OpGt, OpGte, OpLt, OpLte, OpBetween, OpExists, OpNotExists}
+
+	customKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+	codePattern      = regexp.MustCompile(`^[A-Za-z]{2}$`)
+)
+
+// Field describes a field rules can test.
+type Field struct {
+	Name     string   `json:"name"`
+	Category string   `json:"category"`
+	Label    string   `json:"label"`
+	Ops      []string `json:"ops"`
+	Values   []string `json:"values,omitempty"`
+
+	kind     valueKind
+	min, max float64
+}
+
+var catalog = []Field{
+	{Name: "age", Category: CategoryDemographics, Label: "Age", Ops: numberOps, kind: kindNumber, min: 13, max: 120},
+	{Name: "gender", Category: CategoryDemographics, Label: "Gender", Ops: setOps, kind: kindEnum, Values: []string{"female", "male", "non_binary", "unknown"}},
+	{Name: "language", Category: CategoryDemographics, Label: "Language", Ops: setOps, kind: kindLowerCode},
+	{Name: "country", Category: CategoryGeography, Label: "Country", Ops: setOps, kind: kindUpperCode},
+	{Name: "region", Category: CategoryGeography, Label: "Region", Ops: setOps, kind: kindText},
+	{Name: "city", Category: CategoryGeography, Label: "City", Ops: setOps, kind: kindText},
+	{Name: "interests", Category: CategoryInterests, Label: "Interests", Ops: listOps, kind: kindList},
+}
+
+// Fields lists the built-in fields. Custom attributes are written as
+// "custom.<key>" with a lower-case key and accept strings, numbers and
+// booleans.
+func Fields() []Field {
+	return slices.Clone(catalog)
+}
+
+func lookupField(name string) (Field, bool) {
+	if key, ok := strings.CutPrefix(name, CustomPrefix); ok {
+		if !customKeyPattern.MatchString(key) {
+			return Field{}, false
+		}
+		return Field{Name: name, Category: CategoryCustom, Label: key, Ops: customOps, kind: kindScalar}, true
+	}
+	i := slices.IndexFunc(catalog, func(f Field) bool { return f.Name == name })
+	if i < 0 {
+		return Field{}, false
+	}
+	return catalog[i], true
+}
+
+// Rule is a node of a rule tree: a group when All, Any or Not is set,
+// otherwise a condition.
+type Rule struct {
+	All   []Rule `json:"all,omitempty"`
+	Any   []Rule `json:"any,omitempty"`
+	Not   *Rule  `json:"not,omitempty"`
+	Field string `json:"field,omitempty"`
+	Op    string `json:"op,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// Error points at one problem in a rule tree. Path is written like
+// "all[1].any[0].value".
+type Error struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Errors lists every problem found in a rule tree.
+type Errors []Error
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Path+": "+err.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+type parser struct {
+	errs       Errors
+	conditions int
+}
+
+func (p *parser) fail(path, format string, args ...any) {
+	if path == "" {
+		path = "$"
+	}
+	p.errs = append(p.errs, Error{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// Parse decodes and validates a rule tree. It returns the canonical tree
+// or Errors listing every problem.
+func Parse(raw []byte) (Rule, error) {
+	p := &parser{}
+	rule, _ := p.node(raw, "", 1)
+	if p.conditions == 0 && len(p.errs) == 0 {
+		p.fail("", "the rules need at least one condition")
+	}
+	if p.conditions > MaxConditions {
+		p.fail("", "the rules have %d conditions, at most %d are allowed", p.conditions, MaxConditions)
+	}
+	if len(p.errs) > 0 {
+		return Rule{}, p.errs
+	}
+	return rule, nil
+}
+
+func (p *parser) node(raw json.RawMessage, path string, depth int) (Rule, bool) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil || obj == nil {
+		p.fail(path, "must be an object")
+		return Rule{}, false
+	}
+	if depth > MaxDepth {
+		p.fail(path, "groups nest more than %d levels deep", MaxDepth)
+		return Rule{}, false
+	}
+
+	var groups []string
+	for _, key := range []string{"all", "any", "not"} {
+		if _, ok := obj[key]; ok {
+			groups = append(groups, key)
+		}
+	}
+	switch {
+	case len(groups) > 1:
+		p.fail(path, "a group has exactly one of all, any or not")
+		return Rule{}, false
+	case len(groups) == 1 && len(obj) > 1:
+		p.fail(path, "a %s group has no other keys", groups[0])
+		return Rule{}, false
+	case len(groups) == 0:
+		return p.condition(obj, path)
+	}
+
+	key := groups[0]
+	childPath := join(path, key)
+	if key == "not" {
+		child, ok := p.node(obj[key], childPath, depth+1)
+		if !ok {
+			return Rule{}, false
+		}
+		return Rule{Not: &child}, true
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(obj[key], &items); err != nil || len(items) == 0 {
+		p.fail(childPath, "must be a non-empty array of rules")
+		return Rule{}, false
+	}
+	children := make([]Rule, 0, len(items))
+	ok := true
+	for i, item := range items {
+		child, childOK := p.node(item, fmt.Sprintf("%s[%d]", childPath, i), depth+1)
+		ok = ok && childOK
+		children = append(children, child)
+	}
+	if key == "all" {
+		return Rule{All: children}, ok
+	}
+	return Rule{Any: children}, ok
+}
+
+func (p *parser) condition(obj map[string]json.RawMessage, path string) (Rule, bool) {
+	p.conditions++
+	for key := range obj {
+		if key != "field" && key != "op" && key != "value" {
+			p.fail(join(path, key), "unknown key, expected field, op and value or a group")
+			return Rule{}, false
+		}
+	}
+
+	var rule Rule
+	if err := json.Unmarshal(obj["field"], &rule.Field); err != nil || rule.Field == "" {
+		p.fail(join(path, "field"), "is required")
+		return Rule{}, false
+	}
+	field, ok := lookupField(rule.Field)
+	if !ok {
+		p.fail(join(path, "field"), "unknown field %q", rule.Field)
+		return Rule{}, false
+	}
+	if err := json.Unmarshal(obj["op"], &rule.Op); err != nil || rule.Op == "" {
+		p.fail(join(path, "op"), "is required")
+		return Rule{}, false
+	}
+	if !slices.Contains(field.Ops, rule.Op) {
+		p.fail(join(path, "op"), "%s does not support %q, expected one of %s", rule.Field, rule.Op, strings.Join(field.Ops, ", "))
+		return Rule{}, false
+	}
+
+	valuePath := join(path, "value")
+	raw, hasValue := obj["value"]
+	if rule.Op == OpExists || rule.Op == OpNotExists {
+		if hasValue {
+			p.fail(valuePath, "%s takes no value", rule.Op)
+			return Rule{}, false
+		}
+		return rule, true
+	}
+	if !hasValue {
+		p.fail(valuePath, "is required")
+		return Rule{}, false
+	}
+
+	value, msg := parseValue(field, rule.Op, raw)
+	if msg != "" {
+		p.fail(valuePath, "%s", msg)
+		return Rule{}, false
+	}
+	rule.Value = value
+	return rule, true
+}
+
+// parseValue checks a condition value against its field and operator and
+// returns it normalized: trimmed, codes in their usual case, lists
+// without duplicates.
+func parseValue(field Field, op string, raw json.RawMessage) (any, string) {
+	switch op {
+	case OpIn, OpNotIn, OpContainsAny, OpContainsAll, OpContainsNone:
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil || len(items) == 0 {
+			return nil, "must be a non-empty array"
+		}
+		if len(items) > MaxValues {
+			return nil, fmt.Sprintf("must have at most %d values", MaxValues)
+		}
+		values := make([]any, 0, len(items))
+		for i, item := range items {
+			v, msg := parseScalar(field, item)
+			if msg != "" {
+				return nil, fmt.Sprintf("item %d %s", i, msg)
+			}
+			if !slices.Contains(values, v) {
+				values = append(values, v)
+			}
+		}
+		return values, ""
+	case OpGt, OpGte, OpLt, OpLte:
+		n, msg := parseNumber(field, raw)
+		if msg != "" {
+			return nil, msg
+		}
+		return n, ""
+	case OpBetween:
+		var bounds []json.RawMessage
+		if err := json.Unmarshal(raw, &bounds); err != nil || len(bounds) != 2 {
+			return nil, "must be an array of two numbers [min, max]"
+		}
+		low, msg := parseNumber(field, bounds[0])
+		if msg != "" {
+			return nil, msg
+		}
+		high, msg := parseNumber(field, bounds[1])
+		if msg != "" {
+			return nil, msg
+		}
+		if low > high {
+			return nil, "min must not exceed max"
+		}
+		return []any{low, high}, ""
+	default:
+		return parseScalar(field, raw)
+	}
+}
+
+func parseScalar(field Field, raw json.RawMessage) (any, string) {
+	switch field.kind {
+	case kindNumber:
+		n, msg := parseNumber(field, raw)
+		if msg != "" {
+			return nil, msg
+		}
+		return n, ""
+	case kindScalar:
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, "must be a string, number or boolean"
+		}
+		switch v := v.(type) {
+		case string:
+			v = strings.TrimSpace(v)
+			if v == "" || len([]rune(v)) > 200 {
+				return nil, "must be 1 to 200 characters"
+			}
+			return v, ""
+		case json.Number:
+			f, err := v.Float64()
+			if err != nil {
+				return nil, "must be a finite number"
+			}
+			return f, ""
+		case bool:
+			return v, ""
+		}
+		return nil, "must be a string, number or boolean"
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, "must be a string"
+	}
+	s = strings.TrimSpace(s)
+	switch field.kind {
+	case kindEnum:
+		s = strings.ToLower(s)
+		if !slices.Contains(field.Values, s) {
+			return nil, fmt.Sprintf("must be one of %s", strings.Join(field.Values, ", "))
+		}
+	case kindUpperCode, kindLowerCode:
+		if !codePattern.MatchString(s) {
+			return nil, "must be a two-letter code"
+		}
+		if field.kind == kindUpperCode {
+			s = strings.ToUpper(s)
+		} else {
+			s = strings.ToLower(s)
+		}
+	case kindList:
+		s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+		fallthrough
+	default:
+		if s == "" || len([]rune(s)) > 100 {
+			return nil, "must be 1 to 100 characters"
+		}
+	}
+	return s, ""
+}
+
+func parseNumber(field Field, raw json.RawMessage) (float64, string) {
+	var n float64
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return 0, "must be a number"
+	}
+	if field.kind == kindNumber && (n < field.min || n > field.max) {
+		return 0, fmt.Sprintf("must be between %g and %g", field.min, field.max)
+	}
+	return n, ""
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,166 @@
+package audience
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCanonical(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "condition",
+			raw:  `{"field":"age","op":"gte","value":18}`,
+			want: `{"field":"age","op":"gte","value":18}`,
+		},
+		{
+			name: "codes and enums are normalized",
+			raw:  `{"all":[{"field":"country","op":"in","value":[" id","SG","sg"]},{"field":"gender","op":"eq","value":"Female"},{"field":"language","op":"neq","value":"EN"}]}`,
+			want: `{"all":[{"field":"country","op":"in","value":["ID","SG"]},{"field":"gender","op":"eq","value":"female"},{"field":"language","op":"neq","value":"en"}]}`,
+		},
+		{
+			name: "interests are lower-cased and collapsed",
+			raw:  `{"field":"interests","op":"contains_any","value":["  Road   Trips ","road trips","Food"]}`,
+			want: `{"field":"interests","op":"contains_any","value":["road trips","food"]}`,
+		},
+		{
+			name: "between",
+			raw:  `{"not":{"field":"age","op":"between","value":[18,34]}}`,
+			want: `{"not":{"field":"age","op":"between","value":[18,34]}}`,
+		},
+		{
+			name: "custom attributes",
+			raw:  `{"any":[{"field":"custom.loyalty_tier","op":"eq","value":" gold "},{"field":"custom.orders","op":"gt","value":3},{"field":"custom.vip","op":"eq","value":true},{"field":"custom.referrer","op":"exists"}]}`,
+			want: `{"any":[{"field":"custom.loyalty_tier","op":"eq","value":"gold"},{"field":"custom.orders","op":"gt","value":3},{"field":"custom.vip","op":"eq","value":true},{"field":"custom.referrer","op":"exists"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse([]byte(tt.raw))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Parse = %s, want %s", got, tt.want)
+			}
+
+			// The canonical form parses to itself.
+			again, err := Parse(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(again, rule) {
+				t.Errorf("reparsed %+v, want %+v", again, rule)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	deep := `{"field":"age","op":"gt","value":20}`
+	for range MaxDepth {
+		deep = `{"not":` + deep + `}`
+	}
+	many := make([]string, MaxConditions+1)
+	for i := range many {
+		many[i] = `{"field":"custom.n","op":"eq","value":1}`
+	}
+	values := make([]string, MaxValues+1)
+	for i := range values {
+		values[i] = fmt.Sprintf(`"c%d"`, i)
+	}
+
+	tests := []struct {
+		name string
+		raw  string
+		want Errors
+	}{
+		{"not an object", `[1]`, Errors{{"$", "must be an object"}}},
+		{"empty object", `{}`, Errors{{"field", "is required"}}},
+		{"two groups", `{"all":[],"any":[]}`, Errors{{"$", "a group has exactly one of all, any or not"}}},
+		{"group with extra keys", `{"all":[],"field":"age"}`, Errors{{"$", "a all group has no other keys"}}},
+		{"empty group", `{"any":[]}`, Errors{{"any", "must be a non-empty array of rules"}}},
+		{"unknown key", `{"field":"age","op":"gt","value":1,"x":1}`, Errors{{"x", "unknown key, expected field, op and value or a group"}}},
+		{"unknown field", `{"field":"income","op":"gt","value":1}`, Errors{{"field", `unknown field "income"`}}},
+		{"bad custom key", `{"field":"custom.Tier","op":"eq","value":1}`, Errors{{"field", `unknown field "custom.Tier"`}}},
+		{"missing op", `{"field":"age","value":1}`, Errors{{"op", "is required"}}},
+		{"unsupported op", `{"field":"country","op":"gt","value":"ID"}`, Errors{{"op", `country does not support "gt", expected one of eq, neq, in, not_in`}}},
+		{"missing value", `{"field":"age","op":"gt"}`, Errors{{"value", "is required"}}},
+		{"value on exists", `{"field":"custom.a","op":"exists","value":1}`, Errors{{"value", "exists takes no value"}}},
+		{"age out of range", `{"field":"age","op":"gt","value":7}`, Errors{{"value", "must be between 13 and 120"}}},
+		{"reversed bounds", `{"field":"age","op":"between","value":[40,20]}`, Errors{{"value", "min must not exceed max"}}},
+		{"one bound", `{"field":"age","op":"between","value":[40]}`, Errors{{"value", "must be an array of two numbers [min, max]"}}},
+		{"bad code", `{"field":"country","op":"eq","value":"IDN"}`, Errors{{"value", "must be a two-letter code"}}},
+		{"bad enum", `{"field":"gender","op":"eq","value":"x"}`, Errors{{"value", "must be one of female, male, non_binary, unknown"}}},
+		{"bad list item", `{"field":"country","op":"in","value":["ID",1]}`, Errors{{"value", "item 1 must be a string"}}},
+		{"empty list", `{"field":"country","op":"in","value":[]}`, Errors{{"value", "must be a non-empty array"}}},
+		{"too many values", `{"field":"city","op":"in","value":[` + strings.Join(values, ",") + `]}`, Errors{{"value", fmt.Sprintf("must have at most %d values", MaxValues)}}},
+		{"custom object value", `{"field":"custom.a","op":"eq","value":{}}`, Errors{{"value", "must be a string, number or boolean"}}},
+		{"too deep", deep, Errors{{"not.not.not.not.not.not", fmt.Sprintf("groups nest more than %d levels deep", MaxDepth)}}},
+		{"too many conditions", `{"all":[` + strings.Join(many, ",") + `]}`, Errors{{"$", fmt.Sprintf("the rules have %d conditions, at most %d are allowed", MaxConditions+1, MaxConditions)}}},
+		{
+			name: "every problem is reported",
+			raw:  `{"all":[{"field":"age","op":"gt","value":"x"},{"any":[{"field":"city","op":"eq","value":""}]}]}`,
+			want: Errors{
+				{"all[0].value", "must be a number"},
+				{"all[1].any[0].value", "must be 1 to 100 characters"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.raw))
+			var got Errors
+			if !errors.As(err, &got) {
+				t.Fatalf("err = %v, want Errors", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{
+			`{"all":[{"field":"age","op":"between","value":[18,34]},{"field":"country","op":"in","value":["ID","SG"]}]}`,
+			"Age is 18 to 34 and Country is one of ID, SG",
+		},
+		{
+			`{"any":[{"all":[{"field":"gender","op":"eq","value":"female"},{"field":"age","op":"lt","value":30}]},{"field":"city","op":"neq","value":"Bandung"}]}`,
+			"(Gender is female and Age is under 30) or City is not Bandung",
+		},
+		{
+			`{"all":[{"any":[{"field":"age","op":"gte","value":21}]},{"not":{"field":"interests","op":"contains_none","value":["golf"]}}]}`,
+			"Age is at least 21 and not (Not interested in golf)",
+		},
+		{
+			`{"all":[{"field":"custom.tier","op":"eq","value":"gold"},{"field":"custom.churned","op":"not_exists"},{"field":"interests","op":"contains_all","value":["travel","food"]}]}`,
+			`Custom attribute "tier" is gold and Custom attribute "churned" is not set and Interested in all of travel, food`,
+		},
+	}
+	for _, tt := range tests {
+		rule, err := Parse([]byte(tt.raw))
+		if err != nil {
+			t.Fatalf("Parse(%s): %v", tt.raw, err)
+		}
+		if got := Summary(rule); got != tt.want {
+			t.Errorf("Summary(%s) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package audience
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Summary renders a rule tree as a sentence, for example
+// "Age is 18 to 34 and Country is one of ID, SG".
+func Summary(rule Rule) string {
+	return summarize(rule, false)
+}
+
+// summarize wraps nested groups of several rules in parentheses so the
+// sentence keeps the tree's precedence.
+func summarize(rule Rule, nested bool) string {
+	switch {
+	case rule.Not != nil:
+		return "not (" + summarize(*rule.Not, false) + ")"
+	case len(rule.All) > 0:
+		return group(rule.All, " and ", nested)
+	case len(rule.Any) > 0:
+		return group(rule.Any, " or ", nested)
+	}
+	return describe(rule)
+}
+
+func group(rules []Rule, sep string, nested bool) string {
+	if len(rules) == 1 {
+		return summarize(rules[0], nested)
+	}
+	parts := make([]string, 0, len(rules))
+	for _, r := range rules {
+		parts = append(parts, summarize(r, true))
+	}
+	s := strings.Join(parts, sep)
+	if nested {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+func describe(rule Rule) string {
+	label := rule.Field
+	if field, ok := lookupField(rule.Field); ok {
+		label = field.Label
+		if field.Category == CategoryCustom {
+			label = fmt.Sprintf("Custom attribute %q", field.Label)
+		}
+	}
+
+	switch rule.Op {
+	case OpEq:
+		return label + " is " + format(rule.Value)
+	case OpNeq:
+		return label + " is not " + format(rule.Value)
+	case OpIn:
+		return label + " is one of " + list(rule.Value, ", ")
+	case OpNotIn:
+		return label + " is none of " + list(rule.Value, ", ")
+	case OpGt:
+		return label + " is over " + format(rule.Value)
+	case OpGte:
+		return label + " is at least " + format(rule.Value)
+	case OpLt:
+		return label + " is under " + format(rule.Value)
+	case OpLte:
+		return label + " is at most " + format(rule.Value)
+	case OpBetween:
+		if bounds, ok := rule.Value.([]any); ok && len(bounds) == 2 {
+			return label + " is " + format(bounds[0]) + " to " + format(bounds[1])
+		}
+	case OpContainsAny:
+		return "Interested in any of " + list(rule.Value, ", ")
+	case OpContainsAll:
+		return "Interested in all of " + list(rule.Value, ", ")
+	case OpContainsNone:
+		return "Not interested in " + list(rule.Value, ", ")
+	case OpExists:
+		return label + " is set"
+	case OpNotExists:
+		return label + " is not set"
+	}
+	return label + " " + rule.Op + " " + format(rule.Value)
+}
+
+func list(value any, sep string) string {
+	items, ok := value.([]any)
+	if !ok {
+		return format(value)
+	}
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, format(item))
+	}
+	return strings.Join(parts, sep)
+}
+
+func format(value any) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,324 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func jsonEqual(t *testing.T, got []byte, want string) bool {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("result is not JSON: %s", got)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("bad expectation %s: %v", want, err)
+	}
+	return reflect.DeepEqual(g, w)
+}
+
+// The cases are the examples of RFC 7396, Appendix A.
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+		if err != nil {
+			t.Errorf("MergePatch(%s, %s): %v", tt.doc, tt.patch, err)
+			continue
+		}
+		if !jsonEqual(t, got, tt.want) {
+			t.Errorf("MergePatch(%s, %s) = %s, want %s", tt.doc, tt.patch, got, tt.want)
+		}
+	}
+}
+
+func TestMergePatchInvalid(t *testing.T) {
+	if _, err := MergePatch([]byte(`{}`), []byte(`{`)); !errors.Is(err, ErrInvalidPatch) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidPatch)
+	}
+}
+
+// The cases are the examples of RFC 6902, Appendix A, and cases from the
+// json-patch-tests suite (github.com/json-patch/json-patch-tests).
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "A.1 adding an object member",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux"}]`,
+			want:  `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			name:  "A.2 adding an array element",
+			doc:   `{"foo":["bar","baz"]}`,
+			patch: `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			want:  `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			name:  "A.3 removing an object member",
+			doc:   `{"baz":"qux","foo":"bar"}`,
+			patch: `[{"op":"remove","path":"/baz"}]`,
+			want:  `{"foo":"bar"}`,
+		},
+		{
+			name:  "A.4 removing an array element",
+			doc:   `{"foo":["bar","qux","baz"]}`,
+			patch: `[{"op":"remove","path":"/foo/1"}]`,
+			want:  `{"foo":["bar","baz"]}`,
+		},
+		{
+			name:  "A.5 replacing a value",
+			doc:   `{"baz":"qux","foo":"bar"}`,
+			patch: `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			want:  `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			name:  "A.6 moving a value",
+			doc:   `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			want:  `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			name:  "A.7 moving an array element",
+			doc:   `{"foo":["all","grass","cows","eat"]}`,
+			patch: `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			want:  `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			name:  "A.8 testing a value: success",
+			doc:   `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch: `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			want:  `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			name:    "A.9 testing a value: error",
+			doc:     `{"baz":"qux"}`,
+			patch:   `[{"op":"test","path":"/baz","value":"bar"}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:  "A.10 adding a nested member object",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+			want:  `{"foo":"bar","child":{"grandchild":{}}}`,
+		},
+		{
+			name:  "A.11 ignoring unrecognized elements",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux","xyz":123}]`,
+			want:  `{"foo":"bar","baz":"qux"}`,
+		},
+		{
+			name:    "A.12 adding to a nonexistent target",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:  "A.14 ~ escape ordering",
+			doc:   `{"/":9,"~1":10}`,
+			patch: `[{"op":"test","path":"/~01","value":10}]`,
+			want:  `{"/":9,"~1":10}`,
+		},
+		{
+			name:    "A.15 comparing strings and numbers",
+			doc:     `{"/":9,"~1":10}`,
+			patch:   `[{"op":"test","path":"/~01","value":"10"}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:  "A.16 adding an array value",
+			doc:   `{"foo":["bar"]}`,
+			patch: `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			want:  `{"foo":["bar",["abc","def"]]}`,
+		},
+		{
+			name:  "empty patch",
+			doc:   `{"foo":1}`,
+			patch: `[]`,
+			want:  `{"foo":1}`,
+		},
+		{
+			name:  "replace the whole document",
+			doc:   `{"foo":1}`,
+			patch: `[{"op":"replace","path":"","value":{"baz":"qux"}}]`,
+			want:  `{"baz":"qux"}`,
+		},
+		{
+			name:  "add with an empty key",
+			doc:   `{}`,
+			patch: `[{"op":"add","path":"/","value":1}]`,
+			want:  `{"":1}`,
+		},
+		{
+			name:  "add null",
+			doc:   `{}`,
+			patch: `[{"op":"add","path":"/foo","value":null}]`,
+			want:  `{"foo":null}`,
+		},
+		{
+			name:  "replace a value with null",
+			doc:   `{"foo":1}`,
+			patch: `[{"op":"replace","path":"/foo","value":null}]`,
+			want:  `{"foo":null}`,
+		},
+		{
+			name:  "test null",
+			doc:   `{"foo":null}`,
+			patch: `[{"op":"test","path":"/foo","value":null}]`,
+			want:  `{"foo":null}`,
+		},
+		{
+			name:  "test objects regardless of member order",
+			doc:   `{"foo":{"a":1,"b":2}}`,
+			patch: `[{"op":"test","path":"/foo","value":{"b":2,"a":1}}]`,
+			want:  `{"foo":{"a":1,"b":2}}`,
+		},
+		{
+			name:  "copy an object",
+			doc:   `{"foo":{"bar":1}}`,
+			patch: `[{"op":"copy","from":"/foo","path":"/baz"}]`,
+			want:  `{"foo":{"bar":1},"baz":{"bar":1}}`,
+		},
+		{
+			name:  "add at the end of an array by index",
+			doc:   `["foo","sil"]`,
+			patch: `[{"op":"add","path":"/2","value":"bar"}]`,
+			want:  `["foo","sil","bar"]`,
+		},
+		{
+			name:  "add at the start of an array",
+			doc:   `["foo","sil"]`,
+			patch: `[{"op":"add","path":"/0","value":"bar"}]`,
+			want:  `["bar","foo","sil"]`,
+		},
+		{
+			name:  "move to the same location",
+			doc:   `{"foo":1}`,
+			patch: `[{"op":"move","from":"/foo","path":"/foo"}]`,
+			want:  `{"foo":1}`,
+		},
+		{
+			name:    "add past the end of an array",
+			doc:     `["foo","sil"]`,
+			patch:   `[{"op":"add","path":"/3","value":"bar"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "remove a missing member",
+			doc:     `{"foo":1}`,
+			patch:   `[{"op":"remove","path":"/bar"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "replace a missing member",
+			doc:     `{"foo":1}`,
+			patch:   `[{"op":"replace","path":"/bar","value":2}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "leading zero in an array index",
+			doc:     `["foo","bar"]`,
+			patch:   `[{"op":"test","path":"/01","value":"bar"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "negative array index",
+			doc:     `["foo","bar"]`,
+			patch:   `[{"op":"add","path":"/-1","value":"baz"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "unknown operation",
+			doc:     `{"foo":1}`,
+			patch:   `[{"op":"spam","path":"/foo","value":1}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "missing value",
+			doc:     `{"foo":1}`,
+			patch:   `[{"op":"add","path":"/bar"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "missing from",
+			doc:     `{"foo":1}`,
+			patch:   `[{"op":"move","path":"/bar"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "path without a leading slash",
+			doc:     `{"foo":1}`,
+			patch:   `[{"op":"add","path":"bar","value":1}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "move into its own child",
+			doc:     `{"foo":{"bar":1}}`,
+			patch:   `[{"op":"move","from":"/foo","path":"/foo/bar/baz"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "patch is not an array",
+			doc:     `{"foo":1}`,
+			patch:   `{"op":"add","path":"/bar","value":1}`,
+			wantErr: ErrInvalidPatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !jsonEqual(t, got, tt.want) {
+				t.Errorf("Apply = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// A failed operation leaves the document untouched: the patch is applied
+// as a whole or not at all.
+func TestApplyIsAtomic(t *testing.T) {
+	doc := []byte(`{"foo":"bar"}`)
+	_, err := Apply(doc, []byte(`[{"op":"add","path":"/baz","value":1},{"op":"test","path":"/foo","value":"qux"}]`))
+	if !errors.Is(err, ErrTestFailed) {
+		t.Fatalf("err = %v, want %v", err, ErrTestFailed)
+	}
+	if string(doc) != `{"foo":"bar"}` {
+		t.Errorf("document changed to %s", doc)
+	}
+}
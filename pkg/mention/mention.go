@@ -0,0 +1,53 @@
+// Package mention finds @mentions of workspace members in Markdown text.
+// A mention is an @ followed by the member's email address, for example
+// "@jane@example.com".
+package mention
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MaxPerText caps how many distinct members one text can mention.
+const MaxPerText = 20
+
+var (
+	mentionPattern = regexp.MustCompile(`(^|[^A-Za-z0-9._%+\-@\\])@([A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,})`)
+	inlineCode     = regexp.MustCompile("`+[^`]*`+")
+)
+
+// Extract returns the lower-cased email addresses mentioned in a Markdown
+// body, in order of first appearance and without duplicates. Mentions inside
+// fenced code blocks, inline code spans or escaped as \@ are ignored.
+func Extract(markdown string) []string {
+	var emails []string
+	seen := map[string]bool{}
+	fence := ""
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		line = inlineCode.ReplaceAllString(line, " ")
+		for _, m := range mentionPattern.FindAllStringSubmatch(line, -1) {
+			email := strings.ToLower(m[2])
+			if seen[email] {
+				continue
+			}
+			seen[email] = true
+			emails = append(emails, email)
+			if len(emails) == MaxPerText {
+				return emails
+			}
+		}
+	}
+	return emails
+}
@@ -0,0 +1,95 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultLimit = 10
+	MaxLimit     = 100
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the keyset position of a row inside a sorted list. Lists sort by
+// one typed key (Time, Number or Text) and break ties with ID; keys that the
+// list does not sort by keep whatever constant value the query returned.
+// Clients only ever see the opaque Encode form.
+type Cursor struct {
+	SortBy    string    `json:"s,omitempty"`
+	SortOrder string    `json:"o,omitempty"`
+	Backward  bool      `json:"b,omitempty"`
+	Time      time.Time `json:"t"`
+	Number    float64   `json:"n,omitempty"`
+	Text      string    `json:"x,omitempty"`
+	ID        uuid.UUID `json:"i"`
+}
+
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode parses a cursor produced by Encode. An empty token means "first
+// page" and yields a nil cursor.
+func Decode(token string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Limit applies the default and upper bound to a requested page size.
+func Limit(requested int) int {
+	if requested <= 0 {
+		return DefaultLimit
+	}
+	return min(requested, MaxLimit)
+}
+
+// ScanOrder is the direction the query has to walk in: the list's own order
+// when paging forward, the reverse when paging backward from a prev cursor.
+func ScanOrder(sortOrder string, cur *Cursor) string {
+	if cur == nil || !cur.Backward {
+		return sortOrder
+	}
+	if sortOrder == OrderAsc {
+		return OrderDesc
+	}
+	return OrderAsc
+}
+
+// Trim takes rows fetched with LIMIT limit+1 in ScanOrder direction and
+// returns the page in display order together with whether a next and a
+// previous page exist.
+func Trim[T any](rows []T, limit int, cur *Cursor) (items []T, hasNext, hasPrev bool) {
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	if cur != nil && cur.Backward {
+		slices.Reverse(rows)
+		return rows, true, hasMore
+	}
+	return rows, hasMore, cur != nil
+}
@@ -0,0 +1,78 @@
+package spreadsheet
+
+import "strings"
+
+// Locale controls how numbers and dates are rendered as text. It only
+// affects CSV output; XLSX stores native numbers and dates and uses the
+// Excel formats, and NDJSON always uses plain JSON numbers and ISO dates.
+type Locale struct {
+	Tag string
+	// Decimal separates the integer and fractional parts of a number.
+	Decimal byte
+	// Comma is the CSV field separator; locales with a decimal comma use ';'
+	// so that Excel splits the columns correctly.
+	Comma          rune
+	DateLayout     string
+	DateTimeLayout string
+	// ExcelDate and ExcelDateTime are XLSX number format codes.
+	ExcelDate     string
+	ExcelDateTime string
+}
+
+// DefaultLocale renders ISO 8601 dates and a decimal point.
+var DefaultLocale = Locale{
+	Tag:            "",
+	Decimal:        '.',
+	Comma:          ',',
+	DateLayout:     "2006-01-02",
+	DateTimeLayout: "2006-01-02 15:04:05",
+	ExcelDate:      "yyyy-mm-dd",
+	ExcelDateTime:  "yyyy-mm-dd hh:mm:ss",
+}
+
+// locales is ordered so that a bare language ("en") resolves to its first
+// regional entry.
+var locales = []Locale{
+	{"en-US", '.', ',', "01/02/2006", "01/02/2006 15:04:05", "mm/dd/yyyy", "mm/dd/yyyy hh:mm:ss"},
+	{"en-GB", '.', ',', "02/01/2006", "02/01/2006 15:04:05", "dd/mm/yyyy", "dd/mm/yyyy hh:mm:ss"},
+	{"de-DE", ',', ';', "02.01.2006", "02.01.2006 15:04:05", "dd.mm.yyyy", "dd.mm.yyyy hh:mm:ss"},
+	{"fr-FR", ',', ';', "02/01/2006", "02/01/2006 15:04:05", "dd/mm/yyyy", "dd/mm/yyyy hh:mm:ss"},
+	{"es-ES", ',', ';', "02/01/2006", "02/01/2006 15:04:05", "dd/mm/yyyy", "dd/mm/yyyy hh:mm:ss"},
+	{"it-IT", ',', ';', "02/01/2006", "02/01/2006 15:04:05", "dd/mm/yyyy", "dd/mm/yyyy hh:mm:ss"},
+	{"nl-NL", ',', ';', "02-01-2006", "02-01-2006 15:04:05", "dd-mm-yyyy", "dd-mm-yyyy hh:mm:ss"},
+	{"pt-BR", ',', ';', "02/01/2006", "02/01/2006 15:04:05", "dd/mm/yyyy", "dd/mm/yyyy hh:mm:ss"},
+	{"id-ID", ',', ';', "02/01/2006", "02/01/2006 15:04:05", "dd/mm/yyyy", "dd/mm/yyyy hh:mm:ss"},
+	{"ja-JP", '.', ',', "2006/01/02", "2006/01/02 15:04:05", "yyyy/mm/dd", "yyyy/mm/dd hh:mm:ss"},
+}
+
+// LookupLocale finds a locale by BCP 47 tag such as "de-DE" or "de_DE". A
+// bare language matches the first locale of that language; an empty tag
+// returns DefaultLocale.
+func LookupLocale(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if tag == "" {
+		return DefaultLocale, true
+	}
+	for _, l := range locales {
+		if strings.ToLower(l.Tag) == tag {
+			return l, true
+		}
+	}
+	if !strings.Contains(tag, "-") {
+		for _, l := range locales {
+			if strings.HasPrefix(strings.ToLower(l.Tag), tag+"-") {
+				return l, true
+			}
+		}
+	}
+	return Locale{}, false
+}
+
+// LocaleTags lists the supported locale tags.
+func LocaleTags() []string {
+	tags := make([]string, 0, len(locales))
+	for _, l := range locales {
+		tags = append(tags, l.Tag)
+	}
+	return tags
+}
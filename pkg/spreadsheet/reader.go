@@ -0,0 +1,312 @@
+// Package spreadsheet reads tabular data from CSV and XLSX files using only
+// the standard library.
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidFile = errors.New("invalid spreadsheet file")
+
+// ErrRowLimit is returned when a sheet has more rows than the caller
+// allows.
+var ErrRowLimit = errors.New("sheet exceeds the row limit")
+
+const (
+	// maxPartSize caps how much of a single XLSX part is decompressed, so a
+	// small upload cannot expand into an unbounded amount of memory.
+	maxPartSize = 64 << 20
+	// maxSheetRows and maxSheetColumns are the limits of an Excel worksheet;
+	// references beyond them are not valid.
+	maxSheetRows    = 1 << 20
+	maxSheetColumns = 1 << 14
+	// maxSheetCells caps the cells kept once rows are padded out to their
+	// cell references, so a few far-right cells cannot blow up memory.
+	maxSheetCells = 1 << 22
+)
+
+// Sheet is the first worksheet of a file as rows of cell text. Row i holds
+// spreadsheet row i+1; empty rows are kept so row numbers stay meaningful.
+type Sheet struct {
+	Rows [][]string
+	// Date1904 is set for workbooks that count serial dates from 1904.
+	Date1904 bool
+	// Comma is the field separator of a CSV file, zero for workbooks.
+	Comma rune
+}
+
+// ReadCSV parses comma or semicolon separated text; the separator is picked
+// from the header line. A UTF-8 byte order mark is ignored. Files with more
+// than maxRows rows fail with ErrRowLimit.
+func ReadCSV(r io.Reader, maxRows int) (*Sheet, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		_, _ = br.Discard(3)
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if header, err := br.Peek(4096); len(header) > 0 {
+		line, _, _ := bytes.Cut(header, []byte("\n"))
+		if bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(",")) {
+			reader.Comma = ';'
+		}
+	} else if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	sheet := &Sheet{Comma: reader.Comma}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return sheet, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		if len(sheet.Rows) == maxRows {
+			return nil, ErrRowLimit
+		}
+		sheet.Rows = append(sheet.Rows, row)
+	}
+}
+
+// ReadXLSX parses the first worksheet of an Office Open XML workbook.
+// Sheets reaching past row maxRows fail with ErrRowLimit before any
+// padding rows are allocated.
+func ReadXLSX(r io.ReaderAt, size int64, maxRows int) (*Sheet, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+
+	var wb workbook
+	if err := decodePart(files, "xl/workbook.xml", &wb); err != nil {
+		return nil, err
+	}
+	if len(wb.Sheets) == 0 {
+		return nil, fmt.Errorf("%w: workbook has no sheets", ErrInvalidFile)
+	}
+
+	var rels relationships
+	if err := decodePart(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	var sheetPath string
+	for _, rel := range rels.Items {
+		if rel.ID == wb.Sheets[0].RelID {
+			sheetPath = resolveTarget(rel.Target)
+		}
+	}
+	if sheetPath == "" {
+		return nil, fmt.Errorf("%w: first sheet not found", ErrInvalidFile)
+	}
+
+	var shared []string
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		var sst sharedStrings
+		if err := decodePart(files, "xl/sharedStrings.xml", &sst); err != nil {
+			return nil, err
+		}
+		shared = make([]string, len(sst.Items))
+		for i, si := range sst.Items {
+			shared[i] = si.text()
+		}
+	}
+
+	var ws worksheet
+	if err := decodePart(files, sheetPath, &ws); err != nil {
+		return nil, err
+	}
+
+	sheet := &Sheet{Date1904: wb.Properties.Date1904}
+	totalCells := 0
+	for _, row := range ws.Rows {
+		rowIdx := len(sheet.Rows)
+		if row.Num != 0 {
+			if row.Num < 0 || row.Num > maxSheetRows {
+				return nil, fmt.Errorf("%w: bad row number %d", ErrInvalidFile, row.Num)
+			}
+			rowIdx = row.Num - 1
+		}
+		if rowIdx >= maxRows {
+			return nil, ErrRowLimit
+		}
+		for len(sheet.Rows) <= rowIdx {
+			sheet.Rows = append(sheet.Rows, nil)
+		}
+
+		var cells []string
+		for _, c := range row.Cells {
+			col := len(cells)
+			if c.Ref != "" {
+				if col, err = columnIndex(c.Ref); err != nil {
+					return nil, err
+				}
+			}
+			if col >= maxSheetColumns {
+				return nil, fmt.Errorf("%w: bad cell reference %q", ErrInvalidFile, c.Ref)
+			}
+			if col >= len(cells) {
+				totalCells += col + 1 - len(cells)
+				if totalCells > maxSheetCells {
+					return nil, fmt.Errorf("%w: sheet has too many cells", ErrInvalidFile)
+				}
+				cells = append(cells, make([]string, col+1-len(cells))...)
+			}
+			if cells[col], err = c.value(shared); err != nil {
+				return nil, err
+			}
+		}
+		sheet.Rows[rowIdx] = cells
+	}
+	return sheet, nil
+}
+
+// ExcelSerialTime converts a spreadsheet serial date (days since the epoch
+// of the workbook's date system, with the fraction as time of day) to UTC.
+func ExcelSerialTime(serial float64, date1904 bool) time.Time {
+	// 1899-12-30 absorbs Excel's fictitious 1900-02-29 for all dates after it.
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if date1904 {
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	days := math.Floor(serial)
+	secs := math.Round((serial - days) * 86400)
+	return epoch.AddDate(0, 0, int(days)).Add(time.Duration(secs) * time.Second)
+}
+
+type workbook struct {
+	Properties struct {
+		Date1904 bool `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		Name  string `xml:"name,attr"`
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relationships struct {
+	Items []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type richText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (rt richText) text() string {
+	if len(rt.Runs) == 0 {
+		return rt.T
+	}
+	var sb strings.Builder
+	for _, run := range rt.Runs {
+		sb.WriteString(run.T)
+	}
+	return sb.String()
+}
+
+type sharedStrings struct {
+	Items []richText `xml:"si"`
+}
+
+type worksheet struct {
+	Rows []struct {
+		Num   int    `xml:"r,attr"`
+		Cells []cell `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type cell struct {
+	Ref    string   `xml:"r,attr"`
+	Type   string   `xml:"t,attr"`
+	Value  string   `xml:"v"`
+	Inline richText `xml:"is"`
+}
+
+func (c cell) value(shared []string) (string, error) {
+	switch c.Type {
+	case "s":
+		idx, err := strconv.Atoi(c.Value)
+		if err != nil || idx < 0 || idx >= len(shared) {
+			return "", fmt.Errorf("%w: bad shared string reference in %s", ErrInvalidFile, c.Ref)
+		}
+		return shared[idx], nil
+	case "inlineStr":
+		return c.Inline.text(), nil
+	case "b":
+		if c.Value == "1" {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	default:
+		return c.Value, nil
+	}
+}
+
+func decodePart(files map[string]*zip.File, name string, v interface{}) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%w: missing %s", ErrInvalidFile, name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(rc, maxPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidFile, name, err)
+	}
+	return nil
+}
+
+// resolveTarget turns a workbook relationship target into a zip entry name.
+func resolveTarget(target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join("xl", target)
+}
+
+// columnIndex returns the zero-based column of a cell reference like "AB12".
+func columnIndex(ref string) (int, error) {
+	col := 0
+	for i, r := range ref {
+		if r >= 'A' && r <= 'Z' {
+			// Stop before a long run of letters can overflow.
+			if col > maxSheetColumns {
+				break
+			}
+			col = col*26 + int(r-'A'+1)
+			continue
+		}
+		if i == 0 {
+			break
+		}
+		return col - 1, nil
+	}
+	return 0, fmt.Errorf("%w: bad cell reference %q", ErrInvalidFile, ref)
+}
@@ -0,0 +1,355 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testWorkbook = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	testRels     = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`
+)
+
+// buildXLSX zips the given parts, filling in a one-sheet workbook for the
+// parts that are not given. A part given as "" is left out.
+func buildXLSX(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	all := map[string]string{
+		"xl/workbook.xml":            testWorkbook,
+		"xl/_rels/workbook.xml.rels": testRels,
+	}
+	for name, body := range parts {
+		all[name] = body
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range all {
+		if body == "" {
+			continue
+		}
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func sheetXML(rows string) string {
+	return `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + rows + `</sheetData></worksheet>`
+}
+
+func readXLSX(data []byte, maxRows int) (*Sheet, error) {
+	return ReadXLSX(bytes.NewReader(data), int64(len(data)), maxRows)
+}
+
+func TestReadXLSX(t *testing.T) {
+	data := buildXLSX(t, map[string]string{
+		"xl/workbook.xml": strings.Replace(testWorkbook, "<sheets>", `<workbookPr date1904="1"/><sheets>`, 1),
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>title</t></si><si><r><t>bud</t></r><r><t>get</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": sheetXML(
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>` +
+				`<row r="3"><c r="A3" t="inlineStr"><is><t>Spring sale</t></is></c><c t="b"><v>1</v></c><c><v>1500.5</v></c></row>`,
+		),
+	})
+
+	sheet, err := readXLSX(data, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"title", "", "budget"},
+		nil,
+		{"Spring sale", "TRUE", "1500.5"},
+	}
+	if !reflect.DeepEqual(sheet.Rows, want) {
+		t.Errorf("rows = %q, want %q", sheet.Rows, want)
+	}
+	if !sheet.Date1904 {
+		t.Error("Date1904 not set")
+	}
+}
+
+func TestReadXLSXHostile(t *testing.T) {
+	farRight := strings.Builder{}
+	for r := 1; r <= 300; r++ {
+		fmt.Fprintf(&farRight, `<row r="%d"><c r="XFD%d"><v>1</v></c></row>`, r, r)
+	}
+
+	tests := []struct {
+		name    string
+		data    func(t *testing.T) []byte
+		maxRows int
+		wantErr error
+	}{
+		{
+			name: "row number past the sheet limit",
+			data: func(t *testing.T) []byte {
+				return buildXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": sheetXML(`<row r="20000000"><c r="A20000000"><v>1</v></c></row>`)})
+			},
+			maxRows: 1 << 30,
+			wantErr: ErrInvalidFile,
+		},
+		{
+			name: "negative row number",
+			data: func(t *testing.T) []byte {
+				return buildXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": sheetXML(`<row r="-5"><c><v>1</v></c></row>`)})
+			},
+			maxRows: 100,
+			wantErr: ErrInvalidFile,
+		},
+		{
+			name: "row number past the caller's limit",
+			data: func(t *testing.T) []byte {
+				return buildXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": sheetXML(`<row r="1048576"><c r="A1048576"><v>1</v></c></row>`)})
+			},
+			maxRows: 100,
+			wantErr: ErrRowLimit,
+		},
+		{
+			name: "column reference that overflows",
+			data: func(t *testing.T) []byte {
+				return buildXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": sheetXML(`<row r="1"><c r="ZZZZZZZZZZZZZZZZ1"><v>1</v></c></row>`)})
+			},
+			maxRows: 100,
+			wantErr: ErrInvalidFile,
+		},
+		{
+			name: "column past the sheet limit",
+			data: func(t *testing.T) []byte {
+				return buildXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": sheetXML(`<row r="1"><c r="XFE1"><v>1</v></c></row>`)})
+			},
+			maxRows: 100,
+			wantErr: ErrInvalidFile,
+		},
+		{
+			name: "reference without a row",
+			data: func(t *testing.T) []byte {
+				return buildXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": sheetXML(`<row r="1"><c r="AB"><v>1</v></c></row>`)})
+			},
+			maxRows: 100,
+			wantErr: ErrInvalidFile,
+		},
+		{
+			name: "many cells far to the right",
+			data: func(t *testing.T) []byte {
+				return buildXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": sheetXML(farRight.String())})
+			},
+			maxRows: 1000,
+			wantErr: ErrInvalidFile,
+		},
+		{
+			name: "shared string out of range",
+			data: func(t *testing.T) []byte {
+				return buildXLSX(t, map[string]string{
+					"xl/sharedStrings.xml":     `<sst><si><t>a</t></si></sst>`,
+					"xl/worksheets/sheet1.xml": sheetXML(`<row r="1"><c r="A1" t="s"><v>7</v></c></row>`),
+				})
+			},
+			maxRows: 100,
+			wantErr: ErrInvalidFile,
+		},
+		{
+			name: "missing worksheet",
+			data: func(t *testing.T) []byte {
+				return buildXLSX(t, nil)
+			},
+			maxRows: 100,
+			wantErr: ErrInvalidFile,
+		},
+		{
+			name: "missing workbook",
+			data: func(t *testing.T) []byte {
+				return buildXLSX(t, map[string]string{"xl/workbook.xml": ""})
+			},
+			maxRows: 100,
+			wantErr: ErrInvalidFile,
+		},
+		{
+			name: "workbook without sheets",
+			data: func(t *testing.T) []byte {
+				return buildXLSX(t, map[string]string{"xl/workbook.xml": `<workbook><sheets/></workbook>`})
+			},
+			maxRows: 100,
+			wantErr: ErrInvalidFile,
+		},
+		{
+			name: "malformed worksheet",
+			data: func(t *testing.T) []byte {
+				return buildXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row>`})
+			},
+			maxRows: 100,
+			wantErr: ErrInvalidFile,
+		},
+		{
+			name: "not a zip file",
+			data: func(t *testing.T) []byte {
+				return []byte("title,budget\n")
+			},
+			maxRows: 100,
+			wantErr: ErrInvalidFile,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readXLSX(tt.data(t), tt.maxRows); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadXLSXRowLimit(t *testing.T) {
+	var rows strings.Builder
+	for r := 1; r <= 5; r++ {
+		fmt.Fprintf(&rows, `<row r="%d"><c r="A%d"><v>%d</v></c></row>`, r, r, r)
+	}
+	data := buildXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": sheetXML(rows.String())})
+
+	if _, err := readXLSX(data, 5); err != nil {
+		t.Errorf("five rows with a limit of five: %v", err)
+	}
+	if _, err := readXLSX(data, 4); !errors.Is(err, ErrRowLimit) {
+		t.Errorf("five rows with a limit of four: err = %v, want %v", err, ErrRowLimit)
+	}
+}
+
+func TestXLSXRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewXLSXWriter(&buf, "Campaigns", []string{"title", "budget", "start"}, DefaultLocale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	if err := w.WriteRow([]Cell{Text("A & <B>"), Float(1250.5, 2), Date(start)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow([]Cell{Text("C"), Empty(), Int(3)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	sheet, err := readXLSX(buf.Bytes(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sheet.Rows) != 3 {
+		t.Fatalf("rows = %q", sheet.Rows)
+	}
+	if got := sheet.Rows[0]; !reflect.DeepEqual(got, []string{"title", "budget", "start"}) {
+		t.Errorf("header = %q", got)
+	}
+	if got := sheet.Rows[1][0]; got != "A & <B>" {
+		t.Errorf("title = %q", got)
+	}
+	if got := sheet.Rows[1][1]; got != "1250.5" {
+		t.Errorf("budget = %q", got)
+	}
+	if got := sheet.Rows[1][2]; got != fmt.Sprint(ExcelSerial(start)) {
+		t.Errorf("start = %q, want serial %v", got, ExcelSerial(start))
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		maxRows int
+		want    [][]string
+		comma   rune
+		wantErr error
+	}{
+		{
+			name:    "comma separated",
+			input:   "title,budget\nSale,100\n",
+			maxRows: 10,
+			want:    [][]string{{"title", "budget"}, {"Sale", "100"}},
+			comma:   ',',
+		},
+		{
+			name:    "semicolons and a byte order mark",
+			input:   "\xEF\xBB\xBFtitle;budget\nSale;1,5\n",
+			maxRows: 10,
+			want:    [][]string{{"title", "budget"}, {"Sale", "1,5"}},
+			comma:   ';',
+		},
+		{
+			name:    "ragged rows",
+			input:   "a,b,c\n1\n",
+			maxRows: 10,
+			want:    [][]string{{"a", "b", "c"}, {"1"}},
+			comma:   ',',
+		},
+		{
+			name:    "too many rows",
+			input:   "a\n1\n2\n3\n",
+			maxRows: 3,
+			wantErr: ErrRowLimit,
+		},
+		{
+			name:    "stray quotes",
+			input:   "a,b\nsay \"hi\",1\n",
+			maxRows: 10,
+			want:    [][]string{{"a", "b"}, {`say "hi"`, "1"}},
+			comma:   ',',
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheet, err := ReadCSV(strings.NewReader(tt.input), tt.maxRows)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(sheet.Rows, tt.want) {
+				t.Errorf("rows = %q, want %q", sheet.Rows, tt.want)
+			}
+			if sheet.Comma != tt.comma {
+				t.Errorf("comma = %q, want %q", sheet.Comma, tt.comma)
+			}
+		})
+	}
+}
+
+func TestExcelSerialTime(t *testing.T) {
+	tests := []struct {
+		serial   float64
+		date1904 bool
+		want     time.Time
+	}{
+		{1, false, time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{61, false, time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{45000, false, time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{45000.75, false, time.Date(2023, 3, 15, 18, 0, 0, 0, time.UTC)},
+		{0, true, time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{43538, true, time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := ExcelSerialTime(tt.serial, tt.date1904); !got.Equal(tt.want) {
+			t.Errorf("ExcelSerialTime(%v, %v) = %v, want %v", tt.serial, tt.date1904, got, tt.want)
+		}
+		if !tt.date1904 && tt.serial >= 61 {
+			if got := ExcelSerial(tt.want); got != tt.serial {
+				t.Errorf("ExcelSerial(%v) = %v, want %v", tt.want, got, tt.serial)
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package spreadsheet
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrTooManyRows = errors.New("too many rows for the output format")
+
+type cellKind int
+
+const (
+	kindEmpty cellKind = iota
+	kindText
+	kindInt
+	kindFloat
+	kindDate
+	kindDateTime
+)
+
+// Cell is a typed value so that each writer can render it natively.
+type Cell struct {
+	kind     cellKind
+	text     string
+	number   float64
+	decimals int
+	time     time.Time
+}
+
+func Empty() Cell { return Cell{} }
+
+func Text(s string) Cell { return Cell{kind: kindText, text: s} }
+
+func Int(n int64) Cell { return Cell{kind: kindInt, number: float64(n)} }
+
+// Float is a number rounded to decimals places; a negative value keeps the
+// shortest exact representation.
+func Float(f float64, decimals int) Cell {
+	return Cell{kind: kindFloat, number: f, decimals: decimals}
+}
+
+// Date keeps only the calendar day of t in its own location.
+func Date(t time.Time) Cell { return Cell{kind: kindDate, time: t} }
+
+// DateTime renders t as wall clock time in its own location.
+func DateTime(t time.Time) Cell { return Cell{kind: kindDateTime, time: t} }
+
+// Writer writes rows under the header given to its constructor. Close must
+// be called to flush buffered output; it does not close the underlying
+// io.Writer.
+type Writer interface {
+	WriteRow(cells []Cell) error
+	Close() error
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	locale Locale
+	record []string
+}
+
+// NewCSVWriter writes a UTF-8 CSV with a byte order mark, which Excel needs
+// to detect the encoding, using the locale's separator and formats.
+func NewCSVWriter(w io.Writer, header []string, locale Locale) (Writer, error) {
+	if _, err := io.WriteString(w, "\xEF\xBB\xBF"); err != nil {
+		return nil, err
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = locale.Comma
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: cw, locale: locale, record: make([]string, len(header))}, nil
+}
+
+func (w *csvWriter) WriteRow(cells []Cell) error {
+	w.record = w.record[:0]
+	for _, c := range cells {
+		w.record = append(w.record, w.format(c))
+	}
+	return w.w.Write(w.record)
+}
+
+func (w *csvWriter) format(c Cell) string {
+	switch c.kind {
+	case kindText:
+		return escapeFormula(c.text)
+	case kindInt:
+		return strconv.FormatInt(int64(c.number), 10)
+	case kindFloat:
+		s := strconv.FormatFloat(c.number, 'f', c.decimals, 64)
+		if w.locale.Decimal != '.' {
+			s = strings.Replace(s, ".", string(w.locale.Decimal), 1)
+		}
+		return s
+	case kindDate:
+		return c.time.Format(w.locale.DateLayout)
+	case kindDateTime:
+		return c.time.Format(w.locale.DateTimeLayout)
+	default:
+		return ""
+	}
+}
+
+func (w *csvWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// escapeFormula prefixes text that a spreadsheet would evaluate as a formula
+// with an apostrophe, so user-entered titles cannot run in Excel.
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+type ndjsonWriter struct {
+	w    *bufio.Writer
+	keys [][]byte
+}
+
+// NewNDJSONWriter writes one JSON object per line keyed by header. Dates use
+// ISO 8601 and numbers are plain JSON numbers regardless of locale.
+func NewNDJSONWriter(w io.Writer, header []string) (Writer, error) {
+	keys := make([][]byte, len(header))
+	for i, h := range header {
+		key, err := json.Marshal(h)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+	}
+	return &ndjsonWriter{w: bufio.NewWriter(w), keys: keys}, nil
+}
+
+func (w *ndjsonWriter) WriteRow(cells []Cell) error {
+	w.w.WriteByte('{')
+	for i, c := range cells {
+		if i > 0 {
+			w.w.WriteByte(',')
+		}
+		w.w.Write(w.keys[i])
+		w.w.WriteByte(':')
+		if err := w.writeValue(c); err != nil {
+			return err
+		}
+	}
+	w.w.WriteString("}\n")
+	return nil
+}
+
+func (w *ndjsonWriter) writeValue(c Cell) error {
+	var v interface{}
+	switch c.kind {
+	case kindText:
+		v = c.text
+	case kindInt:
+		v = int64(c.number)
+	case kindFloat:
+		if math.IsNaN(c.number) || math.IsInf(c.number, 0) {
+			v = nil
+		} else if c.decimals >= 0 {
+			v = json.Number(strconv.FormatFloat(c.number, 'f', c.decimals, 64))
+		} else {
+			v = c.number
+		}
+	case kindDate:
+		v = c.time.Format("2006-01-02")
+	case kindDateTime:
+		v = c.time.Format(time.RFC3339)
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(raw)
+	return err
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.w.Flush()
+}
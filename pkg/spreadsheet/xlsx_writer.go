@@ -0,0 +1,206 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxXLSXRows is the worksheet row limit of Excel, header included.
+const maxXLSXRows = 1 << 20
+
+// Style indexes into the cellXfs written by xlsxStyles.
+const (
+	styleDefault = iota
+	styleHeader
+	styleDate
+	styleDateTime
+	styleMoney
+)
+
+type xlsxWriter struct {
+	zw   *zip.Writer
+	w    *bufio.Writer
+	rows int
+	buf  []byte
+}
+
+// NewXLSXWriter streams a single-sheet workbook. Every part except the
+// worksheet is written up front, so rows go straight to w and memory use does
+// not grow with the number of rows.
+func NewXLSXWriter(w io.Writer, sheetName string, header []string, locale Locale) (Writer, error) {
+	zw := zip.NewWriter(w)
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook(sheetName)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles(locale)},
+	}
+	for _, p := range parts {
+		f, err := zw.Create(p.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, p.body); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	xw := &xlsxWriter{zw: zw, w: bufio.NewWriter(sheet)}
+	xw.w.WriteString(xml.Header)
+	xw.w.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	xw.w.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	xw.w.WriteString(`<sheetData>`)
+
+	cells := make([]Cell, len(header))
+	for i, h := range header {
+		cells[i] = Text(h)
+	}
+	if err := xw.writeRow(cells, styleHeader); err != nil {
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (w *xlsxWriter) WriteRow(cells []Cell) error {
+	return w.writeRow(cells, styleDefault)
+}
+
+func (w *xlsxWriter) writeRow(cells []Cell, textStyle int) error {
+	if w.rows >= maxXLSXRows {
+		return ErrTooManyRows
+	}
+	w.rows++
+	row := strconv.Itoa(w.rows)
+
+	w.w.WriteString(`<row r="` + row + `">`)
+	for i, c := range cells {
+		if c.kind == kindEmpty {
+			continue
+		}
+		ref := columnName(i) + row
+		switch c.kind {
+		case kindText:
+			w.w.WriteString(`<c r="` + ref + `" t="inlineStr"`)
+			if textStyle != styleDefault {
+				w.w.WriteString(` s="` + strconv.Itoa(textStyle) + `"`)
+			}
+			w.w.WriteString(`><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(w.w, []byte(c.text)); err != nil {
+				return err
+			}
+			w.w.WriteString(`</t></is></c>`)
+		case kindInt:
+			w.writeNumber(ref, styleDefault, strconv.FormatInt(int64(c.number), 10))
+		case kindFloat:
+			style := styleDefault
+			if c.decimals == 2 {
+				style = styleMoney
+			}
+			w.writeNumber(ref, style, strconv.FormatFloat(c.number, 'f', -1, 64))
+		case kindDate:
+			serial := ExcelSerial(time.Date(c.time.Year(), c.time.Month(), c.time.Day(), 0, 0, 0, 0, time.UTC))
+			w.writeNumber(ref, styleDate, strconv.FormatFloat(serial, 'f', -1, 64))
+		case kindDateTime:
+			w.writeNumber(ref, styleDateTime, strconv.FormatFloat(ExcelSerial(c.time), 'f', -1, 64))
+		}
+	}
+	_, err := w.w.WriteString(`</row>`)
+	return err
+}
+
+func (w *xlsxWriter) writeNumber(ref string, style int, value string) {
+	w.w.WriteString(`<c r="` + ref + `"`)
+	if style != styleDefault {
+		w.w.WriteString(` s="` + strconv.Itoa(style) + `"`)
+	}
+	w.w.WriteString(`><v>` + value + `</v></c>`)
+}
+
+func (w *xlsxWriter) Close() error {
+	w.w.WriteString(`</sheetData></worksheet>`)
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
+
+// ExcelSerial is the inverse of ExcelSerialTime for the 1900 date system. The
+// wall clock of t is used, so convert t to the desired location first.
+func ExcelSerial(t time.Time) float64 {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return wall.Sub(epoch).Seconds() / 86400
+}
+
+// columnName returns the letters of a zero-based column index, e.g. 27 → "AB".
+func columnName(idx int) string {
+	var name []byte
+	for idx >= 0 {
+		name = append([]byte{byte('A' + idx%26)}, name...)
+		idx = idx/26 - 1
+	}
+	return string(name)
+}
+
+func xmlAttr(s string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return strings.ReplaceAll(sb.String(), `"`, "&quot;")
+}
+
+func xlsxWorkbook(sheetName string) string {
+	return xml.Header +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + xmlAttr(sheetName) + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+}
+
+func xlsxStyles(locale Locale) string {
+	return xml.Header +
+		`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<numFmts count="2">` +
+		`<numFmt numFmtId="164" formatCode="` + xmlAttr(locale.ExcelDate) + `"/>` +
+		`<numFmt numFmtId="165" formatCode="` + xmlAttr(locale.ExcelDateTime) + `"/>` +
+		`</numFmts>` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="5">` +
+		`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`</cellXfs>` +
+		`</styleSheet>`
+}
+
+const xlsxContentTypes = xml.Header +
+	`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = xml.Header +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbookRels = xml.Header +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
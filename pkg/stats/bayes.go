@@ -0,0 +1,149 @@
+package stats
+
+import "math"
+
+const (
+	// integrationSteps is the number of Simpson intervals used to
+	// integrate over a posterior; it must be even.
+	integrationSteps = 2000
+	// normalApproxThreshold is the smallest posterior parameter from which
+	// ProbabilityToBeat switches to the normal approximation, which is
+	// accurate there and avoids slow incomplete beta evaluations.
+	normalApproxThreshold = 5000
+
+	betaMaxIterations = 10000
+	betaEpsilon       = 1e-14
+)
+
+// ProbabilityToBeat is the posterior probability that b's true rate is
+// higher than a's. Each rate gets a uniform Beta(1, 1) prior, so its
+// posterior is Beta(1+successes, 1+failures).
+func ProbabilityToBeat(a, b Sample) (float64, error) {
+	if !a.valid() || !b.valid() {
+		return 0, ErrInvalidCounts
+	}
+	aa, ba := posterior(a)
+	ab, bb := posterior(b)
+
+	if min(aa, ba, ab, bb) >= normalApproxThreshold {
+		meanA, varA := betaMoments(aa, ba)
+		meanB, varB := betaMoments(ab, bb)
+		return NormalCDF((meanB - meanA) / math.Sqrt(varA+varB)), nil
+	}
+
+	// P(B > A) = integral of pdf_B(x) * cdf_A(x) over [0, 1]. Almost all of
+	// B's mass lies within a few standard deviations of its mean, so the
+	// integral only covers that range.
+	meanB, varB := betaMoments(ab, bb)
+	sd := math.Sqrt(varB)
+	lo := math.Max(0, meanB-12*sd)
+	hi := math.Min(1, meanB+12*sd)
+	h := (hi - lo) / integrationSteps
+
+	logNorm := logBeta(ab, bb)
+	f := func(x float64) float64 {
+		if x <= 0 {
+			// A's cdf is zero there.
+			return 0
+		}
+		if x >= 1 {
+			// A's cdf is one there and B's density is zero unless B has
+			// no failures, when it is 1/B(ab, 1).
+			if bb == 1 {
+				return math.Exp(-logNorm)
+			}
+			return 0
+		}
+		pdf := math.Exp((ab-1)*math.Log(x) + (bb-1)*math.Log1p(-x) - logNorm)
+		return pdf * RegularizedIncompleteBeta(aa, ba, x)
+	}
+
+	sum := f(lo) + f(hi)
+	for i := 1; i < integrationSteps; i++ {
+		weight := 2.0
+		if i%2 == 1 {
+			weight = 4
+		}
+		sum += weight * f(lo+float64(i)*h)
+	}
+	p := sum * h / 3
+	return math.Min(1, math.Max(0, p)), nil
+}
+
+func posterior(s Sample) (alpha, beta float64) {
+	return 1 + float64(s.Successes), 1 + float64(s.Trials-s.Successes)
+}
+
+func betaMoments(alpha, beta float64) (mean, variance float64) {
+	sum := alpha + beta
+	return alpha / sum, alpha * beta / (sum * sum * (sum + 1))
+}
+
+func logBeta(a, b float64) float64 {
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	return la + lb - lab
+}
+
+// RegularizedIncompleteBeta is I_x(a, b), the cdf of Beta(a, b) at x. It is
+// evaluated with Lentz's continued fraction, using the symmetry
+// I_x(a, b) = 1 - I_{1-x}(b, a) where that converges faster.
+func RegularizedIncompleteBeta(a, b, x float64) float64 {
+	switch {
+	case x <= 0:
+		return 0
+	case x >= 1:
+		return 1
+	}
+	front := math.Exp(a*math.Log(x) + b*math.Log1p(-x) - logBeta(a, b))
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+	return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+}
+
+func betaContinuedFraction(a, b, x float64) float64 {
+	const tiny = 1e-300
+	qab, qap, qam := a+b, a+1, a-1
+	c := 1.0
+	d := 1 - qab*x/qap
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= betaMaxIterations; m++ {
+		fm := float64(m)
+		m2 := 2 * fm
+
+		aa := fm * (b - fm) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		aa = -(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < betaEpsilon {
+			break
+		}
+	}
+	return h
+}
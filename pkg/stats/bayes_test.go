@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// For integer parameters I_x(a, b) is the probability that a binomial with
+// a+b-1 trials and success probability x has at least a successes, which
+// gives the exact references below.
+func TestRegularizedIncompleteBeta(t *testing.T) {
+	tests := []struct {
+		a, b, x float64
+		want    float64
+	}{
+		{1, 1, 0.3, 0.3},
+		{3, 1, 0.5, 0.125},
+		{1, 4, 0.5, 0.9375},
+		{2, 3, 0.3, 0.3483},
+		{10, 10, 0.5, 0.5},
+		{50, 50, 0.4, 0.02193044213008523},
+		{3, 40, 0.05, 0.3510136093462275},
+		{20, 2, 0.9, 0.364729963771708},
+		{2, 3, 0, 0},
+		{2, 3, 1, 1},
+		{2, 3, -0.5, 0},
+		{2, 3, 1.5, 1},
+	}
+	for _, tt := range tests {
+		if got := RegularizedIncompleteBeta(tt.a, tt.b, tt.x); !approxEqual(got, tt.want, 1e-10) {
+			t.Errorf("I_%v(%v, %v) = %v, want %v", tt.x, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// The references are the closed-form sum for Beta posteriors with integer
+// parameters (Evan Miller, "Formulas for Bayesian A/B Testing").
+func TestProbabilityToBeat(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Sample
+		want    float64
+		tol     float64
+		wantErr error
+	}{
+		{"single trials", Sample{Successes: 0, Trials: 1}, Sample{Successes: 1, Trials: 1}, 5.0 / 6, 1e-6, nil},
+		{"small samples", Sample{Successes: 10, Trials: 100}, Sample{Successes: 15, Trials: 100}, 0.8532837899338557, 1e-6, nil},
+		{"medium samples", Sample{Successes: 200, Trials: 1000}, Sample{Successes: 230, Trials: 1000}, 0.948618289213723, 1e-6, nil},
+		{"identical samples", Sample{Successes: 30, Trials: 300}, Sample{Successes: 30, Trials: 300}, 0.5, 1e-6, nil},
+		{"normal approximation", Sample{Successes: 5000, Trials: 10000}, Sample{Successes: 5100, Trials: 10000}, 0.9213512602240277, 1e-3, nil},
+		{"invalid counts", Sample{Successes: 2, Trials: 1}, Sample{Successes: 1, Trials: 1}, 0, 0, ErrInvalidCounts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ProbabilityToBeat(tt.a, tt.b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !approxEqual(got, tt.want, tt.tol) {
+				t.Errorf("ProbabilityToBeat = %v, want %v", got, tt.want)
+			}
+
+			// Swapping the samples gives the complement.
+			swapped, err := ProbabilityToBeat(tt.b, tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !approxEqual(got+swapped, 1, tt.tol) {
+				t.Errorf("P(b > a) + P(a > b) = %v, want 1", got+swapped)
+			}
+			if math.IsNaN(got) {
+				t.Errorf("ProbabilityToBeat is NaN")
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Package stats compares conversion rates of experiment variants, both the
+// frequentist way (two-proportion z-test, confidence intervals) and the
+// Bayesian way (probability that one rate beats another under Beta
+// posteriors).
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidCounts is returned for negative counts, empty samples or more
+// successes than trials.
+var ErrInvalidCounts = errors.New("invalid counts")
+
+// ErrInvalidConfidence is returned for a confidence level outside (0, 1).
+var ErrInvalidConfidence = errors.New("confidence must be between 0 and 1")
+
+// Sample is the outcome of one variant: Successes out of Trials.
+type Sample struct {
+	Successes int64
+	Trials    int64
+}
+
+// Rate is the observed success rate.
+func (s Sample) Rate() float64 {
+	if s.Trials == 0 {
+		return 0
+	}
+	return float64(s.Successes) / float64(s.Trials)
+}
+
+func (s Sample) valid() bool {
+	return s.Trials > 0 && s.Successes >= 0 && s.Successes <= s.Trials
+}
+
+// Interval is a two-sided confidence interval.
+type Interval struct {
+	Low  float64
+	High float64
+}
+
+// ZTest is the result of a two-sided two-proportion z-test.
+type ZTest struct {
+	Z      float64
+	PValue float64
+}
+
+// NormalCDF is the standard normal cumulative distribution function.
+func NormalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+// NormalQuantile is the inverse of NormalCDF for p in (0, 1).
+func NormalQuantile(p float64) float64 {
+	if p <= 0 {
+		return math.Inf(-1)
+	}
+	if p >= 1 {
+		return math.Inf(1)
+	}
+	return -math.Sqrt2 * math.Erfcinv(2*p)
+}
+
+// criticalValue is the z value that leaves (1-confidence)/2 in each tail.
+func criticalValue(confidence float64) (float64, error) {
+	if !(confidence > 0 && confidence < 1) {
+		return 0, ErrInvalidConfidence
+	}
+	return NormalQuantile(1 - (1-confidence)/2), nil
+}
+
+// TwoProportionZTest tests whether b's rate differs from a's, using the
+// pooled standard error. Z is positive when b converts better.
+func TwoProportionZTest(a, b Sample) (ZTest, error) {
+	if !a.valid() || !b.valid() {
+		return ZTest{}, ErrInvalidCounts
+	}
+	na, nb := float64(a.Trials), float64(b.Trials)
+	pooled := float64(a.Successes+b.Successes) / (na + nb)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/na + 1/nb))
+	if se == 0 {
+		// Both samples are all successes or all failures: no evidence of a
+		// difference.
+		return ZTest{Z: 0, PValue: 1}, nil
+	}
+	z := (b.Rate() - a.Rate()) / se
+	return ZTest{Z: z, PValue: 2 * NormalCDF(-math.Abs(z))}, nil
+}
+
+// WilsonInterval is the Wilson score interval for the rate of s. Unlike the
+// plain normal interval it stays inside [0, 1] and behaves for rates near
+// the edges.
+func WilsonInterval(s Sample, confidence float64) (Interval, error) {
+	z, err := criticalValue(confidence)
+	if err != nil {
+		return Interval{}, err
+	}
+	if !s.valid() {
+		return Interval{}, ErrInvalidCounts
+	}
+	n, p := float64(s.Trials), s.Rate()
+	z2 := z * z
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	half := z / (1 + z2/n) * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+	return Interval{Low: math.Max(0, center-half), High: math.Min(1, center+half)}, nil
+}
+
+// DifferenceInterval is the normal confidence interval for b's rate minus
+// a's, using the unpooled standard error.
+func DifferenceInterval(a, b Sample, confidence float64) (Interval, error) {
+	z, err := criticalValue(confidence)
+	if err != nil {
+		return Interval{}, err
+	}
+	if !a.valid() || !b.valid() {
+		return Interval{}, ErrInvalidCounts
+	}
+	pa, pb := a.Rate(), b.Rate()
+	se := math.Sqrt(pa*(1-pa)/float64(a.Trials) + pb*(1-pb)/float64(b.Trials))
+	diff := pb - pa
+	return Interval{Low: diff - z*se, High: diff + z*se}, nil
+}
+
+// LiftInterval scales DifferenceInterval by a's rate, giving the interval
+// of b's relative lift over a. It fails when a has no successes.
+func LiftInterval(a, b Sample, confidence float64) (Interval, error) {
+	diff, err := DifferenceInterval(a, b, confidence)
+	if err != nil {
+		return Interval{}, err
+	}
+	pa := a.Rate()
+	if pa == 0 {
+		return Interval{}, ErrInvalidCounts
+	}
+	return Interval{Low: diff.Low / pa, High: diff.High / pa}, nil
+}
@@ -0,0 +1,223 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func approxEqual(got, want, tol float64) bool {
+	return math.Abs(got-want) <= tol
+}
+
+func TestNormalCDF(t *testing.T) {
+	tests := []struct {
+		z    float64
+		want float64
+	}{
+		{0, 0.5},
+		{1, 0.8413447460685429},
+		{-1, 0.15865525393145707},
+		{1.959963984540054, 0.975},
+		{-1.6448536269514722, 0.05},
+		{math.Inf(1), 1},
+		{math.Inf(-1), 0},
+	}
+	for _, tt := range tests {
+		if got := NormalCDF(tt.z); !approxEqual(got, tt.want, 1e-12) {
+			t.Errorf("NormalCDF(%v) = %v, want %v", tt.z, got, tt.want)
+		}
+	}
+}
+
+func TestNormalQuantile(t *testing.T) {
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0.5, 0},
+		{0.975, 1.959963984540054},
+		{0.05, -1.6448536269514722},
+		{0.995, 2.5758293035489004},
+		{0, math.Inf(-1)},
+		{1, math.Inf(1)},
+	}
+	for _, tt := range tests {
+		got := NormalQuantile(tt.p)
+		if math.IsInf(tt.want, 0) {
+			if got != tt.want {
+				t.Errorf("NormalQuantile(%v) = %v, want %v", tt.p, got, tt.want)
+			}
+			continue
+		}
+		if !approxEqual(got, tt.want, 1e-9) {
+			t.Errorf("NormalQuantile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestTwoProportionZTest(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Sample
+		wantZ   float64
+		wantP   float64
+		wantErr error
+	}{
+		{
+			name:  "b converts better",
+			a:     Sample{Successes: 100, Trials: 1000},
+			b:     Sample{Successes: 120, Trials: 1000},
+			wantZ: 1.429300849823232,
+			wantP: 0.152917781863946,
+		},
+		{
+			name:  "b converts worse",
+			a:     Sample{Successes: 120, Trials: 1000},
+			b:     Sample{Successes: 100, Trials: 1000},
+			wantZ: -1.429300849823232,
+			wantP: 0.152917781863946,
+		},
+		{
+			name:  "equal rates",
+			a:     Sample{Successes: 50, Trials: 500},
+			b:     Sample{Successes: 100, Trials: 1000},
+			wantZ: 0,
+			wantP: 1,
+		},
+		{
+			name:  "all failures",
+			a:     Sample{Successes: 0, Trials: 10},
+			b:     Sample{Successes: 0, Trials: 20},
+			wantZ: 0,
+			wantP: 1,
+		},
+		{
+			name:    "no trials",
+			a:       Sample{},
+			b:       Sample{Successes: 1, Trials: 10},
+			wantErr: ErrInvalidCounts,
+		},
+		{
+			name:    "more successes than trials",
+			a:       Sample{Successes: 11, Trials: 10},
+			b:       Sample{Successes: 1, Trials: 10},
+			wantErr: ErrInvalidCounts,
+		},
+		{
+			name:    "negative successes",
+			a:       Sample{Successes: 1, Trials: 10},
+			b:       Sample{Successes: -1, Trials: 10},
+			wantErr: ErrInvalidCounts,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TwoProportionZTest(tt.a, tt.b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !approxEqual(got.Z, tt.wantZ, 1e-9) || !approxEqual(got.PValue, tt.wantP, 1e-9) {
+				t.Errorf("got z=%v p=%v, want z=%v p=%v", got.Z, got.PValue, tt.wantZ, tt.wantP)
+			}
+		})
+	}
+}
+
+// The interval references are from Newcombe, "Two-sided confidence
+// intervals for the single proportion" and "Interval estimation for the
+// difference between independent proportions" (Statistics in Medicine,
+// 1998).
+func TestWilsonInterval(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          Sample
+		confidence float64
+		want       Interval
+		wantErr    error
+	}{
+		{"81 of 263", Sample{Successes: 81, Trials: 263}, 0.95, Interval{0.2553, 0.3662}, nil},
+		{"15 of 148", Sample{Successes: 15, Trials: 148}, 0.95, Interval{0.0624, 0.1605}, nil},
+		{"0 of 10", Sample{Successes: 0, Trials: 10}, 0.95, Interval{0, 0.2775}, nil},
+		{"10 of 10", Sample{Successes: 10, Trials: 10}, 0.95, Interval{0.7225, 1}, nil},
+		{"no trials", Sample{}, 0.95, Interval{}, ErrInvalidCounts},
+		{"confidence of one", Sample{Successes: 1, Trials: 2}, 1, Interval{}, ErrInvalidConfidence},
+		{"confidence of zero", Sample{Successes: 1, Trials: 2}, 0, Interval{}, ErrInvalidConfidence},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WilsonInterval(tt.s, tt.confidence)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !approxEqual(got.Low, tt.want.Low, 5e-5) || !approxEqual(got.High, tt.want.High, 5e-5) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDifferenceAndLiftInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     Sample
+		wantDiff Interval
+		wantLift Interval
+		wantErr  error
+	}{
+		{
+			name:     "56 of 70 against 48 of 80",
+			a:        Sample{Successes: 48, Trials: 80},
+			b:        Sample{Successes: 56, Trials: 70},
+			wantDiff: Interval{0.0575, 0.3425},
+			wantLift: Interval{0.0958, 0.5708},
+		},
+		{
+			name:     "identical samples",
+			a:        Sample{Successes: 50, Trials: 100},
+			b:        Sample{Successes: 50, Trials: 100},
+			wantDiff: Interval{-0.1386, 0.1386},
+			wantLift: Interval{-0.2772, 0.2772},
+		},
+		{
+			name:    "no trials",
+			a:       Sample{Successes: 0, Trials: 0},
+			b:       Sample{Successes: 1, Trials: 10},
+			wantErr: ErrInvalidCounts,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := DifferenceInterval(tt.a, tt.b, 0.95)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DifferenceInterval err = %v, want %v", err, tt.wantErr)
+			}
+			lift, err := LiftInterval(tt.a, tt.b, 0.95)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LiftInterval err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !approxEqual(diff.Low, tt.wantDiff.Low, 5e-5) || !approxEqual(diff.High, tt.wantDiff.High, 5e-5) {
+				t.Errorf("DifferenceInterval = %+v, want %+v", diff, tt.wantDiff)
+			}
+			if !approxEqual(lift.Low, tt.wantLift.Low, 5e-5) || !approxEqual(lift.High, tt.wantLift.High, 5e-5) {
+				t.Errorf("LiftInterval = %+v, want %+v", lift, tt.wantLift)
+			}
+		})
+	}
+}
+
+func TestLiftIntervalWithoutBaselineSuccesses(t *testing.T) {
+	_, err := LiftInterval(Sample{Successes: 0, Trials: 10}, Sample{Successes: 1, Trials: 10}, 0.95)
+	if !errors.Is(err, ErrInvalidCounts) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidCounts)
+	}
+}
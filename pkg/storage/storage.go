@@ -0,0 +1,59 @@
+// Package storage keeps uploaded files in an object store. Objects are
+// addressed by slash-separated keys; backends hand out signed, expiring
+// download URLs so files can be fetched without an API token.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound         = errors.New("object not found")
+	ErrInvalidKey       = errors.New("invalid object key")
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+)
+
+// Storage is implemented by every backend.
+type Storage interface {
+	// Put stores size bytes read from r under key, replacing any object
+	// already there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object under key; it fails with ErrNotFound when there
+	// is none.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object under key. Deleting a missing object is not
+	// an error.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that downloads key without credentials for
+	// ttl. A non-empty filename is suggested to the browser as the name to
+	// save the download under.
+	SignedURL(key string, ttl time.Duration, filename string) (string, error)
+}
+
+// validateKey rejects keys that could escape the bucket or the storage
+// directory.
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	return nil
+}
+
+// ContentDisposition is the attachment header value for filename.
+func ContentDisposition(filename string) string {
+	if filename == "" {
+		return "attachment"
+	}
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
@@ -0,0 +1,135 @@
+// Package thumbnail renders small previews of JPEG, PNG and GIF images using
+// only the standard library.
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+const (
+	// MaxSide is the longest edge of a thumbnail in pixels.
+	MaxSide = 320
+	// MaxPixels caps the decoded size of a source image, so a small file
+	// cannot expand into gigabytes of memory.
+	MaxPixels = 40_000_000
+)
+
+var ErrTooLarge = errors.New("image dimensions too large")
+
+// Thumbnail is an encoded preview image.
+type Thumbnail struct {
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Config reads the dimensions of an image without decoding it.
+func Config(r io.Reader) (width, height int, err error) {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// Generate decodes the image in src and scales it down to fit MaxSide. Images
+// that already fit are re-encoded at their size. Opaque images come out as
+// JPEG, the rest as PNG to keep their transparency.
+func Generate(src io.ReadSeeker) (*Thumbnail, error) {
+	width, height, err := Config(src)
+	if err != nil {
+		return nil, err
+	}
+	if width*height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(src)
+	if err != nil {
+		return nil, err
+	}
+
+	thumb := resize(img, fit(img.Bounds().Dx(), img.Bounds().Dy()))
+
+	var buf bytes.Buffer
+	res := &Thumbnail{Width: thumb.Bounds().Dx(), Height: thumb.Bounds().Dy()}
+	if thumb.Opaque() {
+		res.ContentType = "image/jpeg"
+		err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 82})
+	} else {
+		res.ContentType = "image/png"
+		err = png.Encode(&buf, thumb)
+	}
+	if err != nil {
+		return nil, err
+	}
+	res.Data = buf.Bytes()
+	return res, nil
+}
+
+// fit scales width x height down to fit MaxSide, keeping the aspect ratio.
+func fit(width, height int) image.Rectangle {
+	if width <= MaxSide && height <= MaxSide {
+		return image.Rect(0, 0, width, height)
+	}
+	if width >= height {
+		return image.Rect(0, 0, MaxSide, max(1, height*MaxSide/width))
+	}
+	return image.Rect(0, 0, max(1, width*MaxSide/height), MaxSide)
+}
+
+// resize box-filters src into dst bounds: every destination pixel averages
+// the source pixels it covers.
+func resize(src image.Image, bounds image.Rectangle) *image.NRGBA {
+	sb := src.Bounds()
+	dst := image.NewNRGBA(bounds)
+	if bounds.Dx() == sb.Dx() && bounds.Dy() == sb.Dy() {
+		draw.Draw(dst, bounds, src, sb.Min, draw.Src)
+		return dst
+	}
+
+	for y := 0; y < bounds.Dy(); y++ {
+		y0 := sb.Min.Y + y*sb.Dy()/bounds.Dy()
+		y1 := max(y0+1, sb.Min.Y+(y+1)*sb.Dy()/bounds.Dy())
+		for x := 0; x < bounds.Dx(); x++ {
+			x0 := sb.Min.X + x*sb.Dx()/bounds.Dx()
+			x1 := max(x0+1, sb.Min.X+(x+1)*sb.Dx()/bounds.Dx())
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBA64Model.Convert(src.At(sx, sy)).(color.NRGBA64)
+					// Weight colour by alpha so transparent pixels don't
+					// darken the edges.
+					r += uint64(c.R) * uint64(c.A)
+					g += uint64(c.G) * uint64(c.A)
+					b += uint64(c.B) * uint64(c.A)
+					a += uint64(c.A)
+					n++
+				}
+			}
+			var px color.NRGBA
+			if a > 0 {
+				px = color.NRGBA{
+					R: uint8(r / a >> 8),
+					G: uint8(g / a >> 8),
+					B: uint8(b / a >> 8),
+					A: uint8(a / n >> 8),
+				}
+			}
+			dst.SetNRGBA(x, y, px)
+		}
+	}
+	return dst
+}
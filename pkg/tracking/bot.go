@@ -0,0 +1,29 @@
+package tracking
+
+import "strings"
+
+// botMarkers are user agent fragments of crawlers, link unfurlers, uptime
+// monitors and HTTP libraries, which follow links without a person behind
+// them.
+var botMarkers = []string{
+	"bot", "crawl", "spider", "slurp", "scrape", "preview", "fetch",
+	"facebookexternalhit", "whatsapp", "telegram", "skypeuripreview",
+	"headless", "phantomjs", "lighthouse", "pingdom", "uptime", "monitor",
+	"curl", "wget", "python-requests", "python-urllib", "go-http-client",
+	"java/", "okhttp", "axios", "node-fetch", "libwww", "httpclient",
+}
+
+// IsBot reports whether a request with the given user agent is most likely
+// automated. Requests without a user agent count as bots.
+func IsBot(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+	for _, marker := range botMarkers {
+		if strings.Contains(ua, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,27 @@
 package utils
 
 const (
-	RoleAdmin = "admin"
-	RoleUser  = "user"
+	RoleAdmin   = "admin"
+	RoleManager = "manager"
+	RoleUser    = "user"
 )
+
+var Roles = []string{
+	RoleAdmin,
+	RoleManager,
+	RoleUser,
+}
+
+const (
+	CampaignStatusDraft     = "draft"
+	CampaignStatusActive    = "active"
+	CampaignStatusPaused    = "paused"
+	CampaignStatusCompleted = "completed"
+)
+
+var CampaignStatuses = []string{
+	CampaignStatusDraft,
+	CampaignStatusActive,
+	CampaignStatusPaused,
+	CampaignStatusCompleted,
+}